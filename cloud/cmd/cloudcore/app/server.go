@@ -41,6 +41,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudstream"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudstream/iptables"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/audit"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
@@ -56,6 +57,7 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1/validation"
 	"github.com/kubeedge/kubeedge/pkg/features"
+	"github.com/kubeedge/kubeedge/pkg/tracing"
 	"github.com/kubeedge/kubeedge/pkg/util"
 	"github.com/kubeedge/kubeedge/pkg/util/flag"
 	"github.com/kubeedge/kubeedge/pkg/version"
@@ -94,6 +96,17 @@ kubernetes controller which manages devices so that the device metadata/status d
 			// start monitor server
 			go monitor.ServeMonitor(config.CommonConfig.MonitorServer)
 
+			// start tracing of the messages
+			if tc := config.CommonConfig.Tracing; tc != nil && tc.Enable {
+				if err := tracing.Init("cloudcore", tracing.Options{
+					Endpoint:               tc.Endpoint,
+					SamplingRatePerMillion: tc.SamplingRatePerMillion,
+				}); err != nil {
+					klog.Exit(err)
+				}
+				defer tracing.Shutdown()
+			}
+
 			// To help debugging, immediately log version
 			klog.Infof("Version: %+v", version.Get())
 			client.InitKubeEdgeClient(config.KubeAPIConfig)
@@ -117,6 +130,9 @@ kubernetes controller which manages devices so that the device metadata/status d
 			registerModules(config)
 
 			ctx := beehiveContext.GetContext()
+			if err := audit.Init(config.CommonConfig.Audit, ctx.Done()); err != nil {
+				klog.Exit(err)
+			}
 			if config.Modules.IptablesManager == nil || config.Modules.IptablesManager.Enable && config.Modules.IptablesManager.Mode == v1alpha1.InternalMode {
 				// By default, IptablesManager manages tunnel port related iptables rules
 				// The internal mode will share the host network, forward to the stream port.
@@ -15,8 +15,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
@@ -24,14 +27,21 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/apis/devices/v1beta1"
 	v1 "github.com/kubeedge/kubeedge/pkg/apis/rules/v1"
 	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	crdinformers "github.com/kubeedge/kubeedge/pkg/client/informers/externalversions"
+	appslisters "github.com/kubeedge/kubeedge/pkg/client/listers/apps/v1alpha1"
 )
 
 const (
-	ValidateCRDWebhookConfigName    = "kubeedge-crds-validate-webhook-configuration"
-	ValidateDeviceModelWebhookName  = "validatedevicemodel.kubeedge.io"
-	ValidateRuleWebhookName         = "validatedrule.kubeedge.io"
-	ValidateRuleEndpointWebhookName = "validatedruleendpoint.kubeedge.io"
-	ValidateNodeUpgradeWebhookName  = "validatenodeupgradejob.kubeedge.io"
+	ValidateCRDWebhookConfigName              = "kubeedge-crds-validate-webhook-configuration"
+	ValidateDeviceModelWebhookName            = "validatedevicemodel.kubeedge.io"
+	ValidateRuleWebhookName                   = "validatedrule.kubeedge.io"
+	ValidateRuleEndpointWebhookName           = "validatedruleendpoint.kubeedge.io"
+	ValidateNodeUpgradeWebhookName            = "validatenodeupgradejob.kubeedge.io"
+	ValidateEdgeAppWebhookName                = "validateedgeapplication.kubeedge.io"
+	ValidateNodeJobWebhookName                = "validatenodejob.kubeedge.io"
+	ValidateDeviceWebhookName                 = "validatedevice.kubeedge.io"
+	ValidateNodeGroupWebhookName              = "validatenodegroup.kubeedge.io"
+	ValidateOfflineMigrationPolicyWebhookName = "validateofflinemigrationpolicy.kubeedge.io"
 
 	OfflineMigrationConfigName  = "mutate-offlinemigration"
 	OfflineMigrationWebhookName = "mutateofflinemigration.kubeedge.io"
@@ -65,6 +75,10 @@ func addToScheme(scheme *runtime.Scheme) {
 type AdmissionController struct {
 	Client    *kubernetes.Clientset
 	CrdClient *versioned.Clientset
+
+	// listers are used by the webhooks called for every pod, to avoid requesting the apiserver
+	NamespaceLister              corelisters.NamespaceLister
+	OfflineMigrationPolicyLister appslisters.OfflineMigrationPolicyLister
 }
 
 func strPtr(s string) *string { return &s }
@@ -89,6 +103,21 @@ func Run(opt *options.AdmissionOptions) error {
 	controller.Client = cli
 	controller.CrdClient = vcli
 
+	informerFactory := informers.NewSharedInformerFactory(cli, 0)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(vcli, 0)
+	namespaceInformer := informerFactory.Core().V1().Namespaces().Informer()
+	policyInformer := crdInformerFactory.Apps().V1alpha1().OfflineMigrationPolicies().Informer()
+	controller.NamespaceLister = informerFactory.Core().V1().Namespaces().Lister()
+	controller.OfflineMigrationPolicyLister = crdInformerFactory.Apps().V1alpha1().OfflineMigrationPolicies().Lister()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informerFactory.Start(stopCh)
+	crdInformerFactory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, namespaceInformer.HasSynced, policyInformer.HasSynced) {
+		return errors.New("failed to wait for the caches of namespaces and OfflineMigrationPolicies to sync")
+	}
+
 	caBundle, err := os.ReadFile(opt.CaCertFile)
 	if err != nil {
 		return fmt.Errorf("unable to read cacert file: %v", err)
@@ -105,6 +134,11 @@ func Run(opt *options.AdmissionOptions) error {
 	http.HandleFunc("/offlinemigration", serveOfflineMigration)
 	http.HandleFunc("/nodeupgradejobs", serveNodeUpgradeJob)
 	http.HandleFunc("/mutating/nodeupgradejobs", serveMutatingNodeUpgradeJob)
+	http.HandleFunc("/nodejobs", serveNodeJob)
+	http.HandleFunc("/edgeapplications", serveEdgeApplication)
+	http.HandleFunc("/devices", serveDevice)
+	http.HandleFunc("/nodegroups", serveNodeGroup)
+	http.HandleFunc("/offlinemigrationpolicies", serveOfflineMigrationPolicy)
 
 	tlsConfig, err := configTLS(opt, restConfig)
 	if err != nil {
@@ -273,6 +307,141 @@ func (ac *AdmissionController) registerWebhooks(opt *options.AdmissionOptions, c
 				SideEffects:             &noneSideEffect,
 				AdmissionReviewVersions: []string{"v1"},
 			},
+			// NodeJob validating webhook
+			{
+				Name: ValidateNodeJobWebhookName,
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Create,
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"operations.kubeedge.io"},
+						APIVersions: []string{"v1alpha1"},
+						Resources:   []string{"nodejobs"},
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: opt.AdmissionServiceNamespace,
+						Name:      opt.AdmissionServiceName,
+						Path:      strPtr("/nodejobs"),
+						Port:      &opt.Port,
+					},
+					CABundle: cabundle,
+				},
+				FailurePolicy:           &failPolicy,
+				SideEffects:             &noneSideEffect,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+			// EdgeApplication validating webhook
+			{
+				Name: ValidateEdgeAppWebhookName,
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Create,
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"apps.kubeedge.io"},
+						APIVersions: []string{"v1alpha1"},
+						Resources:   []string{"edgeapplications"},
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: opt.AdmissionServiceNamespace,
+						Name:      opt.AdmissionServiceName,
+						Path:      strPtr("/edgeapplications"),
+						Port:      &opt.Port,
+					},
+					CABundle: cabundle,
+				},
+				FailurePolicy:           &failPolicy,
+				SideEffects:             &noneSideEffect,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+			// Device validating webhook
+			{
+				Name: ValidateDeviceWebhookName,
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Create,
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"devices.kubeedge.io"},
+						APIVersions: []string{"v1beta1"},
+						Resources:   []string{"devices"},
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: opt.AdmissionServiceNamespace,
+						Name:      opt.AdmissionServiceName,
+						Path:      strPtr("/devices"),
+						Port:      &opt.Port,
+					},
+					CABundle: cabundle,
+				},
+				FailurePolicy:           &failPolicy,
+				SideEffects:             &noneSideEffect,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+			// NodeGroup validating webhook
+			{
+				Name: ValidateNodeGroupWebhookName,
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Create,
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"apps.kubeedge.io"},
+						APIVersions: []string{"v1alpha1"},
+						Resources:   []string{"nodegroups"},
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: opt.AdmissionServiceNamespace,
+						Name:      opt.AdmissionServiceName,
+						Path:      strPtr("/nodegroups"),
+						Port:      &opt.Port,
+					},
+					CABundle: cabundle,
+				},
+				FailurePolicy:           &failPolicy,
+				SideEffects:             &noneSideEffect,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+			// OfflineMigrationPolicy validating webhook
+			{
+				Name: ValidateOfflineMigrationPolicyWebhookName,
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Create,
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"apps.kubeedge.io"},
+						APIVersions: []string{"v1alpha1"},
+						Resources:   []string{"offlinemigrationpolicies"},
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: opt.AdmissionServiceNamespace,
+						Name:      opt.AdmissionServiceName,
+						Path:      strPtr("/offlinemigrationpolicies"),
+						Port:      &opt.Port,
+					},
+					CABundle: cabundle,
+				},
+				FailurePolicy:           &failPolicy,
+				SideEffects:             &noneSideEffect,
+				AdmissionReviewVersions: []string{"v1"},
+			},
 		},
 	}
 	if err := registerValidateWebhook(ac.Client.AdmissionregistrationV1().ValidatingWebhookConfigurations(),
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissioncontroller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	devicesv1beta1 "github.com/kubeedge/kubeedge/pkg/apis/devices/v1beta1"
+)
+
+func serveDevice(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, admitDevice)
+}
+
+func admitDevice(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	switch review.Request.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		device := devicesv1beta1.Device{}
+		deserializer := codecs.UniversalDeserializer()
+		if _, _, err := deserializer.Decode(review.Request.Object.Raw, nil, &device); err != nil {
+			return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+		}
+
+		if device.Spec.DeviceModelRef == nil || device.Spec.DeviceModelRef.Name == "" {
+			return admissionResponse(field.Required(field.NewPath("spec", "deviceModelRef", "name"), ""))
+		}
+		model, err := controller.CrdClient.DevicesV1beta1().DeviceModels(device.Namespace).
+			Get(context.Background(), device.Spec.DeviceModelRef.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return admissionResponse(field.NotFound(field.NewPath("spec", "deviceModelRef", "name"), device.Spec.DeviceModelRef.Name))
+			}
+			return admissionResponse(fmt.Errorf("failed to get device model %s/%s: %v", device.Namespace, device.Spec.DeviceModelRef.Name, err))
+		}
+		return admissionResponse(validateDevice(&device, model))
+
+	case admissionv1.Delete:
+		//no rule defined for above operations, greenlight for all of above.
+		return admissionResponse(nil)
+	default:
+		err := fmt.Errorf("unsupported webhook operation %v", review.Request.Operation)
+		return admissionResponse(err)
+	}
+}
+
+// validateDevice validates the device against the device model it references.
+func validateDevice(device *devicesv1beta1.Device, model *devicesv1beta1.DeviceModel) error {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	protocolName := device.Spec.Protocol.ProtocolName
+	if model.Spec.Protocol != "" && protocolName != "" && protocolName != model.Spec.Protocol {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("protocol", "protocolName"), protocolName,
+			fmt.Sprintf("must be the same as the protocol %q of device model %s", model.Spec.Protocol, model.Name)))
+	}
+
+	modelProperties := make(map[string]*devicesv1beta1.ModelProperty, len(model.Spec.Properties))
+	for i := range model.Spec.Properties {
+		modelProperties[model.Spec.Properties[i].Name] = &model.Spec.Properties[i]
+	}
+
+	propertyNames := make(map[string]struct{}, len(device.Spec.Properties))
+	for i, property := range device.Spec.Properties {
+		fldPath := specPath.Child("properties").Index(i)
+		if _, ok := propertyNames[property.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("name"), property.Name))
+			continue
+		}
+		propertyNames[property.Name] = struct{}{}
+
+		modelProperty, ok := modelProperties[property.Name]
+		if !ok {
+			allErrs = append(allErrs, field.NotFound(fldPath.Child("name"), property.Name))
+			continue
+		}
+
+		if visitorProtocol := property.Visitors.ProtocolName; visitorProtocol != "" && visitorProtocol != protocolName {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("visitors", "protocolName"), visitorProtocol,
+				fmt.Sprintf("must be the same as spec.protocol.protocolName %q", protocolName)))
+		}
+		if property.Desired.Value != "" {
+			allErrs = append(allErrs, validateDesiredValue(property.Desired.Value, modelProperty, fldPath.Child("desired", "value"))...)
+		}
+	}
+	return allErrs.ToAggregate()
+}
+
+// validateDesiredValue checks the desired value is of the property type, and in the range of
+// Minimum and Maximum of the property if they are numbers.
+func validateDesiredValue(value string, property *devicesv1beta1.ModelProperty, fldPath *field.Path) field.ErrorList {
+	switch property.Type {
+	case devicesv1beta1.INT, devicesv1beta1.FLOAT, devicesv1beta1.DOUBLE:
+		var v float64
+		var err error
+		if property.Type == devicesv1beta1.INT {
+			var i int64
+			i, err = strconv.ParseInt(value, 10, 64)
+			v = float64(i)
+		} else {
+			v, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return field.ErrorList{field.Invalid(fldPath, value, fmt.Sprintf("must be a value of type %s", property.Type))}
+		}
+		if property.Minimum != "" {
+			if minimum, err := strconv.ParseFloat(property.Minimum, 64); err == nil && v < minimum {
+				return field.ErrorList{field.Invalid(fldPath, value,
+					fmt.Sprintf("must be greater than or equal to the minimum %s of property %s", property.Minimum, property.Name))}
+			}
+		}
+		if property.Maximum != "" {
+			if maximum, err := strconv.ParseFloat(property.Maximum, 64); err == nil && v > maximum {
+				return field.ErrorList{field.Invalid(fldPath, value,
+					fmt.Sprintf("must be less than or equal to the maximum %s of property %s", property.Maximum, property.Name))}
+			}
+		}
+	case devicesv1beta1.BOOLEAN:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return field.ErrorList{field.Invalid(fldPath, value, fmt.Sprintf("must be a value of type %s", property.Type))}
+		}
+	}
+	return nil
+}
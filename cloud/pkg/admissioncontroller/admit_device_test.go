@@ -0,0 +1,103 @@
+package admissioncontroller
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	devicesv1beta1 "github.com/kubeedge/kubeedge/pkg/apis/devices/v1beta1"
+)
+
+func TestValidateDevice(t *testing.T) {
+	model := &devicesv1beta1.DeviceModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "thermometer"},
+		Spec: devicesv1beta1.DeviceModelSpec{
+			Protocol: "modbus",
+			Properties: []devicesv1beta1.ModelProperty{
+				{Name: "temperature", Type: devicesv1beta1.INT, Minimum: "-10", Maximum: "50"},
+				{Name: "ratio", Type: devicesv1beta1.FLOAT, Minimum: "0", Maximum: "1"},
+				{Name: "enabled", Type: devicesv1beta1.BOOLEAN},
+				{Name: "label", Type: devicesv1beta1.STRING},
+			},
+		},
+	}
+	property := func(name, desired, visitorProtocol string) devicesv1beta1.DeviceProperty {
+		return devicesv1beta1.DeviceProperty{
+			Name:     name,
+			Desired:  devicesv1beta1.TwinProperty{Value: desired},
+			Visitors: devicesv1beta1.VisitorConfig{ProtocolName: visitorProtocol},
+		}
+	}
+
+	cases := map[string]struct {
+		protocol   string
+		properties []devicesv1beta1.DeviceProperty
+		wantErr    bool
+	}{
+		"valid device": {
+			protocol: "modbus",
+			properties: []devicesv1beta1.DeviceProperty{
+				property("temperature", "20", "modbus"),
+				property("ratio", "0.5", "modbus"),
+				property("enabled", "true", ""),
+				property("label", "anything", "modbus"),
+			},
+		},
+		"protocol not matching model": {
+			protocol: "opcua",
+			wantErr:  true,
+		},
+		"property not in model": {
+			protocol:   "modbus",
+			properties: []devicesv1beta1.DeviceProperty{property("humidity", "", "modbus")},
+			wantErr:    true,
+		},
+		"duplicated property": {
+			protocol:   "modbus",
+			properties: []devicesv1beta1.DeviceProperty{property("label", "", ""), property("label", "", "")},
+			wantErr:    true,
+		},
+		"visitor protocol not matching": {
+			protocol:   "modbus",
+			properties: []devicesv1beta1.DeviceProperty{property("temperature", "", "opcua")},
+			wantErr:    true,
+		},
+		"desired value below minimum": {
+			protocol:   "modbus",
+			properties: []devicesv1beta1.DeviceProperty{property("temperature", "-11", "")},
+			wantErr:    true,
+		},
+		"desired value above maximum": {
+			protocol:   "modbus",
+			properties: []devicesv1beta1.DeviceProperty{property("ratio", "1.5", "")},
+			wantErr:    true,
+		},
+		"desired value of wrong type": {
+			protocol:   "modbus",
+			properties: []devicesv1beta1.DeviceProperty{property("temperature", "20.5", "")},
+			wantErr:    true,
+		},
+		"invalid boolean": {
+			protocol:   "modbus",
+			properties: []devicesv1beta1.DeviceProperty{property("enabled", "yes", "")},
+			wantErr:    true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			device := &devicesv1beta1.Device{
+				Spec: devicesv1beta1.DeviceSpec{
+					DeviceModelRef: &v1.LocalObjectReference{Name: model.Name},
+					Protocol:       devicesv1beta1.ProtocolConfig{ProtocolName: c.protocol},
+					Properties:     c.properties,
+				},
+			}
+			err := validateDevice(device, model)
+			if (err != nil) != c.wantErr {
+				t.Errorf("unexpected error, got: %v, wantErr: %v", err, c.wantErr)
+			}
+		})
+	}
+}
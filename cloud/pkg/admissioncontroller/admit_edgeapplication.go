@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissioncontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+func serveEdgeApplication(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, admitEdgeApplication)
+}
+
+func admitEdgeApplication(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	switch review.Request.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		edgeApp := appsv1alpha1.EdgeApplication{}
+		deserializer := codecs.UniversalDeserializer()
+		if _, _, err := deserializer.Decode(review.Request.Object.Raw, nil, &edgeApp); err != nil {
+			return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+		}
+
+		return admissionResponse(validateEdgeApplication(&edgeApp))
+
+	case admissionv1.Delete:
+		//no rule defined for above operations, greenlight for all of above.
+		return admissionResponse(nil)
+	default:
+		err := fmt.Errorf("unsupported webhook operation %v", review.Request.Operation)
+		return admissionResponse(err)
+	}
+}
+
+func validateEdgeApplication(edgeApp *appsv1alpha1.EdgeApplication) error {
+	allErrs := validateManifests(edgeApp.Spec.WorkloadTemplate.Manifests, field.NewPath("spec", "workloadTemplate", "manifests"))
+	nodeGroupNames := map[string]struct{}{}
+	targetsPath := field.NewPath("spec", "workloadScope", "targetNodeGroups")
+	for i, target := range edgeApp.Spec.WorkloadScope.TargetNodeGroups {
+		fldPath := targetsPath.Index(i)
+		if target.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("name"), ""))
+		}
+		if _, ok := nodeGroupNames[target.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("name"), target.Name))
+		}
+		nodeGroupNames[target.Name] = struct{}{}
+		allErrs = append(allErrs, validateOverriders(&target.Overriders, fldPath.Child("overriders"))...)
+	}
+	if strategy := edgeApp.Spec.RolloutStrategy; strategy != nil {
+		allErrs = append(allErrs, validateRolloutStrategy(strategy, field.NewPath("spec", "rolloutStrategy"))...)
+	}
+	return allErrs.ToAggregate()
+}
+
+// validateManifests checks each manifest is a Kubernetes object with apiVersion, kind and name,
+// and no object is specified more than once.
+func validateManifests(manifests []appsv1alpha1.Manifest, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	objects := map[string]struct{}{}
+	for i, manifest := range manifests {
+		idxPath := fldPath.Index(i)
+		obj := map[string]interface{}{}
+		if err := json.Unmarshal(manifest.Raw, &obj); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath, string(manifest.Raw), fmt.Sprintf("must be a JSON object, %v", err)))
+			continue
+		}
+		u := unstructured.Unstructured{Object: obj}
+		if u.GetAPIVersion() == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("apiVersion"), ""))
+		}
+		if u.GetKind() == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("kind"), ""))
+		}
+		if u.GetName() == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("metadata", "name"), ""))
+		}
+		key := fmt.Sprintf("%s/%s/%s/%s", u.GetAPIVersion(), u.GetKind(), u.GetNamespace(), u.GetName())
+		if _, ok := objects[key]; ok {
+			allErrs = append(allErrs, field.Duplicate(idxPath, key))
+		}
+		objects[key] = struct{}{}
+	}
+	return allErrs
+}
+
+func validateRolloutStrategy(strategy *appsv1alpha1.RolloutStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if maxUnavailable := strategy.MaxUnavailable; maxUnavailable != nil {
+		// the percentage is scaled on 100 node groups only for validation
+		value, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, 100, true)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), maxUnavailable.String(), err.Error()))
+		} else if value < 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), maxUnavailable.String(), "must be greater than 0"))
+		}
+	}
+	if deadline := strategy.ProgressDeadlineSeconds; deadline != nil && *deadline < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("progressDeadlineSeconds"), *deadline, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateOverriders(overriders *appsv1alpha1.Overriders, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, overrider := range overriders.EnvOverriders {
+		idxPath := fldPath.Child("envOverriders").Index(i)
+		allErrs = append(allErrs, validateContainerName(overrider.ContainerName, idxPath.Child("containerName"))...)
+		allErrs = append(allErrs, validateOverriderOperator(overrider.Operator, idxPath.Child("operator"))...)
+		for j, env := range overrider.Value {
+			if env.Name == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("value").Index(j).Child("name"), ""))
+			}
+		}
+	}
+	for i, overrider := range overriders.ResourcesOverriders {
+		idxPath := fldPath.Child("resourcesOverriders").Index(i)
+		allErrs = append(allErrs, validateContainerName(overrider.ContainerName, idxPath.Child("containerName"))...)
+		for name, limit := range overrider.Value.Limits {
+			request, ok := overrider.Value.Requests[name]
+			if ok && request.Cmp(limit) > 0 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("value", "requests").Key(string(name)),
+					request.String(), fmt.Sprintf("must be less than or equal to %s limit", name)))
+			}
+		}
+	}
+	for i, overrider := range overriders.CommandOverriders {
+		idxPath := fldPath.Child("commandOverriders").Index(i)
+		allErrs = append(allErrs, validateContainerName(overrider.ContainerName, idxPath.Child("containerName"))...)
+		allErrs = append(allErrs, validateOverriderOperator(overrider.Operator, idxPath.Child("operator"))...)
+	}
+	for i, overrider := range overriders.ArgsOverriders {
+		idxPath := fldPath.Child("argsOverriders").Index(i)
+		allErrs = append(allErrs, validateContainerName(overrider.ContainerName, idxPath.Child("containerName"))...)
+		allErrs = append(allErrs, validateOverriderOperator(overrider.Operator, idxPath.Child("operator"))...)
+	}
+	if overriders.TolerationsOverrider != nil {
+		allErrs = append(allErrs, validateOverriderOperator(overriders.TolerationsOverrider.Operator,
+			fldPath.Child("tolerationsOverrider", "operator"))...)
+	}
+	for i := range overriders.JSONPatchOverriders {
+		allErrs = append(allErrs, validateJSONPatchOverrider(&overriders.JSONPatchOverriders[i], fldPath.Child("jsonPatchOverriders").Index(i))...)
+	}
+	if patch := overriders.StrategicMergePatch; patch != nil && len(patch.Raw) != 0 {
+		obj := map[string]interface{}{}
+		if err := json.Unmarshal(patch.Raw, &obj); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("strategicMergePatch"), string(patch.Raw),
+				fmt.Sprintf("must be a JSON object, %v", err)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateContainerName(name string, fldPath *field.Path) field.ErrorList {
+	if name == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	return nil
+}
+
+func validateOverriderOperator(op appsv1alpha1.OverriderOperator, fldPath *field.Path) field.ErrorList {
+	switch op {
+	case appsv1alpha1.OverriderOpAdd, appsv1alpha1.OverriderOpRemove, appsv1alpha1.OverriderOpReplace:
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(fldPath, op, []string{
+		string(appsv1alpha1.OverriderOpAdd), string(appsv1alpha1.OverriderOpRemove), string(appsv1alpha1.OverriderOpReplace),
+	})}
+}
+
+func validateJSONPatchOverrider(overrider *appsv1alpha1.JSONPatchOverrider, fldPath *field.Path) field.ErrorList {
+	allErrs := validateOverriderOperator(overrider.Operator, fldPath.Child("operator"))
+	if !strings.HasPrefix(overrider.Path, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), overrider.Path, "must start with '/'"))
+	}
+
+	hasValue := overrider.Value != nil && len(overrider.Value.Raw) != 0
+	switch overrider.Operator {
+	case appsv1alpha1.OverriderOpRemove:
+		if hasValue {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("value"), "must be empty when operator is 'remove'"))
+		}
+	case appsv1alpha1.OverriderOpAdd, appsv1alpha1.OverriderOpReplace:
+		if !hasValue {
+			allErrs = append(allErrs, field.Required(fldPath.Child("value"),
+				fmt.Sprintf("must be specified when operator is '%s'", overrider.Operator)))
+		} else if !json.Valid(overrider.Value.Raw) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("value"), string(overrider.Value.Raw), "must be valid JSON"))
+		}
+	}
+	return allErrs
+}
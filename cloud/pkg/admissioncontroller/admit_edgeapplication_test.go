@@ -0,0 +1,175 @@
+package admissioncontroller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+func TestValidateEdgeApplication(t *testing.T) {
+	cases := map[string]struct {
+		overriders appsv1alpha1.Overriders
+		wantErr    bool
+	}{
+		"valid overriders": {
+			overriders: appsv1alpha1.Overriders{
+				EnvOverriders: []appsv1alpha1.EnvOverrider{{
+					ContainerName: "nginx",
+					Operator:      appsv1alpha1.OverriderOpAdd,
+					Value:         []corev1.EnvVar{{Name: "SITE", Value: "a"}},
+				}},
+				JSONPatchOverriders: []appsv1alpha1.JSONPatchOverrider{
+					{Path: "/spec/template/spec/hostNetwork", Operator: appsv1alpha1.OverriderOpAdd, Value: &runtime.RawExtension{Raw: []byte("true")}},
+					{Path: "/spec/template/spec/tolerations", Operator: appsv1alpha1.OverriderOpRemove},
+				},
+				StrategicMergePatch: &runtime.RawExtension{Raw: []byte(`{"spec":{}}`)},
+			},
+		},
+		"empty container name": {
+			overriders: appsv1alpha1.Overriders{
+				ArgsOverriders: []appsv1alpha1.CommandArgsOverrider{{Operator: appsv1alpha1.OverriderOpAdd}},
+			},
+			wantErr: true,
+		},
+		"invalid operator": {
+			overriders: appsv1alpha1.Overriders{
+				TolerationsOverrider: &appsv1alpha1.TolerationsOverrider{Operator: "merge"},
+			},
+			wantErr: true,
+		},
+		"json patch path without leading slash": {
+			overriders: appsv1alpha1.Overriders{
+				JSONPatchOverriders: []appsv1alpha1.JSONPatchOverrider{
+					{Path: "spec/replicas", Operator: appsv1alpha1.OverriderOpReplace, Value: &runtime.RawExtension{Raw: []byte("1")}},
+				},
+			},
+			wantErr: true,
+		},
+		"json patch remove with value": {
+			overriders: appsv1alpha1.Overriders{
+				JSONPatchOverriders: []appsv1alpha1.JSONPatchOverrider{
+					{Path: "/spec/replicas", Operator: appsv1alpha1.OverriderOpRemove, Value: &runtime.RawExtension{Raw: []byte("1")}},
+				},
+			},
+			wantErr: true,
+		},
+		"json patch add without value": {
+			overriders: appsv1alpha1.Overriders{
+				JSONPatchOverriders: []appsv1alpha1.JSONPatchOverrider{
+					{Path: "/spec/replicas", Operator: appsv1alpha1.OverriderOpAdd},
+				},
+			},
+			wantErr: true,
+		},
+		"strategic merge patch is not an object": {
+			overriders: appsv1alpha1.Overriders{
+				StrategicMergePatch: &runtime.RawExtension{Raw: []byte(`[]`)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			edgeApp := &appsv1alpha1.EdgeApplication{
+				Spec: appsv1alpha1.EdgeApplicationSpec{
+					WorkloadScope: appsv1alpha1.WorkloadScope{
+						TargetNodeGroups: []appsv1alpha1.TargetNodeGroup{{Name: "group", Overriders: c.overriders}},
+					},
+				},
+			}
+			err := validateEdgeApplication(edgeApp)
+			if (err != nil) != c.wantErr {
+				t.Errorf("unexpected error, got: %v, wantErr: %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEdgeApplicationDuplicateNodeGroup(t *testing.T) {
+	edgeApp := &appsv1alpha1.EdgeApplication{
+		Spec: appsv1alpha1.EdgeApplicationSpec{
+			WorkloadScope: appsv1alpha1.WorkloadScope{
+				TargetNodeGroups: []appsv1alpha1.TargetNodeGroup{{Name: "group"}, {Name: "group"}},
+			},
+		},
+	}
+	if err := validateEdgeApplication(edgeApp); err == nil {
+		t.Errorf("expect error for duplicated node groups")
+	}
+}
+
+func TestValidateRolloutStrategy(t *testing.T) {
+	zero := intstr.FromInt(0)
+	percent := intstr.FromString("20%")
+	invalid := intstr.FromString("abc")
+	deadline := int32(0)
+	cases := map[string]struct {
+		strategy *appsv1alpha1.RolloutStrategy
+		wantErr  bool
+	}{
+		"default strategy":       {strategy: &appsv1alpha1.RolloutStrategy{}},
+		"percent maxUnavailable": {strategy: &appsv1alpha1.RolloutStrategy{MaxUnavailable: &percent}},
+		"zero maxUnavailable":    {strategy: &appsv1alpha1.RolloutStrategy{MaxUnavailable: &zero}, wantErr: true},
+		"invalid maxUnavailable": {strategy: &appsv1alpha1.RolloutStrategy{MaxUnavailable: &invalid}, wantErr: true},
+		"zero progress deadline": {strategy: &appsv1alpha1.RolloutStrategy{ProgressDeadlineSeconds: &deadline}, wantErr: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			edgeApp := &appsv1alpha1.EdgeApplication{
+				Spec: appsv1alpha1.EdgeApplicationSpec{RolloutStrategy: c.strategy},
+			}
+			err := validateEdgeApplication(edgeApp)
+			if (err != nil) != c.wantErr {
+				t.Errorf("unexpected error, got: %v, wantErr: %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateManifests(t *testing.T) {
+	deployment := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx"}}`
+	cases := map[string]struct {
+		manifests []string
+		wantErr   bool
+	}{
+		"valid manifests": {
+			manifests: []string{deployment, `{"apiVersion":"v1","kind":"Service","metadata":{"name":"nginx"}}`},
+		},
+		"not a JSON object": {
+			manifests: []string{`[]`},
+			wantErr:   true,
+		},
+		"missing kind": {
+			manifests: []string{`{"apiVersion":"apps/v1","metadata":{"name":"nginx"}}`},
+			wantErr:   true,
+		},
+		"missing name": {
+			manifests: []string{`{"apiVersion":"apps/v1","kind":"Deployment"}`},
+			wantErr:   true,
+		},
+		"duplicated manifests": {
+			manifests: []string{deployment, deployment},
+			wantErr:   true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			edgeApp := &appsv1alpha1.EdgeApplication{}
+			for _, m := range c.manifests {
+				edgeApp.Spec.WorkloadTemplate.Manifests = append(edgeApp.Spec.WorkloadTemplate.Manifests,
+					appsv1alpha1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(m)}})
+			}
+			err := validateEdgeApplication(edgeApp)
+			if (err != nil) != c.wantErr {
+				t.Errorf("unexpected error, got: %v, wantErr: %v", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissioncontroller
+
+import (
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/maintenancewindow"
+)
+
+var supportedNodeFields = []string{
+	string(appsv1alpha1.NodeFieldArchitecture),
+	string(appsv1alpha1.NodeFieldOperatingSystem),
+	string(appsv1alpha1.NodeFieldOSImage),
+	string(appsv1alpha1.NodeFieldKernelVersion),
+	string(appsv1alpha1.NodeFieldContainerRuntimeVersion),
+	string(appsv1alpha1.NodeFieldKubeletVersion),
+	string(appsv1alpha1.NodeFieldKubeEdgeVersion),
+}
+
+func serveNodeGroup(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, admitNodeGroup)
+}
+
+func admitNodeGroup(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	switch review.Request.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		nodeGroup := appsv1alpha1.NodeGroup{}
+		deserializer := codecs.UniversalDeserializer()
+		if _, _, err := deserializer.Decode(review.Request.Object.Raw, nil, &nodeGroup); err != nil {
+			return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+		}
+
+		return admissionResponse(validateNodeGroup(&nodeGroup))
+
+	case admissionv1.Delete:
+		//no rule defined for above operations, greenlight for all of above.
+		return admissionResponse(nil)
+	default:
+		err := fmt.Errorf("unsupported webhook operation %v", review.Request.Operation)
+		return admissionResponse(err)
+	}
+}
+
+func validateNodeGroup(nodeGroup *appsv1alpha1.NodeGroup) error {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateNodeNames(nodeGroup.Spec.Nodes, specPath.Child("nodes"))...)
+	allErrs = append(allErrs, validateNodeNames(nodeGroup.Spec.ExcludeNodes, specPath.Child("excludeNodes"))...)
+	allErrs = append(allErrs, metav1validation.ValidateLabels(nodeGroup.Spec.MatchLabels, specPath.Child("matchLabels"))...)
+	if nodeGroup.Spec.LabelSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(nodeGroup.Spec.LabelSelector,
+			metav1validation.LabelSelectorValidationOptions{}, specPath.Child("labelSelector"))...)
+	}
+	for i, requirement := range nodeGroup.Spec.NodeFieldSelector {
+		allErrs = append(allErrs, validateNodeFieldSelectorRequirement(requirement, specPath.Child("nodeFieldSelector").Index(i))...)
+	}
+	for i, window := range nodeGroup.Spec.MaintenanceWindows {
+		if err := maintenancewindow.Validate(window); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("maintenanceWindows").Index(i), window, err.Error()))
+		}
+	}
+	return allErrs.ToAggregate()
+}
+
+func validateNodeNames(names []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := make(map[string]struct{}, len(names))
+	for i, name := range names {
+		idxPath := fldPath.Index(i)
+		if _, ok := seen[name]; ok {
+			allErrs = append(allErrs, field.Duplicate(idxPath, name))
+			continue
+		}
+		seen[name] = struct{}{}
+		for _, msg := range validation.IsDNS1123Subdomain(name) {
+			allErrs = append(allErrs, field.Invalid(idxPath, name, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateNodeFieldSelectorRequirement(requirement appsv1alpha1.NodeFieldSelectorRequirement, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	supported := false
+	for _, key := range supportedNodeFields {
+		if string(requirement.Key) == key {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("key"), requirement.Key, supportedNodeFields))
+	}
+
+	switch requirement.Operator {
+	case metav1.LabelSelectorOpIn, metav1.LabelSelectorOpNotIn:
+		if len(requirement.Values) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("values"),
+				"must be specified when `operator` is 'In' or 'NotIn'"))
+		}
+	case metav1.LabelSelectorOpExists, metav1.LabelSelectorOpDoesNotExist:
+		if len(requirement.Values) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("values"),
+				"may not be specified when `operator` is 'Exists' or 'DoesNotExist'"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("operator"), requirement.Operator, []string{
+			string(metav1.LabelSelectorOpIn), string(metav1.LabelSelectorOpNotIn),
+			string(metav1.LabelSelectorOpExists), string(metav1.LabelSelectorOpDoesNotExist),
+		}))
+	}
+	return allErrs
+}
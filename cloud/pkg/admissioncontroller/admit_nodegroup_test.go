@@ -0,0 +1,86 @@
+package admissioncontroller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+func TestValidateNodeGroup(t *testing.T) {
+	cases := map[string]struct {
+		spec    appsv1alpha1.NodeGroupSpec
+		wantErr bool
+	}{
+		"valid nodegroup": {
+			spec: appsv1alpha1.NodeGroupSpec{
+				Nodes:        []string{"edge-1"},
+				ExcludeNodes: []string{"edge-2"},
+				MatchLabels:  map[string]string{"region": "hangzhou"},
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+				}},
+				NodeFieldSelector: []appsv1alpha1.NodeFieldSelectorRequirement{
+					{Key: appsv1alpha1.NodeFieldArchitecture, Operator: metav1.LabelSelectorOpIn, Values: []string{"arm64"}},
+					{Key: appsv1alpha1.NodeFieldKubeEdgeVersion, Operator: metav1.LabelSelectorOpExists},
+				},
+				MaintenanceWindows: []appsv1alpha1.MaintenanceWindow{
+					{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 3 * time.Hour}},
+				},
+			},
+		},
+		"duplicated node": {
+			spec:    appsv1alpha1.NodeGroupSpec{Nodes: []string{"edge-1", "edge-1"}},
+			wantErr: true,
+		},
+		"invalid node name": {
+			spec:    appsv1alpha1.NodeGroupSpec{ExcludeNodes: []string{"Edge_1"}},
+			wantErr: true,
+		},
+		"invalid match labels": {
+			spec:    appsv1alpha1.NodeGroupSpec{MatchLabels: map[string]string{"region": "-bad-"}},
+			wantErr: true,
+		},
+		"invalid label selector operator": {
+			spec: appsv1alpha1.NodeGroupSpec{LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "zone", Operator: "Equals", Values: []string{"a"}},
+			}}},
+			wantErr: true,
+		},
+		"label selector In without values": {
+			spec: appsv1alpha1.NodeGroupSpec{LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "zone", Operator: metav1.LabelSelectorOpIn},
+			}}},
+			wantErr: true,
+		},
+		"unsupported node field": {
+			spec: appsv1alpha1.NodeGroupSpec{NodeFieldSelector: []appsv1alpha1.NodeFieldSelectorRequirement{
+				{Key: "hostname", Operator: metav1.LabelSelectorOpExists},
+			}},
+			wantErr: true,
+		},
+		"node field Exists with values": {
+			spec: appsv1alpha1.NodeGroupSpec{NodeFieldSelector: []appsv1alpha1.NodeFieldSelectorRequirement{
+				{Key: appsv1alpha1.NodeFieldArchitecture, Operator: metav1.LabelSelectorOpExists, Values: []string{"amd64"}},
+			}},
+			wantErr: true,
+		},
+		"invalid maintenance window": {
+			spec: appsv1alpha1.NodeGroupSpec{MaintenanceWindows: []appsv1alpha1.MaintenanceWindow{
+				{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateNodeGroup(&appsv1alpha1.NodeGroup{Spec: c.spec})
+			if (err != nil) != c.wantErr {
+				t.Errorf("unexpected error, got: %v, wantErr: %v", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissioncontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func serveNodeJob(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, admitNodeJob)
+}
+
+func admitNodeJob(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	deserializer := codecs.UniversalDeserializer()
+	switch review.Request.Operation {
+	case admissionv1.Create:
+		job := v1alpha1.NodeJob{}
+		if _, _, err := deserializer.Decode(review.Request.Object.Raw, nil, &job); err != nil {
+			return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+		}
+		return admissionResponse(validateNodeJob(&job))
+
+	case admissionv1.Update:
+		newJob := v1alpha1.NodeJob{}
+		if _, _, err := deserializer.Decode(review.Request.Object.Raw, nil, &newJob); err != nil {
+			return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+		}
+		oldJob := v1alpha1.NodeJob{}
+		if _, _, err := deserializer.Decode(review.Request.OldObject.Raw, nil, &oldJob); err != nil {
+			return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+		}
+
+		// the same as NodeUpgradeJob, spec fields are not allowed to update once a NodeJob is created.
+		if !reflect.DeepEqual(oldJob.Spec, newJob.Spec) {
+			return admissionResponse(errors.New("spec fields are not allowed to update once it's created"))
+		}
+		return admissionResponse(nil)
+
+	default:
+		return admissionResponse(fmt.Errorf("unsupported webhook operation %v", review.Request.Operation))
+	}
+}
+
+func validateNodeJob(job *v1alpha1.NodeJob) error {
+	if job.Spec.Action == "" {
+		return fmt.Errorf("action must be specified")
+	}
+
+	// we must specify NodeNames or LabelSelector, and we can only specify only one
+	if len(job.Spec.NodeNames) == 0 && job.Spec.LabelSelector == nil {
+		return fmt.Errorf("both NodeNames and LabelSelector are NOT specified")
+	}
+	if len(job.Spec.NodeNames) != 0 && job.Spec.LabelSelector != nil {
+		return fmt.Errorf("both NodeNames and LabelSelector are specified")
+	}
+
+	// actions can be registered on edge side, only the parameters of built-in actions are validated
+	if job.Spec.Action == v1alpha1.NodeJobActionUpdateConfig {
+		patch, ok := job.Spec.Parameters[v1alpha1.NodeJobParamPatch]
+		if !ok {
+			return fmt.Errorf("parameter %q is required by action %s", v1alpha1.NodeJobParamPatch, job.Spec.Action)
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(patch), &obj); err != nil {
+			return fmt.Errorf("parameter %q must be a JSON object: %v", v1alpha1.NodeJobParamPatch, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package admissioncontroller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func TestValidateNodeJob(t *testing.T) {
+	cases := map[string]struct {
+		spec    v1alpha1.NodeJobSpec
+		wantErr bool
+	}{
+		"valid": {
+			spec: v1alpha1.NodeJobSpec{Action: v1alpha1.NodeJobActionRestartEdgeCore, NodeNames: []string{"edge-1"}},
+		},
+		"custom action": {
+			spec: v1alpha1.NodeJobSpec{Action: "flushCache", LabelSelector: &metav1.LabelSelector{}},
+		},
+		"empty action": {
+			spec:    v1alpha1.NodeJobSpec{NodeNames: []string{"edge-1"}},
+			wantErr: true,
+		},
+		"no node selected": {
+			spec:    v1alpha1.NodeJobSpec{Action: v1alpha1.NodeJobActionReboot},
+			wantErr: true,
+		},
+		"both node names and label selector": {
+			spec: v1alpha1.NodeJobSpec{
+				Action:        v1alpha1.NodeJobActionReboot,
+				NodeNames:     []string{"edge-1"},
+				LabelSelector: &metav1.LabelSelector{},
+			},
+			wantErr: true,
+		},
+		"valid config patch": {
+			spec: v1alpha1.NodeJobSpec{
+				Action:     v1alpha1.NodeJobActionUpdateConfig,
+				NodeNames:  []string{"edge-1"},
+				Parameters: map[string]string{v1alpha1.NodeJobParamPatch: `{"modules":{"edged":{"tailoredKubeletConfig":{"maxPods":200}}}}`},
+			},
+		},
+		"missing config patch": {
+			spec:    v1alpha1.NodeJobSpec{Action: v1alpha1.NodeJobActionUpdateConfig, NodeNames: []string{"edge-1"}},
+			wantErr: true,
+		},
+		"config patch is not an object": {
+			spec: v1alpha1.NodeJobSpec{
+				Action:     v1alpha1.NodeJobActionUpdateConfig,
+				NodeNames:  []string{"edge-1"},
+				Parameters: map[string]string{v1alpha1.NodeJobParamPatch: `[]`},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateNodeJob(&v1alpha1.NodeJob{Spec: c.spec})
+			if (err != nil) != c.wantErr {
+				t.Errorf("unexpected error: %v, wantErr: %v", err, c.wantErr)
+			}
+		})
+	}
+}
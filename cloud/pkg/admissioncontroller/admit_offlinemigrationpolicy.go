@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissioncontroller
+
+import (
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+func serveOfflineMigrationPolicy(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, admitOfflineMigrationPolicy)
+}
+
+func admitOfflineMigrationPolicy(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	switch review.Request.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		policy := appsv1alpha1.OfflineMigrationPolicy{}
+		deserializer := codecs.UniversalDeserializer()
+		if _, _, err := deserializer.Decode(review.Request.Object.Raw, nil, &policy); err != nil {
+			return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+		}
+
+		// pods are rejected by the offline migration webhook if the policy applied is invalid
+		if _, err := policyFromSpec(&policy); err != nil {
+			return admissionResponse(err)
+		}
+		policies, err := controller.OfflineMigrationPolicyLister.List(labels.Everything())
+		if err != nil {
+			return admissionResponse(fmt.Errorf("failed to list OfflineMigrationPolicies: %v", err))
+		}
+		return admissionResponse(validateOfflineMigrationPolicyConflicts(&policy, policies))
+
+	case admissionv1.Delete:
+		//no rule defined for above operations, greenlight for all of above.
+		return admissionResponse(nil)
+	default:
+		err := fmt.Errorf("unsupported webhook operation %v", review.Request.Operation)
+		return admissionResponse(err)
+	}
+}
+
+// validateOfflineMigrationPolicyConflicts makes sure that at most one policy applies to the pods of
+// each NodeGroup, and at most one policy is the cluster default policy.
+func validateOfflineMigrationPolicyConflicts(policy *appsv1alpha1.OfflineMigrationPolicy,
+	policies []*appsv1alpha1.OfflineMigrationPolicy) error {
+	nodeGroups := make(map[string]bool, len(policy.Spec.NodeGroups))
+	for _, name := range policy.Spec.NodeGroups {
+		if nodeGroups[name] {
+			return fmt.Errorf("NodeGroup %s is duplicated in spec.nodeGroups", name)
+		}
+		nodeGroups[name] = true
+	}
+
+	for _, p := range policies {
+		// the policy itself is listed when it is updated
+		if p.Name == policy.Name {
+			continue
+		}
+		if len(policy.Spec.NodeGroups) == 0 {
+			if len(p.Spec.NodeGroups) == 0 {
+				return fmt.Errorf("OfflineMigrationPolicy %s is already the cluster default policy", p.Name)
+			}
+			continue
+		}
+		for _, name := range p.Spec.NodeGroups {
+			if nodeGroups[name] {
+				return fmt.Errorf("NodeGroup %s is already selected by OfflineMigrationPolicy %s", name, p.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package admissioncontroller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+func TestValidateOfflineMigrationPolicyConflicts(t *testing.T) {
+	newPolicy := func(name string, nodeGroups ...string) *appsv1alpha1.OfflineMigrationPolicy {
+		return &appsv1alpha1.OfflineMigrationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: appsv1alpha1.OfflineMigrationPolicySpec{
+				NodeGroups: nodeGroups,
+				Type:       appsv1alpha1.OfflineMigrationNone,
+			},
+		}
+	}
+	policies := []*appsv1alpha1.OfflineMigrationPolicy{
+		newPolicy("default"),
+		newPolicy("hangzhou", "hangzhou", "shanghai"),
+	}
+
+	cases := map[string]struct {
+		policy  *appsv1alpha1.OfflineMigrationPolicy
+		wantErr bool
+	}{
+		"new nodegroup": {
+			policy: newPolicy("beijing", "beijing"),
+		},
+		"update the cluster default policy": {
+			policy: newPolicy("default"),
+		},
+		"update the nodegroup policy": {
+			policy: newPolicy("hangzhou", "hangzhou"),
+		},
+		"another cluster default policy": {
+			policy:  newPolicy("fallback"),
+			wantErr: true,
+		},
+		"nodegroup selected by another policy": {
+			policy:  newPolicy("east", "beijing", "shanghai"),
+			wantErr: true,
+		},
+		"duplicated nodegroup": {
+			policy:  newPolicy("beijing", "beijing", "beijing"),
+			wantErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateOfflineMigrationPolicyConflicts(c.policy, policies)
+			if (err != nil) != c.wantErr {
+				t.Errorf("unexpected error, got: %v, wantErr: %v", err, c.wantErr)
+			}
+		})
+	}
+}
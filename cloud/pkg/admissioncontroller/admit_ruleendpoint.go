@@ -45,6 +45,9 @@ func validateRuleEndpoint(ruleEndpoint *rulesv1.RuleEndpoint) error {
 		if !exist {
 			return fmt.Errorf("\"service_port\" property missed in property when ruleEndpoint is \"servicebus\"")
 		}
+		if scheme, exist := ruleEndpoint.Spec.Properties["service_scheme"]; exist && scheme != "http" && scheme != "https" {
+			return fmt.Errorf("\"service_scheme\" property must be \"http\" or \"https\" when ruleEndpoint is \"servicebus\"")
+		}
 	}
 	return nil
 }
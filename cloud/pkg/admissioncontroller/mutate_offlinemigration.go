@@ -2,66 +2,244 @@ package admissioncontroller
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/nodegroup"
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
 )
 
 type patchMapValue struct {
-	Op    string        `json:"op"`
-	Path  string        `json:"path"`
-	Value []interface{} `json:"value,omitempty"`
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
 }
 
-func mutateOfflineMigration(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
-	reviewResponse := admissionv1.AdmissionResponse{
-		Allowed: true,
-	}
+// offlineMigrationPolicy is the policy applied to the pod, it is reported in the
+// OfflineMigrationAppliedAnnotation of the pod.
+type offlineMigrationPolicy struct {
+	Type              appsv1alpha1.OfflineMigrationPolicyType `json:"type"`
+	TolerationSeconds *int64                                  `json:"tolerationSeconds,omitempty"`
+	// Source is where the policy comes from, such as pod, namespace/<name> and OfflineMigrationPolicy/<name>.
+	Source string `json:"source"`
+}
 
+// defaultOfflineMigrationPolicy keeps the pods on their nodes forever, which is the
+// behavior before the policy can be chosen.
+var defaultOfflineMigrationPolicy = offlineMigrationPolicy{
+	Type:   appsv1alpha1.OfflineMigrationForever,
+	Source: "default",
+}
+
+func mutateOfflineMigration(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	var pod corev1.Pod
 	if err := json.Unmarshal(review.Request.Object.Raw, &pod); err != nil {
 		klog.Errorf("Could not unmarshal raw object: %v", err)
 		return toAdmissionResponse(err)
 	}
 
-	payload := generatePatch(pod.Spec.Tolerations)
-	if len(payload) == 0 {
-		return &reviewResponse
+	// the namespace of the pod may be empty when the pod is created with generateName
+	namespace := pod.Namespace
+	if namespace == "" {
+		namespace = review.Request.Namespace
+	}
+	// the pod is not rejected because of the caches, the default policy is applied instead
+	ns, err := controller.NamespaceLister.Get(namespace)
+	if err != nil {
+		klog.Warningf("Failed to get namespace %s, apply the default offline migration policy to pod %s/%s: %v",
+			namespace, namespace, pod.Name, err)
+		return patchOfflineMigration(&pod, &defaultOfflineMigrationPolicy)
+	}
+	policies, err := controller.OfflineMigrationPolicyLister.List(labels.Everything())
+	if err != nil {
+		klog.Warningf("Failed to list OfflineMigrationPolicies, apply the default offline migration policy to pod %s/%s: %v",
+			namespace, pod.Name, err)
+		return patchOfflineMigration(&pod, &defaultOfflineMigrationPolicy)
 	}
 
+	policy, err := resolveOfflineMigrationPolicy(&pod, ns, policies)
+	if err != nil {
+		return toAdmissionResponse(err)
+	}
+	klog.V(4).Infof("Apply offline migration policy %+v to pod %s/%s", policy, namespace, pod.Name)
+	return patchOfflineMigration(&pod, policy)
+}
+
+func patchOfflineMigration(pod *corev1.Pod, policy *offlineMigrationPolicy) *admissionv1.AdmissionResponse {
+	payload, err := generatePatch(pod, policy)
+	if err != nil {
+		return toAdmissionResponse(err)
+	}
 	patch, err := json.Marshal(payload)
 	if err != nil {
 		return toAdmissionResponse(err)
 	}
 
-	reviewResponse.Patch = patch
 	pt := admissionv1.PatchTypeJSONPatch
-	reviewResponse.PatchType = &pt
-	return &reviewResponse
+	return &admissionv1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &pt,
+	}
 }
 
-func generatePatch(tolerations []corev1.Toleration) []patchMapValue {
-	currentTolerations := make([]interface{}, 0, len(tolerations)+1)
-	for _, v := range tolerations {
-		if v.Key == corev1.TaintNodeUnreachable {
+// resolveOfflineMigrationPolicy chooses the policy of the pod, in the order of the pod annotations,
+// the namespace annotations, the OfflineMigrationPolicy of the NodeGroup the pod is placed on,
+// the cluster default OfflineMigrationPolicy and defaultOfflineMigrationPolicy.
+func resolveOfflineMigrationPolicy(pod *corev1.Pod, ns *corev1.Namespace,
+	policies []*appsv1alpha1.OfflineMigrationPolicy) (*offlineMigrationPolicy, error) {
+	if policy, err := policyFromAnnotations(pod.Annotations, "pod"); policy != nil || err != nil {
+		return policy, err
+	}
+	if ns != nil {
+		if policy, err := policyFromAnnotations(ns.Annotations, "namespace/"+ns.Name); policy != nil || err != nil {
+			return policy, err
+		}
+	}
+
+	// policies are checked in the order of names to make the result stable if several policies match
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+	var clusterDefault *appsv1alpha1.OfflineMigrationPolicy
+	nodeGroupName := pod.Spec.NodeSelector[nodegroup.LabelBelongingTo]
+	for _, p := range policies {
+		if len(p.Spec.NodeGroups) == 0 {
+			if clusterDefault == nil {
+				clusterDefault = p
+			}
+			continue
+		}
+		if nodeGroupName == "" {
 			continue
 		}
-		currentTolerations = append(currentTolerations, v)
-	}
-	currentTolerations = append(currentTolerations, corev1.Toleration{
-		Key:      corev1.TaintNodeUnreachable,
-		Operator: corev1.TolerationOpExists,
-	})
-	patch := []patchMapValue{
-		{
+		for _, name := range p.Spec.NodeGroups {
+			if name == nodeGroupName {
+				return policyFromSpec(p)
+			}
+		}
+	}
+	if clusterDefault != nil {
+		return policyFromSpec(clusterDefault)
+	}
+
+	policy := defaultOfflineMigrationPolicy
+	return &policy, nil
+}
+
+// policyFromAnnotations returns nil if the policy is not specified by the annotations.
+func policyFromAnnotations(annotations map[string]string, source string) (*offlineMigrationPolicy, error) {
+	policyType, ok := annotations[appsv1alpha1.OfflineMigrationPolicyAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	policy := &offlineMigrationPolicy{
+		Type:   appsv1alpha1.OfflineMigrationPolicyType(policyType),
+		Source: source,
+	}
+	if policy.Type == appsv1alpha1.OfflineMigrationTolerationSeconds {
+		value := annotations[appsv1alpha1.OfflineMigrationTolerationSecondsAnnotation]
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation %s=%q of %s: %v",
+				appsv1alpha1.OfflineMigrationTolerationSecondsAnnotation, value, source, err)
+		}
+		policy.TolerationSeconds = &seconds
+	}
+	if err := validateOfflineMigrationPolicy(policy); err != nil {
+		return nil, fmt.Errorf("invalid offline migration policy of %s: %v", source, err)
+	}
+	return policy, nil
+}
+
+func policyFromSpec(p *appsv1alpha1.OfflineMigrationPolicy) (*offlineMigrationPolicy, error) {
+	policy := &offlineMigrationPolicy{
+		Type:              p.Spec.Type,
+		TolerationSeconds: p.Spec.TolerationSeconds,
+		Source:            "OfflineMigrationPolicy/" + p.Name,
+	}
+	if policy.Type != appsv1alpha1.OfflineMigrationTolerationSeconds {
+		policy.TolerationSeconds = nil
+	}
+	if err := validateOfflineMigrationPolicy(policy); err != nil {
+		return nil, fmt.Errorf("invalid OfflineMigrationPolicy %s: %v", p.Name, err)
+	}
+	return policy, nil
+}
+
+func validateOfflineMigrationPolicy(policy *offlineMigrationPolicy) error {
+	switch policy.Type {
+	case appsv1alpha1.OfflineMigrationForever, appsv1alpha1.OfflineMigrationNone:
+		return nil
+	case appsv1alpha1.OfflineMigrationTolerationSeconds:
+		if policy.TolerationSeconds == nil || *policy.TolerationSeconds < 0 {
+			return fmt.Errorf("tolerationSeconds must be a non-negative integer for policy %s", policy.Type)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported policy type %q, must be one of %s, %s and %s", policy.Type,
+		appsv1alpha1.OfflineMigrationForever, appsv1alpha1.OfflineMigrationTolerationSeconds, appsv1alpha1.OfflineMigrationNone)
+}
+
+// generatePatch sets the toleration of the unreachable taint by the policy, and reports the policy
+// in the annotation of the pod.
+func generatePatch(pod *corev1.Pod, policy *offlineMigrationPolicy) ([]patchMapValue, error) {
+	patch := []patchMapValue{}
+
+	if policy.Type != appsv1alpha1.OfflineMigrationNone {
+		tolerations := make([]interface{}, 0, len(pod.Spec.Tolerations)+1)
+		for _, v := range pod.Spec.Tolerations {
+			if v.Key == corev1.TaintNodeUnreachable {
+				continue
+			}
+			tolerations = append(tolerations, v)
+		}
+		toleration := corev1.Toleration{
+			Key:      corev1.TaintNodeUnreachable,
+			Operator: corev1.TolerationOpExists,
+		}
+		if policy.Type == appsv1alpha1.OfflineMigrationTolerationSeconds {
+			// tolerationSeconds only takes effect on NoExecute taints
+			toleration.Effect = corev1.TaintEffectNoExecute
+			toleration.TolerationSeconds = policy.TolerationSeconds
+		}
+		tolerations = append(tolerations, toleration)
+		patch = append(patch, patchMapValue{
 			Op:    "replace",
 			Path:  "/spec/tolerations",
-			Value: currentTolerations,
-		},
+			Value: tolerations,
+		})
+	}
+
+	applied, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
 	}
-	return patch
+	if pod.Annotations == nil {
+		patch = append(patch, patchMapValue{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: map[string]string{appsv1alpha1.OfflineMigrationAppliedAnnotation: string(applied)},
+		})
+	} else {
+		patch = append(patch, patchMapValue{
+			Op:    "add",
+			Path:  "/metadata/annotations/" + escapeJSONPointer(appsv1alpha1.OfflineMigrationAppliedAnnotation),
+			Value: string(applied),
+		})
+	}
+	return patch, nil
+}
+
+// escapeJSONPointer escapes the reference token of JSON pointer, see RFC 6901
+func escapeJSONPointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
 }
 
 func serveOfflineMigration(w http.ResponseWriter, r *http.Request) {
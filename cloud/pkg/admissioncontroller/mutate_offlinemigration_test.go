@@ -0,0 +1,187 @@
+package admissioncontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/nodegroup"
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+	appslisters "github.com/kubeedge/kubeedge/pkg/client/listers/apps/v1alpha1"
+)
+
+func TestResolveOfflineMigrationPolicy(t *testing.T) {
+	seconds := int64(300)
+	policies := []*appsv1alpha1.OfflineMigrationPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "hangzhou"},
+			Spec: appsv1alpha1.OfflineMigrationPolicySpec{
+				NodeGroups:        []string{"hangzhou"},
+				Type:              appsv1alpha1.OfflineMigrationTolerationSeconds,
+				TolerationSeconds: &seconds,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       appsv1alpha1.OfflineMigrationPolicySpec{Type: appsv1alpha1.OfflineMigrationNone},
+		},
+	}
+	onNodeGroup := func(name string) corev1.PodSpec {
+		return corev1.PodSpec{NodeSelector: map[string]string{nodegroup.LabelBelongingTo: name}}
+	}
+
+	cases := map[string]struct {
+		pod        corev1.Pod
+		ns         *corev1.Namespace
+		policies   []*appsv1alpha1.OfflineMigrationPolicy
+		wantType   appsv1alpha1.OfflineMigrationPolicyType
+		wantSource string
+		wantErr    bool
+	}{
+		"built-in default": {
+			wantType:   appsv1alpha1.OfflineMigrationForever,
+			wantSource: "default",
+		},
+		"pod annotations": {
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					appsv1alpha1.OfflineMigrationPolicyAnnotation:            "TolerationSeconds",
+					appsv1alpha1.OfflineMigrationTolerationSecondsAnnotation: "60",
+				}},
+				Spec: onNodeGroup("hangzhou"),
+			},
+			ns: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Annotations: map[string]string{
+				appsv1alpha1.OfflineMigrationPolicyAnnotation: "None",
+			}}},
+			policies:   policies,
+			wantType:   appsv1alpha1.OfflineMigrationTolerationSeconds,
+			wantSource: "pod",
+		},
+		"namespace annotations": {
+			pod: corev1.Pod{Spec: onNodeGroup("hangzhou")},
+			ns: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Annotations: map[string]string{
+				appsv1alpha1.OfflineMigrationPolicyAnnotation: "Forever",
+			}}},
+			policies:   policies,
+			wantType:   appsv1alpha1.OfflineMigrationForever,
+			wantSource: "namespace/ns",
+		},
+		"nodegroup policy": {
+			pod:        corev1.Pod{Spec: onNodeGroup("hangzhou")},
+			ns:         &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}},
+			policies:   policies,
+			wantType:   appsv1alpha1.OfflineMigrationTolerationSeconds,
+			wantSource: "OfflineMigrationPolicy/hangzhou",
+		},
+		"cluster default policy": {
+			pod:        corev1.Pod{Spec: onNodeGroup("beijing")},
+			policies:   policies,
+			wantType:   appsv1alpha1.OfflineMigrationNone,
+			wantSource: "OfflineMigrationPolicy/default",
+		},
+		"invalid policy type": {
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				appsv1alpha1.OfflineMigrationPolicyAnnotation: "Sometimes",
+			}}},
+			wantErr: true,
+		},
+		"missing toleration seconds": {
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				appsv1alpha1.OfflineMigrationPolicyAnnotation: "TolerationSeconds",
+			}}},
+			wantErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			policy, err := resolveOfflineMigrationPolicy(&c.pod, c.ns, c.policies)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("unexpected error, got: %v, wantErr: %v", err, c.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if policy.Type != c.wantType || policy.Source != c.wantSource {
+				t.Errorf("expected policy %s from %s, but got %s from %s", c.wantType, c.wantSource, policy.Type, policy.Source)
+			}
+		})
+	}
+}
+
+func TestGeneratePatch(t *testing.T) {
+	seconds := int64(300)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}},
+		Spec: corev1.PodSpec{Tolerations: []corev1.Toleration{
+			{Key: corev1.TaintNodeUnreachable, Operator: corev1.TolerationOpExists},
+			{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "edge"},
+		}},
+	}
+
+	patch, err := generatePatch(pod, &offlineMigrationPolicy{
+		Type:              appsv1alpha1.OfflineMigrationTolerationSeconds,
+		TolerationSeconds: &seconds,
+		Source:            "pod",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patch) != 2 {
+		t.Fatalf("expected 2 patches, but got %v", patch)
+	}
+	tolerations := patch[0].Value.([]interface{})
+	if len(tolerations) != 2 {
+		t.Fatalf("expected 2 tolerations, but got %v", tolerations)
+	}
+	unreachable := tolerations[1].(corev1.Toleration)
+	if unreachable.Effect != corev1.TaintEffectNoExecute || unreachable.TolerationSeconds == nil || *unreachable.TolerationSeconds != seconds {
+		t.Errorf("unexpected unreachable toleration: %v", unreachable)
+	}
+	if patch[1].Path != "/metadata/annotations/apps.kubeedge.io~1offline-migration-applied" {
+		t.Errorf("unexpected annotation patch path: %s", patch[1].Path)
+	}
+	applied := offlineMigrationPolicy{}
+	if err = json.Unmarshal([]byte(patch[1].Value.(string)), &applied); err != nil || applied.Source != "pod" {
+		t.Errorf("unexpected applied annotation: %v, %v", patch[1].Value, err)
+	}
+
+	patch, err = generatePatch(&corev1.Pod{}, &offlineMigrationPolicy{Type: appsv1alpha1.OfflineMigrationNone, Source: "pod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patch) != 1 || patch[0].Path != "/metadata/annotations" {
+		t.Errorf("expected only the annotation patch for policy None, but got %v", patch)
+	}
+}
+
+func TestMutateOfflineMigrationWithoutNamespace(t *testing.T) {
+	controller.NamespaceLister = corelisters.NewNamespaceLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))
+	controller.OfflineMigrationPolicyLister = appslisters.NewOfflineMigrationPolicyLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))
+
+	raw, err := json.Marshal(corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "not-synced"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := mutateOfflineMigration(admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+		Object: runtime.RawExtension{Raw: raw},
+	}})
+	if !resp.Allowed {
+		t.Fatalf("expected the pod is allowed, but got %v", resp.Result)
+	}
+	var patch []patchMapValue
+	if err := json.Unmarshal(resp.Patch, &patch); err != nil {
+		t.Fatal(err)
+	}
+	if len(patch) != 2 || !strings.Contains(fmt.Sprint(patch[1].Value), `"source":"default"`) {
+		t.Errorf("expected the default policy is applied, but got %v", patch)
+	}
+}
@@ -23,12 +23,16 @@ import (
 
 var DoneTLSTunnelCerts = make(chan bool, 1)
 
+// CertsPrepared is closed when the certificates of cloudhub are prepared
+var CertsPrepared = make(chan struct{})
+
 type cloudHub struct {
 	enable               bool
 	informersSyncedFuncs []cache.InformerSynced
 
 	messageHandler handler.Handler
 	dispatcher     dispatcher.MessageDispatcher
+	sessionManager *session.Manager
 }
 
 var _ core.Module = (*cloudHub)(nil)
@@ -53,6 +57,7 @@ func newCloudHub(enable bool) *cloudHub {
 		enable:         enable,
 		dispatcher:     messageDispatcher,
 		messageHandler: messageHandler,
+		sessionManager: sessionManager,
 	}
 
 	ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, clusterObjectSyncInformer.Informer().HasSynced)
@@ -96,6 +101,7 @@ func (ch *cloudHub) Start() {
 	// TODO: Will improve in the future
 	DoneTLSTunnelCerts <- true
 	close(DoneTLSTunnelCerts)
+	close(CertsPrepared)
 
 	// generate Token
 	if err := httpserver.GenerateToken(); err != nil {
@@ -103,7 +109,7 @@ func (ch *cloudHub) Start() {
 	}
 
 	// HttpServer mainly used to issue certificates for the edge
-	go httpserver.StartHTTPServer()
+	go httpserver.StartHTTPServer(ch.sessionManager.NodeCertificate)
 
 	servers.StartCloudHub(ch.messageHandler)
 
@@ -31,8 +31,10 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/audit"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
 	commonconst "github.com/kubeedge/kubeedge/common/constants"
 	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
@@ -41,8 +43,12 @@ import (
 	synclisters "github.com/kubeedge/kubeedge/pkg/client/listers/reliablesyncs/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/metaserver"
 	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
+	"github.com/kubeedge/kubeedge/pkg/tracing"
 )
 
+// dispatcherSpanName is the name of the spans of the messages dispatched by cloudhub
+const dispatcherSpanName = "cloudhub/dispatcher"
+
 // There are two `AcknowledgeMode` for message that send to edge node
 // ------------------------------------------------------------------
 // ACK mode: In this mode, the edge node MUST send acknowledgement to
@@ -112,12 +118,14 @@ func NewMessageDispatcher(
 	objectSyncLister synclisters.ObjectSyncLister,
 	clusterObjectSyncLister synclisters.ClusterObjectSyncLister,
 	reliableClient reliableclient.Interface) MessageDispatcher {
-	return &messageDispatcher{
+	md := &messageDispatcher{
 		objectSyncLister:        objectSyncLister,
 		clusterObjectSyncLister: clusterObjectSyncLister,
 		reliableClient:          reliableClient,
 		SessionManager:          sessionManager,
 	}
+	monitor.SetMessagePoolBacklogFunc(md.messagePoolBacklog)
+	return md
 }
 
 func (md *messageDispatcher) DispatchDownstream() {
@@ -147,17 +155,25 @@ func (md *messageDispatcher) DispatchDownstream() {
 				continue
 			}
 
+			span := tracing.StartMessageSpan(dispatcherSpanName, &msg)
 			switch {
 			case noAckRequired(&msg):
 				md.enqueueNoAckMessage(nodeID, &msg)
 			default:
 				md.enqueueAckMessage(nodeID, &msg)
 			}
+			span.End()
 		}
 	}
 }
 
 func (md *messageDispatcher) DispatchUpstream(message *beehivemodel.Message, info *model.HubInfo) {
+	if message.GetOperation() != model.OpKeepalive && message.GetOperation() != beehivemodel.ResponseOperation {
+		monitor.CloudHubMessages.WithLabelValues(monitor.NodeLabel(info.NodeID), monitor.DirectionUpstream).Inc()
+		span := tracing.StartMessageSpan(dispatcherSpanName, message)
+		defer span.End()
+	}
+
 	switch {
 	case message.GetOperation() == model.OpKeepalive:
 		klog.V(4).Infof("Keepalive message received from node: %s", info.NodeID)
@@ -183,6 +199,18 @@ func (md *messageDispatcher) DispatchUpstream(message *beehivemodel.Message, inf
 		message.Router.Resource = fmt.Sprintf("node/%s/%s", info.NodeID, message.Router.Resource)
 		beehivecontext.Send(modules.RouterModuleName, *message)
 
+	case message.GetGroup() == commonconst.AuditGroup:
+		audit.ProcessEdgeEvents(info.NodeID, message)
+
+	case message.GetGroup() == modules.NodeUpgradeJobControllerModuleGroup:
+		// the resource is like upgrade/${UpgradeID}/node/${NodeID} or nodejob/${JobID}/node/${NodeID},
+		// edge node can only report its own result
+		if !strings.HasSuffix(message.GetResource(), "/node/"+info.NodeID) {
+			klog.Errorf("node %s reports NodeUpgradeJob or NodeJob message with invalid resource %s", info.NodeID, message.GetResource())
+			return
+		}
+		beehivecontext.Send(modules.NodeUpgradeJobControllerModuleName, *message)
+
 	default:
 		err := md.PubToController(info, message)
 		if err != nil {
@@ -480,7 +508,14 @@ func isVolumeOperation(op string) bool {
 	return op == commonconst.CSIOperationTypeCreateVolume ||
 		op == commonconst.CSIOperationTypeDeleteVolume ||
 		op == commonconst.CSIOperationTypeControllerPublishVolume ||
-		op == commonconst.CSIOperationTypeControllerUnpublishVolume
+		op == commonconst.CSIOperationTypeControllerUnpublishVolume ||
+		op == commonconst.CSIOperationTypeControllerExpandVolume ||
+		op == commonconst.CSIOperationTypeCreateSnapshot ||
+		op == commonconst.CSIOperationTypeDeleteSnapshot ||
+		op == commonconst.CSIOperationTypeListSnapshots ||
+		op == commonconst.CSIOperationTypeGetCapacity ||
+		op == commonconst.CSIOperationTypeListVolumes ||
+		op == commonconst.CSIOperationTypeControllerGetCapabilities
 }
 
 // GetNodeMessagePool returns the message pool for given node
@@ -496,6 +531,16 @@ func (md *messageDispatcher) GetNodeMessagePool(nodeID string) *common.NodeMessa
 	return nsp.(*common.NodeMessagePool)
 }
 
+// messagePoolBacklog visits the number of messages waiting in the message pool of each node
+func (md *messageDispatcher) messagePoolBacklog(visit func(nodeID, messageType string, backlog int)) {
+	md.NodeMessagePools.Range(func(key, value interface{}) bool {
+		nodeID, pool := key.(string), value.(*common.NodeMessagePool)
+		visit(nodeID, monitor.MessageTypeAck, pool.AckMessageQueue.Len())
+		visit(nodeID, monitor.MessageTypeNoAck, pool.NoAckMessageQueue.Len())
+		return true
+	})
+}
+
 func (md *messageDispatcher) AddNodeMessagePool(nodeID string, pool *common.NodeMessagePool) {
 	md.NodeMessagePools.Store(nodeID, pool)
 }
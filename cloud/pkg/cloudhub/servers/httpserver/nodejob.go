@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/emicklei/go-restful"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// uploadNodeJobArtifact stores the artifact collected by NodeJob on the edge node, such as the log bundle,
+// and returns where it is stored in the format of <cloudcore host>:<file path>
+func uploadNodeJobArtifact(request *restful.Request, response *restful.Response) {
+	nodeName, status, err := verifyEdgeNode(request.Request)
+	if err != nil {
+		klog.Errorf("failed to upload NodeJob artifact for edgenode: %s, %v", request.Request.Header.Get(constants.NodeName), err)
+		writeNodeJobError(response, status, err)
+		return
+	}
+
+	jobName := request.QueryParameter("job")
+	job, err := client.GetCRDClient().OperationsV1alpha1().NodeJobs().Get(context.TODO(), jobName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			writeNodeJobError(response, http.StatusNotFound, fmt.Errorf("NodeJob %q not found", jobName))
+			return
+		}
+		klog.Errorf("failed to get NodeJob %s: %v", jobName, err)
+		writeNodeJobError(response, http.StatusInternalServerError, err)
+		return
+	}
+	if err = checkNodeJobRunning(job, nodeName); err != nil {
+		writeNodeJobError(response, http.StatusForbidden, err)
+		return
+	}
+
+	path, err := nodeJobArtifactPath(constants.DefaultNodeJobArtifactDir, jobName, nodeName, request.QueryParameter("file"))
+	if err != nil {
+		writeNodeJobError(response, http.StatusBadRequest, err)
+		return
+	}
+	if err = saveNodeJobArtifact(path, request.Request.Body, constants.DefaultNodeJobArtifactLimit); err != nil {
+		klog.Errorf("failed to save NodeJob artifact of edgenode %s: %v", nodeName, err)
+		writeNodeJobError(response, http.StatusInternalServerError, err)
+		return
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		klog.Warningf("failed to get hostname: %v", err)
+	}
+	if _, err = response.Write([]byte(fmt.Sprintf("%s:%s", host, path))); err != nil {
+		klog.Errorf("failed to write NodeJob artifact response, err: %v", err)
+	}
+}
+
+// checkNodeJobRunning checks that the NodeJob is running on the node, only then the node may upload its artifact
+func checkNodeJobRunning(job *v1alpha1.NodeJob, nodeName string) error {
+	for _, status := range job.Status.Status {
+		if status.NodeName != nodeName {
+			continue
+		}
+		if status.State != v1alpha1.NodeJobRunning {
+			return fmt.Errorf("NodeJob %s is not running on node %s", job.Name, nodeName)
+		}
+		return nil
+	}
+	return fmt.Errorf("NodeJob %s does not target node %s", job.Name, nodeName)
+}
+
+// nodeJobArtifactPath returns the path to store the artifact, which is like <dir>/<job>/<node>-<file>
+func nodeJobArtifactPath(dir, job, node, file string) (string, error) {
+	if errs := validation.IsDNS1123Subdomain(job); len(errs) != 0 {
+		return "", fmt.Errorf("invalid NodeJob name %q: %v", job, errs)
+	}
+	if errs := validation.IsDNS1123Subdomain(node); len(errs) != 0 {
+		return "", fmt.Errorf("invalid node name %q: %v", node, errs)
+	}
+	if file == "" || file == "." || file == ".." || filepath.Base(file) != file {
+		return "", fmt.Errorf("invalid file name %q", file)
+	}
+	return filepath.Join(dir, job, fmt.Sprintf("%s-%s", node, file)), nil
+}
+
+// saveNodeJobArtifact writes the artifact to path, the file is removed if it is larger than limit
+func saveNodeJobArtifact(path string, body io.Reader, limit int64) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, io.LimitReader(body, limit+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n > limit {
+		err = fmt.Errorf("artifact is larger than the limit %d bytes", limit)
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+func writeNodeJobError(response *restful.Response, status int, err error) {
+	response.WriteHeader(status)
+	if _, err := response.Write([]byte(err.Error())); err != nil {
+		klog.Errorf("failed to write response, err: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func TestNodeJobArtifactPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     string
+		node    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", job: "collect", node: "edge-1", file: "edge_2023.tar.gz", want: "/data/collect/edge-1-edge_2023.tar.gz"},
+		{name: "job with path", job: "../collect", node: "edge-1", file: "a.tar.gz", wantErr: true},
+		{name: "empty node", job: "collect", node: "", file: "a.tar.gz", wantErr: true},
+		{name: "file with path", job: "collect", node: "edge-1", file: "../../etc/passwd", wantErr: true},
+		{name: "empty file", job: "collect", node: "edge-1", file: "", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := nodeJobArtifactPath("/data", test.job, test.node, test.file)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v, wantErr: %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("Got = %v, Want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSaveNodeJobArtifact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "job", "edge-1-a.tar.gz")
+
+	if err := saveNodeJobArtifact(path, strings.NewReader("logs"), 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "logs" {
+		t.Errorf("unexpected artifact content: %q, %v", data, err)
+	}
+
+	if err := saveNodeJobArtifact(path, strings.NewReader("too many logs"), 4); err == nil {
+		t.Errorf("expect error when the artifact exceeds the limit")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expect the artifact exceeding the limit to be removed, got: %v", err)
+	}
+}
+
+func TestCheckNodeJobRunning(t *testing.T) {
+	job := &v1alpha1.NodeJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "collect"},
+		Status: v1alpha1.NodeJobStatus{
+			State: v1alpha1.NodeJobRunning,
+			Status: []v1alpha1.NodeJobNodeStatus{
+				{NodeName: "edge-1", State: v1alpha1.NodeJobRunning},
+				{NodeName: "edge-2", State: v1alpha1.NodeJobCompleted, Result: v1alpha1.NodeJobSuccess},
+			},
+		},
+	}
+	tests := []struct {
+		node    string
+		wantErr bool
+	}{
+		{node: "edge-1"},
+		{node: "edge-2", wantErr: true},
+		{node: "edge-3", wantErr: true},
+	}
+	for _, test := range tests {
+		if err := checkNodeJobRunning(job, test.node); (err != nil) != test.wantErr {
+			t.Errorf("node %s: unexpected error: %v, wantErr: %v", test.node, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+// offlineTokenIssuerUsages is the usage of the delegation certificate, it is not usable
+// as a TLS client or server certificate.
+var offlineTokenIssuerUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}
+
+// signOfflineTokenIssuerCert signs the delegation certificate of the key that the edge node
+// uses to mint ServiceAccount tokens when it is disconnected from the cloud
+func signOfflineTokenIssuerCert(request *restful.Request, response *restful.Response) {
+	// the delegation certificate is only issued to the node of the client certificate
+	nodeName, status, err := verifyEdgeNode(request.Request)
+	if err != nil {
+		klog.Errorf("failed to sign the offline token issuer certificate for edgenode: %s, %v", request.Request.Header.Get(constants.NodeName), err)
+		writeNodeJobError(response, status, err)
+		return
+	}
+
+	request.Request.Body = http.MaxBytesReader(response, request.Request.Body, constants.MaxRespBodyLength)
+	csrContent, err := io.ReadAll(request.Request.Body)
+	if err != nil {
+		writeNodeJobError(response, http.StatusBadRequest, err)
+		return
+	}
+	csr, err := parseOfflineTokenIssuerCSR(nodeName, csrContent)
+	if err != nil {
+		klog.Errorf("invalid offline token issuer CSR of edgenode %s: %v", nodeName, err)
+		writeNodeJobError(response, http.StatusBadRequest, err)
+		return
+	}
+
+	certDER, err := signCerts(pkix.Name{CommonName: constants.OfflineTokenIssuerPrefix + nodeName}, csr.PublicKey, offlineTokenIssuerUsages)
+	if err != nil {
+		klog.Errorf("failed to sign the offline token issuer certificate for edgenode %s: %v", nodeName, err)
+		writeNodeJobError(response, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err = response.Write(certDER); err != nil {
+		klog.Errorf("failed to write offline token issuer certificate, err: %v", err)
+	}
+}
+
+// parseOfflineTokenIssuerCSR parses and checks the CSR of the offline token issuer key.
+// The subject of the CSR is ignored, the certificate is always issued to the node name.
+func parseOfflineTokenIssuerCSR(nodeName string, csrDER []byte) (*x509.CertificateRequest, error) {
+	if errs := validation.IsDNS1123Subdomain(nodeName); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid node name %q: %v", nodeName, errs)
+	}
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSR: %v", err)
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid CSR signature: %v", err)
+	}
+	if _, ok := csr.PublicKey.(*ecdsa.PublicKey); !ok {
+		return nil, fmt.Errorf("unsupported public key type %T, only ECDSA keys are supported", csr.PublicKey)
+	}
+	return csr, nil
+}
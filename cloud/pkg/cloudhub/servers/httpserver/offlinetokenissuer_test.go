@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+func newTestCSR(t *testing.T, key crypto.Signer) []byte {
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "edge-1"},
+	}, key)
+	if err != nil {
+		t.Fatalf("failed to create CSR: %v", err)
+	}
+	return csr
+}
+
+func TestParseOfflineTokenIssuerCSR(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecCSR := newTestCSR(t, ecKey)
+	tampered := append([]byte{}, ecCSR...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		node    string
+		csr     []byte
+		wantErr bool
+	}{
+		{name: "valid", node: "edge-1", csr: ecCSR},
+		{name: "invalid node name", node: "Edge_1", csr: ecCSR, wantErr: true},
+		{name: "empty node name", node: "", csr: ecCSR, wantErr: true},
+		{name: "invalid CSR", node: "edge-1", csr: []byte("csr"), wantErr: true},
+		{name: "invalid signature", node: "edge-1", csr: tampered, wantErr: true},
+		{name: "unsupported key", node: "edge-1", csr: newTestCSR(t, edKey), wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseOfflineTokenIssuerCSR(test.node, test.csr)
+			if (err != nil) != test.wantErr {
+				t.Errorf("unexpected error: %v, wantErr: %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyEdgeNode(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "KubeEdge"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldCa := hubconfig.Config.Ca
+	hubconfig.Config.Ca = caDER
+	defer func() { hubconfig.Config.Ca = oldCa }()
+
+	nodeKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "edge-1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caTemplate, &nodeKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeCert, err := x509.ParseCertificate(nodeDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// certificates of upgraded nodes are issued to the legacy common name
+	legacyDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "kubeedge.io"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caTemplate, &nodeKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyCert, err := x509.ParseCertificate(legacyDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldNodeCertificate := nodeCertificate
+	nodeCertificate = func(nodeName string) (*x509.Certificate, bool) {
+		return legacyCert, nodeName == "edge-1"
+	}
+	defer func() { nodeCertificate = oldNodeCertificate }()
+
+	tests := []struct {
+		name       string
+		header     string
+		certs      []*x509.Certificate
+		wantStatus int
+	}{
+		{name: "valid", header: "edge-1", certs: []*x509.Certificate{nodeCert}, wantStatus: http.StatusOK},
+		{name: "other node", header: "edge-2", certs: []*x509.Certificate{nodeCert}, wantStatus: http.StatusForbidden},
+		{name: "no certificate", header: "edge-1", wantStatus: http.StatusUnauthorized},
+		{name: "legacy certificate of the node", header: "edge-1", certs: []*x509.Certificate{legacyCert}, wantStatus: http.StatusOK},
+		{name: "legacy certificate of other node", header: "edge-2", certs: []*x509.Certificate{legacyCert}, wantStatus: http.StatusForbidden},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, constants.DefaultOfflineTokenIssuerURL, nil)
+			r.Header.Set(constants.NodeName, test.header)
+			r.TLS = &tls.ConnectionState{PeerCertificates: test.certs}
+			node, status, err := verifyEdgeNode(r)
+			if status != test.wantStatus {
+				t.Fatalf("Got status %d, Want %d, err: %v", status, test.wantStatus, err)
+			}
+			if status == http.StatusOK && node != "edge-1" {
+				t.Errorf("Got node %q, Want edge-1", node)
+			}
+		})
+	}
+}
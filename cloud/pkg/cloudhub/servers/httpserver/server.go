@@ -17,6 +17,7 @@ limitations under the License.
 package httpserver
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/tls"
@@ -31,6 +32,7 @@ import (
 
 	"github.com/emicklei/go-restful"
 	"github.com/golang-jwt/jwt"
+	"k8s.io/apimachinery/pkg/util/validation"
 	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
@@ -38,14 +40,22 @@ import (
 	"github.com/kubeedge/kubeedge/common/constants"
 )
 
-// StartHTTPServer starts the http service
-func StartHTTPServer() {
+// nodeCertificate returns the client certificate that the node connects to cloudhub with
+var nodeCertificate func(nodeName string) (*x509.Certificate, bool)
+
+// StartHTTPServer starts the http service, nodeCert looks up the certificate of the
+// cloudhub connection of a node
+func StartHTTPServer(nodeCert func(nodeName string) (*x509.Certificate, bool)) {
+	nodeCertificate = nodeCert
+
 	serverContainer := restful.NewContainer()
 	ws := new(restful.WebService)
 	ws.Path("/")
 	ws.Route(ws.GET(constants.DefaultCertURL).To(edgeCoreClientCert))
 	ws.Route(ws.GET(constants.DefaultCAURL).To(getCA))
 	ws.Route(ws.POST(constants.DefaultNodeUpgradeURL).To(upgradeEdge))
+	ws.Route(ws.POST(constants.DefaultNodeJobArtifactURL).To(uploadNodeJobArtifact))
+	ws.Route(ws.POST(constants.DefaultOfflineTokenIssuerURL).To(signOfflineTokenIssuerCert))
 	serverContainer.Add(ws)
 
 	addr := fmt.Sprintf("%s:%d", hubconfig.Config.HTTPS.Address, hubconfig.Config.HTTPS.Port)
@@ -86,22 +96,37 @@ func EncodeCertPEM(cert *x509.Certificate) []byte {
 
 // edgeCoreClientCert will verify the certificate of EdgeCore or token then create EdgeCoreCert and return it
 func edgeCoreClientCert(request *restful.Request, response *restful.Response) {
+	nodeName := request.Request.Header.Get(constants.NodeName)
 	if cert := request.Request.TLS.PeerCertificates; len(cert) > 0 {
 		if err := verifyCert(cert[0]); err != nil {
-			klog.Errorf("failed to sign the certificate for edgenode: %s, failed to verify the certificate", request.Request.Header.Get(constants.NodeName))
+			klog.Errorf("failed to sign the certificate for edgenode: %s, failed to verify the certificate", nodeName)
 			response.WriteHeader(http.StatusUnauthorized)
 			if _, err := response.Write([]byte(err.Error())); err != nil {
 				klog.Errorf("failed to write response, err: %v", err)
 			}
 		} else {
-			signEdgeCert(response, request.Request)
+			// the rotated certificate is issued to the node, legacy certificates that can't be
+			// tied to the node keep their common name
+			commonName := cert[0].Subject.CommonName
+			if node, _, err := verifyEdgeNode(request.Request); err == nil {
+				commonName = node
+			}
+			signEdgeCert(response, request.Request, commonName)
 		}
 		return
 	}
 	if verifyAuthorization(response, request.Request) {
-		signEdgeCert(response, request.Request)
+		if errs := validation.IsDNS1123Subdomain(nodeName); len(errs) != 0 {
+			klog.Errorf("failed to sign the certificate for edgenode: %s, invalid node name: %v", nodeName, errs)
+			response.WriteHeader(http.StatusBadRequest)
+			if _, err := response.Write([]byte(fmt.Sprintf("invalid node name %q", nodeName))); err != nil {
+				klog.Errorf("failed to write response, err: %v", err)
+			}
+			return
+		}
+		signEdgeCert(response, request.Request, nodeName)
 	} else {
-		klog.Errorf("failed to sign the certificate for edgenode: %s, invalid token", request.Request.Header.Get(constants.NodeName))
+		klog.Errorf("failed to sign the certificate for edgenode: %s, invalid token", nodeName)
 	}
 }
 
@@ -122,6 +147,36 @@ func verifyCert(cert *x509.Certificate) error {
 	return nil
 }
 
+// verifyEdgeNode verifies the edge certificate of the request and returns the node it is issued to,
+// with the http status code if it fails. The node must be the one in the NodeName header,
+// so that an edge node can't act on behalf of other nodes. A legacy certificate issued to another
+// common name is accepted only if the node connects to cloudhub with it.
+func verifyEdgeNode(r *http.Request) (string, int, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return "", http.StatusUnauthorized, fmt.Errorf("edge certificate is required")
+	}
+	cert := r.TLS.PeerCertificates[0]
+	if err := verifyCert(cert); err != nil {
+		return "", http.StatusUnauthorized, err
+	}
+	nodeName := r.Header.Get(constants.NodeName)
+	if cert.Subject.CommonName != nodeName && !isNodeCertificate(nodeName, cert) {
+		return "", http.StatusForbidden, fmt.Errorf("edge certificate is issued to %q, not node %q", cert.Subject.CommonName, nodeName)
+	}
+	return nodeName, http.StatusOK, nil
+}
+
+// isNodeCertificate returns whether cert is the one the node connects to cloudhub with.
+// Certificates issued before the node name was used as the common name (e.g. "kubeedge.io")
+// are tied to the node this way.
+func isNodeCertificate(nodeName string, cert *x509.Certificate) bool {
+	if nodeCertificate == nil || nodeName == "" {
+		return false
+	}
+	registered, ok := nodeCertificate(nodeName)
+	return ok && bytes.Equal(registered.Raw, cert.Raw)
+}
+
 // verifyAuthorization verifies the token from EdgeCore CSR
 func verifyAuthorization(w http.ResponseWriter, r *http.Request) bool {
 	authorizationHeader := r.Header.Get("authorization")
@@ -172,8 +227,9 @@ func verifyAuthorization(w http.ResponseWriter, r *http.Request) bool {
 	return true
 }
 
-// signEdgeCert signs the CSR from EdgeCore
-func signEdgeCert(w http.ResponseWriter, r *http.Request) {
+// signEdgeCert signs the CSR from EdgeCore, the certificate is issued to the node commonName
+// whatever the subject of the CSR is
+func signEdgeCert(w http.ResponseWriter, r *http.Request, commonName string) {
 	r.Body = http.MaxBytesReader(w, r.Body, constants.MaxRespBodyLength)
 	csrContent, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -197,7 +253,9 @@ func signEdgeCert(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	klog.V(4).Infof("receive sign crt request, ExtKeyUsages: %v", usages)
-	clientCertDER, err := signCerts(csr.Subject, csr.PublicKey, usages)
+	subject := csr.Subject
+	subject.CommonName = commonName
+	clientCertDER, err := signCerts(subject, csr.PublicKey, usages)
 	if err != nil {
 		klog.Errorf("fail to signCerts for edgenode:%s! error:%v", r.Header.Get(constants.NodeName), err)
 		return
@@ -32,6 +32,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
 	edgeconst "github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
@@ -39,11 +40,15 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/apis/reliablesyncs/v1alpha1"
 	reliableclient "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
 	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
+	"github.com/kubeedge/kubeedge/pkg/tracing"
 	"github.com/kubeedge/viaduct/pkg/conn"
 )
 
 var sendRetryInterval = 5 * time.Second
 
+// sessionSpanName is the name of the spans of the messages sent to edge by the node session
+const sessionSpanName = "cloudhub/session"
+
 // session termination error type
 const (
 	NoErr = iota
@@ -290,10 +295,14 @@ func (ns *NodeSession) syncNoAckMessage() (bool, error) {
 
 	common.TrimMessage(msg)
 
-	if err := ns.connection.WriteMessageAsync(msg); err != nil {
+	span := tracing.StartMessageSpan(sessionSpanName, msg)
+	err = ns.connection.WriteMessageAsync(msg)
+	tracing.EndSpan(span, err)
+	if err != nil {
 		ns.SetTerminateErr(TransportErr)
 		return true, fmt.Errorf("send message to edge node %s err: %v", ns.nodeID, err)
 	}
+	monitor.CloudHubMessages.WithLabelValues(monitor.NodeLabel(ns.nodeID), monitor.DirectionDownstream).Inc()
 
 	return false, nil
 }
@@ -316,7 +325,10 @@ func (ns *NodeSession) syncAckMessage() (bool, error) {
 	copyMsg := common.DeepCopy(msg)
 	common.TrimMessage(copyMsg)
 
+	// the span covers the retries and the wait for the ack of edge
+	span := tracing.StartMessageSpan(sessionSpanName, copyMsg)
 	err = ns.sendMessageWithRetry(copyMsg, msg)
+	tracing.EndSpan(span, err)
 	switch {
 	case err == nil:
 		// no err, forget this key and return
@@ -324,6 +336,7 @@ func (ns *NodeSession) syncAckMessage() (bool, error) {
 		return false, nil
 
 	case err == ErrWaitTimeout:
+		monitor.MessageAckTimeouts.WithLabelValues(monitor.NodeLabel(ns.nodeID)).Inc()
 		// if err is timeout err, we will add the message to queue again
 		ns.nodeMessagePool.AckMessageQueue.AddRateLimited(key)
 		return false, fmt.Errorf("send message to node %s err: %v, message: %s", ns.nodeID, err, msg.String())
@@ -343,14 +356,17 @@ func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message)
 	retryCount := 0
 	ticker := time.NewTimer(sendRetryInterval)
 
+	sentAt := time.Now()
 	err := ns.connection.WriteMessageAsync(copyMsg)
 	if err != nil {
 		return err
 	}
+	monitor.CloudHubMessages.WithLabelValues(monitor.NodeLabel(ns.nodeID), monitor.DirectionDownstream).Inc()
 
 	for {
 		select {
 		case <-ackChan:
+			monitor.ObserveSince(monitor.MessageAckDuration.WithLabelValues(monitor.NodeLabel(ns.nodeID)), sentAt)
 			ns.saveSuccessPoint(msg)
 			return nil
 
@@ -17,6 +17,7 @@ limitations under the License.
 package session
 
 import (
+	"crypto/x509"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -78,6 +79,7 @@ func (sm *Manager) DeleteSession(session *NodeSession) {
 
 	sm.NodeSessions.Delete(session.nodeID)
 	monitor.ConnectedNodes.Set(float64(atomic.AddInt32(&sm.NodeNumber, -1)))
+	monitor.DeleteNodeMetrics(session.nodeID)
 }
 
 // GetSession get the node session for the node
@@ -90,6 +92,20 @@ func (sm *Manager) GetSession(nodeID string) (*NodeSession, bool) {
 	return nil, false
 }
 
+// NodeCertificate returns the client certificate that the node connects to cloudhub with
+func (sm *Manager) NodeCertificate(nodeID string) (*x509.Certificate, bool) {
+	session, exist := sm.GetSession(nodeID)
+	if !exist {
+		return nil, false
+	}
+
+	state := session.connection.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, false
+	}
+	return state.PeerCertificates[0], true
+}
+
 // ReachLimit checks whether the connected nodes exceeds the node limit number
 func (sm *Manager) ReachLimit() bool {
 	return atomic.LoadInt32(&sm.NodeNumber) >= sm.NodeLimit
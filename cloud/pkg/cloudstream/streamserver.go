@@ -109,6 +109,9 @@ func (s *StreamServer) installDebugHandler() {
 		To(s.getMetrics))
 	ws.Route(ws.GET("/resource").
 		To(s.getMetrics))
+	// the metrics of edgecore, served by its monitor server
+	ws.Route(ws.GET("/edgecore").
+		To(s.getMetrics))
 	s.container.Add(ws)
 }
 
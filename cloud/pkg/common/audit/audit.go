@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the Applications processed by cloudcore on behalf of edge nodes,
+// and the audit events forwarded by edge nodes, in the audit log of cloudcore.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/metaserver"
+	utilaudit "github.com/kubeedge/kubeedge/pkg/util/audit"
+	passthrough "github.com/kubeedge/kubeedge/pkg/util/pass-through"
+)
+
+const (
+	// NodeAnnotationKey is the annotation of audit events recording the edge node that
+	// the Application or the forwarded audit event comes from
+	NodeAnnotationKey = "kubeedge.io/edge-node"
+	// ApplicationStatusAnnotationKey is the annotation of audit events recording the
+	// status of the Application, Approved or Rejected
+	ApplicationStatusAnnotationKey = "kubeedge.io/application-status"
+
+	// edge nodes are identified in the same way as the kubelet identity
+	edgeNodeUserPrefix = "system:node:"
+	edgeNodesGroup     = "system:nodes"
+)
+
+var (
+	backend audit.Backend
+	policy  audit.PolicyRuleEvaluator
+)
+
+// Init initializes the audit backend and policy of cloudcore, it does nothing if audit is disabled
+func Init(config *v1alpha1.Audit, stopCh <-chan struct{}) error {
+	if config == nil || !config.Enable {
+		return nil
+	}
+	p, err := utilaudit.LoadPolicy(config.PolicyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load audit policy: %v", err)
+	}
+	b, err := utilaudit.NewLogBackend(config.LogPath, int(config.LogMaxAge), int(config.LogMaxBackups), int(config.LogMaxSize))
+	if err != nil {
+		return fmt.Errorf("failed to create audit log: %v", err)
+	}
+	if err = b.Run(stopCh); err != nil {
+		return err
+	}
+	backend, policy = b, p
+	return nil
+}
+
+// LogApplication records the processed Application, received is the time the Application is received
+func LogApplication(app *metaserver.Application, received time.Time) {
+	if backend == nil || app == nil {
+		return
+	}
+	ev := applicationEvent(app, received, time.Now(), policy)
+	if ev == nil {
+		return
+	}
+	backend.ProcessEvents(ev)
+}
+
+// applicationEvent builds the audit event of the Application at the level decided by the policy,
+// it returns nil if the Application is not audited
+func applicationEvent(app *metaserver.Application, received, now time.Time, policy audit.PolicyRuleEvaluator) *auditinternal.Event {
+	attrs := applicationAttributes(app)
+	level := policy.EvaluatePolicyRule(attrs).Level
+	if level == auditinternal.LevelNone {
+		return nil
+	}
+
+	ev := &auditinternal.Event{
+		Level:      level,
+		AuditID:    uuid.NewUUID(),
+		Stage:      auditinternal.StageResponseComplete,
+		RequestURI: app.Key,
+		Verb:       attrs.Verb,
+		User: authnv1.UserInfo{
+			Username: attrs.User.GetName(),
+			Groups:   attrs.User.GetGroups(),
+		},
+		RequestReceivedTimestamp: metav1.NewMicroTime(received),
+		StageTimestamp:           metav1.NewMicroTime(now),
+		Annotations: map[string]string{
+			NodeAnnotationKey:              app.Nodename,
+			ApplicationStatusAnnotationKey: string(app.Status),
+		},
+	}
+	if attrs.ResourceRequest {
+		ev.ObjectRef = &auditinternal.ObjectReference{
+			Resource:    attrs.Resource,
+			Namespace:   attrs.Namespace,
+			Name:        attrs.Name,
+			APIGroup:    attrs.APIGroup,
+			APIVersion:  attrs.APIVersion,
+			Subresource: attrs.Subresource,
+		}
+	}
+
+	switch {
+	case app.Status == metaserver.Approved:
+		ev.ResponseStatus = &metav1.Status{Status: metav1.StatusSuccess, Code: http.StatusOK}
+	case app.Error.ErrStatus.Code != 0:
+		status := app.Error.ErrStatus
+		ev.ResponseStatus = &status
+	default:
+		ev.ResponseStatus = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Message: app.Reason}
+	}
+
+	if level.GreaterOrEqual(auditinternal.LevelRequest) && len(app.ReqBody) > 0 && string(app.ReqBody) != "null" {
+		ev.RequestObject = &runtime.Unknown{Raw: app.ReqBody, ContentType: runtime.ContentTypeJSON}
+	}
+	if level.GreaterOrEqual(auditinternal.LevelRequestResponse) && len(app.RespBody) > 0 && string(app.RespBody) != "null" {
+		ev.ResponseObject = &runtime.Unknown{Raw: app.RespBody, ContentType: runtime.ContentTypeJSON}
+	}
+	return ev
+}
+
+// applicationAttributes translates the Application to the attributes that the audit policy is evaluated with
+func applicationAttributes(app *metaserver.Application) authorizer.AttributesRecord {
+	attrs := authorizer.AttributesRecord{
+		User: &user.DefaultInfo{
+			Name:   edgeNodeUserPrefix + app.Nodename,
+			Groups: []string{edgeNodesGroup, user.AllAuthenticated},
+		},
+		Verb: string(app.Verb),
+	}
+	if passthrough.IsPassThroughPath(app.Key, string(app.Verb)) {
+		attrs.Path = app.Key
+		return attrs
+	}
+
+	gvr, namespace, name := metaserver.ParseKey(app.Key)
+	attrs.ResourceRequest = true
+	attrs.APIGroup = gvr.Group
+	attrs.APIVersion = gvr.Version
+	attrs.Resource = gvr.Resource
+	attrs.Namespace = namespace
+	attrs.Name = name
+	attrs.Subresource = app.Subresource
+	switch app.Verb {
+	case metaserver.UpdateStatus:
+		attrs.Verb = string(metaserver.Update)
+		attrs.Subresource = "status"
+	case metaserver.Patch:
+		var pi metaserver.PatchInfo
+		if err := app.OptionTo(&pi); err == nil {
+			if attrs.Name == "" {
+				attrs.Name = pi.Name
+			}
+			if len(pi.Subresources) > 0 {
+				attrs.Subresource = pi.Subresources[0]
+			}
+		}
+	}
+	return attrs
+}
+
+// ProcessEdgeEvents records the audit events forwarded by the edge node. The events are annotated
+// with the node name that cloudhub authenticated, so that they cannot be forged for other nodes.
+func ProcessEdgeEvents(nodeName string, msg *model.Message) {
+	if backend == nil {
+		klog.V(4).Infof("audit is disabled, drop audit events from edge node %s", nodeName)
+		return
+	}
+	content, err := msg.GetContentData()
+	if err != nil {
+		klog.Errorf("failed to get audit events from edge node %s: %v", nodeName, err)
+		return
+	}
+	events, err := decodeEdgeEvents(nodeName, content)
+	if err != nil {
+		klog.Errorf("failed to decode audit events from edge node %s: %v", nodeName, err)
+		return
+	}
+	backend.ProcessEvents(events...)
+}
+
+func decodeEdgeEvents(nodeName string, content []byte) ([]*auditinternal.Event, error) {
+	var list auditv1.EventList
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, err
+	}
+	events, err := utilaudit.FromV1Events(list.Items)
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range events {
+		if ev.Annotations == nil {
+			ev.Annotations = map[string]string{}
+		}
+		ev.Annotations[NodeAnnotationKey] = nodeName
+	}
+	return events, nil
+}
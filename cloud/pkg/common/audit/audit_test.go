@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	auditpolicy "k8s.io/apiserver/pkg/audit/policy"
+
+	"github.com/kubeedge/kubeedge/pkg/metaserver"
+)
+
+func TestApplicationEvent(t *testing.T) {
+	received := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := received.Add(100 * time.Millisecond)
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "token")
+
+	tests := []struct {
+		name            string
+		app             *metaserver.Application
+		level           auditinternal.Level
+		wantNil         bool
+		wantVerb        string
+		wantRef         auditinternal.ObjectReference
+		wantCode        int32
+		wantRequestBody bool
+	}{
+		{
+			name: "approved get secret",
+			app: &metaserver.Application{Key: "/core/v1/secrets/default/token", Verb: metaserver.Get,
+				Nodename: "edge-1", Status: metaserver.Approved},
+			level:    auditinternal.LevelMetadata,
+			wantVerb: "get",
+			wantRef:  auditinternal.ObjectReference{Resource: "secrets", Namespace: "default", Name: "token", APIVersion: "v1"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "rejected get secret",
+			app: &metaserver.Application{Key: "/core/v1/secrets/default/token", Verb: metaserver.Get,
+				Nodename: "edge-1", Status: metaserver.Rejected, Error: *notFound},
+			level:    auditinternal.LevelMetadata,
+			wantVerb: "get",
+			wantRef:  auditinternal.ObjectReference{Resource: "secrets", Namespace: "default", Name: "token", APIVersion: "v1"},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "update status with request body",
+			app: &metaserver.Application{Key: "/core/v1/pods/default/app", Verb: metaserver.UpdateStatus,
+				Nodename: "edge-1", Status: metaserver.Approved, ReqBody: []byte(`{"kind":"Pod"}`)},
+			level:           auditinternal.LevelRequest,
+			wantVerb:        "update",
+			wantRef:         auditinternal.ObjectReference{Resource: "pods", Namespace: "default", Name: "app", APIVersion: "v1", Subresource: "status"},
+			wantCode:        http.StatusOK,
+			wantRequestBody: true,
+		},
+		{
+			name: "not audited",
+			app: &metaserver.Application{Key: "/core/v1/pods/default/app", Verb: metaserver.Get,
+				Nodename: "edge-1", Status: metaserver.Approved},
+			level:   auditinternal.LevelNone,
+			wantNil: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := auditpolicy.NewFakePolicyRuleEvaluator(test.level, nil)
+			ev := applicationEvent(test.app, received, now, policy)
+			if test.wantNil {
+				if ev != nil {
+					t.Errorf("expect no audit event, got %+v", ev)
+				}
+				return
+			}
+			if ev == nil {
+				t.Fatalf("expect audit event, got nil")
+			}
+			if ev.User.Username != "system:node:edge-1" {
+				t.Errorf("unexpected user %q", ev.User.Username)
+			}
+			if ev.Verb != test.wantVerb {
+				t.Errorf("expect verb %q, got %q", test.wantVerb, ev.Verb)
+			}
+			if ev.ObjectRef == nil || *ev.ObjectRef != test.wantRef {
+				t.Errorf("expect object reference %+v, got %+v", test.wantRef, ev.ObjectRef)
+			}
+			if ev.ResponseStatus == nil || ev.ResponseStatus.Code != test.wantCode {
+				t.Errorf("expect response code %d, got %+v", test.wantCode, ev.ResponseStatus)
+			}
+			if (ev.RequestObject != nil) != test.wantRequestBody {
+				t.Errorf("expect request object %v, got %+v", test.wantRequestBody, ev.RequestObject)
+			}
+			if ev.Annotations[NodeAnnotationKey] != "edge-1" || ev.Annotations[ApplicationStatusAnnotationKey] != string(test.app.Status) {
+				t.Errorf("unexpected annotations %v", ev.Annotations)
+			}
+			if latency := ev.StageTimestamp.Sub(ev.RequestReceivedTimestamp.Time); latency != 100*time.Millisecond {
+				t.Errorf("expect latency 100ms, got %v", latency)
+			}
+		})
+	}
+}
+
+func TestDecodeEdgeEvents(t *testing.T) {
+	list := auditv1.EventList{Items: []auditv1.Event{
+		{Level: auditv1.LevelMetadata, AuditID: "1", Verb: "get", Annotations: map[string]string{NodeAnnotationKey: "edge-2"}},
+		{Level: auditv1.LevelMetadata, AuditID: "2", Verb: "list"},
+	}}
+	content, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := decodeEdgeEvents("edge-1", content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expect 2 events, got %d", len(events))
+	}
+	for _, ev := range events {
+		if ev.Annotations[NodeAnnotationKey] != "edge-1" {
+			t.Errorf("expect event %s annotated with the node it comes from, got %v", ev.AuditID, ev.Annotations)
+		}
+	}
+
+	if _, err = decodeEdgeEvents("edge-1", []byte("events")); err == nil {
+		t.Errorf("expect error for invalid events")
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitor
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BacklogFunc calls visit with the number of messages waiting in the
+// message pool of each edge node, by the message type.
+type BacklogFunc func(visit func(nodeID, messageType string, backlog int))
+
+var messagePoolBacklog = &backlogCollector{
+	desc: prometheus.NewDesc(
+		prometheus.BuildFQName(metricNamespace, CloudHubSubsystem, "message_pool_backlog"),
+		"Number of messages waiting in the message pools to be sent to the edge nodes",
+		[]string{"node", "type"}, nil,
+	),
+}
+
+// SetMessagePoolBacklogFunc sets the function that the backlog of the message pools
+// is collected from when the metrics are scraped.
+func SetMessagePoolBacklogFunc(f BacklogFunc) {
+	messagePoolBacklog.lock.Lock()
+	defer messagePoolBacklog.lock.Unlock()
+	messagePoolBacklog.backlog = f
+}
+
+// backlogCollector collects the backlog of the message pools at scrape time,
+// since the queues of the pools come and go with the node sessions.
+type backlogCollector struct {
+	desc *prometheus.Desc
+
+	lock    sync.Mutex
+	backlog BacklogFunc
+}
+
+func (c *backlogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *backlogCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	backlog := c.backlog
+	c.lock.Unlock()
+
+	type key struct{ node, messageType string }
+	// the backlog of the nodes with the same node label is summed up
+	sums := map[key]int{
+		{messageType: MessageTypeAck}:   0,
+		{messageType: MessageTypeNoAck}: 0,
+	}
+	if backlog != nil {
+		backlog(func(nodeID, messageType string, n int) {
+			sums[key{node: NodeLabel(nodeID), messageType: messageType}] += n
+		})
+	}
+	for k, n := range sums {
+		if k.node == "" && n == 0 && nodeLabelEnabled.Load() {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), k.node, k.messageType)
+	}
+}
@@ -19,8 +19,8 @@ package monitor
 import (
 	"context"
 	"net/http"
-	"net/http/pprof"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,6 +29,7 @@ import (
 
 	beehivecontext "github.com/kubeedge/beehive/pkg/core/context"
 	config "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/profiling"
 )
 
 const (
@@ -36,6 +37,28 @@ const (
 
 	// CloudHubSubsystem - subsystem name used by CloudHub
 	CloudHubSubsystem = "CloudHub"
+	// EdgeControllerSubsystem - subsystem name used by EdgeController
+	EdgeControllerSubsystem = "EdgeController"
+	// DeviceControllerSubsystem - subsystem name used by DeviceController
+	DeviceControllerSubsystem = "DeviceController"
+	// SyncControllerSubsystem - subsystem name used by SyncController
+	SyncControllerSubsystem = "SyncController"
+	// DynamicControllerSubsystem - subsystem name used by DynamicController
+	DynamicControllerSubsystem = "DynamicController"
+	// RouterSubsystem - subsystem name used by Router
+	RouterSubsystem = "Router"
+)
+
+// The values of the labels of metrics
+const (
+	MessageTypeAck   = "ack"
+	MessageTypeNoAck = "noack"
+
+	DirectionUpstream   = "upstream"
+	DirectionDownstream = "downstream"
+
+	ResultSuccess = "success"
+	ResultFailure = "failure"
 )
 
 var (
@@ -47,35 +70,197 @@ var (
 			Help:      "Number of nodes that connected to the cloudHub instance",
 		},
 	)
+
+	MessageAckDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "message_ack_duration_seconds",
+			Help:      "Duration from the first sending of a message requiring ack to the ack of the edge node",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"node"},
+	)
+
+	MessageAckTimeouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "message_ack_timeouts_total",
+			Help:      "Number of messages that were not acked by the edge node after all the retries",
+		},
+		[]string{"node"},
+	)
+
+	CloudHubMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "messages_total",
+			Help:      "Number of messages sent to or received from the edge nodes, excluding keepalive and ack messages",
+		},
+		[]string{"node", "direction"},
+	)
+
+	UpstreamMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: EdgeControllerSubsystem,
+			Name:      "upstream_messages_total",
+			Help:      "Number of upstream messages dispatched by the resource type and operation",
+		},
+		[]string{"resource_type", "operation"},
+	)
+
+	UpstreamProcessingDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: EdgeControllerSubsystem,
+			Name:      "upstream_processing_duration_seconds",
+			Help:      "Duration of processing an upstream message by the resource type and operation",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"resource_type", "operation"},
+	)
+
+	DeviceTwinSyncs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: DeviceControllerSubsystem,
+			Name:      "twin_syncs_total",
+			Help:      "Number of device twin syncs, downstream for the desired values sent to edge and upstream for the reported values from edge",
+		},
+		[]string{"direction", "result"},
+	)
+
+	ObjectSyncs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: SyncControllerSubsystem,
+			Name:      "object_syncs",
+			Help:      "Number of ObjectSyncs and ClusterObjectSyncs recording the objects sent to the edge nodes",
+		},
+		[]string{"kind", "node"},
+	)
+
+	DynamicControllerListeners = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: DynamicControllerSubsystem,
+			Name:      "listeners",
+			Help:      "Number of the watches of the edge nodes by the resource",
+		},
+		[]string{"resource"},
+	)
+
+	DynamicControllerRejectedListeners = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: DynamicControllerSubsystem,
+			Name:      "rejected_listeners_total",
+			Help:      "Number of the watches of the edge nodes rejected by the subscription quota, by the resource",
+		},
+		[]string{"resource"},
+	)
+
+	RuleExecutions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: RouterSubsystem,
+			Name:      "rule_executions_total",
+			Help:      "Number of the messages forwarded by the rules",
+		},
+		[]string{"result"},
+	)
+
+	RuleExecutionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: RouterSubsystem,
+			Name:      "rule_execution_duration_seconds",
+			Help:      "Duration of forwarding a message from the source to the target of a rule",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"result"},
+	)
 )
 
+// collectors are all the metrics of cloudcore
+var collectors = []prometheus.Collector{
+	ConnectedNodes,
+	MessageAckDuration,
+	MessageAckTimeouts,
+	CloudHubMessages,
+	messagePoolBacklog,
+	UpstreamMessages,
+	UpstreamProcessingDuration,
+	DeviceTwinSyncs,
+	ObjectSyncs,
+	DynamicControllerListeners,
+	DynamicControllerRejectedListeners,
+	RuleExecutions,
+	RuleExecutionDuration,
+}
+
 var registerOnce sync.Once
 
 // registerMetrics register all metrics.
 func registerMetrics() {
 	registerOnce.Do(func() {
-		prometheus.MustRegister(
-			ConnectedNodes,
-		)
+		prometheus.MustRegister(collectors...)
 	})
 }
 
-func InstallHandlerForPProf(mux *http.ServeMux) {
-	mux.HandleFunc("/debug/pprof/", pprof.Index)
-	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+// nodeLabelEnabled indicates whether the metrics of each edge node have the node label
+var nodeLabelEnabled atomic.Bool
+
+// NodeLabel returns the value of the node label for the metrics of the node,
+// which is empty if the node label is not enabled, so that the metrics of
+// all nodes are aggregated into one series.
+func NodeLabel(nodeID string) string {
+	if nodeLabelEnabled.Load() {
+		return nodeID
+	}
+	return ""
+}
+
+// DeleteNodeMetrics deletes the series of the node when its session is torn down, so that the
+// series of the nodes no longer connected are not kept forever.
+func DeleteNodeMetrics(nodeID string) {
+	node := NodeLabel(nodeID)
+	// the series of all the nodes are aggregated if the node label is not enabled
+	if node == "" {
+		return
+	}
+	labels := prometheus.Labels{"node": node}
+	CloudHubMessages.DeletePartialMatch(labels)
+	MessageAckDuration.DeletePartialMatch(labels)
+	MessageAckTimeouts.DeletePartialMatch(labels)
+}
+
+// SetObjectSyncs replaces the number of the ObjectSyncs or ClusterObjectSyncs of the kind
+// with counts, which maps the node labels to the numbers.
+func SetObjectSyncs(kind string, counts map[string]int) {
+	ObjectSyncs.DeletePartialMatch(prometheus.Labels{"kind": kind})
+	for node, count := range counts {
+		ObjectSyncs.WithLabelValues(kind, node).Set(float64(count))
+	}
+}
+
+// ObserveSince records the seconds elapsed since start
+func ObserveSince(observer prometheus.Observer, start time.Time) {
+	observer.Observe(time.Since(start).Seconds())
 }
 
 // ServeMonitor serve monitoring metric.
 func ServeMonitor(config config.MonitorServer) {
+	nodeLabelEnabled.Store(config.EnableNodeLabel)
 	registerMetrics()
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 	if config.EnableProfiling {
-		InstallHandlerForPProf(mux)
+		profiling.InstallHandler(mux)
 	}
 
 	s := http.Server{
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitor
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// catalog is the documented metrics of cloudcore
+var catalog = []string{
+	"KubeEdge_CloudHub_connected_nodes",
+	"KubeEdge_CloudHub_message_ack_duration_seconds",
+	"KubeEdge_CloudHub_message_ack_timeouts_total",
+	"KubeEdge_CloudHub_message_pool_backlog",
+	"KubeEdge_CloudHub_messages_total",
+	"KubeEdge_DeviceController_twin_syncs_total",
+	"KubeEdge_DynamicController_listeners",
+	"KubeEdge_DynamicController_rejected_listeners_total",
+	"KubeEdge_EdgeController_upstream_messages_total",
+	"KubeEdge_EdgeController_upstream_processing_duration_seconds",
+	"KubeEdge_Router_rule_execution_duration_seconds",
+	"KubeEdge_Router_rule_executions_total",
+	"KubeEdge_SyncController_object_syncs",
+}
+
+func TestMetricsRegistration(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	for _, c := range collectors {
+		if err := registry.Register(c); err != nil {
+			t.Fatalf("failed to register metric: %v", err)
+		}
+	}
+
+	// the vectors are gathered only when they have series
+	MessageAckDuration.WithLabelValues("")
+	MessageAckTimeouts.WithLabelValues("")
+	CloudHubMessages.WithLabelValues("", DirectionUpstream)
+	UpstreamMessages.WithLabelValues("nodestatus", "update")
+	UpstreamProcessingDuration.WithLabelValues("nodestatus", "update")
+	DeviceTwinSyncs.WithLabelValues(DirectionDownstream, ResultSuccess)
+	ObjectSyncs.WithLabelValues("ObjectSync", "")
+	DynamicControllerListeners.WithLabelValues("pods")
+	DynamicControllerRejectedListeners.WithLabelValues("pods")
+	RuleExecutions.WithLabelValues(ResultSuccess)
+	RuleExecutionDuration.WithLabelValues(ResultSuccess)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	var names []string
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != strings.Join(catalog, ",") {
+		t.Errorf("expected metrics %v, but got %v", catalog, names)
+	}
+}
+
+func TestNodeLabel(t *testing.T) {
+	defer nodeLabelEnabled.Store(false)
+
+	if label := NodeLabel("node1"); label != "" {
+		t.Errorf("expected empty node label when it is not enabled, but got %q", label)
+	}
+	nodeLabelEnabled.Store(true)
+	if label := NodeLabel("node1"); label != "node1" {
+		t.Errorf("expected node label node1, but got %q", label)
+	}
+}
+
+func TestMessagePoolBacklog(t *testing.T) {
+	defer SetMessagePoolBacklogFunc(nil)
+	defer nodeLabelEnabled.Store(false)
+
+	SetMessagePoolBacklogFunc(func(visit func(nodeID, messageType string, backlog int)) {
+		visit("node1", MessageTypeAck, 3)
+		visit("node1", MessageTypeNoAck, 1)
+		visit("node2", MessageTypeAck, 2)
+	})
+
+	expected := `
+# HELP KubeEdge_CloudHub_message_pool_backlog Number of messages waiting in the message pools to be sent to the edge nodes
+# TYPE KubeEdge_CloudHub_message_pool_backlog gauge
+KubeEdge_CloudHub_message_pool_backlog{node="",type="ack"} 5
+KubeEdge_CloudHub_message_pool_backlog{node="",type="noack"} 1
+`
+	if err := testutil.CollectAndCompare(messagePoolBacklog, strings.NewReader(expected)); err != nil {
+		t.Errorf("unexpected aggregated backlog: %v", err)
+	}
+
+	nodeLabelEnabled.Store(true)
+	expected = `
+# HELP KubeEdge_CloudHub_message_pool_backlog Number of messages waiting in the message pools to be sent to the edge nodes
+# TYPE KubeEdge_CloudHub_message_pool_backlog gauge
+KubeEdge_CloudHub_message_pool_backlog{node="node1",type="ack"} 3
+KubeEdge_CloudHub_message_pool_backlog{node="node1",type="noack"} 1
+KubeEdge_CloudHub_message_pool_backlog{node="node2",type="ack"} 2
+`
+	if err := testutil.CollectAndCompare(messagePoolBacklog, strings.NewReader(expected)); err != nil {
+		t.Errorf("unexpected backlog per node: %v", err)
+	}
+}
+
+func TestSetObjectSyncs(t *testing.T) {
+	defer ObjectSyncs.Reset()
+
+	SetObjectSyncs("ObjectSync", map[string]int{"node1": 2, "node2": 1})
+	SetObjectSyncs("ClusterObjectSync", map[string]int{"node1": 1})
+	SetObjectSyncs("ObjectSync", map[string]int{"node1": 3})
+
+	expected := `
+# HELP KubeEdge_SyncController_object_syncs Number of ObjectSyncs and ClusterObjectSyncs recording the objects sent to the edge nodes
+# TYPE KubeEdge_SyncController_object_syncs gauge
+KubeEdge_SyncController_object_syncs{kind="ClusterObjectSync",node="node1"} 1
+KubeEdge_SyncController_object_syncs{kind="ObjectSync",node="node1"} 3
+`
+	if err := testutil.CollectAndCompare(ObjectSyncs, strings.NewReader(expected)); err != nil {
+		t.Errorf("unexpected object syncs: %v", err)
+	}
+}
+
+func TestDeleteNodeMetrics(t *testing.T) {
+	defer nodeLabelEnabled.Store(false)
+	defer CloudHubMessages.Reset()
+	defer MessageAckDuration.Reset()
+	defer MessageAckTimeouts.Reset()
+	for _, vec := range []interface{ Reset() }{CloudHubMessages, MessageAckDuration, MessageAckTimeouts} {
+		vec.Reset()
+	}
+
+	// the aggregated series are kept if the node label is not enabled
+	CloudHubMessages.WithLabelValues(NodeLabel("node1"), DirectionUpstream).Inc()
+	DeleteNodeMetrics("node1")
+	if count := testutil.CollectAndCount(CloudHubMessages); count != 1 {
+		t.Errorf("expected the aggregated series kept, but got %d series", count)
+	}
+	CloudHubMessages.Reset()
+
+	nodeLabelEnabled.Store(true)
+	for _, node := range []string{"node1", "node2"} {
+		CloudHubMessages.WithLabelValues(node, DirectionUpstream).Inc()
+		CloudHubMessages.WithLabelValues(node, DirectionDownstream).Inc()
+		MessageAckDuration.WithLabelValues(node).Observe(1)
+		MessageAckTimeouts.WithLabelValues(node).Inc()
+	}
+	DeleteNodeMetrics("node1")
+
+	for _, c := range []struct {
+		collector prometheus.Collector
+		count     int
+	}{
+		{CloudHubMessages, 2},
+		{MessageAckDuration, 1},
+		{MessageAckTimeouts, 1},
+	} {
+		if count := testutil.CollectAndCount(c.collector); count != c.count {
+			t.Errorf("expected %d series of node2 left, but got %d", c.count, count)
+		}
+	}
+	if _, err := MessageAckTimeouts.GetMetricWithLabelValues("node2"); err != nil {
+		t.Errorf("expected the series of node2 kept: %v", err)
+	}
+}
@@ -65,7 +65,16 @@ func setupControllers(ctx context.Context, mgr manager.Manager) error {
 				&overridemanager.NameOverrider{},
 				&overridemanager.ReplicasOverrider{},
 				&overridemanager.ImageOverrider{},
+				&overridemanager.EnvOverrider{},
+				&overridemanager.ResourcesOverrider{},
+				&overridemanager.CommandOverrider{},
+				&overridemanager.ArgsOverrider{},
+				&overridemanager.TolerationsOverrider{},
+				&overridemanager.AffinityOverrider{},
 				&overridemanager.NodeSelectorOverrider{},
+				// patches should be applied after all the other overriders
+				&overridemanager.JSONPatchOverrider{},
+				&overridemanager.StrategicMergePatchOverrider{},
 			},
 		},
 	}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -99,6 +100,9 @@ func (c *Controller) syncEdgeApplication(ctx context.Context, edgeApp *appsv1alp
 	// It will traverse all templates in EdgeApplication. If error occurs during traverse,
 	// it will log the error and continue.
 	modifiedTmplInfos := []*utils.TemplateInfo{}
+	groupTmplInfos := map[string][]*utils.TemplateInfo{}
+	failedNodeGroups := map[string]struct{}{}
+	overrideFailures := []*overrideFailure{}
 	errs := []error{}
 	overriderInfos := utils.GetAllOverriders(edgeApp)
 	tmplInfos, err := utils.GetTemplatesInfosOfEdgeApp(edgeApp, c.Serializer)
@@ -124,9 +128,9 @@ func (c *Controller) syncEdgeApplication(ctx context.Context, edgeApp *appsv1alp
 
 		// apply overriders
 		//
-		// TODO: consider the situation that not all the overrides have been applied successfully
-		// If one succeeded and another failed, the status of edgeApp will only contain the successful
-		// one, and have no status about the failed one.
+		// If the overriders of a node group cannot be applied, the template of that node group
+		// will not be applied, and its status will be set to OverrideFailed. The resource that
+		// has been applied before will be retained.
 		for _, info := range overriderInfos {
 			copy := tmpl.DeepCopy()
 			klog.V(4).Infof("override obj %s/%s of gvk %s, for nodegroup %s", copy.GetNamespace(), copy.GetName(), copy.GroupVersionKind(), info.TargetNodeGroup)
@@ -134,44 +138,101 @@ func (c *Controller) syncEdgeApplication(ctx context.Context, edgeApp *appsv1alp
 				klog.Errorf("failed to apply override of nodegroup %s to obj %s/%s of gvk %s, %v",
 					info.TargetNodeGroup, copy.GetNamespace(), copy.GetName(), copy.GroupVersionKind(), err)
 				errs = append(errs, err)
+				failedNodeGroups[info.TargetNodeGroup] = struct{}{}
+				overrideFailures = append(overrideFailures, &overrideFailure{
+					TemplateInfo: &utils.TemplateInfo{Ordinal: tmplInfo.Ordinal, Template: copy},
+					Message:      fmt.Sprintf("failed to apply override of nodegroup %s, %v", info.TargetNodeGroup, err),
+				})
 				continue
 			}
-			modifiedTmplInfos = append(modifiedTmplInfos, &utils.TemplateInfo{Ordinal: tmplInfo.Ordinal, Template: copy})
+			groupTmplInfos[info.TargetNodeGroup] = append(groupTmplInfos[info.TargetNodeGroup],
+				&utils.TemplateInfo{Ordinal: tmplInfo.Ordinal, Template: copy})
 		}
 	}
 
-	// 2. remove status that do not need
-	if err := c.updateStatus(ctx, edgeApp, modifiedTmplInfos); err != nil {
-		klog.Errorf("failed to update status for EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
-		errs = append(errs, err)
+	// 2. decide which node groups the overridden templates should be applied to, according to
+	// the rollout strategy. The templates of the node groups that the rollout has not reached
+	// are pending, their resources will be retained as what they are.
+	plan, err := planRollout(edgeApp, getNodeGroupStates(edgeApp, groupTmplInfos, failedNodeGroups), time.Now())
+	if err != nil {
+		klog.Errorf("failed to plan the rollout of EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+		return controllerruntime.Result{}, err
+	}
+	pendingTmplInfos := []*utils.TemplateInfo{}
+	for _, info := range overriderInfos {
+		if plan.isActive(info.TargetNodeGroup) {
+			modifiedTmplInfos = append(modifiedTmplInfos, groupTmplInfos[info.TargetNodeGroup]...)
+		} else {
+			pendingTmplInfos = append(pendingTmplInfos, groupTmplInfos[info.TargetNodeGroup]...)
+		}
 	}
 
 	// 3. apply all templates
 	// It will create/update the resource in the template and notify the status manager
 	// to monitor its status.
+	changed := map[string]struct{}{}
 	for _, tmplInfo := range modifiedTmplInfos {
 		tmpl := tmplInfo.Template
-		if err := c.applyTemplate(ctx, tmpl); err != nil {
+		isChanged, err := c.applyTemplate(ctx, tmpl)
+		if err != nil {
 			klog.Errorf("failed to apply overridden template of EdgeApplication %s/%s, %v, template: %v", edgeApp.Namespace, edgeApp.Name, err, tmpl)
 			errs = append(errs, err)
 			continue
 		}
+		if isChanged {
+			resourceInfo := utils.GetResourceInfoOfTemplateInfo(tmplInfo)
+			changed[resourceInfo.String()] = struct{}{}
+		}
 		klog.V(4).Infof("successfully applied overridden template of EdgeApplication %s/%s, template: %v", edgeApp.Namespace, edgeApp.Name, tmpl)
 	}
 
-	// 4. delete resources that have been removed from the manifests
-	if err := c.deleteRedundantResources(ctx, edgeApp, modifiedTmplInfos); err != nil {
+	// the resources of templates that are pending or failed to override are still contained
+	// in the EdgeApplication, they should not be deleted
+	containedTmplInfos := append([]*utils.TemplateInfo{}, modifiedTmplInfos...)
+	containedTmplInfos = append(containedTmplInfos, pendingTmplInfos...)
+	for _, failure := range overrideFailures {
+		containedTmplInfos = append(containedTmplInfos, failure.TemplateInfo)
+	}
+
+	// 4. remove status that do not need, and update the rollout status
+	status := &syncStatus{
+		tmplInfos:        append(append([]*utils.TemplateInfo{}, modifiedTmplInfos...), pendingTmplInfos...),
+		overrideFailures: overrideFailures,
+		changed:          changed,
+	}
+	if plan != nil {
+		status.rolloutStatus = plan.status
+	}
+	if err := c.updateStatus(ctx, edgeApp, status); err != nil {
+		klog.Errorf("failed to update status for EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+		errs = append(errs, err)
+	}
+
+	// 5. delete resources that have been removed from the manifests
+	if err := c.deleteRedundantResources(ctx, edgeApp, containedTmplInfos); err != nil {
 		klog.Errorf("failed to delete redundant resource for EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
 		errs = append(errs, err)
 	}
 
-	// 5. update the LastContainedResourcesAnnotation
-	if err := c.addOrUpdateLastContainedResourcesAnnotation(ctx, edgeApp, modifiedTmplInfos); err != nil {
+	// 6. update the LastContainedResourcesAnnotation
+	if err := c.addOrUpdateLastContainedResourcesAnnotation(ctx, edgeApp, containedTmplInfos); err != nil {
 		klog.Errorf("failed to update annotation of EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
 		errs = append(errs, err)
 	}
 
-	return controllerruntime.Result{}, errors.NewAggregate(errs)
+	// 7. remove the rollout action annotation that has been handled
+	result := controllerruntime.Result{}
+	if plan != nil {
+		if plan.actionHandled {
+			if err := c.removeRolloutActionAnnotation(ctx, edgeApp); err != nil {
+				klog.Errorf("failed to remove rollout action annotation of EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+				errs = append(errs, err)
+			}
+		}
+		result.RequeueAfter = plan.requeueAfter
+	}
+
+	return result, errors.NewAggregate(errs)
 }
 
 func (c *Controller) deleteRedundantResources(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication, currentTmplInfos []*utils.TemplateInfo) error {
@@ -198,7 +259,27 @@ func (c *Controller) deleteRedundantResources(ctx context.Context, edgeApp *apps
 	return nil
 }
 
-func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication, tmplInfos []*utils.TemplateInfo) error {
+// overrideFailure records the template that failed to apply the overriders of a node group.
+type overrideFailure struct {
+	*utils.TemplateInfo
+	Message string
+}
+
+// syncStatus contains what is used to update the status of EdgeApplication in a sync.
+type syncStatus struct {
+	// tmplInfos are the templates that have been applied or are pending for rollout.
+	tmplInfos []*utils.TemplateInfo
+	// overrideFailures are the templates that failed to apply overriders.
+	overrideFailures []*overrideFailure
+	// changed contains the resources that have been created or updated in this sync,
+	// their status will be reset to Processing.
+	changed map[string]struct{}
+	// rolloutStatus is the new rollout status, nil if no rollout strategy.
+	rolloutStatus *appsv1alpha1.RolloutStatus
+}
+
+func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication, syncStatus *syncStatus) error {
+	tmplInfos, overrideFailures := syncStatus.tmplInfos, syncStatus.overrideFailures
 	newStatus := []appsv1alpha1.ManifestStatus{}
 	tmplMap := map[int][]*utils.TemplateInfo{}
 	for _, tmplInfo := range tmplInfos {
@@ -220,6 +301,13 @@ func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.Edg
 			if utils.IsIdentifierSameAsResourceInfo(id, resourceInfo) {
 				// this status still need to retain
 				statusExists[resourceInfo.String()] = struct{}{}
+				_, changed := syncStatus.changed[resourceInfo.String()]
+				if changed || status.Condition == appsv1alpha1.EdgeAppOverrideFailed {
+					// the resource has been updated or the overriders have been applied
+					// successfully this time, wait for the status manager to check it.
+					status.Condition = appsv1alpha1.EdgeAppProcessing
+					status.Message = ""
+				}
 				newStatus = append(newStatus, status)
 			}
 		}
@@ -235,7 +323,7 @@ func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.Edg
 				Identifier: appsv1alpha1.ResourceIdentifier{
 					Ordinal:   resourceInfo.Ordinal,
 					Group:     resourceInfo.Group,
-					Version:   resourceInfo.Kind,
+					Version:   resourceInfo.Version,
 					Kind:      resourceInfo.Kind,
 					Namespace: resourceInfo.Namespace,
 					Name:      resourceInfo.Name,
@@ -244,6 +332,27 @@ func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.Edg
 		}
 	}
 
+	// add status entries for the templates that failed to override
+	for _, failure := range overrideFailures {
+		resourceInfo := utils.GetResourceInfoOfTemplateInfo(failure.TemplateInfo)
+		if _, ok := statusExists[resourceInfo.String()]; ok {
+			continue
+		}
+		statusExists[resourceInfo.String()] = struct{}{}
+		newStatus = append(newStatus, appsv1alpha1.ManifestStatus{
+			Condition: appsv1alpha1.EdgeAppOverrideFailed,
+			Message:   failure.Message,
+			Identifier: appsv1alpha1.ResourceIdentifier{
+				Ordinal:   resourceInfo.Ordinal,
+				Group:     resourceInfo.Group,
+				Version:   resourceInfo.Version,
+				Kind:      resourceInfo.Kind,
+				Namespace: resourceInfo.Namespace,
+				Name:      resourceInfo.Name,
+			},
+		})
+	}
+
 	// ensure each template have its corresponding status
 	// Because of error, some entries in edgeApp.Spec.WorkloadTemplate.Manifests cannot
 	// be parsed as an template object or cannot applied override to it. These entries should
@@ -273,7 +382,8 @@ func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.Edg
 		return newStatus[i].Identifier.Name < newStatus[j].Identifier.Name
 	})
 
-	if equality.Semantic.DeepEqual(newStatus, edgeApp.Status.WorkloadStatus) {
+	if equality.Semantic.DeepEqual(newStatus, edgeApp.Status.WorkloadStatus) &&
+		equality.Semantic.DeepEqual(syncStatus.rolloutStatus, edgeApp.Status.RolloutStatus) {
 		klog.V(4).Infof("newStatus is same as the current status in edgeApp %s/%s, skip update status",
 			edgeApp.Namespace, edgeApp.Name)
 		return nil
@@ -281,6 +391,7 @@ func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.Edg
 
 	newEdgeApp := edgeApp.DeepCopy()
 	newEdgeApp.Status.WorkloadStatus = newStatus
+	newEdgeApp.Status.RolloutStatus = syncStatus.rolloutStatus
 	return c.Client.Status().Patch(ctx, newEdgeApp, client.MergeFrom(edgeApp))
 }
 
@@ -298,71 +409,73 @@ func (c *Controller) ifObjExists(ctx context.Context, obj *unstructured.Unstruct
 	return true, unstructuredObj, nil
 }
 
-func (c *Controller) updateTemplate(ctx context.Context, tmpl *unstructured.Unstructured, curObj *unstructured.Unstructured) error {
+func (c *Controller) updateTemplate(ctx context.Context, tmpl *unstructured.Unstructured, curObj *unstructured.Unstructured) (bool, error) {
 	if _, ok := curObj.GetAnnotations()[constants.LastAppliedTemplateAnnotationKey]; !ok {
 		klog.Warningf("cannot find LastAppliedTemplateAnnotation on obj %s/%s of gvk %s, update it with new template",
 			curObj.GetNamespace(), curObj.GetName(), curObj.GroupVersionKind())
 		if err := c.Client.Update(ctx, tmpl); err != nil {
-			return fmt.Errorf("failed to update object with template %s, %v", tmpl, err)
+			return false, fmt.Errorf("failed to update object with template %s, %v", tmpl, err)
 		}
-		return nil
+		return true, nil
 	}
 
 	same, err := isSameAsLastApplied(tmpl, curObj)
 	if err != nil {
 		// error occurs when comparing the overridden template with the last applied template
-		return err
+		return false, err
 	} else if err == nil && same {
 		// nothing to do for this template
-		return nil
+		return false, nil
 	}
 
 	// The existing object has different last applied template than what is specified in the EdgeApplication.
 	// Update the object with the template in EdgeApplication, and update its LastAppliedTemplateAnnotation.
 	if err := addOrUpdateLastAppliedTemplateAnnotation(tmpl); err != nil {
-		return fmt.Errorf("failed to add LastAppliedTemplateAnnotation to obj %s/%s of gvk %s, %v",
+		return false, fmt.Errorf("failed to add LastAppliedTemplateAnnotation to obj %s/%s of gvk %s, %v",
 			tmpl.GetNamespace(), tmpl.GetName(), tmpl.GroupVersionKind(), err)
 	}
 	if err := c.update(ctx, tmpl, curObj); err != nil {
-		return fmt.Errorf("failed to update object %s/%s of gvk %s, %v",
+		return false, fmt.Errorf("failed to update object %s/%s of gvk %s, %v",
 			curObj.GetNamespace(), curObj.GetName(), curObj.GroupVersionKind(), err)
 	}
-	return nil
+	return true, nil
 }
 
 // applyTemplate will apply the passed-in template
 // If the object has already existed, it will update it when it is different from what specified in the template
 // If the object does not exist, it will create it according to the template
-func (c *Controller) applyTemplate(ctx context.Context, tmpl *unstructured.Unstructured) error {
+// It returns true if the object has been created or updated.
+func (c *Controller) applyTemplate(ctx context.Context, tmpl *unstructured.Unstructured) (bool, error) {
 	ns, name := tmpl.GetNamespace(), tmpl.GetName()
 	gvk := tmpl.GroupVersionKind()
 	exists, curObj, err := c.ifObjExists(ctx, tmpl)
 	if err != nil {
 		klog.Errorf("failed to check the existence of obj %s/%s, gvk: %s, %v", ns, name, gvk, err)
-		return err
+		return false, err
 	}
 
+	changed := true
 	if exists {
 		// the obj has already exited in the cluster
 		// try to update it
 		klog.V(4).Infof("object %s/%s of gvk %s has already existed, try to update it with template: %v", ns, name, gvk, tmpl)
-		if err := c.updateTemplate(ctx, tmpl, curObj); err != nil {
+		if changed, err = c.updateTemplate(ctx, tmpl, curObj); err != nil {
 			klog.Errorf("failed to update the object %s/%s, gvk: %s, %v", ns, name, gvk, err)
-			return err
+			return false, err
 		}
 	} else {
 		klog.V(4).Infof("try to create object %s/%s of gvk %s with template: %v", ns, name, gvk, tmpl)
 		if err := addOrUpdateLastAppliedTemplateAnnotation(tmpl); err != nil {
-			return fmt.Errorf("failed to add LastAppliedTemplateAnnotation to obj %s/%s of gvk %s, %v",
+			return false, fmt.Errorf("failed to add LastAppliedTemplateAnnotation to obj %s/%s of gvk %s, %v",
 				tmpl.GetNamespace(), tmpl.GetName(), tmpl.GroupVersionKind(), err)
 		}
 		if err := c.Client.Create(ctx, tmpl); err != nil {
 			klog.Errorf("failed to create the object %s/%s of gvk %s with template: %v, %v", ns, name, gvk, tmpl, err)
-			return err
+			return false, err
 		}
 	}
 	// notify the StatusManager to watch its status.
-	return c.StatusManager.WatchStatus(utils.ResourceInfo{
+	return changed, c.StatusManager.WatchStatus(utils.ResourceInfo{
 		Group:     gvk.Group,
 		Version:   gvk.Version,
 		Kind:      gvk.Kind,
@@ -433,6 +546,16 @@ func (c *Controller) addOrUpdateLastContainedResourcesAnnotation(ctx context.Con
 	return c.Client.Patch(ctx, newEdgeApp, client.MergeFrom(edgeApp))
 }
 
+// removeRolloutActionAnnotation removes the RolloutActionAnnotation from the EdgeApplication.
+func (c *Controller) removeRolloutActionAnnotation(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication) error {
+	if _, ok := edgeApp.Annotations[appsv1alpha1.RolloutActionAnnotation]; !ok {
+		return nil
+	}
+	newEdgeApp := edgeApp.DeepCopy()
+	delete(newEdgeApp.Annotations, appsv1alpha1.RolloutActionAnnotation)
+	return c.Client.Patch(ctx, newEdgeApp, client.MergeFrom(edgeApp))
+}
+
 func (c *Controller) update(ctx context.Context, tmpl *unstructured.Unstructured, curObj *unstructured.Unstructured) error {
 	if c.UseServerSideApply {
 		if err := c.Client.Update(ctx, tmpl); err != nil {
@@ -0,0 +1,28 @@
+package overridemanager
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type AffinityOverrider struct{}
+
+var _ Overrider = &AffinityOverrider{}
+
+func (o *AffinityOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	affinityOverrider := overriders.Overriders.AffinityOverrider
+	if affinityOverrider == nil {
+		return nil
+	}
+
+	podSpec, prefix, err := getPodSpec(rawObj)
+	if err != nil {
+		return err
+	}
+	if err := applyFieldOverride(rawObj, prefix+"/affinity", podSpec.Affinity != nil, affinityOverrider.Value == nil, affinityOverrider.Value); err != nil {
+		return fmt.Errorf("failed to apply affinity override on obj %s/%s, %v",
+			rawObj.GetNamespace(), rawObj.GetName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package overridemanager
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+type CommandOverrider struct{}
+
+var _ Overrider = &CommandOverrider{}
+
+func (o *CommandOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	commandOverriders := overriders.Overriders.CommandOverriders
+	for index := range commandOverriders {
+		commandOverrider := &commandOverriders[index]
+		container, containerPath, err := getContainer(rawObj, commandOverrider.ContainerName)
+		if err != nil {
+			return err
+		}
+
+		command, err := overrideCommandArgs(container.Command, commandOverrider)
+		if err != nil {
+			return err
+		}
+		if err := applyFieldOverride(rawObj, containerPath+"/command", container.Command != nil, len(command) == 0, command); err != nil {
+			return fmt.Errorf("failed to apply command override of container %s on obj %s/%s, %v",
+				commandOverrider.ContainerName, rawObj.GetNamespace(), rawObj.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+type ArgsOverrider struct{}
+
+var _ Overrider = &ArgsOverrider{}
+
+func (o *ArgsOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	argsOverriders := overriders.Overriders.ArgsOverriders
+	for index := range argsOverriders {
+		argsOverrider := &argsOverriders[index]
+		container, containerPath, err := getContainer(rawObj, argsOverrider.ContainerName)
+		if err != nil {
+			return err
+		}
+
+		args, err := overrideCommandArgs(container.Args, argsOverrider)
+		if err != nil {
+			return err
+		}
+		if err := applyFieldOverride(rawObj, containerPath+"/args", container.Args != nil, len(args) == 0, args); err != nil {
+			return fmt.Errorf("failed to apply args override of container %s on obj %s/%s, %v",
+				argsOverrider.ContainerName, rawObj.GetNamespace(), rawObj.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+func overrideCommandArgs(cur []string, overrider *appsv1alpha1.CommandArgsOverrider) ([]string, error) {
+	switch overrider.Operator {
+	case appsv1alpha1.OverriderOpAdd:
+		return append(append([]string{}, cur...), overrider.Value...), nil
+	case appsv1alpha1.OverriderOpRemove:
+		removed := make(map[string]struct{}, len(overrider.Value))
+		for _, value := range overrider.Value {
+			removed[value] = struct{}{}
+		}
+		result := []string{}
+		for _, item := range cur {
+			if _, ok := removed[item]; !ok {
+				result = append(result, item)
+			}
+		}
+		return result, nil
+	case appsv1alpha1.OverriderOpReplace:
+		return overrider.Value, nil
+	}
+
+	return nil, fmt.Errorf("unsupported command/args operator(%s)", overrider.Operator)
+}
@@ -0,0 +1,73 @@
+package overridemanager
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+type EnvOverrider struct{}
+
+var _ Overrider = &EnvOverrider{}
+
+func (o *EnvOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	envOverriders := overriders.Overriders.EnvOverriders
+	for index := range envOverriders {
+		envOverrider := &envOverriders[index]
+		container, containerPath, err := getContainer(rawObj, envOverrider.ContainerName)
+		if err != nil {
+			return err
+		}
+
+		env, err := overrideEnv(container.Env, envOverrider)
+		if err != nil {
+			return err
+		}
+		if err := applyFieldOverride(rawObj, containerPath+"/env", container.Env != nil, len(env) == 0, env); err != nil {
+			return fmt.Errorf("failed to apply env override of container %s on obj %s/%s, %v",
+				envOverrider.ContainerName, rawObj.GetNamespace(), rawObj.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+func overrideEnv(curEnv []corev1.EnvVar, envOverrider *appsv1alpha1.EnvOverrider) ([]corev1.EnvVar, error) {
+	switch envOverrider.Operator {
+	case appsv1alpha1.OverriderOpAdd:
+		env := append([]corev1.EnvVar{}, curEnv...)
+		for _, value := range envOverrider.Value {
+			found := false
+			for i := range env {
+				if env[i].Name == value.Name {
+					env[i] = value
+					found = true
+					break
+				}
+			}
+			if !found {
+				env = append(env, value)
+			}
+		}
+		return env, nil
+	case appsv1alpha1.OverriderOpRemove:
+		removed := make(map[string]struct{}, len(envOverrider.Value))
+		for _, value := range envOverrider.Value {
+			removed[value.Name] = struct{}{}
+		}
+		env := []corev1.EnvVar{}
+		for _, e := range curEnv {
+			if _, ok := removed[e.Name]; !ok {
+				env = append(env, e)
+			}
+		}
+		return env, nil
+	case appsv1alpha1.OverriderOpReplace:
+		return envOverrider.Value, nil
+	}
+
+	return nil, fmt.Errorf("unsupported env operator(%s)", envOverrider.Operator)
+}
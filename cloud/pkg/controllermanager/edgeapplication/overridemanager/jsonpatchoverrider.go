@@ -0,0 +1,74 @@
+package overridemanager
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+// JSONPatchOverrider applies the JSON patches on the whole manifest. It should be
+// the last but one of the overriders, so that it can patch the result of the others.
+type JSONPatchOverrider struct{}
+
+var _ Overrider = &JSONPatchOverrider{}
+
+func (o *JSONPatchOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	jsonPatchOverriders := overriders.Overriders.JSONPatchOverriders
+	if len(jsonPatchOverriders) == 0 {
+		return nil
+	}
+
+	patches := make([]overrideOption, 0, len(jsonPatchOverriders))
+	for _, overrider := range jsonPatchOverriders {
+		patch := overrideOption{
+			Op:   string(overrider.Operator),
+			Path: overrider.Path,
+		}
+		if overrider.Operator != appsv1alpha1.OverriderOpRemove && overrider.Value != nil {
+			patch.Value = json.RawMessage(overrider.Value.Raw)
+		}
+		patches = append(patches, patch)
+	}
+
+	if err := applyJSONPatch(rawObj, patches); err != nil {
+		return fmt.Errorf("failed to apply json patches on obj %s/%s, %v",
+			rawObj.GetNamespace(), rawObj.GetName(), err)
+	}
+	return nil
+}
+
+// StrategicMergePatchOverrider applies the strategic merge patch on the whole manifest.
+// It should be the last one of the overriders.
+type StrategicMergePatchOverrider struct{}
+
+var _ Overrider = &StrategicMergePatchOverrider{}
+
+func (o *StrategicMergePatchOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	patch := overriders.Overriders.StrategicMergePatch
+	if patch == nil || len(patch.Raw) == 0 {
+		return nil
+	}
+
+	// strategic merge patch needs the go struct to get the patch strategies of fields
+	dataStruct, err := scheme.Scheme.New(rawObj.GroupVersionKind())
+	if err != nil {
+		return fmt.Errorf("strategic merge patch is unsupported for obj %s/%s of gvk %s, %v",
+			rawObj.GetNamespace(), rawObj.GetName(), rawObj.GroupVersionKind(), err)
+	}
+
+	original, err := rawObj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch.Raw, dataStruct)
+	if err != nil {
+		return fmt.Errorf("failed to apply strategic merge patch on obj %s/%s, %v",
+			rawObj.GetNamespace(), rawObj.GetName(), err)
+	}
+	return rawObj.UnmarshalJSON(patched)
+}
@@ -0,0 +1,241 @@
+package overridemanager
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+func newDeployment(t *testing.T) *unstructured.Unstructured {
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: DeploymentKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "nginx",
+							Image: "nginx:1.23",
+							Env:   []corev1.EnvVar{{Name: "A", Value: "a"}, {Name: "B", Value: "b"}},
+							Args:  []string{"--v=2", "--debug"},
+						},
+					},
+					Tolerations: []corev1.Toleration{
+						{Key: "node-role.kubernetes.io/edge", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+	if err != nil {
+		t.Fatalf("failed to convert deployment to unstructured, %v", err)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	cases := map[string]struct {
+		overrider  Overrider
+		overriders appsv1alpha1.Overriders
+		wantErr    bool
+		check      func(t *testing.T, deployment *appsv1.Deployment)
+	}{
+		"add env": {
+			overrider: &EnvOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				EnvOverriders: []appsv1alpha1.EnvOverrider{{
+					ContainerName: "nginx",
+					Operator:      appsv1alpha1.OverriderOpAdd,
+					Value:         []corev1.EnvVar{{Name: "B", Value: "b2"}, {Name: "C", Value: "c"}},
+				}},
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment) {
+				want := []corev1.EnvVar{{Name: "A", Value: "a"}, {Name: "B", Value: "b2"}, {Name: "C", Value: "c"}}
+				if got := deployment.Spec.Template.Spec.Containers[0].Env; !equality.Semantic.DeepEqual(got, want) {
+					t.Errorf("unexpected env, got: %v, want: %v", got, want)
+				}
+			},
+		},
+		"remove all env": {
+			overrider: &EnvOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				EnvOverriders: []appsv1alpha1.EnvOverrider{{
+					ContainerName: "nginx",
+					Operator:      appsv1alpha1.OverriderOpRemove,
+					Value:         []corev1.EnvVar{{Name: "A"}, {Name: "B"}},
+				}},
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment) {
+				if got := deployment.Spec.Template.Spec.Containers[0].Env; len(got) != 0 {
+					t.Errorf("expect env to be removed, got: %v", got)
+				}
+			},
+		},
+		"env of unknown container": {
+			overrider: &EnvOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				EnvOverriders: []appsv1alpha1.EnvOverrider{{
+					ContainerName: "unknown",
+					Operator:      appsv1alpha1.OverriderOpAdd,
+				}},
+			},
+			wantErr: true,
+		},
+		"replace resources": {
+			overrider: &ResourcesOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				ResourcesOverriders: []appsv1alpha1.ResourcesOverrider{{
+					ContainerName: "nginx",
+					Value: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+					},
+				}},
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment) {
+				got := deployment.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory]
+				if got.Cmp(resource.MustParse("128Mi")) != 0 {
+					t.Errorf("unexpected memory limit, got: %v", got.String())
+				}
+			},
+		},
+		"remove args": {
+			overrider: &ArgsOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				ArgsOverriders: []appsv1alpha1.CommandArgsOverrider{{
+					ContainerName: "nginx",
+					Operator:      appsv1alpha1.OverriderOpRemove,
+					Value:         []string{"--debug"},
+				}},
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment) {
+				want := []string{"--v=2"}
+				if got := deployment.Spec.Template.Spec.Containers[0].Args; !equality.Semantic.DeepEqual(got, want) {
+					t.Errorf("unexpected args, got: %v, want: %v", got, want)
+				}
+			},
+		},
+		"add command": {
+			overrider: &CommandOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				CommandOverriders: []appsv1alpha1.CommandArgsOverrider{{
+					ContainerName: "nginx",
+					Operator:      appsv1alpha1.OverriderOpAdd,
+					Value:         []string{"nginx", "-g"},
+				}},
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment) {
+				want := []string{"nginx", "-g"}
+				if got := deployment.Spec.Template.Spec.Containers[0].Command; !equality.Semantic.DeepEqual(got, want) {
+					t.Errorf("unexpected command, got: %v, want: %v", got, want)
+				}
+			},
+		},
+		"add tolerations": {
+			overrider: &TolerationsOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				TolerationsOverrider: &appsv1alpha1.TolerationsOverrider{
+					Operator: appsv1alpha1.OverriderOpAdd,
+					Value:    []corev1.Toleration{{Key: "site", Operator: corev1.TolerationOpEqual, Value: "a"}},
+				},
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment) {
+				if got := deployment.Spec.Template.Spec.Tolerations; len(got) != 2 || got[1].Key != "site" {
+					t.Errorf("unexpected tolerations, got: %v", got)
+				}
+			},
+		},
+		"set affinity": {
+			overrider: &AffinityOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				AffinityOverrider: &appsv1alpha1.AffinityOverrider{
+					Value: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+				},
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment) {
+				if got := deployment.Spec.Template.Spec.Affinity; got == nil || got.NodeAffinity == nil {
+					t.Errorf("unexpected affinity, got: %v", got)
+				}
+			},
+		},
+		"json patch": {
+			overrider: &JSONPatchOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				JSONPatchOverriders: []appsv1alpha1.JSONPatchOverrider{
+					{
+						Path:     "/spec/template/spec/hostNetwork",
+						Operator: appsv1alpha1.OverriderOpAdd,
+						Value:    &runtime.RawExtension{Raw: []byte("true")},
+					},
+					{
+						Path:     "/spec/template/spec/tolerations",
+						Operator: appsv1alpha1.OverriderOpRemove,
+					},
+				},
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment) {
+				if !deployment.Spec.Template.Spec.HostNetwork {
+					t.Errorf("expect hostNetwork to be true")
+				}
+				if got := deployment.Spec.Template.Spec.Tolerations; len(got) != 0 {
+					t.Errorf("expect tolerations to be removed, got: %v", got)
+				}
+			},
+		},
+		"invalid json patch path": {
+			overrider: &JSONPatchOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				JSONPatchOverriders: []appsv1alpha1.JSONPatchOverrider{{
+					Path:     "/spec/notExist/field",
+					Operator: appsv1alpha1.OverriderOpReplace,
+					Value:    &runtime.RawExtension{Raw: []byte("1")},
+				}},
+			},
+			wantErr: true,
+		},
+		"strategic merge patch": {
+			overrider: &StrategicMergePatchOverrider{},
+			overriders: appsv1alpha1.Overriders{
+				StrategicMergePatch: &runtime.RawExtension{
+					Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"nginx","image":"nginx:1.24"}]}}}}`),
+				},
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment) {
+				container := deployment.Spec.Template.Spec.Containers[0]
+				if container.Image != "nginx:1.24" {
+					t.Errorf("unexpected image, got: %s", container.Image)
+				}
+				if len(container.Env) != 2 {
+					t.Errorf("expect env to be retained, got: %v", container.Env)
+				}
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			obj := newDeployment(t)
+			err := c.overrider.ApplyOverrides(obj, OverriderInfo{TargetNodeGroup: "group", Overriders: &c.overriders})
+			if (err != nil) != c.wantErr {
+				t.Fatalf("unexpected error, got: %v, wantErr: %v", err, c.wantErr)
+			}
+			if c.check == nil {
+				return
+			}
+			deployment, err := ConvertToDeployment(obj)
+			if err != nil {
+				t.Fatalf("failed to convert deployment, %v", err)
+			}
+			c.check(t, deployment)
+		})
+	}
+}
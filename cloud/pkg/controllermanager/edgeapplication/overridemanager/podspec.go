@@ -0,0 +1,99 @@
+package overridemanager
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+// getPodSpec returns the pod spec of the workload and the JSON path of it in the workload.
+func getPodSpec(rawObj *unstructured.Unstructured) (*corev1.PodSpec, string, error) {
+	switch rawObj.GetKind() {
+	case PodKind:
+		podObj, err := ConvertToPod(rawObj)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to convert Pod from unstructured object: %v", err)
+		}
+		return &podObj.Spec, podSpecPrefix, nil
+	case ReplicaSetKind:
+		replicaSetObj, err := ConvertToReplicaSet(rawObj)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to convert ReplicaSet from unstructured object: %v", err)
+		}
+		return &replicaSetObj.Spec.Template.Spec, podTemplatePrefix, nil
+	case DeploymentKind:
+		deploymentObj, err := ConvertToDeployment(rawObj)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to convert Deployment from unstructured object: %v", err)
+		}
+		return &deploymentObj.Spec.Template.Spec, podTemplatePrefix, nil
+	case DaemonSetKind:
+		daemonSetObj, err := ConvertToDaemonSet(rawObj)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to convert DaemonSet from unstructured object: %v", err)
+		}
+		return &daemonSetObj.Spec.Template.Spec, podTemplatePrefix, nil
+	case StatefulSetKind:
+		statefulSetObj, err := ConvertToStatefulSet(rawObj)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to convert StatefulSet from unstructured object: %v", err)
+		}
+		return &statefulSetObj.Spec.Template.Spec, podTemplatePrefix, nil
+	case JobKind:
+		jobObj, err := ConvertToJob(rawObj)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to convert Job from unstructured object: %v", err)
+		}
+		return &jobObj.Spec.Template.Spec, podTemplatePrefix, nil
+	}
+
+	return nil, "", fmt.Errorf("cannot find pod spec in obj %s/%s of gvk %s",
+		rawObj.GetNamespace(), rawObj.GetName(), rawObj.GroupVersionKind())
+}
+
+// getContainer returns the container with the given name and the JSON path of it in the workload.
+func getContainer(rawObj *unstructured.Unstructured, containerName string) (*corev1.Container, string, error) {
+	podSpec, prefix, err := getPodSpec(rawObj)
+	if err != nil {
+		return nil, "", err
+	}
+	for index := range podSpec.Containers {
+		if podSpec.Containers[index].Name == containerName {
+			return &podSpec.Containers[index], fmt.Sprintf("%s/containers/%d", prefix, index), nil
+		}
+	}
+	return nil, "", fmt.Errorf("cannot find container %s in obj %s/%s of gvk %s",
+		containerName, rawObj.GetNamespace(), rawObj.GetName(), rawObj.GroupVersionKind())
+}
+
+// fieldOverrideOption returns the JSON patch that sets the field at path to value,
+// the field will be removed if value is empty.
+func fieldOverrideOption(path string, exists bool, empty bool, value interface{}) *overrideOption {
+	if empty {
+		if !exists {
+			return nil
+		}
+		return &overrideOption{
+			Op:   string(appsv1alpha1.OverriderOpRemove),
+			Path: path,
+		}
+	}
+	// "add" replaces the value if the field already exists
+	return &overrideOption{
+		Op:    string(appsv1alpha1.OverriderOpAdd),
+		Path:  path,
+		Value: value,
+	}
+}
+
+// applyFieldOverride applies the patch returned by fieldOverrideOption to rawObj.
+func applyFieldOverride(rawObj *unstructured.Unstructured, path string, exists bool, empty bool, value interface{}) error {
+	patch := fieldOverrideOption(path, exists, empty, value)
+	if patch == nil {
+		return nil
+	}
+	return applyJSONPatch(rawObj, []overrideOption{*patch})
+}
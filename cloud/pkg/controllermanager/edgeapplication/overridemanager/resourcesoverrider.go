@@ -0,0 +1,31 @@
+package overridemanager
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type ResourcesOverrider struct{}
+
+var _ Overrider = &ResourcesOverrider{}
+
+func (o *ResourcesOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	resourcesOverriders := overriders.Overriders.ResourcesOverriders
+	for index := range resourcesOverriders {
+		resourcesOverrider := &resourcesOverriders[index]
+		_, containerPath, err := getContainer(rawObj, resourcesOverrider.ContainerName)
+		if err != nil {
+			return err
+		}
+
+		// resources is not a pointer in container, so it always exists in the typed object,
+		// use "add" to set it whether it exists in the manifest or not.
+		if err := applyFieldOverride(rawObj, containerPath+"/resources", false, false, resourcesOverrider.Value); err != nil {
+			return fmt.Errorf("failed to apply resources override of container %s on obj %s/%s, %v",
+				resourcesOverrider.ContainerName, rawObj.GetNamespace(), rawObj.GetName(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package overridemanager
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+type TolerationsOverrider struct{}
+
+var _ Overrider = &TolerationsOverrider{}
+
+func (o *TolerationsOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	tolerationsOverrider := overriders.Overriders.TolerationsOverrider
+	if tolerationsOverrider == nil {
+		return nil
+	}
+
+	podSpec, prefix, err := getPodSpec(rawObj)
+	if err != nil {
+		return err
+	}
+	tolerations, err := overrideTolerations(podSpec.Tolerations, tolerationsOverrider)
+	if err != nil {
+		return err
+	}
+	if err := applyFieldOverride(rawObj, prefix+"/tolerations", podSpec.Tolerations != nil, len(tolerations) == 0, tolerations); err != nil {
+		return fmt.Errorf("failed to apply tolerations override on obj %s/%s, %v",
+			rawObj.GetNamespace(), rawObj.GetName(), err)
+	}
+	return nil
+}
+
+// isSameToleration reports whether the two tolerations tolerate the same taint.
+func isSameToleration(a, b *corev1.Toleration) bool {
+	return a.Key == b.Key && a.Operator == b.Operator && a.Effect == b.Effect
+}
+
+func overrideTolerations(cur []corev1.Toleration, overrider *appsv1alpha1.TolerationsOverrider) ([]corev1.Toleration, error) {
+	switch overrider.Operator {
+	case appsv1alpha1.OverriderOpAdd:
+		tolerations := append([]corev1.Toleration{}, cur...)
+		for index := range overrider.Value {
+			found := false
+			for i := range tolerations {
+				if isSameToleration(&tolerations[i], &overrider.Value[index]) {
+					tolerations[i] = overrider.Value[index]
+					found = true
+					break
+				}
+			}
+			if !found {
+				tolerations = append(tolerations, overrider.Value[index])
+			}
+		}
+		return tolerations, nil
+	case appsv1alpha1.OverriderOpRemove:
+		tolerations := []corev1.Toleration{}
+		for i := range cur {
+			removed := false
+			for index := range overrider.Value {
+				if isSameToleration(&cur[i], &overrider.Value[index]) {
+					removed = true
+					break
+				}
+			}
+			if !removed {
+				tolerations = append(tolerations, cur[i])
+			}
+		}
+		return tolerations, nil
+	case appsv1alpha1.OverriderOpReplace:
+		return overrider.Value, nil
+	}
+
+	return nil, fmt.Errorf("unsupported tolerations operator(%s)", overrider.Operator)
+}
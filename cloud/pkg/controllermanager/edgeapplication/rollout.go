@@ -0,0 +1,246 @@
+package edgeapplication
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/utils"
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+type nodeGroupState int
+
+const (
+	nodeGroupProcessing nodeGroupState = iota
+	nodeGroupAvailable
+	nodeGroupFailed
+)
+
+// rolloutPlan is the decision of the rollout made in a sync of EdgeApplication.
+type rolloutPlan struct {
+	status *appsv1alpha1.RolloutStatus
+	// activeNodeGroups are the node groups that the templates should be applied to,
+	// nil means all the node groups.
+	activeNodeGroups map[string]struct{}
+	// actionHandled indicates the rollout action annotation has been handled and should be removed.
+	actionHandled bool
+	// requeueAfter is the time to check the progress deadline of the current wave.
+	requeueAfter time.Duration
+}
+
+// isActive returns whether the templates of the node group should be applied.
+func (p *rolloutPlan) isActive(nodeGroup string) bool {
+	if p == nil || p.activeNodeGroups == nil {
+		return true
+	}
+	_, ok := p.activeNodeGroups[nodeGroup]
+	return ok
+}
+
+// splitWaves splits the target node groups into waves according to the MaxUnavailable of the strategy.
+func splitWaves(edgeApp *appsv1alpha1.EdgeApplication) ([][]string, error) {
+	targets := edgeApp.Spec.WorkloadScope.TargetNodeGroups
+	waveSize := 1
+	if strategy := edgeApp.Spec.RolloutStrategy; strategy != nil && strategy.MaxUnavailable != nil {
+		size, err := intstr.GetScaledValueFromIntOrPercent(strategy.MaxUnavailable, len(targets), true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxUnavailable %s, %v", strategy.MaxUnavailable.String(), err)
+		}
+		if size > 1 {
+			waveSize = size
+		}
+	}
+
+	waves := [][]string{}
+	for start := 0; start < len(targets); start += waveSize {
+		end := start + waveSize
+		if end > len(targets) {
+			end = len(targets)
+		}
+		wave := make([]string, 0, end-start)
+		for _, target := range targets[start:end] {
+			wave = append(wave, target.Name)
+		}
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+// rolloutRevision returns the hash of the workload template and the workload scope of the EdgeApplication.
+func rolloutRevision(edgeApp *appsv1alpha1.EdgeApplication) (string, error) {
+	data, err := json.Marshal([]interface{}{edgeApp.Spec.WorkloadTemplate, edgeApp.Spec.WorkloadScope})
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New64a()
+	if _, err := hasher.Write(data); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hasher.Sum64()), nil
+}
+
+// getNodeGroupStates gets the states of node groups according to the status of their workloads.
+// The node groups that failed to apply overrides in this sync are failed.
+func getNodeGroupStates(edgeApp *appsv1alpha1.EdgeApplication, groupTmplInfos map[string][]*utils.TemplateInfo,
+	failedNodeGroups map[string]struct{}) map[string]nodeGroupState {
+	states := make(map[string]nodeGroupState, len(edgeApp.Spec.WorkloadScope.TargetNodeGroups))
+	for _, target := range edgeApp.Spec.WorkloadScope.TargetNodeGroups {
+		if _, ok := failedNodeGroups[target.Name]; ok {
+			states[target.Name] = nodeGroupFailed
+			continue
+		}
+		state := nodeGroupAvailable
+		for _, tmplInfo := range groupTmplInfos[target.Name] {
+			info := utils.GetResourceInfoOfTemplateInfo(tmplInfo)
+			condition := appsv1alpha1.EdgeAppProcessing
+			for _, status := range edgeApp.Status.WorkloadStatus {
+				if status.Identifier.Ordinal == info.Ordinal && utils.IsIdentifierSameAsResourceInfo(status.Identifier, info) {
+					condition = status.Condition
+					break
+				}
+			}
+			if condition == appsv1alpha1.EdgeAppOverrideFailed {
+				state = nodeGroupFailed
+				break
+			}
+			if condition != appsv1alpha1.EdgeAppAvailable {
+				state = nodeGroupProcessing
+			}
+		}
+		states[target.Name] = state
+	}
+	return states
+}
+
+// planRollout decides which node groups the templates should be applied to in this sync, according to
+// the rollout strategy, the current rollout status and the states of the node groups.
+// It returns nil if the EdgeApplication has no rollout strategy.
+func planRollout(edgeApp *appsv1alpha1.EdgeApplication, states map[string]nodeGroupState, now time.Time) (*rolloutPlan, error) {
+	strategy := edgeApp.Spec.RolloutStrategy
+	if strategy == nil {
+		return nil, nil
+	}
+
+	waves, err := splitWaves(edgeApp)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := rolloutRevision(edgeApp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute the rollout revision, %v", err)
+	}
+
+	plan := &rolloutPlan{}
+	started := false
+	status := edgeApp.Status.RolloutStatus.DeepCopy()
+	if status == nil || status.Revision != revision {
+		// a new revision, start the rollout from the first wave
+		status = &appsv1alpha1.RolloutStatus{
+			Revision:      revision,
+			Phase:         appsv1alpha1.RolloutProgressing,
+			WaveStartTime: &metav1.Time{Time: now},
+		}
+		started = true
+	}
+	status.TotalWaves = int32(len(waves))
+	if int(status.CurrentWave) >= len(waves) {
+		status.CurrentWave = int32(len(waves)) - 1
+		if status.CurrentWave < 0 {
+			status.CurrentWave = 0
+		}
+	}
+
+	advance := func() {
+		status.Message = ""
+		if int(status.CurrentWave)+1 >= len(waves) {
+			status.Phase = appsv1alpha1.RolloutCompleted
+			return
+		}
+		status.CurrentWave++
+		status.Phase = appsv1alpha1.RolloutProgressing
+		status.WaveStartTime = &metav1.Time{Time: now}
+		started = true
+	}
+
+	if len(waves) == 0 {
+		status.Phase = appsv1alpha1.RolloutCompleted
+	}
+
+	switch action := edgeApp.Annotations[appsv1alpha1.RolloutActionAnnotation]; action {
+	case "":
+	case appsv1alpha1.RolloutActionAbort:
+		plan.actionHandled = true
+		if status.Phase == appsv1alpha1.RolloutProgressing || status.Phase == appsv1alpha1.RolloutPaused {
+			status.Phase = appsv1alpha1.RolloutAborted
+			status.Message = fmt.Sprintf("aborted manually at wave %d", status.CurrentWave)
+		}
+	case appsv1alpha1.RolloutActionPromote:
+		plan.actionHandled = true
+		if status.Phase == appsv1alpha1.RolloutProgressing || status.Phase == appsv1alpha1.RolloutPaused {
+			advance()
+		}
+	default:
+		plan.actionHandled = true
+		klog.Warningf("unknown rollout action %q of EdgeApplication %s/%s, ignore it", action, edgeApp.Namespace, edgeApp.Name)
+	}
+
+	// The status of the workloads of the wave started in this sync is out of date,
+	// so check the progress of the wave in the next sync.
+	if status.Phase == appsv1alpha1.RolloutProgressing && !started {
+		wave := waves[status.CurrentWave]
+		failed, available := []string{}, true
+		for _, nodeGroup := range wave {
+			switch states[nodeGroup] {
+			case nodeGroupFailed:
+				failed = append(failed, nodeGroup)
+			case nodeGroupProcessing:
+				available = false
+			}
+		}
+
+		switch {
+		case len(failed) != 0:
+			status.Phase = appsv1alpha1.RolloutPaused
+			status.Message = fmt.Sprintf("wave %d is paused for the failure of node groups %v", status.CurrentWave, failed)
+		case available && (strategy.PauseBetweenWaves && int(status.CurrentWave)+1 < len(waves)):
+			status.Phase = appsv1alpha1.RolloutPaused
+			status.Message = fmt.Sprintf("wave %d is available, waiting for promotion", status.CurrentWave)
+		case available:
+			advance()
+		case strategy.ProgressDeadlineSeconds != nil && status.WaveStartTime != nil:
+			deadline := status.WaveStartTime.Add(time.Duration(*strategy.ProgressDeadlineSeconds) * time.Second)
+			if !now.Before(deadline) {
+				status.Phase = appsv1alpha1.RolloutPaused
+				status.Message = fmt.Sprintf("wave %d does not become available in %d seconds",
+					status.CurrentWave, *strategy.ProgressDeadlineSeconds)
+			} else {
+				plan.requeueAfter = deadline.Sub(now)
+			}
+		}
+	}
+
+	status.UpdatedNodeGroups = nil
+	if status.Phase == appsv1alpha1.RolloutCompleted {
+		plan.activeNodeGroups = nil
+		for _, wave := range waves {
+			status.UpdatedNodeGroups = append(status.UpdatedNodeGroups, wave...)
+		}
+	} else {
+		plan.activeNodeGroups = map[string]struct{}{}
+		for index := 0; index <= int(status.CurrentWave); index++ {
+			for _, nodeGroup := range waves[index] {
+				plan.activeNodeGroups[nodeGroup] = struct{}{}
+				status.UpdatedNodeGroups = append(status.UpdatedNodeGroups, nodeGroup)
+			}
+		}
+	}
+
+	plan.status = status
+	return plan, nil
+}
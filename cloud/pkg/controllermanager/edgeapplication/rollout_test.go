@@ -0,0 +1,199 @@
+package edgeapplication
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+func newRolloutEdgeApp(strategy *appsv1alpha1.RolloutStrategy, groups ...string) *appsv1alpha1.EdgeApplication {
+	edgeApp := &appsv1alpha1.EdgeApplication{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec:       appsv1alpha1.EdgeApplicationSpec{RolloutStrategy: strategy},
+	}
+	for _, group := range groups {
+		edgeApp.Spec.WorkloadScope.TargetNodeGroups = append(edgeApp.Spec.WorkloadScope.TargetNodeGroups,
+			appsv1alpha1.TargetNodeGroup{Name: group})
+	}
+	return edgeApp
+}
+
+func TestSplitWaves(t *testing.T) {
+	two := intstr.FromInt(2)
+	half := intstr.FromString("50%")
+	cases := map[string]struct {
+		strategy *appsv1alpha1.RolloutStrategy
+		want     [][]string
+	}{
+		"default": {
+			strategy: &appsv1alpha1.RolloutStrategy{},
+			want:     [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		"number": {
+			strategy: &appsv1alpha1.RolloutStrategy{MaxUnavailable: &two},
+			want:     [][]string{{"a", "b"}, {"c"}},
+		},
+		"percent rounded up": {
+			strategy: &appsv1alpha1.RolloutStrategy{MaxUnavailable: &half},
+			want:     [][]string{{"a", "b"}, {"c"}},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			waves, err := splitWaves(newRolloutEdgeApp(c.strategy, "a", "b", "c"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(waves, c.want) {
+				t.Errorf("unexpected waves, got: %v, want: %v", waves, c.want)
+			}
+		})
+	}
+}
+
+func TestPlanRollout(t *testing.T) {
+	now := time.Now()
+	deadline := int32(60)
+
+	t.Run("no strategy", func(t *testing.T) {
+		plan, err := planRollout(newRolloutEdgeApp(nil, "a", "b"), nil, now)
+		if err != nil || plan != nil {
+			t.Fatalf("expect nil plan, got: %v, %v", plan, err)
+		}
+		if !plan.isActive("a") || !plan.isActive("b") {
+			t.Errorf("expect all node groups to be active without strategy")
+		}
+	})
+
+	t.Run("new revision starts from the first wave", func(t *testing.T) {
+		edgeApp := newRolloutEdgeApp(&appsv1alpha1.RolloutStrategy{}, "a", "b")
+		plan, err := planRollout(edgeApp, map[string]nodeGroupState{"a": nodeGroupAvailable}, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if plan.status.Phase != appsv1alpha1.RolloutProgressing || plan.status.CurrentWave != 0 || plan.status.TotalWaves != 2 {
+			t.Errorf("unexpected status: %+v", plan.status)
+		}
+		if !plan.isActive("a") || plan.isActive("b") {
+			t.Errorf("expect only node group a to be active")
+		}
+	})
+
+	newInProgress := func(strategy *appsv1alpha1.RolloutStrategy, wave int32, phase appsv1alpha1.RolloutPhase) *appsv1alpha1.EdgeApplication {
+		edgeApp := newRolloutEdgeApp(strategy, "a", "b", "c")
+		revision, err := rolloutRevision(edgeApp)
+		if err != nil {
+			t.Fatalf("failed to compute revision, %v", err)
+		}
+		edgeApp.Status.RolloutStatus = &appsv1alpha1.RolloutStatus{
+			Revision:      revision,
+			Phase:         phase,
+			CurrentWave:   wave,
+			TotalWaves:    3,
+			WaveStartTime: &metav1.Time{Time: now.Add(-time.Minute * 2)},
+		}
+		return edgeApp
+	}
+
+	cases := map[string]struct {
+		edgeApp    *appsv1alpha1.EdgeApplication
+		states     map[string]nodeGroupState
+		wantPhase  appsv1alpha1.RolloutPhase
+		wantWave   int32
+		wantActive []string
+	}{
+		"advance when the wave is available": {
+			edgeApp:    newInProgress(&appsv1alpha1.RolloutStrategy{}, 0, appsv1alpha1.RolloutProgressing),
+			states:     map[string]nodeGroupState{"a": nodeGroupAvailable},
+			wantPhase:  appsv1alpha1.RolloutProgressing,
+			wantWave:   1,
+			wantActive: []string{"a", "b"},
+		},
+		"wait when the wave is processing": {
+			edgeApp:    newInProgress(&appsv1alpha1.RolloutStrategy{}, 1, appsv1alpha1.RolloutProgressing),
+			states:     map[string]nodeGroupState{"a": nodeGroupAvailable, "b": nodeGroupProcessing},
+			wantPhase:  appsv1alpha1.RolloutProgressing,
+			wantWave:   1,
+			wantActive: []string{"a", "b"},
+		},
+		"pause on failure": {
+			edgeApp:    newInProgress(&appsv1alpha1.RolloutStrategy{}, 1, appsv1alpha1.RolloutProgressing),
+			states:     map[string]nodeGroupState{"a": nodeGroupAvailable, "b": nodeGroupFailed},
+			wantPhase:  appsv1alpha1.RolloutPaused,
+			wantWave:   1,
+			wantActive: []string{"a", "b"},
+		},
+		"pause when the deadline exceeded": {
+			edgeApp:    newInProgress(&appsv1alpha1.RolloutStrategy{ProgressDeadlineSeconds: &deadline}, 0, appsv1alpha1.RolloutProgressing),
+			states:     map[string]nodeGroupState{"a": nodeGroupProcessing},
+			wantPhase:  appsv1alpha1.RolloutPaused,
+			wantWave:   0,
+			wantActive: []string{"a"},
+		},
+		"pause between waves": {
+			edgeApp:    newInProgress(&appsv1alpha1.RolloutStrategy{PauseBetweenWaves: true}, 0, appsv1alpha1.RolloutProgressing),
+			states:     map[string]nodeGroupState{"a": nodeGroupAvailable},
+			wantPhase:  appsv1alpha1.RolloutPaused,
+			wantWave:   0,
+			wantActive: []string{"a"},
+		},
+		"complete after the last wave": {
+			edgeApp:    newInProgress(&appsv1alpha1.RolloutStrategy{PauseBetweenWaves: true}, 2, appsv1alpha1.RolloutProgressing),
+			states:     map[string]nodeGroupState{"a": nodeGroupAvailable, "b": nodeGroupAvailable, "c": nodeGroupAvailable},
+			wantPhase:  appsv1alpha1.RolloutCompleted,
+			wantWave:   2,
+			wantActive: []string{"a", "b", "c"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			plan, err := planRollout(c.edgeApp, c.states, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if plan.status.Phase != c.wantPhase || plan.status.CurrentWave != c.wantWave {
+				t.Errorf("unexpected status, got phase %s wave %d, want phase %s wave %d",
+					plan.status.Phase, plan.status.CurrentWave, c.wantPhase, c.wantWave)
+			}
+			if !reflect.DeepEqual(plan.status.UpdatedNodeGroups, c.wantActive) {
+				t.Errorf("unexpected updated node groups, got: %v, want: %v", plan.status.UpdatedNodeGroups, c.wantActive)
+			}
+			for _, group := range c.wantActive {
+				if !plan.isActive(group) {
+					t.Errorf("expect node group %s to be active", group)
+				}
+			}
+		})
+	}
+
+	t.Run("promote the paused rollout", func(t *testing.T) {
+		edgeApp := newInProgress(&appsv1alpha1.RolloutStrategy{}, 0, appsv1alpha1.RolloutPaused)
+		edgeApp.Annotations = map[string]string{appsv1alpha1.RolloutActionAnnotation: appsv1alpha1.RolloutActionPromote}
+		plan, err := planRollout(edgeApp, map[string]nodeGroupState{"a": nodeGroupFailed}, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !plan.actionHandled || plan.status.Phase != appsv1alpha1.RolloutProgressing || plan.status.CurrentWave != 1 {
+			t.Errorf("unexpected plan: %+v, status: %+v", plan, plan.status)
+		}
+	})
+
+	t.Run("abort the rollout", func(t *testing.T) {
+		edgeApp := newInProgress(&appsv1alpha1.RolloutStrategy{}, 1, appsv1alpha1.RolloutProgressing)
+		edgeApp.Annotations = map[string]string{appsv1alpha1.RolloutActionAnnotation: appsv1alpha1.RolloutActionAbort}
+		plan, err := planRollout(edgeApp, map[string]nodeGroupState{"a": nodeGroupAvailable, "b": nodeGroupAvailable}, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !plan.actionHandled || plan.status.Phase != appsv1alpha1.RolloutAborted || plan.isActive("c") {
+			t.Errorf("unexpected plan: %+v, status: %+v", plan, plan.status)
+		}
+	})
+}
@@ -200,7 +200,16 @@ func (d deploymentAvailable) IsAvailable(ctx context.Context, client client.Clie
 		return false, fmt.Errorf("failed to convert unstructured to deployment for %s/%s, %v", info.Namespace, info.Name, err)
 	}
 
-	return deploy.Status.ReadyReplicas == *deploy.Spec.Replicas, nil
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+	// the deployment is available only when the latest spec has been rolled out,
+	// so that the EdgeApplication rollout will not go ahead with the old pods.
+	return deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas == replicas &&
+		deploy.Status.Replicas == replicas &&
+		deploy.Status.ReadyReplicas == replicas, nil
 }
 
 func getObjAccordingToResourceInfo(ctx context.Context, client client.Client, info utils.ResourceInfo) (*unstructured.Unstructured, error) {
@@ -15,9 +15,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
@@ -103,11 +105,17 @@ func (c *Controller) syncNodeGroup(ctx context.Context, nodeGroup *appsv1alpha1.
 		}
 	}
 
-	newNodes, err := c.getNodesSelectedBy(ctx, nodeGroup)
+	selection, err := c.getNodesSelectedBy(ctx, nodeGroup)
+	if selection == nil {
+		klog.Errorf("failed to select nodes for nodegroup %s, %s", nodeGroup.Name, err)
+		return controllerruntime.Result{Requeue: true}, err
+	}
 	if err != nil {
 		klog.Errorf("failed to get all new nodes, %s, continue with what have found.", err)
 	}
+	newNodes := selection.selected
 	debugLogNodes("get new nodes", newNodes)
+	debugLogNodes("get rejected nodes", selection.rejected)
 
 	oldNodes, err := c.getNodesByLabels(ctx, map[string]string{LabelBelongingTo: nodeGroup.Name})
 	if err != nil {
@@ -147,16 +155,28 @@ func (c *Controller) syncNodeGroup(ctx context.Context, nodeGroup *appsv1alpha1.
 			nodeStatus.SelectionStatusReason = err.Error()
 		} else {
 			nodeStatus.SelectionStatus = appsv1alpha1.SucceededSelection
+			nodeStatus.SelectionStatusReason = selection.reasons[node.Name]
 		}
 		nodeStatusList = append(nodeStatusList, nodeStatus)
 	}
+	// update status for nodes that are selected but cannot be members of this node group.
+	for _, node := range selection.rejected {
+		existingNodes = existingNodes.Insert(node.Name)
+		nodeReadyConditionStatus, _ := getNodeReadyConditionFromNode(&node)
+		nodeStatusList = append(nodeStatusList, appsv1alpha1.NodeStatus{
+			NodeName:              node.Name,
+			SelectionStatus:       appsv1alpha1.FailedSelection,
+			SelectionStatusReason: selection.reasons[node.Name],
+			ReadyStatus:           conditionStatusReadyStatusMap[nodeReadyConditionStatus],
+		})
+	}
 	// update status for nodes that do not exist but specified by node name.
 	nonExistingNodes := sets.NewString(nodeGroup.Spec.Nodes...).Difference(existingNodes)
 	for node := range nonExistingNodes {
 		nodeStatusList = append(nodeStatusList, appsv1alpha1.NodeStatus{
 			NodeName:              node,
 			SelectionStatus:       appsv1alpha1.FailedSelection,
-			SelectionStatusReason: "node does not exist",
+			SelectionStatusReason: reasonNodeNotExist,
 			ReadyStatus:           appsv1alpha1.Unknown,
 		})
 	}
@@ -187,6 +207,9 @@ func (c *Controller) SetupWithManager(ctx context.Context, mgr controllerruntime
 	return controllerruntime.NewControllerManagedBy(mgr).
 		For(&appsv1alpha1.NodeGroup{}).
 		Watches(&source.Kind{Type: &corev1.Node{}}, handler.EnqueueRequestsFromMapFunc(c.nodeMapFunc)).
+		// the selection of a nodegroup may change when the spec of other exclusive nodegroups changes
+		Watches(&source.Kind{Type: &appsv1alpha1.NodeGroup{}}, handler.EnqueueRequestsFromMapFunc(c.nodeGroupMapFunc),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(c)
 }
 
@@ -248,14 +271,31 @@ func (c *Controller) removeFinalizer(ctx context.Context, nodeGroup *appsv1alpha
 	return nil
 }
 
-func (c *Controller) getNodesSelectedBy(ctx context.Context, nodeGroup *appsv1alpha1.NodeGroup) ([]corev1.Node, error) {
+// nodeSelection is the result of selecting nodes for a NodeGroup.
+type nodeSelection struct {
+	// selected are the nodes that should be members of the NodeGroup.
+	selected []corev1.Node
+	// rejected are the nodes selected by the NodeGroup but cannot be its members,
+	// such as excluded nodes and nodes conflicting with exclusive NodeGroups.
+	rejected []corev1.Node
+	// reasons contains the reason of selection or rejection for each node.
+	reasons map[string]string
+}
+
+func (c *Controller) getNodesSelectedBy(ctx context.Context, nodeGroup *appsv1alpha1.NodeGroup) (*nodeSelection, error) {
+	// all nodegroups are needed to guard against exclusive nodegroups
+	nodeGroupList := &appsv1alpha1.NodeGroupList{}
+	if err := c.Client.List(ctx, nodeGroupList); err != nil {
+		return nil, fmt.Errorf("failed to list all nodegroups, %v", err)
+	}
+
 	errs := []error{}
-	nodesByLabel, err := c.getNodesByLabels(ctx, nodeGroup.Spec.MatchLabels)
+	nodesBySelector, err := c.getNodesBySelector(ctx, nodeGroup)
 	if err != nil {
-		klog.Errorf("failed to get nodes by MatchLabels %v, %s", nodeGroup.Spec.MatchLabels, err)
+		klog.Errorf("failed to get nodes by selector of nodegroup %s, %s", nodeGroup.Name, err)
 		errs = append(errs, err)
 	}
-	klog.V(4).Infof("get %d nodes that match labels in nodegroup %s", len(nodesByLabel), nodeGroup.Name)
+	klog.V(4).Infof("get %d nodes that match selector in nodegroup %s", len(nodesBySelector), nodeGroup.Name)
 
 	nodesByName, err := c.getNodesByNodeName(ctx, nodeGroup.Spec.Nodes)
 	if err != nil {
@@ -263,46 +303,80 @@ func (c *Controller) getNodesSelectedBy(ctx context.Context, nodeGroup *appsv1al
 		errs = append(errs, err)
 	}
 	klog.V(4).Infof("get %d nodes that specified by name in nodegroup %s", len(nodesByName), nodeGroup.Name)
+
+	selection := &nodeSelection{reasons: map[string]string{}}
 	// remove duplicate nodes
-	return nodesUnion(nodesByLabel, nodesByName), utilerrors.NewAggregate(errs)
+	for _, node := range nodesUnion(nodesBySelector, nodesByName) {
+		if isExcluded(node.Name, nodeGroup) {
+			selection.rejected = append(selection.rejected, node)
+			selection.reasons[node.Name] = reasonExcluded
+			continue
+		}
+		if conflict := exclusiveConflict(&node, nodeGroup, nodeGroupList.Items); conflict != "" {
+			selection.rejected = append(selection.rejected, node)
+			selection.reasons[node.Name] = conflict
+			continue
+		}
+		selection.selected = append(selection.selected, node)
+		selection.reasons[node.Name] = selectionReason(&node, nodeGroup)
+	}
+	return selection, utilerrors.NewAggregate(errs)
 }
 
 // We can assume that one node can only be in one of following conditions:
 // 1. This node is an orphan, do not and will not belong to any NodeGroup.
 // 2. This node is or will be a member of one NodeGroup.
+// 3. This node is selected by more than one NodeGroup, and at least one of them is exclusive.
 func (c *Controller) nodeMapFunc(obj client.Object) []controllerruntime.Request {
 	node := obj.(*corev1.Node)
+	nodeGroupNames := sets.NewString()
 	if nodeGroupName, ok := node.Labels[LabelBelongingTo]; ok {
-		return []controllerruntime.Request{
-			{
-				NamespacedName: types.NamespacedName{
-					Name: nodeGroupName,
-				},
-			},
+		nodeGroupNames.Insert(nodeGroupName)
+	}
+	// a new node may be added to node groups, or the node is an orphan node
+	nodegroupList := &appsv1alpha1.NodeGroupList{}
+	if err := c.Client.List(context.TODO(), nodegroupList); err != nil {
+		klog.Errorf("failed to list all nodegroups, %s", err)
+	} else {
+		for i := range nodegroupList.Items {
+			if IfMatchNodeGroup(node, &nodegroupList.Items[i]) {
+				nodeGroupNames.Insert(nodegroupList.Items[i].Name)
+			}
 		}
 	}
-	// node do not have belonging label, either a new node will be added to a node group or an orphan node
+
+	requests := []controllerruntime.Request{}
+	for _, name := range nodeGroupNames.List() {
+		requests = append(requests, controllerruntime.Request{
+			NamespacedName: types.NamespacedName{
+				Name: name,
+			},
+		})
+	}
+	return requests
+}
+
+// nodeGroupMapFunc enqueues all the other nodegroups, because the nodes selected by
+// them may conflict with the changed nodegroup.
+func (c *Controller) nodeGroupMapFunc(obj client.Object) []controllerruntime.Request {
 	nodegroupList := &appsv1alpha1.NodeGroupList{}
 	if err := c.Client.List(context.TODO(), nodegroupList); err != nil {
 		klog.Errorf("failed to list all nodegroups, %s", err)
 		return nil
 	}
 
+	requests := []controllerruntime.Request{}
 	for _, nodegroup := range nodegroupList.Items {
-		if IfMatchNodeGroup(node, &nodegroup) {
-			// this node will be added into a node group
-			return []controllerruntime.Request{
-				{
-					NamespacedName: types.NamespacedName{
-						Name: nodegroup.Name,
-					},
-				},
-			}
+		if nodegroup.Name == obj.GetName() {
+			continue
 		}
+		requests = append(requests, controllerruntime.Request{
+			NamespacedName: types.NamespacedName{
+				Name: nodegroup.Name,
+			},
+		})
 	}
-
-	// an orphan node, do not reconcile
-	return []controllerruntime.Request{}
+	return requests
 }
 
 func (c *Controller) evictNodesInNodegroup(ctx context.Context, nodeGroupName string) error {
@@ -331,6 +405,31 @@ func (c *Controller) getNodesByLabels(ctx context.Context, matchLabels map[strin
 	return nodeList.Items, nil
 }
 
+// getNodesBySelector can get all nodes matching the selector of the nodegroup.
+func (c *Controller) getNodesBySelector(ctx context.Context, nodeGroup *appsv1alpha1.NodeGroup) ([]corev1.Node, error) {
+	if !hasSelector(nodeGroup) {
+		// Return empty when no selector is specified
+		// Otherwise, it will select all nodes, it's not what we want
+		return []corev1.Node{}, nil
+	}
+	selector, err := labelSelectorOf(nodeGroup)
+	if err != nil {
+		return nil, err
+	}
+	nodeList := &corev1.NodeList{}
+	if err := c.Client.List(ctx, nodeList, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return nil, err
+	}
+
+	nodes := []corev1.Node{}
+	for i := range nodeList.Items {
+		if matchNodeFieldSelector(&nodeList.Items[i], nodeGroup.Spec.NodeFieldSelector) {
+			nodes = append(nodes, nodeList.Items[i])
+		}
+	}
+	return nodes, nil
+}
+
 // getNodesByNodeName can get all nodes specified by node names.
 func (c *Controller) getNodesByNodeName(ctx context.Context, nodeNames []string) ([]corev1.Node, error) {
 	errs := []error{}
@@ -374,16 +473,12 @@ func (c *Controller) addOrUpdateNodeLabel(ctx context.Context, node *corev1.Node
 }
 
 // IfMatchNodeGroup will check if the node is selected by the nodegroup.
+// Nodes excluded by the nodegroup are not selected.
 func IfMatchNodeGroup(node *corev1.Node, nodegroup *appsv1alpha1.NodeGroup) bool {
-	// check if nodename is in the nodegroup.Spec.Nodes
-	for _, nodeName := range nodegroup.Spec.Nodes {
-		if nodeName == node.Name {
-			return true
-		}
+	if isExcluded(node.Name, nodegroup) {
+		return false
 	}
-	// check if labels of this node selected by nodegroup.Spec.MatchLabels
-	selector := labels.SelectorFromSet(nodegroup.Spec.MatchLabels)
-	return selector.Matches(labels.Set(node.Labels))
+	return selectionReason(node, nodegroup) != ""
 }
 
 func getNodeReadyConditionFromNode(node *corev1.Node) (corev1.ConditionStatus, bool) {
@@ -0,0 +1,164 @@
+package nodegroup
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+const (
+	kubeedgeVersionSeparator = "-kubeedge-"
+
+	reasonSelectedByName     = "selected by node name"
+	reasonSelectedBySelector = "selected by node selector"
+	reasonExcluded           = "node is excluded by excludeNodes"
+	reasonNodeNotExist       = "node does not exist"
+)
+
+// hasSelector returns whether the NodeGroup selects nodes by selector.
+// Empty selectors are ignored, otherwise they would select all the nodes.
+func hasSelector(nodeGroup *appsv1alpha1.NodeGroup) bool {
+	labelSelector := nodeGroup.Spec.LabelSelector
+	return len(nodeGroup.Spec.MatchLabels) != 0 ||
+		(labelSelector != nil && (len(labelSelector.MatchLabels) != 0 || len(labelSelector.MatchExpressions) != 0)) ||
+		len(nodeGroup.Spec.NodeFieldSelector) != 0
+}
+
+// labelSelectorOf returns the label selector combining MatchLabels and LabelSelector of the NodeGroup.
+func labelSelectorOf(nodeGroup *appsv1alpha1.NodeGroup) (labels.Selector, error) {
+	selector := labels.SelectorFromSet(nodeGroup.Spec.MatchLabels)
+	if nodeGroup.Spec.LabelSelector == nil {
+		return selector, nil
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(nodeGroup.Spec.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector of nodegroup %s, %v", nodeGroup.Name, err)
+	}
+	requirements, _ := labelSelector.Requirements()
+	return selector.Add(requirements...), nil
+}
+
+// getNodeField returns the value of the node field, and whether it exists.
+func getNodeField(node *corev1.Node, field appsv1alpha1.NodeField) (string, bool) {
+	info := node.Status.NodeInfo
+	var value string
+	switch field {
+	case appsv1alpha1.NodeFieldArchitecture:
+		value = info.Architecture
+	case appsv1alpha1.NodeFieldOperatingSystem:
+		value = info.OperatingSystem
+	case appsv1alpha1.NodeFieldOSImage:
+		value = info.OSImage
+	case appsv1alpha1.NodeFieldKernelVersion:
+		value = info.KernelVersion
+	case appsv1alpha1.NodeFieldContainerRuntimeVersion:
+		value = info.ContainerRuntimeVersion
+	case appsv1alpha1.NodeFieldKubeletVersion:
+		value = info.KubeletVersion
+	case appsv1alpha1.NodeFieldKubeEdgeVersion:
+		// the kubelet version of edge node is like v1.22.6-kubeedge-v1.13.0
+		index := strings.Index(info.KubeletVersion, kubeedgeVersionSeparator)
+		if index == -1 {
+			return "", false
+		}
+		value = info.KubeletVersion[index+len(kubeedgeVersionSeparator):]
+	}
+	return value, value != ""
+}
+
+// matchNodeFieldSelector returns whether the node satisfies all the requirements.
+func matchNodeFieldSelector(node *corev1.Node, requirements []appsv1alpha1.NodeFieldSelectorRequirement) bool {
+	for _, requirement := range requirements {
+		value, exists := getNodeField(node, requirement.Key)
+		switch requirement.Operator {
+		case metav1.LabelSelectorOpIn:
+			if !exists || !sets.NewString(requirement.Values...).Has(value) {
+				return false
+			}
+		case metav1.LabelSelectorOpNotIn:
+			if exists && sets.NewString(requirement.Values...).Has(value) {
+				return false
+			}
+		case metav1.LabelSelectorOpExists:
+			if !exists {
+				return false
+			}
+		case metav1.LabelSelectorOpDoesNotExist:
+			if exists {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// matchSelector returns whether the node matches the selector of the NodeGroup.
+func matchSelector(node *corev1.Node, nodeGroup *appsv1alpha1.NodeGroup) bool {
+	if !hasSelector(nodeGroup) {
+		return false
+	}
+	selector, err := labelSelectorOf(nodeGroup)
+	if err != nil || !selector.Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	return matchNodeFieldSelector(node, nodeGroup.Spec.NodeFieldSelector)
+}
+
+// isExcluded returns whether the node is excluded by the NodeGroup.
+func isExcluded(nodeName string, nodeGroup *appsv1alpha1.NodeGroup) bool {
+	for _, name := range nodeGroup.Spec.ExcludeNodes {
+		if name == nodeName {
+			return true
+		}
+	}
+	return false
+}
+
+// selectionReason returns the reason why the node is selected by the NodeGroup,
+// and empty string if it is not selected. Excluded nodes are not taken into account.
+func selectionReason(node *corev1.Node, nodeGroup *appsv1alpha1.NodeGroup) string {
+	reasons := []string{}
+	for _, nodeName := range nodeGroup.Spec.Nodes {
+		if nodeName == node.Name {
+			reasons = append(reasons, reasonSelectedByName)
+			break
+		}
+	}
+	if matchSelector(node, nodeGroup) {
+		reasons = append(reasons, reasonSelectedBySelector)
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// exclusiveConflict returns the reason why the node cannot be a member of the NodeGroup because of
+// exclusive NodeGroups, and empty string if there's no conflict.
+func exclusiveConflict(node *corev1.Node, nodeGroup *appsv1alpha1.NodeGroup, nodeGroups []appsv1alpha1.NodeGroup) string {
+	others := []string{}
+	exclusiveOthers := []string{}
+	for i := range nodeGroups {
+		other := &nodeGroups[i]
+		if other.Name == nodeGroup.Name || !other.DeletionTimestamp.IsZero() || !IfMatchNodeGroup(node, other) {
+			continue
+		}
+		others = append(others, other.Name)
+		if other.Spec.Exclusive {
+			exclusiveOthers = append(exclusiveOthers, other.Name)
+		}
+	}
+
+	if nodeGroup.Spec.Exclusive && len(others) != 0 {
+		return fmt.Sprintf("node is also selected by nodegroups %v, which conflicts with exclusive nodegroup %s", others, nodeGroup.Name)
+	}
+	if len(exclusiveOthers) != 0 {
+		return fmt.Sprintf("node is also selected by exclusive nodegroups %v", exclusiveOthers)
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package nodegroup
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+func newSelectorTestNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{
+				Architecture:   "arm64",
+				KubeletVersion: "v1.22.6-kubeedge-v1.13.0",
+			},
+		},
+	}
+}
+
+func TestMatchNodeFieldSelector(t *testing.T) {
+	node := newSelectorTestNode("node1", nil)
+	cases := map[string]struct {
+		requirements []appsv1alpha1.NodeFieldSelectorRequirement
+		want         bool
+	}{
+		"no-requirement": {
+			requirements: nil,
+			want:         true,
+		},
+		"in": {
+			requirements: []appsv1alpha1.NodeFieldSelectorRequirement{
+				{Key: appsv1alpha1.NodeFieldArchitecture, Operator: v1.LabelSelectorOpIn, Values: []string{"amd64", "arm64"}},
+			},
+			want: true,
+		},
+		"not-in": {
+			requirements: []appsv1alpha1.NodeFieldSelectorRequirement{
+				{Key: appsv1alpha1.NodeFieldArchitecture, Operator: v1.LabelSelectorOpNotIn, Values: []string{"arm64"}},
+			},
+			want: false,
+		},
+		"kubeedge-version": {
+			requirements: []appsv1alpha1.NodeFieldSelectorRequirement{
+				{Key: appsv1alpha1.NodeFieldKubeEdgeVersion, Operator: v1.LabelSelectorOpIn, Values: []string{"v1.13.0"}},
+			},
+			want: true,
+		},
+		"exists": {
+			requirements: []appsv1alpha1.NodeFieldSelectorRequirement{
+				{Key: appsv1alpha1.NodeFieldOSImage, Operator: v1.LabelSelectorOpExists},
+			},
+			want: false,
+		},
+		"does-not-exist": {
+			requirements: []appsv1alpha1.NodeFieldSelectorRequirement{
+				{Key: appsv1alpha1.NodeFieldOSImage, Operator: v1.LabelSelectorOpDoesNotExist},
+			},
+			want: true,
+		},
+		"all-requirements": {
+			requirements: []appsv1alpha1.NodeFieldSelectorRequirement{
+				{Key: appsv1alpha1.NodeFieldArchitecture, Operator: v1.LabelSelectorOpIn, Values: []string{"arm64"}},
+				{Key: appsv1alpha1.NodeFieldKubeEdgeVersion, Operator: v1.LabelSelectorOpIn, Values: []string{"v1.12.0"}},
+			},
+			want: false,
+		},
+	}
+	for n, c := range cases {
+		if got := matchNodeFieldSelector(node, c.requirements); got != c.want {
+			t.Errorf("failed at case: %s, want: %v, got: %v", n, c.want, got)
+		}
+	}
+}
+
+func TestIfMatchNodeGroup(t *testing.T) {
+	node := newSelectorTestNode("node1", map[string]string{"zone": "hangzhou", "tier": "gateway"})
+	cases := map[string]struct {
+		spec appsv1alpha1.NodeGroupSpec
+		want bool
+	}{
+		"no-selector": {
+			spec: appsv1alpha1.NodeGroupSpec{},
+			want: false,
+		},
+		"empty-selector": {
+			spec: appsv1alpha1.NodeGroupSpec{
+				MatchLabels:   map[string]string{},
+				LabelSelector: &v1.LabelSelector{},
+			},
+			want: false,
+		},
+		"by-name": {
+			spec: appsv1alpha1.NodeGroupSpec{Nodes: []string{"node1"}},
+			want: true,
+		},
+		"match-labels": {
+			spec: appsv1alpha1.NodeGroupSpec{MatchLabels: map[string]string{"zone": "hangzhou"}},
+			want: true,
+		},
+		"label-expressions": {
+			spec: appsv1alpha1.NodeGroupSpec{
+				LabelSelector: &v1.LabelSelector{
+					MatchExpressions: []v1.LabelSelectorRequirement{
+						{Key: "tier", Operator: v1.LabelSelectorOpIn, Values: []string{"gateway", "camera"}},
+					},
+				},
+			},
+			want: true,
+		},
+		"labels-and-fields": {
+			spec: appsv1alpha1.NodeGroupSpec{
+				MatchLabels: map[string]string{"zone": "hangzhou"},
+				NodeFieldSelector: []appsv1alpha1.NodeFieldSelectorRequirement{
+					{Key: appsv1alpha1.NodeFieldArchitecture, Operator: v1.LabelSelectorOpIn, Values: []string{"amd64"}},
+				},
+			},
+			want: false,
+		},
+		"excluded": {
+			spec: appsv1alpha1.NodeGroupSpec{
+				MatchLabels:  map[string]string{"zone": "hangzhou"},
+				ExcludeNodes: []string{"node1"},
+			},
+			want: false,
+		},
+	}
+	for n, c := range cases {
+		nodeGroup := &appsv1alpha1.NodeGroup{
+			ObjectMeta: v1.ObjectMeta{Name: "group"},
+			Spec:       c.spec,
+		}
+		if got := IfMatchNodeGroup(node, nodeGroup); got != c.want {
+			t.Errorf("failed at case: %s, want: %v, got: %v", n, c.want, got)
+		}
+	}
+}
+
+func TestExclusiveConflict(t *testing.T) {
+	node := newSelectorTestNode("node1", map[string]string{"zone": "hangzhou"})
+	newNodeGroup := func(name string, exclusive bool) appsv1alpha1.NodeGroup {
+		return appsv1alpha1.NodeGroup{
+			ObjectMeta: v1.ObjectMeta{Name: name},
+			Spec: appsv1alpha1.NodeGroupSpec{
+				MatchLabels: map[string]string{"zone": "hangzhou"},
+				Exclusive:   exclusive,
+			},
+		}
+	}
+	cases := map[string]struct {
+		nodeGroups []appsv1alpha1.NodeGroup
+		conflict   bool
+	}{
+		"only-itself": {
+			nodeGroups: []appsv1alpha1.NodeGroup{newNodeGroup("a", true)},
+			conflict:   false,
+		},
+		"non-exclusive": {
+			nodeGroups: []appsv1alpha1.NodeGroup{newNodeGroup("a", false), newNodeGroup("b", false)},
+			conflict:   false,
+		},
+		"itself-exclusive": {
+			nodeGroups: []appsv1alpha1.NodeGroup{newNodeGroup("a", true), newNodeGroup("b", false)},
+			conflict:   true,
+		},
+		"other-exclusive": {
+			nodeGroups: []appsv1alpha1.NodeGroup{newNodeGroup("a", false), newNodeGroup("b", true)},
+			conflict:   true,
+		},
+	}
+	for n, c := range cases {
+		conflict := exclusiveConflict(node, &c.nodeGroups[0], c.nodeGroups)
+		if (conflict != "") != c.conflict {
+			t.Errorf("failed at case: %s, want conflict: %v, got: %q", n, c.conflict, conflict)
+		}
+	}
+}
@@ -17,32 +17,54 @@ limitations under the License.
 package csidriver
 
 import (
-	"encoding/base64"
-	"encoding/json"
-	"errors"
-	"fmt"
+	"sync"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
-	"github.com/golang/protobuf/jsonpb"
 	"github.com/google/uuid"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"k8s.io/klog/v2"
 
-	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/common/constants"
 )
 
+// controllerCapabilitiesTTL is how long the capabilities reported by the CSI driver on the edge
+// node are cached
+const controllerCapabilitiesTTL = 5 * time.Minute
+
+// forwardedControllerCapabilities are the capabilities whose RPCs are forwarded to the edge node,
+// the other capabilities reported by the CSI driver on the edge node are not supported.
+var forwardedControllerCapabilities = map[csi.ControllerServiceCapability_RPC_Type]bool{
+	csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME:         true,
+	csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME:     true,
+	csi.ControllerServiceCapability_RPC_LIST_VOLUMES:                 true,
+	csi.ControllerServiceCapability_RPC_GET_CAPACITY:                 true,
+	csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT:       true,
+	csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS:               true,
+	csi.ControllerServiceCapability_RPC_CLONE_VOLUME:                 true,
+	csi.ControllerServiceCapability_RPC_PUBLISH_READONLY:             true,
+	csi.ControllerServiceCapability_RPC_EXPAND_VOLUME:                true,
+	csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES: true,
+	csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER:     true,
+}
+
 type controllerServer struct {
+	driverName string
+	// caps are the capabilities reported by the CSI driver on the edge node, they are the
+	// default capabilities before the edge node reports its capabilities.
 	caps             []*csi.ControllerServiceCapability
+	capsExpire       time.Time
+	capsLock         sync.RWMutex
 	nodeID           string
 	kubeEdgeEndpoint string
 }
 
 // newControllerServer creates controller server
-func newControllerServer(nodeID, kubeEdgeEndpoint string) *controllerServer {
+func newControllerServer(driverName, nodeID, kubeEdgeEndpoint string) *controllerServer {
 	return &controllerServer{
+		driverName: driverName,
 		caps: getControllerServiceCapabilities(
 			[]csi.ControllerServiceCapability_RPC_Type{
 				csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
@@ -66,70 +88,8 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 
 	volumeID := uuid.New().String()
 
-	// Build message struct
-	resource, err := buildResource(cs.nodeID,
-		DefaultNamespace,
-		constants.CSIResourceTypeVolume,
-		volumeID)
-	if err != nil {
-		klog.Errorf("build message resource failed with error: %s", err)
-		return nil, err
-	}
-
-	m := jsonpb.Marshaler{}
-	js, err := m.MarshalToString(req)
-	if err != nil {
-		klog.Errorf("failed to marshal to string with error: %s", err)
-		return nil, err
-	}
-	klog.V(4).Infof("create volume marshal to string: %s", js)
-	msg := model.NewMessage("").
-		BuildRouter(DefaultReceiveModuleName, GroupResource, resource, constants.CSIOperationTypeCreateVolume).
-		FillBody(js)
-
-	// Marshal message
-	reqData, err := json.Marshal(msg)
-	if err != nil {
-		klog.Errorf("marshal request failed with error: %v", err)
-		return nil, err
-	}
-
-	// Send message to KubeEdge
-	resdata, err := sendToKubeEdge(string(reqData), cs.kubeEdgeEndpoint)
-	if err != nil {
-		klog.Errorf("send to kubeedge failed with error: %v", err)
-		return nil, err
-	}
-
-	// Unmarshal message
-	result, err := extractMessage(resdata)
-	if err != nil {
-		klog.Errorf("unmarshal response failed with error: %v", err)
-		return nil, err
-	}
-
-	klog.V(4).Infof("create volume result: %v", result)
-	data, ok := result.GetContent().(string)
-	if !ok {
-		klog.Errorf("content is not string type: %v", result.GetContent())
-		return nil, fmt.Errorf("content type %T is not string", result.GetContent())
-	}
-
-	if result.GetOperation() == model.ResponseErrorOperation {
-		klog.Errorf("create volume with error: %s", data)
-		return nil, errors.New(data)
-	}
-
-	decodeBytes, err := base64.StdEncoding.DecodeString(data)
-	if err != nil {
-		klog.Errorf("create volume decode with error: %v", err)
-		return nil, err
-	}
-
 	response := &csi.CreateVolumeResponse{}
-	err = json.Unmarshal(decodeBytes, response)
-	if err != nil {
-		klog.Errorf("create volume unmarshal with error: %v", err)
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeCreateVolume, volumeID, req, response); err != nil {
 		return nil, err
 	}
 	klog.V(4).Infof("create volume response: %v", response)
@@ -154,70 +114,8 @@ func (cs *controllerServer) DeleteVolume(ctx context.Context, req *csi.DeleteVol
 		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
 	}
 
-	// Build message struct
-	resource, err := buildResource(cs.nodeID,
-		DefaultNamespace,
-		constants.CSIResourceTypeVolume,
-		req.GetVolumeId())
-	if err != nil {
-		klog.Errorf("build message resource failed with error: %s", err)
-		return nil, err
-	}
-
-	m := jsonpb.Marshaler{}
-	js, err := m.MarshalToString(req)
-	if err != nil {
-		klog.Errorf("failed to marshal to string with error: %s", err)
-		return nil, err
-	}
-	klog.V(4).Infof("delete volume marshal to string: %s", js)
-	msg := model.NewMessage("").
-		BuildRouter(DefaultReceiveModuleName, GroupResource, resource, constants.CSIOperationTypeDeleteVolume).
-		FillBody(js)
-
-	// Marshal message
-	reqData, err := json.Marshal(msg)
-	if err != nil {
-		klog.Errorf("marshal request failed with error: %v", err)
-		return nil, err
-	}
-
-	// Send message to KubeEdge
-	resdata, err := sendToKubeEdge(string(reqData), cs.kubeEdgeEndpoint)
-	if err != nil {
-		klog.Errorf("send to kubeedge failed with error: %v", err)
-		return nil, err
-	}
-
-	// Unmarshal message
-	result, err := extractMessage(resdata)
-	if err != nil {
-		klog.Errorf("unmarshal response failed with error: %v", err)
-		return nil, err
-	}
-
-	klog.V(4).Infof("delete volume result: %v", result)
-	data, ok := result.GetContent().(string)
-	if !ok {
-		klog.Errorf("content is not string type: %v", result.GetContent())
-		return nil, fmt.Errorf("content type %T is not string", result.GetContent())
-	}
-
-	if msg.GetOperation() == model.ResponseErrorOperation {
-		klog.Errorf("delete volume with error: %s", data)
-		return nil, errors.New(data)
-	}
-
-	decodeBytes, err := base64.StdEncoding.DecodeString(data)
-	if err != nil {
-		klog.Errorf("delete volume decode with error: %v", err)
-		return nil, err
-	}
-
 	deleteVolumeResponse := &csi.DeleteVolumeResponse{}
-	err = json.Unmarshal(decodeBytes, deleteVolumeResponse)
-	if err != nil {
-		klog.Errorf("delete volume unmarshal with error: %v", err)
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeDeleteVolume, req.GetVolumeId(), req, deleteVolumeResponse); err != nil {
 		return nil, err
 	}
 	klog.V(4).Infof("delete volume response: %v", deleteVolumeResponse)
@@ -237,70 +135,8 @@ func (cs *controllerServer) ControllerPublishVolume(ctx context.Context, req *cs
 		return nil, status.Error(codes.InvalidArgument, "ControllerPublishVolume Instance ID must be provided")
 	}
 
-	// Build message struct
-	resource, err := buildResource(cs.nodeID,
-		DefaultNamespace,
-		constants.CSIResourceTypeVolume,
-		volumeID)
-	if err != nil {
-		klog.Errorf("build message resource failed with error: %s", err)
-		return nil, err
-	}
-
-	m := jsonpb.Marshaler{}
-	js, err := m.MarshalToString(req)
-	if err != nil {
-		klog.Errorf("failed to marshal to string with error: %s", err)
-		return nil, err
-	}
-	klog.V(4).Infof("controller publish volume marshal to string: %s", js)
-	msg := model.NewMessage("").
-		BuildRouter(DefaultReceiveModuleName, GroupResource, resource, constants.CSIOperationTypeControllerPublishVolume).
-		FillBody(js)
-
-	// Marshal message
-	reqData, err := json.Marshal(msg)
-	if err != nil {
-		klog.Errorf("marshal request failed with error: %v", err)
-		return nil, err
-	}
-
-	// Send message to KubeEdge
-	resdata, err := sendToKubeEdge(string(reqData), cs.kubeEdgeEndpoint)
-	if err != nil {
-		klog.Errorf("send to kubeedge failed with error: %v", err)
-		return nil, err
-	}
-
-	// Unmarshal message
-	result, err := extractMessage(resdata)
-	if err != nil {
-		klog.Errorf("unmarshal response failed with error: %v", err)
-		return nil, err
-	}
-
-	klog.V(4).Infof("controller publish volume result: %v", result)
-	data, ok := result.GetContent().(string)
-	if !ok {
-		klog.Errorf("content is not string type: %v", result.GetContent())
-		return nil, fmt.Errorf("content type %T is not string", result.GetContent())
-	}
-
-	if msg.GetOperation() == model.ResponseErrorOperation {
-		klog.Errorf("controller publish volume with error: %s", data)
-		return nil, errors.New(data)
-	}
-
-	decodeBytes, err := base64.StdEncoding.DecodeString(data)
-	if err != nil {
-		klog.Errorf("controller publish volume decode with error: %v", err)
-		return nil, err
-	}
-
 	controllerPublishVolumeResponse := &csi.ControllerPublishVolumeResponse{}
-	err = json.Unmarshal(decodeBytes, controllerPublishVolumeResponse)
-	if err != nil {
-		klog.Errorf("controller publish volume unmarshal with error: %v", err)
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeControllerPublishVolume, volumeID, req, controllerPublishVolumeResponse); err != nil {
 		return nil, err
 	}
 	klog.V(4).Infof("controller publish volume response: %v", controllerPublishVolumeResponse)
@@ -320,70 +156,8 @@ func (cs *controllerServer) ControllerUnpublishVolume(ctx context.Context, req *
 		return nil, status.Error(codes.InvalidArgument, "ControllerUnpublishVolume Instance ID must be provided")
 	}
 
-	// Build message struct
-	resource, err := buildResource(cs.nodeID,
-		DefaultNamespace,
-		constants.CSIResourceTypeVolume,
-		volumeID)
-	if err != nil {
-		klog.Errorf("Build message resource failed with error: %s", err)
-		return nil, err
-	}
-
-	m := jsonpb.Marshaler{}
-	js, err := m.MarshalToString(req)
-	if err != nil {
-		klog.Errorf("failed to marshal to string with error: %s", err)
-		return nil, err
-	}
-	klog.V(4).Infof("controller Unpublish Volume marshal to string: %s", js)
-	msg := model.NewMessage("").
-		BuildRouter(DefaultReceiveModuleName, GroupResource, resource, constants.CSIOperationTypeControllerUnpublishVolume).
-		FillBody(js)
-
-	// Marshal message
-	reqData, err := json.Marshal(msg)
-	if err != nil {
-		klog.Errorf("marshal request failed with error: %v", err)
-		return nil, err
-	}
-
-	// Send message to KubeEdge
-	resdata, err := sendToKubeEdge(string(reqData), cs.kubeEdgeEndpoint)
-	if err != nil {
-		klog.Errorf("send to kubeedge failed with error: %v", err)
-		return nil, err
-	}
-
-	// Unmarshal message
-	result, err := extractMessage(resdata)
-	if err != nil {
-		klog.Errorf("unmarshal response failed with error: %v", err)
-		return nil, err
-	}
-
-	klog.V(4).Infof("controller Unpublish Volume result: %v", result)
-	data, ok := result.GetContent().(string)
-	if !ok {
-		klog.Errorf("content is not string type: %v", result.GetContent())
-		return nil, fmt.Errorf("content type %T is not string", result.GetContent())
-	}
-
-	if msg.GetOperation() == model.ResponseErrorOperation {
-		klog.Errorf("controller Unpublish Volume with error: %s", data)
-		return nil, errors.New(data)
-	}
-
-	decodeBytes, err := base64.StdEncoding.DecodeString(data)
-	if err != nil {
-		klog.Errorf("controller Unpublish Volume decode with error: %v", err)
-		return nil, err
-	}
-
 	controllerUnpublishVolumeResponse := &csi.ControllerUnpublishVolumeResponse{}
-	err = json.Unmarshal(decodeBytes, controllerUnpublishVolumeResponse)
-	if err != nil {
-		klog.Errorf("controller Unpublish Volume unmarshal with error: %v", err)
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeControllerUnpublishVolume, volumeID, req, controllerUnpublishVolumeResponse); err != nil {
 		return nil, err
 	}
 	klog.V(4).Infof("controller Unpublish Volume response: %v", controllerUnpublishVolumeResponse)
@@ -414,12 +188,45 @@ func (cs *controllerServer) ValidateVolumeCapabilities(ctx context.Context, req
 	}, nil
 }
 
+// ControllerGetCapabilities returns the capabilities reported by the CSI driver on the edge node
+// whose RPCs are forwarded. The capabilities are cached for controllerCapabilitiesTTL, and the last
+// reported capabilities are returned if the edge node fails to report them.
 func (cs *controllerServer) ControllerGetCapabilities(ctx context.Context, req *csi.ControllerGetCapabilitiesRequest) (*csi.ControllerGetCapabilitiesResponse, error) {
+	cs.capsLock.RLock()
+	caps, expire := cs.caps, cs.capsExpire
+	cs.capsLock.RUnlock()
+	if time.Now().Before(expire) {
+		return &csi.ControllerGetCapabilitiesResponse{Capabilities: caps}, nil
+	}
+
+	response := &csi.ControllerGetCapabilitiesResponse{}
+	err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeControllerGetCapabilities, uuid.New().String(), req, response)
+
+	cs.capsLock.Lock()
+	defer cs.capsLock.Unlock()
+	if err != nil {
+		klog.Warningf("failed to get controller capabilities of driver %s from edge node %s, use the last capabilities: %v",
+			cs.driverName, cs.nodeID, err)
+	} else {
+		cs.caps = filterControllerCapabilities(response.GetCapabilities())
+		cs.capsExpire = time.Now().Add(controllerCapabilitiesTTL)
+	}
 	return &csi.ControllerGetCapabilitiesResponse{
 		Capabilities: cs.caps,
 	}, nil
 }
 
+// filterControllerCapabilities drops the capabilities whose RPCs are not forwarded to the edge node
+func filterControllerCapabilities(caps []*csi.ControllerServiceCapability) []*csi.ControllerServiceCapability {
+	var filtered []*csi.ControllerServiceCapability
+	for _, c := range caps {
+		if forwardedControllerCapabilities[c.GetRpc().GetType()] {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
 func getControllerServiceCapabilities(cl []csi.ControllerServiceCapability_RPC_Type) []*csi.ControllerServiceCapability {
 	var csc []*csi.ControllerServiceCapability
 
@@ -437,28 +244,82 @@ func getControllerServiceCapabilities(cl []csi.ControllerServiceCapability_RPC_T
 	return csc
 }
 
+// GetCapacity issues get capacity func
 func (cs *controllerServer) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "")
+	getCapacityResponse := &csi.GetCapacityResponse{}
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeGetCapacity, uuid.New().String(), req, getCapacityResponse); err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("get capacity response: %v", getCapacityResponse)
+	return getCapacityResponse, nil
 }
 
+// ListVolumes issues list volumes func
 func (cs *controllerServer) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "")
+	listVolumesResponse := &csi.ListVolumesResponse{}
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeListVolumes, uuid.New().String(), req, listVolumesResponse); err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("list volumes response: %v", listVolumesResponse)
+	return listVolumesResponse, nil
 }
 
+// ControllerExpandVolume issues controller expand volume func
 func (cs *controllerServer) ControllerExpandVolume(ctx context.Context, req *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "ControllerExpandVolume is not yet implemented")
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+	if req.GetCapacityRange() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Capacity range missing in request")
+	}
+
+	controllerExpandVolumeResponse := &csi.ControllerExpandVolumeResponse{}
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeControllerExpandVolume, req.GetVolumeId(), req, controllerExpandVolumeResponse); err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("controller expand volume response: %v", controllerExpandVolumeResponse)
+	return controllerExpandVolumeResponse, nil
 }
 
+// CreateSnapshot issues create snapshot func
 func (cs *controllerServer) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "CreateSnapshot is not yet implemented")
+	if len(req.GetName()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Name missing in request")
+	}
+	if len(req.GetSourceVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Source Volume ID missing in request")
+	}
+
+	createSnapshotResponse := &csi.CreateSnapshotResponse{}
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeCreateSnapshot, req.GetSourceVolumeId(), req, createSnapshotResponse); err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("create snapshot response: %v", createSnapshotResponse)
+	return createSnapshotResponse, nil
 }
 
+// DeleteSnapshot issues delete snapshot func
 func (cs *controllerServer) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "DeleteSnapshot is not yet implemented")
+	if len(req.GetSnapshotId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot ID missing in request")
+	}
+
+	deleteSnapshotResponse := &csi.DeleteSnapshotResponse{}
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeDeleteSnapshot, req.GetSnapshotId(), req, deleteSnapshotResponse); err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("delete snapshot response: %v", deleteSnapshotResponse)
+	return deleteSnapshotResponse, nil
 }
 
+// ListSnapshots issues list snapshots func
 func (cs *controllerServer) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "ListSnapshots is not yet implemented")
+	listSnapshotsResponse := &csi.ListSnapshotsResponse{}
+	if err := sendToEdge(cs.nodeID, cs.driverName, cs.kubeEdgeEndpoint, constants.CSIOperationTypeListSnapshots, uuid.New().String(), req, listSnapshotsResponse); err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("list snapshots response: %v", listSnapshotsResponse)
+	return listSnapshotsResponse, nil
 }
 
 func (cs *controllerServer) ControllerGetVolume(context.Context, *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csidriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"golang.org/x/net/context"
+)
+
+func TestFilterControllerCapabilities(t *testing.T) {
+	caps := filterControllerCapabilities(getControllerServiceCapabilities([]csi.ControllerServiceCapability_RPC_Type{
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+		csi.ControllerServiceCapability_RPC_UNKNOWN,
+	}))
+	if len(caps) != 2 ||
+		caps[0].GetRpc().GetType() != csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME ||
+		caps[1].GetRpc().GetType() != csi.ControllerServiceCapability_RPC_EXPAND_VOLUME {
+		t.Errorf("expected only the forwarded capabilities, but got %v", caps)
+	}
+}
+
+func TestControllerGetCapabilitiesCached(t *testing.T) {
+	// the kubeedge endpoint is unreachable, so the capabilities must come from the cache
+	cs := newControllerServer("csi-hostpath", "edge-node", "unix:///nonexistent.sock")
+	cs.caps = getControllerServiceCapabilities([]csi.ControllerServiceCapability_RPC_Type{
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+	})
+	cs.capsExpire = time.Now().Add(time.Minute)
+
+	resp, err := cs.ControllerGetCapabilities(context.Background(), &csi.ControllerGetCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Capabilities) != 1 || resp.Capabilities[0].GetRpc().GetType() != csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS {
+		t.Errorf("expected the cached capabilities, but got %v", resp.Capabilities)
+	}
+}
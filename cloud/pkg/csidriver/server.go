@@ -57,7 +57,7 @@ func (cd *CSIDriver) Run() {
 
 	// Create GRPC servers
 	cd.ids = newIdentityServer(cd.DriverName, cd.Version)
-	cd.cs = newControllerServer(cd.NodeID, cd.KubeEdgeEndpoint)
+	cd.cs = newControllerServer(cd.DriverName, cd.NodeID, cd.KubeEdgeEndpoint)
 
 	s := newNonBlockingGRPCServer()
 	s.Start(cd.Endpoint, cd.ids, cd.cs, nil)
@@ -17,6 +17,8 @@ limitations under the License.
 package csidriver
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -26,6 +28,8 @@ import (
 	"sync"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
 	"github.com/kubernetes-csi/csi-lib-utils/protosanitizer"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -181,3 +185,78 @@ func extractMessage(context string) (*model.Message, error) {
 
 	return &msg, nil
 }
+
+// sendToEdge sends the CSI request to the edge node through KubeEdge, and unmarshals
+// the response of the CSI driver on the edge node into resp. The namespace of the resource
+// is the name of the CSI driver, so that the edge node sends the request to the same driver.
+func sendToEdge(nodeID, driverName, kubeEdgeEndpoint, operation, resourceID string, req, resp proto.Message) error {
+	// Build message struct
+	resource, err := buildResource(nodeID, driverName, constants.CSIResourceTypeVolume, resourceID)
+	if err != nil {
+		klog.Errorf("build message resource failed with error: %s", err)
+		return err
+	}
+
+	m := jsonpb.Marshaler{}
+	js, err := m.MarshalToString(req)
+	if err != nil {
+		klog.Errorf("failed to marshal to string with error: %s", err)
+		return err
+	}
+	klog.V(4).Infof("%s marshal to string: %s", operation, js)
+	msg := model.NewMessage("").
+		BuildRouter(DefaultReceiveModuleName, GroupResource, resource, operation).
+		FillBody(js)
+
+	// Marshal message
+	reqData, err := json.Marshal(msg)
+	if err != nil {
+		klog.Errorf("marshal request failed with error: %v", err)
+		return err
+	}
+
+	// Send message to KubeEdge
+	resdata, err := sendToKubeEdge(string(reqData), kubeEdgeEndpoint)
+	if err != nil {
+		klog.Errorf("send to kubeedge failed with error: %v", err)
+		return err
+	}
+
+	// Unmarshal message
+	result, err := extractMessage(resdata)
+	if err != nil {
+		klog.Errorf("unmarshal response failed with error: %v", err)
+		return err
+	}
+
+	klog.V(4).Infof("%s result: %v", operation, result)
+	data, ok := result.GetContent().(string)
+	if !ok {
+		klog.Errorf("content is not string type: %v", result.GetContent())
+		return fmt.Errorf("content type %T is not string", result.GetContent())
+	}
+
+	if result.GetOperation() == model.ResponseErrorOperation {
+		// the errors from edge are encoded as bytes, while the errors from cloudhub are not
+		if decodeBytes, err := base64.StdEncoding.DecodeString(data); err == nil {
+			data = string(decodeBytes)
+		}
+		klog.Errorf("%s with error: %s", operation, data)
+		return errors.New(data)
+	}
+
+	decodeBytes, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		klog.Errorf("%s decode with error: %v", operation, err)
+		return err
+	}
+
+	// the responses of the operations added later are marshaled by jsonpb on edge, and jsonpb
+	// can unmarshal the responses of the other operations marshaled by encoding/json as well
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err := unmarshaler.Unmarshal(bytes.NewReader(decodeBytes), resp); err != nil {
+		klog.Errorf("%s unmarshal with error: %v", operation, err)
+		return err
+	}
+	return nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/manager"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
@@ -266,9 +267,12 @@ func (dc *DownstreamController) sendDeviceMsg(device *v1beta1.Device, operation
 
 	err = dc.messageLayer.Send(*modelMsg)
 	if err != nil {
+		monitor.DeviceTwinSyncs.WithLabelValues(monitor.DirectionDownstream, monitor.ResultFailure).Inc()
 		klog.Errorf("Failed to send device addition message %v, device: %s, operation: %s, error: %v",
 			modelMsg, device.Name, operation, err)
+		return
 	}
+	monitor.DeviceTwinSyncs.WithLabelValues(monitor.DirectionDownstream, monitor.ResultSuccess).Inc()
 }
 
 func (dc *DownstreamController) sendDeviceModelMsg(device *v1beta1.Device, operation string) {
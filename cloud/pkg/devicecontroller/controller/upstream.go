@@ -28,6 +28,7 @@ import (
 	keclient "github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
@@ -163,9 +164,11 @@ func (uc *UpstreamController) updateDeviceStatus() {
 			}
 			err = uc.crdClient.DevicesV1beta1().RESTClient().Patch(MergePatchType).Namespace(cacheDevice.Namespace).Resource(ResourceTypeDevices).Name(deviceID).Body(body).Do(context.Background()).Error()
 			if err != nil {
+				monitor.DeviceTwinSyncs.WithLabelValues(monitor.DirectionUpstream, monitor.ResultFailure).Inc()
 				klog.Errorf("Failed to patch device status %v of device %v in namespace %v, err: %v", deviceStatus, deviceID, cacheDevice.Namespace, err)
 				continue
 			}
+			monitor.DeviceTwinSyncs.WithLabelValues(monitor.DirectionUpstream, monitor.ResultSuccess).Inc()
 			//send confirm message to edge twin
 			resMsg := model.NewMessage(msg.GetID())
 			nodeID, err := messagelayer.GetNodeID(msg)
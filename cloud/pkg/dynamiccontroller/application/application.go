@@ -3,18 +3,22 @@ package application
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/audit"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
@@ -24,6 +28,15 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/util/pass-through"
 )
 
+// passThroughMethods translates the application verb to the http method of pass through request
+var passThroughMethods = map[metaserver.ApplicationVerb]string{
+	metaserver.Get:    http.MethodGet,
+	metaserver.Create: http.MethodPost,
+	metaserver.Update: http.MethodPut,
+	metaserver.Patch:  http.MethodPatch,
+	metaserver.Delete: http.MethodDelete,
+}
+
 type Center struct {
 	HandlerCenter
 	messageLayer  messagelayer.MessageLayer
@@ -51,11 +64,13 @@ func (c *Center) Process(msg model.Message) {
 		return
 	}
 
+	received := time.Now()
 	app, err := metaserver.MsgToApplication(msg)
 	if err != nil {
 		klog.Errorf("failed to translate msg to Application: %v", err)
 		return
 	}
+	defer audit.LogApplication(app, received)
 
 	klog.Infof("[metaserver/ApplicationCenter] get a Application %v", app.String())
 
@@ -107,6 +122,10 @@ func (c *Center) ProcessApplication(app *metaserver.Application) (interface{}, e
 		}
 
 		if err := c.HandlerCenter.AddListener(listener); err != nil {
+			// keep the status error, e.g. of the subscription quota, for the client on edge
+			if _, ok := err.(apierrors.APIStatus); ok {
+				return nil, err
+			}
 			return nil, fmt.Errorf("failed to add listener, %v", err)
 		}
 		return nil, nil
@@ -199,12 +218,26 @@ func (c *Center) ProcessApplication(app *metaserver.Application) (interface{}, e
 }
 
 func (c *Center) passThroughRequest(app *metaserver.Application) (interface{}, error) {
+	// the request is sent with the credentials of cloudcore, so the cleaned path
+	// that is checked against the allow-list is exactly the one requested
+	key := passthrough.CleanPath(app.Key)
+	if !passthrough.IsPassThroughPath(key, string(app.Verb)) {
+		return nil, apierrors.NewForbidden(schema.GroupResource{}, app.Key,
+			fmt.Errorf("request[%s::%s] is not allowed to pass through", app.Key, app.Verb))
+	}
 	kubeClient, ok := c.kubeClient.(*kubernetes.Clientset)
 	if !ok {
 		return nil, fmt.Errorf("converting kubeClient to *kubernetes.Clientset type failed")
 	}
-	verb := strings.ToUpper(string(app.Verb))
-	return kubeClient.RESTClient().Verb(verb).AbsPath(app.Key).Body(app.ReqBody).Do(context.TODO()).Raw()
+	method, ok := passThroughMethods[app.Verb]
+	if !ok {
+		return nil, fmt.Errorf("unsupported pass through verb %v", app.Verb)
+	}
+	req := kubeClient.RESTClient().Verb(method).AbsPath(key)
+	if len(app.ReqBody) > 0 {
+		req = req.Body(app.ReqBody)
+	}
+	return req.Do(context.TODO()).Raw()
 }
 
 // Response update application, generate and send resp message to edge
@@ -321,6 +354,9 @@ func (c *Center) processWatchApp(watchApp *metaserver.Application) error {
 	}
 
 	if err := c.HandlerCenter.AddListener(listener); err != nil {
+		if _, ok := err.(apierrors.APIStatus); ok {
+			return err
+		}
 		return fmt.Errorf("failed to add listener, %v", err)
 	}
 
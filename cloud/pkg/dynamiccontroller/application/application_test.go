@@ -64,6 +64,22 @@ func TestCenter_passThroughRequest(t *testing.T) {
 			},
 			want:    []byte{},
 			wantErr: true,
+		}, {
+			name: "resource request is not allowed to pass through",
+			app: &metaserver.Application{
+				Key:  "/api/v1/namespaces/kube-system/secrets",
+				Verb: "get",
+			},
+			want:    nil,
+			wantErr: true,
+		}, {
+			name: "path escaping the allow-list is not allowed to pass through",
+			app: &metaserver.Application{
+				Key:  "/openapi/../api/v1/secrets",
+				Verb: "get",
+			},
+			want:    nil,
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -55,7 +55,7 @@ func NewHandlerCenter(informerFactory dynamicinformer.DynamicSharedInformerFacto
 	handlers := make(map[schema.GroupVersionResource]*CommonResourceEventHandler)
 
 	c := handlerCenter{
-		listenerManager:              newListenerManager(),
+		listenerManager:              newListenerManager(newSubscriptionRegistry(subscriptionConfig(), getNodeLabels)),
 		handlers:                     handlers,
 		dynamicSharedInformerFactory: informerFactory,
 		messageLayer:                 messagelayer.DynamicControllerMessageLayer(),
@@ -159,6 +159,11 @@ func (c *CommonResourceEventHandler) objToEvent(t watch.EventType, obj interface
 }
 
 func (c *CommonResourceEventHandler) AddListener(s *SelectorListener) error {
+	// check the subscription before listing, so that an over-quota listener does not load the cloud
+	if err := c.listenerManager.CheckSubscription(s); err != nil {
+		return err
+	}
+
 	// filter s.selector.field when sendAllObjects
 	ret, err := c.informer.Lister.List(s.selector.Label)
 	if err != nil {
@@ -166,9 +171,7 @@ func (c *CommonResourceEventHandler) AddListener(s *SelectorListener) error {
 	}
 	s.sendAllObjects(ret, c)
 
-	c.listenerManager.AddListener(s)
-
-	return nil
+	return c.listenerManager.AddListener(s)
 }
 
 func (c *CommonResourceEventHandler) DeleteListener(s *SelectorListener) {
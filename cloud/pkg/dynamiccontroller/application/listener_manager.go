@@ -17,10 +17,14 @@ limitations under the License.
 package application
 
 import (
+	"fmt"
 	"sync"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 )
 
 type listenerManager struct {
@@ -34,25 +38,80 @@ type listenerManager struct {
 	// first key: GVR
 	// second key: listenerID
 	listenerByGVR map[schema.GroupVersionResource]map[string]*SelectorListener
+
+	// subscriptions limits the listeners of each node, nil means no limit
+	subscriptions *subscriptionRegistry
 }
 
-func newListenerManager() *listenerManager {
+func newListenerManager(subscriptions *subscriptionRegistry) *listenerManager {
 	return &listenerManager{
 		listenerByNodeID: make(map[string]map[string]*SelectorListener),
 		listenerByGVR:    make(map[schema.GroupVersionResource]map[string]*SelectorListener),
+		subscriptions:    subscriptions,
+	}
+}
+
+// CheckSubscription returns an error if the node of the listener has not subscribed to
+// its resource, or has registered as many listeners for the resource as it may.
+func (lm *listenerManager) CheckSubscription(listener *SelectorListener) error {
+	lm.lock.RLock()
+	defer lm.lock.RUnlock()
+
+	return lm.checkSubscription(listener)
+}
+
+func (lm *listenerManager) checkSubscription(listener *SelectorListener) error {
+	if lm.subscriptions == nil {
+		return nil
+	}
+	gr := listener.gvr.GroupResource()
+	limit, err := lm.subscriptions.limit(listener.nodeName, gr)
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			monitor.DynamicControllerRejectedListeners.WithLabelValues(gr.String()).Inc()
+		}
+		return err
+	}
+	if limit == noLimit {
+		return nil
+	}
+
+	count := 0
+	for id, l := range lm.listenerByNodeID[listener.nodeName] {
+		if id == listener.id {
+			// the listener is registered already
+			return nil
+		}
+		if l.gvr.GroupResource() == gr {
+			count++
+		}
+	}
+	if count >= limit {
+		monitor.DynamicControllerRejectedListeners.WithLabelValues(gr.String()).Inc()
+		return apierrors.NewForbidden(gr, "", fmt.Errorf("node %s has exceeded its subscription quota of %d watches on %s",
+			listener.nodeName, limit, gr.String()))
 	}
+	return nil
 }
 
-func (lm *listenerManager) AddListener(listener *SelectorListener) {
+// AddListener registers the listener if its node has not exceeded the subscription quota
+func (lm *listenerManager) AddListener(listener *SelectorListener) error {
 	lm.lock.Lock()
 	defer lm.lock.Unlock()
 
+	if err := lm.checkSubscription(listener); err != nil {
+		return err
+	}
+
 	klog.Infof("add listener %s node %s", listener.id, listener.nodeName)
 
 	_, exists := lm.listenerByNodeID[listener.nodeName]
 	if !exists {
 		lm.listenerByNodeID[listener.nodeName] = map[string]*SelectorListener{}
 	}
+	if _, exists = lm.listenerByNodeID[listener.nodeName][listener.id]; !exists {
+		monitor.DynamicControllerListeners.WithLabelValues(listener.gvr.GroupResource().String()).Inc()
+	}
 	lm.listenerByNodeID[listener.nodeName][listener.id] = listener
 
 	_, exists = lm.listenerByGVR[listener.gvr]
@@ -60,6 +119,7 @@ func (lm *listenerManager) AddListener(listener *SelectorListener) {
 		lm.listenerByGVR[listener.gvr] = map[string]*SelectorListener{}
 	}
 	lm.listenerByGVR[listener.gvr][listener.id] = listener
+	return nil
 }
 
 func (lm *listenerManager) DeleteListener(listener *SelectorListener) {
@@ -68,6 +128,9 @@ func (lm *listenerManager) DeleteListener(listener *SelectorListener) {
 
 	listeners, exists := lm.listenerByNodeID[listener.nodeName]
 	if exists {
+		if _, ok := listeners[listener.id]; ok {
+			monitor.DynamicControllerListeners.WithLabelValues(listener.gvr.GroupResource().String()).Dec()
+		}
 		delete(listeners, listener.id)
 		if len(lm.listenerByNodeID[listener.nodeName]) == 0 {
 			delete(lm.listenerByNodeID, listener.nodeName)
@@ -20,7 +20,10 @@ import (
 	"reflect"
 	"testing"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	configv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
 )
 
 var testGVR = schema.GroupVersionResource{
@@ -36,10 +39,14 @@ func TestAddGetListener(t *testing.T) {
 	listener1 := NewSelectorListener("testID1", "node1", testGVR, selector1)
 	listener2 := NewSelectorListener("testID2", "node2", testGVR, selector2)
 
-	lm := newListenerManager()
+	lm := newListenerManager(nil)
 
-	lm.AddListener(listener1)
-	lm.AddListener(listener2)
+	if err := lm.AddListener(listener1); err != nil {
+		t.Fatalf("add listener1 err: %v", err)
+	}
+	if err := lm.AddListener(listener2); err != nil {
+		t.Fatalf("add listener2 err: %v", err)
+	}
 
 	listenerByNodeID := lm.GetListenersForNode("node1")
 	if len(listenerByNodeID) != 1 {
@@ -71,10 +78,14 @@ func TestDeleteListener(t *testing.T) {
 	listener1 := NewSelectorListener("testID1", "node1", testGVR, selector1)
 	listener2 := NewSelectorListener("testID2", "node2", testGVR, selector2)
 
-	lm := newListenerManager()
+	lm := newListenerManager(nil)
 
-	lm.AddListener(listener1)
-	lm.AddListener(listener2)
+	if err := lm.AddListener(listener1); err != nil {
+		t.Fatalf("add listener1 err: %v", err)
+	}
+	if err := lm.AddListener(listener2); err != nil {
+		t.Fatalf("add listener2 err: %v", err)
+	}
 
 	lm.DeleteListener(listener1)
 	listenerByNodeID := lm.GetListenersForNode("node1")
@@ -88,3 +99,91 @@ func TestDeleteListener(t *testing.T) {
 		t.Errorf("listenerByGVR expected length 0. but got %v", len(listenerByNodeID))
 	}
 }
+
+func TestAddListenerWithSubscription(t *testing.T) {
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	labels := map[string]map[string]string{
+		"node1": {"subscription.kubeedge.io/deployments.apps": "2"},
+		"node2": {"subscription.kubeedge.io/deployments.apps": "10"},
+		"node3": {"subscription.kubeedge.io/deployments.apps": "many"},
+	}
+	registry := newSubscriptionRegistry(&configv1alpha1.DynamicControllerSubscription{
+		Enable:                   true,
+		DefaultResources:         []string{"pods"},
+		Limits:                   map[string]int32{"deployments.apps": 3},
+		DefaultMaxWatchesPerNode: 1,
+	}, func(nodeName string) (map[string]string, error) {
+		return labels[nodeName], nil
+	})
+	lm := newListenerManager(registry)
+
+	cases := []struct {
+		name      string
+		listener  *SelectorListener
+		forbidden bool
+	}{
+		{
+			name:     "default resource",
+			listener: NewSelectorListener("pods1", "node1", testGVR, selector1),
+		},
+		{
+			name:      "default resource over the default limit",
+			listener:  NewSelectorListener("pods2", "node1", testGVR, selector2),
+			forbidden: true,
+		},
+		{
+			name:     "listener registered already",
+			listener: NewSelectorListener("pods1", "node1", testGVR, selector1),
+		},
+		{
+			name:     "declared resource",
+			listener: NewSelectorListener("deploy1", "node1", deployments, selector1),
+		},
+		{
+			name:     "declared resource of another version",
+			listener: NewSelectorListener("deploy2", "node1", schema.GroupVersionResource{Group: "apps", Version: "v1beta1", Resource: "deployments"}, selector1),
+		},
+		{
+			name:      "declared resource over the declaration",
+			listener:  NewSelectorListener("deploy3", "node1", deployments, selector1),
+			forbidden: true,
+		},
+		{
+			name:     "declared resource under the admin limit",
+			listener: NewSelectorListener("deploy4", "node2", deployments, selector1),
+		},
+		{
+			name:     "declared resource under the admin limit again",
+			listener: NewSelectorListener("deploy6", "node2", deployments, selector2),
+		},
+		{
+			name:     "declared resource at the admin limit",
+			listener: NewSelectorListener("deploy7", "node2", deployments, selector2),
+		},
+		{
+			name:      "declared resource over the admin limit",
+			listener:  NewSelectorListener("deploy8", "node2", deployments, selector2),
+			forbidden: true,
+		},
+		{
+			name:      "undeclared resource",
+			listener:  NewSelectorListener("daemonsets", "node1", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, selector1),
+			forbidden: true,
+		},
+		{
+			name:      "invalid declaration",
+			listener:  NewSelectorListener("deploy5", "node3", deployments, selector1),
+			forbidden: true,
+		},
+	}
+	for _, c := range cases {
+		err := lm.AddListener(c.listener)
+		if c.forbidden != apierrors.IsForbidden(err) || (!c.forbidden && err != nil) {
+			t.Errorf("%s: expected forbidden %v, but got err %v", c.name, c.forbidden, err)
+		}
+	}
+
+	if len(lm.GetListenersForNode("node1")) != 3 {
+		t.Errorf("expected 3 listeners of node1, but got %v", lm.GetListenersForNode("node1"))
+	}
+}
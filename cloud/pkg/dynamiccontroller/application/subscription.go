@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	genericinformers "github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/dynamiccontroller/config"
+	"github.com/kubeedge/kubeedge/common/constants"
+	configv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+// noLimit means that a node may register any number of listeners for a resource
+const noLimit = -1
+
+// subscriptionRegistry decides how many listeners each edge node may register for a resource,
+// by the declaration of the node and the limits of cluster admin.
+type subscriptionRegistry struct {
+	defaultResources map[schema.GroupResource]bool
+	limits           map[schema.GroupResource]int
+	defaultLimit     int
+	// nodeLabels gets the labels of the node, which declare the resources it subscribes
+	nodeLabels func(nodeName string) (map[string]string, error)
+}
+
+// newSubscriptionRegistry returns nil if the subscription is not enabled,
+// which means that all the listeners are allowed.
+func newSubscriptionRegistry(s *configv1alpha1.DynamicControllerSubscription,
+	nodeLabels func(nodeName string) (map[string]string, error)) *subscriptionRegistry {
+	if s == nil || !s.Enable {
+		return nil
+	}
+
+	r := &subscriptionRegistry{
+		defaultResources: make(map[schema.GroupResource]bool, len(s.DefaultResources)),
+		limits:           make(map[schema.GroupResource]int, len(s.Limits)),
+		defaultLimit:     toLimit(s.DefaultMaxWatchesPerNode),
+		nodeLabels:       nodeLabels,
+	}
+	for _, resource := range s.DefaultResources {
+		r.defaultResources[schema.ParseGroupResource(resource)] = true
+	}
+	for resource, limit := range s.Limits {
+		r.limits[schema.ParseGroupResource(resource)] = toLimit(limit)
+	}
+	return r
+}
+
+func toLimit(max int32) int {
+	if max <= 0 {
+		return noLimit
+	}
+	return int(max)
+}
+
+// limit returns the max number of listeners the node may register for the resource,
+// or a Forbidden error if the node has not declared the resource.
+func (r *subscriptionRegistry) limit(nodeName string, gr schema.GroupResource) (int, error) {
+	limit, ok := r.limits[gr]
+	if !ok {
+		limit = r.defaultLimit
+	}
+	if r.defaultResources[gr] {
+		return limit, nil
+	}
+
+	labels, err := r.nodeLabels(nodeName)
+	if err != nil {
+		return 0, err
+	}
+	key := constants.SubscriptionLabelPrefix + gr.String()
+	value, ok := labels[key]
+	if !ok {
+		return 0, apierrors.NewForbidden(gr, "", fmt.Errorf("node %s has not subscribed to %s, "+
+			"it must be declared with the node label %s", nodeName, gr.String(), key))
+	}
+	declared, err := strconv.Atoi(value)
+	if err != nil || declared <= 0 {
+		return 0, apierrors.NewForbidden(gr, "", fmt.Errorf("node %s declares an invalid subscription %s=%q, "+
+			"the value must be a positive number of watches", nodeName, key, value))
+	}
+	if limit == noLimit || declared < limit {
+		return declared, nil
+	}
+	return limit, nil
+}
+
+func subscriptionConfig() *configv1alpha1.DynamicControllerSubscription {
+	if config.Config.DynamicController == nil {
+		return nil
+	}
+	return config.Config.DynamicController.Subscription
+}
+
+// getNodeLabels gets the labels of the node from the cache of the informer
+func getNodeLabels(nodeName string) (map[string]string, error) {
+	informerPair, err := genericinformers.GetInformersManager().GetInformerPair(v1.SchemeGroupVersion.WithResource("nodes"))
+	if err != nil {
+		return nil, err
+	}
+	obj, err := informerPair.Lister.Get(nodeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %v", nodeName, err)
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	return accessor.GetLabels(), nil
+}
@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	configv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/pass-through"
 )
 
 var Config Configure
@@ -34,5 +35,6 @@ func InitConfigure(dc *configv1alpha1.DynamicController) {
 		Config = Configure{
 			DynamicController: dc,
 		}
+		passthrough.SetPassThroughPaths(dc.PassThroughPaths)
 	})
 }
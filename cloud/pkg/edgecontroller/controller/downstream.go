@@ -24,6 +24,7 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
 	routerv1 "github.com/kubeedge/kubeedge/pkg/apis/rules/v1"
 	crdinformers "github.com/kubeedge/kubeedge/pkg/client/informers/externalversions"
+	"github.com/kubeedge/kubeedge/pkg/tracing"
 )
 
 // DownstreamController watch kubernetes api server and send change to edge
@@ -85,7 +86,7 @@ func (dc *DownstreamController) syncPod() {
 				klog.Warningf("pod event type: %s unsupported", e.Type)
 				continue
 			}
-			if err := dc.messageLayer.Send(*msg); err != nil {
+			if err := dc.send(msg); err != nil {
 				klog.Warningf("send message failed with error: %s, operation: %s, resource: %s", err, msg.GetOperation(), msg.GetResource())
 			} else {
 				klog.V(4).Infof("send message successfully, operation: %s, resource: %s", msg.GetOperation(), msg.GetResource())
@@ -135,7 +136,7 @@ func (dc *DownstreamController) syncConfigMap() {
 					SetResourceVersion(configMap.ResourceVersion).
 					BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, resource, operation).
 					FillBody(configMap)
-				err = dc.messageLayer.Send(*msg)
+				err = dc.send(msg)
 				if err != nil {
 					klog.Warningf("send message failed with error: %s, operation: %s, resource: %s", err, msg.GetOperation(), msg.GetResource())
 				} else {
@@ -188,7 +189,7 @@ func (dc *DownstreamController) syncSecret() {
 					SetResourceVersion(secret.ResourceVersion).
 					BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, resource, operation).
 					FillBody(secret)
-				err = dc.messageLayer.Send(*msg)
+				err = dc.send(msg)
 				if err != nil {
 					klog.Warningf("send message failed with error: %s, operation: %s, resource: %s", err, msg.GetOperation(), msg.GetResource())
 				} else {
@@ -227,7 +228,7 @@ func (dc *DownstreamController) syncEdgeNodes() {
 				}
 				msg := model.NewMessage("").
 					BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, resource, model.DeleteOperation)
-				err = dc.messageLayer.Send(*msg)
+				err = dc.send(msg)
 				if err != nil {
 					klog.Warningf("send message failed with error: %s, operation: %s, resource: %s", err, msg.GetOperation(), msg.GetResource())
 				} else {
@@ -276,7 +277,7 @@ func (dc *DownstreamController) syncRule() {
 				klog.Warningf("rule event type: %s unsupported", e.Type)
 				continue
 			}
-			if err := dc.messageLayer.Send(*msg); err != nil {
+			if err := dc.send(msg); err != nil {
 				klog.Warningf("send message failed with error: %s, operation: %s, resource: %s. Reason: %v", err, msg.GetOperation(), msg.GetResource(), err)
 			} else {
 				klog.V(4).Infof("send message successfully, operation: %s, resource: %s", msg.GetOperation(), msg.GetResource())
@@ -320,7 +321,7 @@ func (dc *DownstreamController) syncRuleEndpoint() {
 				klog.Warningf("ruleEndpoint event type: %s unsupported", e.Type)
 				continue
 			}
-			if err := dc.messageLayer.Send(*msg); err != nil {
+			if err := dc.send(msg); err != nil {
 				klog.Warningf("send message failed with error: %s, operation: %s, resource: %s", err, msg.GetOperation(), msg.GetResource())
 			} else {
 				klog.V(4).Infof("send message successfully, operation: %s, resource: %s", msg.GetOperation(), msg.GetResource())
@@ -329,6 +330,14 @@ func (dc *DownstreamController) syncRuleEndpoint() {
 	}
 }
 
+// send starts the trace of the message to the edge node, and sends the message to cloudhub
+func (dc *DownstreamController) send(msg *model.Message) error {
+	span := tracing.StartMessageSpan(modules.EdgeControllerModuleName, msg)
+	err := dc.messageLayer.Send(*msg)
+	tracing.EndSpan(span, err)
+	return err
+}
+
 // Start DownstreamController
 func (dc *DownstreamController) Start() error {
 	klog.Info("start downstream controller")
@@ -52,6 +52,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/controller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/types"
@@ -62,6 +63,7 @@ import (
 	rulesv1 "github.com/kubeedge/kubeedge/pkg/apis/rules/v1"
 	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
 	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
+	"github.com/kubeedge/kubeedge/pkg/tracing"
 )
 
 // SortedContainerStatuses define A type to help sort container statuses based on container names.
@@ -213,6 +215,7 @@ func (uc *UpstreamController) dispatchMessage() {
 		}
 
 		klog.V(5).Infof("message: %s, operation type is: %s", msg.GetID(), msg.GetOperation())
+		monitor.UpstreamMessages.WithLabelValues(resourceType, msg.GetOperation()).Inc()
 
 		switch resourceType {
 		case model.ResourceTypeNodeStatus:
@@ -270,6 +273,21 @@ func (uc *UpstreamController) dispatchMessage() {
 	}
 }
 
+// observeUpstreamProcessing starts the span of processing the upstream message, and returns
+// the function to end the span and record the duration of processing
+func observeUpstreamProcessing(msg *model.Message) func() {
+	start := time.Now()
+	span := tracing.StartMessageSpan(modules.EdgeControllerModuleName, msg)
+	return func() {
+		span.End()
+		resourceType, err := messagelayer.GetResourceType(*msg)
+		if err != nil {
+			return
+		}
+		monitor.ObserveSince(monitor.UpstreamProcessingDuration.WithLabelValues(resourceType, msg.GetOperation()), start)
+	}
+}
+
 func (uc *UpstreamController) updateRuleStatus() {
 	for {
 		select {
@@ -277,56 +295,62 @@ func (uc *UpstreamController) updateRuleStatus() {
 			klog.Warning("stop updateRuleStatus")
 			return
 		case msg := <-uc.ruleStatusChan:
-			klog.V(5).Infof("message %s, operation is : %s , and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %s", msg.GetID(), err)
-				continue
-			}
-			ruleID, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %s", msg.GetID(), err)
-				continue
-			}
-			var rule *rulesv1.Rule
-			rule, err = uc.crdClient.RulesV1().Rules(namespace).Get(context.Background(), ruleID, metaV1.GetOptions{})
-			if err != nil {
-				klog.Warningf("message: %s process failure, get rule with error: %s, namespaces: %s name: %s", msg.GetID(), err, namespace, ruleID)
-				continue
-			}
-			content, ok := msg.Content.(routerrule.ExecResult)
-			if !ok {
-				klog.Warningf("message: %s process failure, get rule content with error: %s, namespaces: %s name: %s", msg.GetID(), err, namespace, ruleID)
-				continue
-			}
-			if content.Status == "SUCCESS" {
-				rule.Status.SuccessMessages++
-			}
-			if content.Status == "FAIL" {
-				rule.Status.FailMessages++
-				errSlice := make([]string, 0)
-				rule.Status.Errors = append(errSlice, content.Error.Detail)
-			}
-			newStatus := &rulesv1.RuleStatus{
-				SuccessMessages: rule.Status.SuccessMessages,
-				FailMessages:    rule.Status.FailMessages,
-				Errors:          rule.Status.Errors,
-			}
-			body, err := json.Marshal(newStatus)
-			if err != nil {
-				klog.Warningf("message: %s process failure, content marshal err: %s", msg.GetID(), err)
-				continue
-			}
-			_, err = uc.crdClient.RulesV1().Rules(namespace).Patch(context.Background(), ruleID, controller.MergePatchType, body, metaV1.PatchOptions{})
-			if err != nil {
-				klog.Warningf("message: %s process failure, update ruleStatus failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, namespace, ruleID)
-			} else {
-				klog.Infof("UpdateRulestatus successfully!")
-			}
+			uc.processUpdateRuleStatus(msg)
 		}
 	}
 }
 
+func (uc *UpstreamController) processUpdateRuleStatus(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
+
+	klog.V(5).Infof("message %s, operation is : %s , and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %s", msg.GetID(), err)
+		return
+	}
+	ruleID, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %s", msg.GetID(), err)
+		return
+	}
+	var rule *rulesv1.Rule
+	rule, err = uc.crdClient.RulesV1().Rules(namespace).Get(context.Background(), ruleID, metaV1.GetOptions{})
+	if err != nil {
+		klog.Warningf("message: %s process failure, get rule with error: %s, namespaces: %s name: %s", msg.GetID(), err, namespace, ruleID)
+		return
+	}
+	content, ok := msg.Content.(routerrule.ExecResult)
+	if !ok {
+		klog.Warningf("message: %s process failure, get rule content with error: %s, namespaces: %s name: %s", msg.GetID(), err, namespace, ruleID)
+		return
+	}
+	if content.Status == "SUCCESS" {
+		rule.Status.SuccessMessages++
+	}
+	if content.Status == "FAIL" {
+		rule.Status.FailMessages++
+		errSlice := make([]string, 0)
+		rule.Status.Errors = append(errSlice, content.Error.Detail)
+	}
+	newStatus := &rulesv1.RuleStatus{
+		SuccessMessages: rule.Status.SuccessMessages,
+		FailMessages:    rule.Status.FailMessages,
+		Errors:          rule.Status.Errors,
+	}
+	body, err := json.Marshal(newStatus)
+	if err != nil {
+		klog.Warningf("message: %s process failure, content marshal err: %s", msg.GetID(), err)
+		return
+	}
+	_, err = uc.crdClient.RulesV1().Rules(namespace).Patch(context.Background(), ruleID, controller.MergePatchType, body, metaV1.PatchOptions{})
+	if err != nil {
+		klog.Warningf("message: %s process failure, update ruleStatus failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, namespace, ruleID)
+	} else {
+		klog.Infof("UpdateRulestatus successfully!")
+	}
+}
+
 func (uc *UpstreamController) podStatusResponse(msg model.Message, content interface{}) {
 	resMsg := model.NewMessage(msg.GetID()).
 		FillBody(content).
@@ -346,112 +370,118 @@ func (uc *UpstreamController) updatePodStatus() {
 			klog.Warning("stop updatePodStatus")
 			return
 		case msg := <-uc.podStatusChan:
-			klog.V(5).Infof("message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+			uc.processUpdatePodStatus(msg)
+		}
+	}
+}
 
-			namespace, podStatuses := uc.unmarshalPodStatusMessage(msg)
-			switch msg.GetOperation() {
-			case model.UpdateOperation:
-				for _, podStatus := range podStatuses {
-					getPod, err := uc.kubeClient.CoreV1().Pods(namespace).Get(context.Background(), podStatus.Name, metaV1.GetOptions{})
-					if (err == nil && getPod.UID != podStatus.UID) || errors.IsNotFound(err) {
-						klog.Warningf("message: %s, pod not found, namespace: %s, name: %s", msg.GetID(), namespace, podStatus.Name)
-
-						// send response message to edged
-						uc.podStatusResponse(msg, common.MessageSuccessfulContent)
-
-						// Send request to delete this pod on edge side
-						delMsg := model.NewMessage("")
-						nodeID, err := messagelayer.GetNodeID(msg)
-						if err != nil {
-							klog.Warningf("Get node ID failed with error: %s", err)
-							continue
-						}
-						resource, err := messagelayer.BuildResource(nodeID, namespace, model.ResourceTypePod, podStatus.Name)
-						if err != nil {
-							klog.Warningf("Built message resource failed with error: %s", err)
-							continue
-						}
-						pod := &v1.Pod{}
-						pod.Namespace, pod.Name = namespace, podStatus.Name
-						delMsg.Content = pod
-						delMsg.BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, resource, model.DeleteOperation)
-						if err := uc.messageLayer.Send(*delMsg); err != nil {
-							klog.Warningf("Send message failed with error: %s, operation: %s, resource: %s", err, delMsg.GetOperation(), delMsg.GetResource())
-						} else {
-							klog.V(4).Infof("Send message successfully, operation: %s, resource: %s", delMsg.GetOperation(), delMsg.GetResource())
-						}
+func (uc *UpstreamController) processUpdatePodStatus(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-						continue
-					}
-					if err != nil {
-						uc.podStatusResponse(msg, err)
-						klog.Warningf("message: %s, pod is nil, namespace: %s, name: %s, error: %s", msg.GetID(), namespace, podStatus.Name, err)
-						continue
-					}
-					status := podStatus.Status
-					oldStatus := getPod.Status
-					// Set ReadyCondition.LastTransitionTime
-					if readyCondition := uc.getPodCondition(&status, v1.PodReady); readyCondition != nil {
-						// Need to set LastTransitionTime.
-						lastTransitionTime := metaV1.Now()
-						oldReadyCondition := uc.getPodCondition(&oldStatus, v1.PodReady)
-						if oldReadyCondition != nil && readyCondition.Status == oldReadyCondition.Status {
-							lastTransitionTime = oldReadyCondition.LastTransitionTime
-						}
-						readyCondition.LastTransitionTime = lastTransitionTime
-					}
+	klog.V(5).Infof("message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 
-					// Set InitializedCondition.LastTransitionTime.
-					if initCondition := uc.getPodCondition(&status, v1.PodInitialized); initCondition != nil {
-						// Need to set LastTransitionTime.
-						lastTransitionTime := metaV1.Now()
-						oldInitCondition := uc.getPodCondition(&oldStatus, v1.PodInitialized)
-						if oldInitCondition != nil && initCondition.Status == oldInitCondition.Status {
-							lastTransitionTime = oldInitCondition.LastTransitionTime
-						}
-						initCondition.LastTransitionTime = lastTransitionTime
-					}
+	namespace, podStatuses := uc.unmarshalPodStatusMessage(msg)
+	switch msg.GetOperation() {
+	case model.UpdateOperation:
+		for _, podStatus := range podStatuses {
+			getPod, err := uc.kubeClient.CoreV1().Pods(namespace).Get(context.Background(), podStatus.Name, metaV1.GetOptions{})
+			if (err == nil && getPod.UID != podStatus.UID) || errors.IsNotFound(err) {
+				klog.Warningf("message: %s, pod not found, namespace: %s, name: %s", msg.GetID(), namespace, podStatus.Name)
 
-					// ensure that the start time does not change across updates.
-					if oldStatus.StartTime != nil && !oldStatus.StartTime.IsZero() {
-						status.StartTime = oldStatus.StartTime
-					} else if status.StartTime.IsZero() {
-						// if the status has no start time, we need to set an initial time
-						now := metaV1.Now()
-						status.StartTime = &now
-					}
+				// send response message to edged
+				uc.podStatusResponse(msg, common.MessageSuccessfulContent)
 
-					uc.normalizePodStatus(getPod, &status)
-					getPod.Status = status
-
-					if updatedPod, err := uc.kubeClient.CoreV1().Pods(getPod.Namespace).UpdateStatus(context.Background(), getPod, metaV1.UpdateOptions{}); err != nil {
-						uc.podStatusResponse(msg, err)
-						klog.Warningf("message: %s, update pod status failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, getPod.Namespace, getPod.Name)
-					} else {
-						klog.V(5).Infof("message: %s, update pod status successfully, namespace: %s, name: %s", msg.GetID(), updatedPod.Namespace, updatedPod.Name)
-
-						// send response message to edged
-						uc.podStatusResponse(msg, common.MessageSuccessfulContent)
-
-						if updatedPod.DeletionTimestamp != nil && (status.Phase == v1.PodSucceeded || status.Phase == v1.PodFailed) {
-							if uc.isPodNotRunning(status.ContainerStatuses) {
-								if err := uc.kubeClient.CoreV1().Pods(updatedPod.Namespace).Delete(context.Background(), updatedPod.Name, *metaV1.NewDeleteOptions(0)); err != nil {
-									klog.Warningf("message: %s, graceful delete pod failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, updatedPod.Namespace, updatedPod.Name)
-								} else {
-									klog.Infof("message: %s, pod delete successfully, namespace: %s, name: %s", msg.GetID(), updatedPod.Namespace, updatedPod.Name)
-								}
-							}
-						}
-					}
+				// Send request to delete this pod on edge side
+				delMsg := model.NewMessage("")
+				nodeID, err := messagelayer.GetNodeID(msg)
+				if err != nil {
+					klog.Warningf("Get node ID failed with error: %s", err)
+					continue
+				}
+				resource, err := messagelayer.BuildResource(nodeID, namespace, model.ResourceTypePod, podStatus.Name)
+				if err != nil {
+					klog.Warningf("Built message resource failed with error: %s", err)
+					continue
+				}
+				pod := &v1.Pod{}
+				pod.Namespace, pod.Name = namespace, podStatus.Name
+				delMsg.Content = pod
+				delMsg.BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, resource, model.DeleteOperation)
+				if err := uc.messageLayer.Send(*delMsg); err != nil {
+					klog.Warningf("Send message failed with error: %s, operation: %s, resource: %s", err, delMsg.GetOperation(), delMsg.GetResource())
+				} else {
+					klog.V(4).Infof("Send message successfully, operation: %s, resource: %s", delMsg.GetOperation(), delMsg.GetResource())
 				}
 
-			default:
-				klog.Warningf("message: %s process failure, pod status operation: %s unsupported", msg.GetID(), msg.GetOperation())
 				continue
 			}
-			klog.V(4).Infof("message: %s process successfully", msg.GetID())
+			if err != nil {
+				uc.podStatusResponse(msg, err)
+				klog.Warningf("message: %s, pod is nil, namespace: %s, name: %s, error: %s", msg.GetID(), namespace, podStatus.Name, err)
+				continue
+			}
+			status := podStatus.Status
+			oldStatus := getPod.Status
+			// Set ReadyCondition.LastTransitionTime
+			if readyCondition := uc.getPodCondition(&status, v1.PodReady); readyCondition != nil {
+				// Need to set LastTransitionTime.
+				lastTransitionTime := metaV1.Now()
+				oldReadyCondition := uc.getPodCondition(&oldStatus, v1.PodReady)
+				if oldReadyCondition != nil && readyCondition.Status == oldReadyCondition.Status {
+					lastTransitionTime = oldReadyCondition.LastTransitionTime
+				}
+				readyCondition.LastTransitionTime = lastTransitionTime
+			}
+
+			// Set InitializedCondition.LastTransitionTime.
+			if initCondition := uc.getPodCondition(&status, v1.PodInitialized); initCondition != nil {
+				// Need to set LastTransitionTime.
+				lastTransitionTime := metaV1.Now()
+				oldInitCondition := uc.getPodCondition(&oldStatus, v1.PodInitialized)
+				if oldInitCondition != nil && initCondition.Status == oldInitCondition.Status {
+					lastTransitionTime = oldInitCondition.LastTransitionTime
+				}
+				initCondition.LastTransitionTime = lastTransitionTime
+			}
+
+			// ensure that the start time does not change across updates.
+			if oldStatus.StartTime != nil && !oldStatus.StartTime.IsZero() {
+				status.StartTime = oldStatus.StartTime
+			} else if status.StartTime.IsZero() {
+				// if the status has no start time, we need to set an initial time
+				now := metaV1.Now()
+				status.StartTime = &now
+			}
+
+			uc.normalizePodStatus(getPod, &status)
+			getPod.Status = status
+
+			if updatedPod, err := uc.kubeClient.CoreV1().Pods(getPod.Namespace).UpdateStatus(context.Background(), getPod, metaV1.UpdateOptions{}); err != nil {
+				uc.podStatusResponse(msg, err)
+				klog.Warningf("message: %s, update pod status failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, getPod.Namespace, getPod.Name)
+			} else {
+				klog.V(5).Infof("message: %s, update pod status successfully, namespace: %s, name: %s", msg.GetID(), updatedPod.Namespace, updatedPod.Name)
+
+				// send response message to edged
+				uc.podStatusResponse(msg, common.MessageSuccessfulContent)
+
+				if updatedPod.DeletionTimestamp != nil && (status.Phase == v1.PodSucceeded || status.Phase == v1.PodFailed) {
+					if uc.isPodNotRunning(status.ContainerStatuses) {
+						if err := uc.kubeClient.CoreV1().Pods(updatedPod.Namespace).Delete(context.Background(), updatedPod.Name, *metaV1.NewDeleteOptions(0)); err != nil {
+							klog.Warningf("message: %s, graceful delete pod failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, updatedPod.Namespace, updatedPod.Name)
+						} else {
+							klog.Infof("message: %s, pod delete successfully, namespace: %s, name: %s", msg.GetID(), updatedPod.Namespace, updatedPod.Name)
+						}
+					}
+				}
+			}
 		}
+
+	default:
+		klog.Warningf("message: %s process failure, pod status operation: %s unsupported", msg.GetID(), msg.GetOperation())
+		return
 	}
+	klog.V(4).Infof("message: %s process successfully", msg.GetID())
 }
 
 // createNode create new edge node to kubernetes
@@ -469,153 +499,159 @@ func (uc *UpstreamController) updateNodeStatus() {
 			klog.Warning("stop updateNodeStatus")
 			return
 		case msg := <-uc.nodeStatusChan:
-			klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+			uc.processUpdateNodeStatus(msg)
+		}
+	}
+}
 
-			data, err := msg.GetContentData()
-			if err != nil {
-				klog.Warningf("message: %s process failure, get content data failed with error: %s", msg.GetID(), err)
-				continue
-			}
+func (uc *UpstreamController) processUpdateNodeStatus(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %s", msg.GetID(), err)
-				continue
-			}
-			name, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %s", msg.GetID(), err)
-				continue
-			}
+	klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 
-			switch msg.GetOperation() {
-			case model.InsertOperation:
-				_, err := uc.kubeClient.CoreV1().Nodes().Get(context.Background(), name, metaV1.GetOptions{})
-				if err == nil {
-					klog.Infof("node: %s already exists, do nothing", name)
-					uc.nodeMsgResponse(name, namespace, common.MessageSuccessfulContent, msg)
-					continue
-				}
+	data, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("message: %s process failure, get content data failed with error: %s", msg.GetID(), err)
+		return
+	}
 
-				if !errors.IsNotFound(err) {
-					errLog := fmt.Sprintf("get node %s info error: %v , register node failed", name, err)
-					klog.Error(errLog)
-					uc.nodeMsgResponse(name, namespace, errLog, msg)
-					continue
-				}
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %s", msg.GetID(), err)
+		return
+	}
+	name, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %s", msg.GetID(), err)
+		return
+	}
 
-				node := &v1.Node{}
-				err = json.Unmarshal(data, node)
-				if err != nil {
-					errLog := fmt.Sprintf("message: %s process failure, unmarshal marshaled message content with error: %s", msg.GetID(), err)
-					klog.Error(errLog)
-					uc.nodeMsgResponse(name, namespace, errLog, msg)
-					continue
-				}
+	switch msg.GetOperation() {
+	case model.InsertOperation:
+		_, err := uc.kubeClient.CoreV1().Nodes().Get(context.Background(), name, metaV1.GetOptions{})
+		if err == nil {
+			klog.Infof("node: %s already exists, do nothing", name)
+			uc.nodeMsgResponse(name, namespace, common.MessageSuccessfulContent, msg)
+			return
+		}
 
-				if _, err = uc.createNode(name, node); err != nil {
-					errLog := fmt.Sprintf("create node %s error: %v , register node failed", name, err)
-					klog.Error(errLog)
-					uc.nodeMsgResponse(name, namespace, errLog, msg)
-					continue
-				}
+		if !errors.IsNotFound(err) {
+			errLog := fmt.Sprintf("get node %s info error: %v , register node failed", name, err)
+			klog.Error(errLog)
+			uc.nodeMsgResponse(name, namespace, errLog, msg)
+			return
+		}
 
-				uc.nodeMsgResponse(name, namespace, common.MessageSuccessfulContent, msg)
+		node := &v1.Node{}
+		err = json.Unmarshal(data, node)
+		if err != nil {
+			errLog := fmt.Sprintf("message: %s process failure, unmarshal marshaled message content with error: %s", msg.GetID(), err)
+			klog.Error(errLog)
+			uc.nodeMsgResponse(name, namespace, errLog, msg)
+			return
+		}
 
-			case model.UpdateOperation:
-				nodeStatusRequest := &edgeapi.NodeStatusRequest{}
-				err := json.Unmarshal(data, nodeStatusRequest)
-				if err != nil {
-					klog.Warningf("message: %s process failure, unmarshal marshaled message content with error: %s", msg.GetID(), err)
-					continue
-				}
+		if _, err = uc.createNode(name, node); err != nil {
+			errLog := fmt.Sprintf("create node %s error: %v , register node failed", name, err)
+			klog.Error(errLog)
+			uc.nodeMsgResponse(name, namespace, errLog, msg)
+			return
+		}
 
-				getNode, err := uc.kubeClient.CoreV1().Nodes().Get(context.Background(), name, metaV1.GetOptions{})
-				if errors.IsNotFound(err) {
-					klog.Warningf("message: %s process failure, node %s not found", msg.GetID(), name)
-					continue
-				}
+		uc.nodeMsgResponse(name, namespace, common.MessageSuccessfulContent, msg)
 
-				if err != nil {
-					klog.Warningf("message: %s process failure with error: %s, namespaces: %s name: %s", msg.GetID(), err, namespace, name)
-					continue
-				}
+	case model.UpdateOperation:
+		nodeStatusRequest := &edgeapi.NodeStatusRequest{}
+		err := json.Unmarshal(data, nodeStatusRequest)
+		if err != nil {
+			klog.Warningf("message: %s process failure, unmarshal marshaled message content with error: %s", msg.GetID(), err)
+			return
+		}
 
-				// TODO: comment below for test failure. Needs to decide whether to keep post troubleshoot
-				// In case the status stored at metadata service is outdated, update the heartbeat automatically
-				for i := range nodeStatusRequest.Status.Conditions {
-					if time.Since(nodeStatusRequest.Status.Conditions[i].LastHeartbeatTime.Time) > time.Duration(uc.config.NodeUpdateFrequency)*time.Second {
-						nodeStatusRequest.Status.Conditions[i].LastHeartbeatTime = metaV1.NewTime(time.Now())
-					}
-				}
+		getNode, err := uc.kubeClient.CoreV1().Nodes().Get(context.Background(), name, metaV1.GetOptions{})
+		if errors.IsNotFound(err) {
+			klog.Warningf("message: %s process failure, node %s not found", msg.GetID(), name)
+			return
+		}
+
+		if err != nil {
+			klog.Warningf("message: %s process failure with error: %s, namespaces: %s name: %s", msg.GetID(), err, namespace, name)
+			return
+		}
+
+		// TODO: comment below for test failure. Needs to decide whether to keep post troubleshoot
+		// In case the status stored at metadata service is outdated, update the heartbeat automatically
+		for i := range nodeStatusRequest.Status.Conditions {
+			if time.Since(nodeStatusRequest.Status.Conditions[i].LastHeartbeatTime.Time) > time.Duration(uc.config.NodeUpdateFrequency)*time.Second {
+				nodeStatusRequest.Status.Conditions[i].LastHeartbeatTime = metaV1.NewTime(time.Now())
+			}
+		}
 
-				if getNode.Annotations == nil {
-					getNode.Annotations = make(map[string]string)
+		if getNode.Annotations == nil {
+			getNode.Annotations = make(map[string]string)
+		}
+		for name, v := range nodeStatusRequest.ExtendResources {
+			if name == constants.NvidiaGPUScalarResourceName {
+				var gpuStatus []types.NvidiaGPUStatus
+				for _, er := range v {
+					gpuStatus = append(gpuStatus, types.NvidiaGPUStatus{ID: er.Name, Healthy: true})
 				}
-				for name, v := range nodeStatusRequest.ExtendResources {
-					if name == constants.NvidiaGPUScalarResourceName {
-						var gpuStatus []types.NvidiaGPUStatus
-						for _, er := range v {
-							gpuStatus = append(gpuStatus, types.NvidiaGPUStatus{ID: er.Name, Healthy: true})
-						}
-						if len(gpuStatus) > 0 {
-							data, _ := json.Marshal(gpuStatus)
-							getNode.Annotations[constants.NvidiaGPUStatusAnnotationKey] = string(data)
-						}
-					}
-					data, err := json.Marshal(v)
-					if err != nil {
-						klog.Warningf("message: %s process failure, extend resource list marshal with error: %s", msg.GetID(), err)
-						continue
-					}
-					getNode.Annotations[string(name)] = string(data)
+				if len(gpuStatus) > 0 {
+					data, _ := json.Marshal(gpuStatus)
+					getNode.Annotations[constants.NvidiaGPUStatusAnnotationKey] = string(data)
 				}
+			}
+			data, err := json.Marshal(v)
+			if err != nil {
+				klog.Warningf("message: %s process failure, extend resource list marshal with error: %s", msg.GetID(), err)
+				continue
+			}
+			getNode.Annotations[string(name)] = string(data)
+		}
 
-				// Keep the same "VolumesAttached" attribute with upstream,
-				// since this value is maintained by kube-controller-manager.
-				nodeStatusRequest.Status.VolumesAttached = getNode.Status.VolumesAttached
-				if getNode.Status.DaemonEndpoints.KubeletEndpoint.Port != 0 {
-					nodeStatusRequest.Status.DaemonEndpoints.KubeletEndpoint.Port = getNode.Status.DaemonEndpoints.KubeletEndpoint.Port
-				}
+		// Keep the same "VolumesAttached" attribute with upstream,
+		// since this value is maintained by kube-controller-manager.
+		nodeStatusRequest.Status.VolumesAttached = getNode.Status.VolumesAttached
+		if getNode.Status.DaemonEndpoints.KubeletEndpoint.Port != 0 {
+			nodeStatusRequest.Status.DaemonEndpoints.KubeletEndpoint.Port = getNode.Status.DaemonEndpoints.KubeletEndpoint.Port
+		}
 
-				getNode.Status = nodeStatusRequest.Status
+		getNode.Status = nodeStatusRequest.Status
 
-				node, err := uc.kubeClient.CoreV1().Nodes().UpdateStatus(context.Background(), getNode, metaV1.UpdateOptions{})
-				if err != nil {
-					klog.Warningf("message: %s process failure, update node failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, getNode.Namespace, getNode.Name)
-					continue
-				}
+		node, err := uc.kubeClient.CoreV1().Nodes().UpdateStatus(context.Background(), getNode, metaV1.UpdateOptions{})
+		if err != nil {
+			klog.Warningf("message: %s process failure, update node failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, getNode.Namespace, getNode.Name)
+			return
+		}
 
-				nodeID, err := messagelayer.GetNodeID(msg)
-				if err != nil {
-					klog.Warningf("Message: %s process failure, get node id failed with error: %s", msg.GetID(), err)
-					continue
-				}
+		nodeID, err := messagelayer.GetNodeID(msg)
+		if err != nil {
+			klog.Warningf("Message: %s process failure, get node id failed with error: %s", msg.GetID(), err)
+			return
+		}
 
-				resource, err := messagelayer.BuildResource(nodeID, namespace, model.ResourceTypeNode, name)
-				if err != nil {
-					klog.Warningf("Message: %s process failure, build message resource failed with error: %s", msg.GetID(), err)
-					continue
-				}
+		resource, err := messagelayer.BuildResource(nodeID, namespace, model.ResourceTypeNode, name)
+		if err != nil {
+			klog.Warningf("Message: %s process failure, build message resource failed with error: %s", msg.GetID(), err)
+			return
+		}
 
-				resMsg := model.NewMessage(msg.GetID()).
-					SetResourceVersion(node.ResourceVersion).
-					FillBody(common.MessageSuccessfulContent).
-					BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, resource, model.ResponseOperation)
-				if err = uc.messageLayer.Response(*resMsg); err != nil {
-					klog.Warningf("Message: %s process failure, response failed with error: %s", msg.GetID(), err)
-					continue
-				}
+		resMsg := model.NewMessage(msg.GetID()).
+			SetResourceVersion(node.ResourceVersion).
+			FillBody(common.MessageSuccessfulContent).
+			BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, resource, model.ResponseOperation)
+		if err = uc.messageLayer.Response(*resMsg); err != nil {
+			klog.Warningf("Message: %s process failure, response failed with error: %s", msg.GetID(), err)
+			return
+		}
 
-				klog.V(4).Infof("message: %s, update node status successfully, namespace: %s, name: %s", msg.GetID(), getNode.Namespace, getNode.Name)
+		klog.V(4).Infof("message: %s, update node status successfully, namespace: %s, name: %s", msg.GetID(), getNode.Namespace, getNode.Name)
 
-			default:
-				klog.Warningf("message: %s process failure, node status operation: %s unsupported", msg.GetID(), msg.GetOperation())
-				continue
-			}
-			klog.V(4).Infof("message: %s process successfully", msg.GetID())
-		}
+	default:
+		klog.Warningf("message: %s process failure, node status operation: %s unsupported", msg.GetID(), msg.GetOperation())
+		return
 	}
+	klog.V(4).Infof("message: %s process successfully", msg.GetID())
 }
 
 func kubeClientGet(uc *UpstreamController, namespace string, name string, queryType string, msg model.Message) (metaV1.Object, error) {
@@ -651,6 +687,7 @@ func kubeClientGet(uc *UpstreamController, namespace string, name string, queryT
 }
 
 func queryInner(uc *UpstreamController, msg model.Message, queryType string) {
+	defer observeUpstreamProcessing(&msg)()
 	klog.V(4).Infof("message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 	var err error
 	var namespace, name, nodeID, resource string
@@ -815,50 +852,56 @@ func (uc *UpstreamController) registerNode() {
 			klog.Warning("stop registerNode")
 			return
 		case msg := <-uc.createNodeChan:
-			klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+			uc.processRegisterNode(msg)
+		}
+	}
+}
 
-			data, err := msg.GetContentData()
-			if err != nil {
-				klog.Warningf("message: %s process failure, get content data failed with error: %v", msg.GetID(), err)
-				continue
-			}
+func (uc *UpstreamController) processRegisterNode(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
-				continue
-			}
-			name, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
-				continue
-			}
+	klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 
-			node := &v1.Node{}
-			err = json.Unmarshal(data, node)
-			if err != nil {
-				errLog := fmt.Sprintf("message: %s process failure, unmarshal marshaled message content with error: %v", msg.GetID(), err)
-				klog.Error(errLog)
-				uc.nodeMsgResponse(name, namespace, errLog, msg)
-				continue
-			}
+	data, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("message: %s process failure, get content data failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-			resp, err := uc.createNode(name, node)
-			if err != nil {
-				klog.Errorf("create node %s error: %v , register node failed", name, err)
-			}
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
+		return
+	}
+	name, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-			resMsg := model.NewMessage(msg.GetID()).
-				FillBody(&edgeapi.ObjectResp{Object: resp, Err: err}).
-				BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
-			if err = uc.messageLayer.Response(*resMsg); err != nil {
-				klog.Warningf("Response message: %s failed, response failed with error: %v", msg.GetID(), err)
-				continue
-			}
+	node := &v1.Node{}
+	err = json.Unmarshal(data, node)
+	if err != nil {
+		errLog := fmt.Sprintf("message: %s process failure, unmarshal marshaled message content with error: %v", msg.GetID(), err)
+		klog.Error(errLog)
+		uc.nodeMsgResponse(name, namespace, errLog, msg)
+		return
+	}
 
-			klog.V(4).Infof("message: %s, register node successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
-		}
+	resp, err := uc.createNode(name, node)
+	if err != nil {
+		klog.Errorf("create node %s error: %v , register node failed", name, err)
+	}
+
+	resMsg := model.NewMessage(msg.GetID()).
+		FillBody(&edgeapi.ObjectResp{Object: resp, Err: err}).
+		BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+	if err = uc.messageLayer.Response(*resMsg); err != nil {
+		klog.Warningf("Response message: %s failed, response failed with error: %v", msg.GetID(), err)
+		return
 	}
+
+	klog.V(4).Infof("message: %s, register node successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
 }
 
 func (uc *UpstreamController) patchNode() {
@@ -868,42 +911,48 @@ func (uc *UpstreamController) patchNode() {
 			klog.Warning("stop patchNode")
 			return
 		case msg := <-uc.patchNodeChan:
-			klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
-
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
-				continue
-			}
-			name, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
-				continue
-			}
+			uc.processPatchNode(msg)
+		}
+	}
+}
 
-			patchBytes, err := msg.GetContentData()
-			if err != nil {
-				klog.Warningf("message: %s process failure, get data failed with error: %v", msg.GetID(), err)
-				continue
-			}
+func (uc *UpstreamController) processPatchNode(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-			node, err := uc.kubeClient.CoreV1().Nodes().Patch(context.TODO(), name, apimachineryType.StrategicMergePatchType, patchBytes, metaV1.PatchOptions{}, "status")
-			if err != nil {
-				klog.Errorf("message: %s process failure, patch node failed with error: %v, namespace: %s, name: %s", msg.GetID(), err, namespace, name)
-			}
+	klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 
-			resMsg := model.NewMessage(msg.GetID()).
-				SetResourceVersion(node.ResourceVersion).
-				FillBody(&edgeapi.ObjectResp{Object: node, Err: err}).
-				BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
-			if err = uc.messageLayer.Response(*resMsg); err != nil {
-				klog.Warningf("Message: %s process failure, response failed with error: %v", msg.GetID(), err)
-				continue
-			}
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
+		return
+	}
+	name, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-			klog.V(4).Infof("message: %s, patch node status successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
-		}
+	patchBytes, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("message: %s process failure, get data failed with error: %v", msg.GetID(), err)
+		return
+	}
+
+	node, err := uc.kubeClient.CoreV1().Nodes().Patch(context.TODO(), name, apimachineryType.StrategicMergePatchType, patchBytes, metaV1.PatchOptions{}, "status")
+	if err != nil {
+		klog.Errorf("message: %s process failure, patch node failed with error: %v, namespace: %s, name: %s", msg.GetID(), err, namespace, name)
+	}
+
+	resMsg := model.NewMessage(msg.GetID()).
+		SetResourceVersion(node.ResourceVersion).
+		FillBody(&edgeapi.ObjectResp{Object: node, Err: err}).
+		BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+	if err = uc.messageLayer.Response(*resMsg); err != nil {
+		klog.Warningf("Message: %s process failure, response failed with error: %v", msg.GetID(), err)
+		return
 	}
+
+	klog.V(4).Infof("message: %s, patch node status successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
 }
 
 func (uc *UpstreamController) updateNode() {
@@ -913,95 +962,101 @@ func (uc *UpstreamController) updateNode() {
 			klog.Warning("stop updateNode")
 			return
 		case msg := <-uc.updateNodeChan:
-			klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
-			noderequest := &v1.Node{}
+			uc.processUpdateNode(msg)
+		}
+	}
+}
 
-			data, err := msg.GetContentData()
-			if err != nil {
-				klog.Warningf("message: %s process failure, get content data failed with error: %s", msg.GetID(), err)
-				continue
-			}
+func (uc *UpstreamController) processUpdateNode(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-			if err := json.Unmarshal(data, noderequest); err != nil {
-				klog.Warningf("message: %s process failure, unmarshal message content data with error: %s", msg.GetID(), err)
-				continue
-			}
+	klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+	noderequest := &v1.Node{}
 
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %s", msg.GetID(), err)
-				continue
-			}
-			name, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %s", msg.GetID(), err)
-				continue
-			}
+	data, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("message: %s process failure, get content data failed with error: %s", msg.GetID(), err)
+		return
+	}
 
-			switch msg.GetOperation() {
-			case model.UpdateOperation:
-				getNode, err := uc.kubeClient.CoreV1().Nodes().Get(context.Background(), name, metaV1.GetOptions{})
-				if errors.IsNotFound(err) {
-					klog.Warningf("message: %s process failure, node %s not found", msg.GetID(), name)
-					continue
-				}
-				if err != nil {
-					klog.Warningf("message: %s process failure with error: %s, name: %s", msg.GetID(), err, name)
-					continue
-				}
-				// update node labels
-				if getNode.Labels == nil {
-					getNode.Labels = make(map[string]string)
-				}
-				for key, value := range noderequest.Labels {
-					getNode.Labels[key] = value
-				}
+	if err := json.Unmarshal(data, noderequest); err != nil {
+		klog.Warningf("message: %s process failure, unmarshal message content data with error: %s", msg.GetID(), err)
+		return
+	}
 
-				if getNode.Annotations == nil {
-					getNode.Annotations = make(map[string]string)
-				}
-				for k, v := range noderequest.Annotations {
-					getNode.Annotations[k] = v
-				}
-				byteNode, err := json.Marshal(getNode)
-				if err != nil {
-					klog.Warningf("marshal node data failed with err: %s", err)
-					continue
-				}
-				node, err := uc.kubeClient.CoreV1().Nodes().Patch(context.Background(), getNode.Name, apimachineryType.StrategicMergePatchType, byteNode, metaV1.PatchOptions{})
-				if err != nil {
-					klog.Warningf("message: %s process failure, update node failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, getNode.Namespace, getNode.Name)
-					continue
-				}
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %s", msg.GetID(), err)
+		return
+	}
+	name, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %s", msg.GetID(), err)
+		return
+	}
 
-				nodeID, err := messagelayer.GetNodeID(msg)
-				if err != nil {
-					klog.Warningf("Message: %s process failure, get node id failed with error: %s", msg.GetID(), err)
-					continue
-				}
-				resource, err := messagelayer.BuildResource(nodeID, namespace, model.ResourceTypeNode, name)
-				if err != nil {
-					klog.Warningf("Message: %s process failure, build message resource failed with error: %s", msg.GetID(), err)
-					continue
-				}
+	switch msg.GetOperation() {
+	case model.UpdateOperation:
+		getNode, err := uc.kubeClient.CoreV1().Nodes().Get(context.Background(), name, metaV1.GetOptions{})
+		if errors.IsNotFound(err) {
+			klog.Warningf("message: %s process failure, node %s not found", msg.GetID(), name)
+			return
+		}
+		if err != nil {
+			klog.Warningf("message: %s process failure with error: %s, name: %s", msg.GetID(), err, name)
+			return
+		}
+		// update node labels
+		if getNode.Labels == nil {
+			getNode.Labels = make(map[string]string)
+		}
+		for key, value := range noderequest.Labels {
+			getNode.Labels[key] = value
+		}
 
-				resMsg := model.NewMessage(msg.GetID()).
-					SetResourceVersion(node.ResourceVersion).
-					FillBody(common.MessageSuccessfulContent).
-					BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, resource, model.ResponseOperation)
-				if err = uc.messageLayer.Response(*resMsg); err != nil {
-					klog.Warningf("Message: %s process failure, response failed with error: %s", msg.GetID(), err)
-					continue
-				}
+		if getNode.Annotations == nil {
+			getNode.Annotations = make(map[string]string)
+		}
+		for k, v := range noderequest.Annotations {
+			getNode.Annotations[k] = v
+		}
+		byteNode, err := json.Marshal(getNode)
+		if err != nil {
+			klog.Warningf("marshal node data failed with err: %s", err)
+			return
+		}
+		node, err := uc.kubeClient.CoreV1().Nodes().Patch(context.Background(), getNode.Name, apimachineryType.StrategicMergePatchType, byteNode, metaV1.PatchOptions{})
+		if err != nil {
+			klog.Warningf("message: %s process failure, update node failed with error: %s, namespace: %s, name: %s", msg.GetID(), err, getNode.Namespace, getNode.Name)
+			return
+		}
 
-				klog.V(4).Infof("message: %s, update node successfully, namespace: %s, name: %s", msg.GetID(), getNode.Namespace, getNode.Name)
-			default:
-				klog.Warningf("message: %s process failure, node operation: %s unsupported", msg.GetID(), msg.GetOperation())
-				continue
-			}
-			klog.V(4).Infof("message: %s process successfully", msg.GetID())
+		nodeID, err := messagelayer.GetNodeID(msg)
+		if err != nil {
+			klog.Warningf("Message: %s process failure, get node id failed with error: %s", msg.GetID(), err)
+			return
+		}
+		resource, err := messagelayer.BuildResource(nodeID, namespace, model.ResourceTypeNode, name)
+		if err != nil {
+			klog.Warningf("Message: %s process failure, build message resource failed with error: %s", msg.GetID(), err)
+			return
+		}
+
+		resMsg := model.NewMessage(msg.GetID()).
+			SetResourceVersion(node.ResourceVersion).
+			FillBody(common.MessageSuccessfulContent).
+			BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, resource, model.ResponseOperation)
+		if err = uc.messageLayer.Response(*resMsg); err != nil {
+			klog.Warningf("Message: %s process failure, response failed with error: %s", msg.GetID(), err)
+			return
 		}
+
+		klog.V(4).Infof("message: %s, update node successfully, namespace: %s, name: %s", msg.GetID(), getNode.Namespace, getNode.Name)
+	default:
+		klog.Warningf("message: %s process failure, node operation: %s unsupported", msg.GetID(), msg.GetOperation())
+		return
 	}
+	klog.V(4).Infof("message: %s process successfully", msg.GetID())
 }
 
 func (uc *UpstreamController) patchPod() {
@@ -1011,42 +1066,48 @@ func (uc *UpstreamController) patchPod() {
 			klog.Warning("stop patchPod")
 			return
 		case msg := <-uc.patchPodChan:
-			klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+			uc.processPatchPod(msg)
+		}
+	}
+}
 
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
-				continue
-			}
-			name, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
-				continue
-			}
+func (uc *UpstreamController) processPatchPod(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-			patchBytes, err := msg.GetContentData()
-			if err != nil {
-				klog.Warningf("message: %s process failure, get data failed with error: %v", msg.GetID(), err)
-				continue
-			}
+	klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 
-			updatedPod, err := uc.kubeClient.CoreV1().Pods(namespace).Patch(context.TODO(), name, apimachineryType.StrategicMergePatchType, patchBytes, metaV1.PatchOptions{}, "status")
-			if err != nil {
-				klog.Errorf("message: %s process failure, patch pod failed with error: %v, namespace: %s, name: %s", msg.GetID(), err, namespace, name)
-			}
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
+		return
+	}
+	name, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-			resMsg := model.NewMessage(msg.GetID()).
-				SetResourceVersion(updatedPod.ResourceVersion).
-				FillBody(&edgeapi.ObjectResp{Object: updatedPod, Err: err}).
-				BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
-			if err = uc.messageLayer.Response(*resMsg); err != nil {
-				klog.Errorf("Message: %s process failure, response failed with error: %v", msg.GetID(), err)
-				continue
-			}
+	patchBytes, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("message: %s process failure, get data failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-			klog.V(4).Infof("message: %s, patch pod successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
-		}
+	updatedPod, err := uc.kubeClient.CoreV1().Pods(namespace).Patch(context.TODO(), name, apimachineryType.StrategicMergePatchType, patchBytes, metaV1.PatchOptions{}, "status")
+	if err != nil {
+		klog.Errorf("message: %s process failure, patch pod failed with error: %v, namespace: %s, name: %s", msg.GetID(), err, namespace, name)
 	}
+
+	resMsg := model.NewMessage(msg.GetID()).
+		SetResourceVersion(updatedPod.ResourceVersion).
+		FillBody(&edgeapi.ObjectResp{Object: updatedPod, Err: err}).
+		BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+	if err = uc.messageLayer.Response(*resMsg); err != nil {
+		klog.Errorf("Message: %s process failure, response failed with error: %v", msg.GetID(), err)
+		return
+	}
+
+	klog.V(4).Infof("message: %s, patch pod successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
 }
 
 func (uc *UpstreamController) createPod() {
@@ -1056,45 +1117,51 @@ func (uc *UpstreamController) createPod() {
 			klog.Warning("stop createPod")
 			return
 		case msg := <-uc.createPodChan:
-			klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
-				continue
-			}
-			name, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
-				continue
-			}
+			uc.processCreatePod(msg)
+		}
+	}
+}
 
-			podBytes, err := msg.GetContentData()
-			if err != nil {
-				klog.Warningf("message: %s process failure, get data failed with error: %v", msg.GetID(), err)
-				continue
-			}
-			var pod v1.Pod
-			if err = json.Unmarshal(podBytes, &pod); err != nil {
-				klog.Errorf("unmarshal pod request failed with error: %v", err)
-				continue
-			}
+func (uc *UpstreamController) processCreatePod(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-			createPod, err := uc.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), &pod, metaV1.CreateOptions{})
-			if err != nil {
-				klog.Errorf("message: %s process failure, create pod failed with error: %v, namespace: %s, name: %s", msg.GetID(), err, namespace, name)
-			}
+	klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
+		return
+	}
+	name, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-			resMsg := model.NewMessage(msg.GetID()).
-				SetResourceVersion(createPod.ResourceVersion).
-				FillBody(&edgeapi.ObjectResp{Object: createPod, Err: err}).
-				BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
-			if err = uc.messageLayer.Response(*resMsg); err != nil {
-				klog.Errorf("Message: %s process failure, response failed with error: %v", msg.GetID(), err)
-				continue
-			}
-			klog.V(4).Infof("message: %s, create pod successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
-		}
+	podBytes, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("message: %s process failure, get data failed with error: %v", msg.GetID(), err)
+		return
+	}
+	var pod v1.Pod
+	if err = json.Unmarshal(podBytes, &pod); err != nil {
+		klog.Errorf("unmarshal pod request failed with error: %v", err)
+		return
+	}
+
+	createPod, err := uc.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), &pod, metaV1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("message: %s process failure, create pod failed with error: %v, namespace: %s, name: %s", msg.GetID(), err, namespace, name)
 	}
+
+	resMsg := model.NewMessage(msg.GetID()).
+		SetResourceVersion(createPod.ResourceVersion).
+		FillBody(&edgeapi.ObjectResp{Object: createPod, Err: err}).
+		BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+	if err = uc.messageLayer.Response(*resMsg); err != nil {
+		klog.Errorf("Message: %s process failure, response failed with error: %v", msg.GetID(), err)
+		return
+	}
+	klog.V(4).Infof("message: %s, create pod successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
 }
 
 func (uc *UpstreamController) deletePod() {
@@ -1104,61 +1171,67 @@ func (uc *UpstreamController) deletePod() {
 			klog.Warning("stop deletePod")
 			return
 		case msg := <-uc.podDeleteChan:
-			klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+			uc.processDeletePod(msg)
+		}
+	}
+}
 
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
-				continue
-			}
-			name, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
-				continue
-			}
+func (uc *UpstreamController) processDeletePod(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-			deleteOptions := metaV1.DeleteOptions{}
-			deleteReq, ok := msg.Content.(string)
-			if ok {
-				// in earlier version, deletion request content only contains pod UID.
-				var period int64
-				deleteOptions.GracePeriodSeconds = &period
-				// Use the pod UID as the precondition for deletion to prevent deleting a newly created pod with the same name and namespace.
-				deleteOptions.Preconditions = metaV1.NewUIDPreconditions(deleteReq)
-			} else {
-				data, err := msg.GetContentData()
-				if err != nil {
-					klog.Warningf("message: %s process failure, get msg content failed with error: %v", msg.GetID(), err)
-					continue
-				}
+	klog.V(5).Infof("message: %s, operation is: %s, and resource is %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 
-				err = json.Unmarshal(data, &deleteOptions)
-				if err != nil {
-					klog.Warningf("Failed to unmarshal deletion options from msg, pod namespace: %s, pod name: %s, err: %v", namespace, name, err)
-					continue
-				}
-			}
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
+		return
+	}
+	name, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-			var resMsg *model.Message
-			err = uc.kubeClient.CoreV1().Pods(namespace).Delete(context.Background(), name, deleteOptions)
-			if err != nil && !errors.IsNotFound(err) && !strings.Contains(err.Error(), "The object might have been deleted and then recreated") {
-				klog.Warningf("Failed to delete pod, namespace: %s, name: %s, err: %v", namespace, name, err)
-				resMsg = model.NewMessage(msg.GetID()).
-					FillBody(err).
-					BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
-			} else {
-				resMsg = model.NewMessage(msg.GetID()).
-					FillBody(common.MessageSuccessfulContent).
-					BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
-			}
+	deleteOptions := metaV1.DeleteOptions{}
+	deleteReq, ok := msg.Content.(string)
+	if ok {
+		// in earlier version, deletion request content only contains pod UID.
+		var period int64
+		deleteOptions.GracePeriodSeconds = &period
+		// Use the pod UID as the precondition for deletion to prevent deleting a newly created pod with the same name and namespace.
+		deleteOptions.Preconditions = metaV1.NewUIDPreconditions(deleteReq)
+	} else {
+		data, err := msg.GetContentData()
+		if err != nil {
+			klog.Warningf("message: %s process failure, get msg content failed with error: %v", msg.GetID(), err)
+			return
+		}
 
-			if err = uc.messageLayer.Response(*resMsg); err != nil {
-				klog.Errorf("Message: %s process failure, response failed with error: %v", msg.GetID(), err)
-				continue
-			}
-			klog.V(4).Infof("Successfully terminate and remove pod from etcd, namespace: %s, name: %s", namespace, name)
+		err = json.Unmarshal(data, &deleteOptions)
+		if err != nil {
+			klog.Warningf("Failed to unmarshal deletion options from msg, pod namespace: %s, pod name: %s, err: %v", namespace, name, err)
+			return
 		}
 	}
+
+	var resMsg *model.Message
+	err = uc.kubeClient.CoreV1().Pods(namespace).Delete(context.Background(), name, deleteOptions)
+	if err != nil && !errors.IsNotFound(err) && !strings.Contains(err.Error(), "The object might have been deleted and then recreated") {
+		klog.Warningf("Failed to delete pod, namespace: %s, name: %s, err: %v", namespace, name, err)
+		resMsg = model.NewMessage(msg.GetID()).
+			FillBody(err).
+			BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+	} else {
+		resMsg = model.NewMessage(msg.GetID()).
+			FillBody(common.MessageSuccessfulContent).
+			BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+	}
+
+	if err = uc.messageLayer.Response(*resMsg); err != nil {
+		klog.Errorf("Message: %s process failure, response failed with error: %v", msg.GetID(), err)
+		return
+	}
+	klog.V(4).Infof("Successfully terminate and remove pod from etcd, namespace: %s, name: %s", namespace, name)
 }
 
 func (uc *UpstreamController) queryNode() {
@@ -1180,71 +1253,77 @@ func (uc *UpstreamController) createOrUpdateLease() {
 			klog.Warning("stop create or update lease")
 			return
 		case msg := <-uc.createLeaseChan:
-			klog.V(4).Infof("message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+			uc.processCreateOrUpdateLease(msg)
+		}
+	}
+}
 
-			data, err := msg.GetContentData()
-			if err != nil {
-				klog.Warningf("message: %s process failure, get content data failed with error: %v", msg.GetID(), err)
-				continue
-			}
+func (uc *UpstreamController) processCreateOrUpdateLease(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
-				return
-			}
-			name, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
-				continue
-			}
+	klog.V(4).Infof("message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 
-			lease := &coordinationv1.Lease{}
-			err = json.Unmarshal(data, lease)
-			if err != nil {
-				errLog := fmt.Sprintf("message: %s process failure, unmarshal message content with error: %v", msg.GetID(), err)
-				klog.Error(errLog)
-				uc.nodeMsgResponse(name, namespace, errLog, msg)
-				continue
-			}
+	data, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("message: %s process failure, get content data failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-			switch msg.GetOperation() {
-			case model.InsertOperation:
-				resp, err := uc.kubeClient.CoordinationV1().Leases(namespace).Create(context.TODO(), lease, metaV1.CreateOptions{})
-				if err != nil {
-					klog.Errorf("create lease %s failed, error: %v", name, err)
-				}
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
+		return
+	}
+	name, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-				resMsg := model.NewMessage(msg.GetID()).
-					FillBody(&edgeapi.ObjectResp{Object: resp, Err: err}).
-					BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
-				if err = uc.messageLayer.Response(*resMsg); err != nil {
-					klog.Warningf("Response message: %s failed, response failed with error: %v", msg.GetID(), err)
-					continue
-				}
+	lease := &coordinationv1.Lease{}
+	err = json.Unmarshal(data, lease)
+	if err != nil {
+		errLog := fmt.Sprintf("message: %s process failure, unmarshal message content with error: %v", msg.GetID(), err)
+		klog.Error(errLog)
+		uc.nodeMsgResponse(name, namespace, errLog, msg)
+		return
+	}
 
-				klog.V(4).Infof("message: %s, create lease successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
+	switch msg.GetOperation() {
+	case model.InsertOperation:
+		resp, err := uc.kubeClient.CoordinationV1().Leases(namespace).Create(context.TODO(), lease, metaV1.CreateOptions{})
+		if err != nil {
+			klog.Errorf("create lease %s failed, error: %v", name, err)
+		}
 
-			case model.UpdateOperation:
-				resp, err := uc.kubeClient.CoordinationV1().Leases(namespace).Update(context.TODO(), lease, metaV1.UpdateOptions{})
-				if err != nil {
-					klog.Errorf("Update lease %s failed, error: %v", name, err)
-				}
+		resMsg := model.NewMessage(msg.GetID()).
+			FillBody(&edgeapi.ObjectResp{Object: resp, Err: err}).
+			BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+		if err = uc.messageLayer.Response(*resMsg); err != nil {
+			klog.Warningf("Response message: %s failed, response failed with error: %v", msg.GetID(), err)
+			return
+		}
 
-				resMsg := model.NewMessage(msg.GetID()).
-					FillBody(&edgeapi.ObjectResp{Object: resp, Err: err}).
-					BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
-				if err = uc.messageLayer.Response(*resMsg); err != nil {
-					klog.Warningf("Response message: %s failed, response failed with error: %v", msg.GetID(), err)
-					continue
-				}
+		klog.V(4).Infof("message: %s, create lease successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
 
-				klog.V(4).Infof("message: %s, update lease successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
+	case model.UpdateOperation:
+		resp, err := uc.kubeClient.CoordinationV1().Leases(namespace).Update(context.TODO(), lease, metaV1.UpdateOptions{})
+		if err != nil {
+			klog.Errorf("Update lease %s failed, error: %v", name, err)
+		}
 
-			default:
-				klog.Warningf("message: %s process failure, operation: %s unsupported", msg.GetID(), msg.GetOperation())
-			}
+		resMsg := model.NewMessage(msg.GetID()).
+			FillBody(&edgeapi.ObjectResp{Object: resp, Err: err}).
+			BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+		if err = uc.messageLayer.Response(*resMsg); err != nil {
+			klog.Warningf("Response message: %s failed, response failed with error: %v", msg.GetID(), err)
+			return
 		}
+
+		klog.V(4).Infof("message: %s, update lease successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
+
+	default:
+		klog.Warningf("message: %s process failure, operation: %s unsupported", msg.GetID(), msg.GetOperation())
 	}
 }
 
@@ -1255,34 +1334,40 @@ func (uc *UpstreamController) queryLease() {
 			klog.Warning("stop queryLease")
 			return
 		case msg := <-uc.queryLeaseChan:
-			klog.V(4).Infof("message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
-			namespace, err := messagelayer.GetNamespace(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
-				return
-			}
-			name, err := messagelayer.GetResourceName(msg)
-			if err != nil {
-				klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
-				return
-			}
+			uc.processQueryLease(msg)
+		}
+	}
+}
 
-			object, err := kubeClientGet(uc, namespace, name, model.ResourceTypeLease, msg)
-			if err != nil {
-				klog.Errorf("Query lease %s failed, error: %v", name, err)
-			}
+func (uc *UpstreamController) processQueryLease(msg model.Message) {
+	defer observeUpstreamProcessing(&msg)()
 
-			resMsg := model.NewMessage(msg.GetID()).
-				FillBody(&edgeapi.ObjectResp{Object: object, Err: err}).
-				BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
-			if err = uc.messageLayer.Response(*resMsg); err != nil {
-				klog.Warningf("Response message: %s failed, response failed with error: %v", msg.GetID(), err)
-				continue
-			}
+	klog.V(4).Infof("message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+	namespace, err := messagelayer.GetNamespace(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get namespace failed with error: %v", msg.GetID(), err)
+		return
+	}
+	name, err := messagelayer.GetResourceName(msg)
+	if err != nil {
+		klog.Warningf("message: %s process failure, get resource name failed with error: %v", msg.GetID(), err)
+		return
+	}
 
-			klog.V(4).Infof("message: %s, query lease successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
-		}
+	object, err := kubeClientGet(uc, namespace, name, model.ResourceTypeLease, msg)
+	if err != nil {
+		klog.Errorf("Query lease %s failed, error: %v", name, err)
 	}
+
+	resMsg := model.NewMessage(msg.GetID()).
+		FillBody(&edgeapi.ObjectResp{Object: object, Err: err}).
+		BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+	if err = uc.messageLayer.Response(*resMsg); err != nil {
+		klog.Warningf("Response message: %s failed, response failed with error: %v", msg.GetID(), err)
+		return
+	}
+
+	klog.V(4).Infof("message: %s, query lease successfully, namespace: %s, name: %s", msg.GetID(), namespace, name)
 }
 
 func (uc *UpstreamController) unmarshalPodStatusMessage(msg model.Message) (ns string, podStatuses []edgeapi.PodStatusRequest) {
@@ -24,6 +24,7 @@ import (
 
 	"github.com/google/uuid"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachineryType "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -38,21 +39,26 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/nodegroup"
 	"github.com/kubeedge/kubeedge/cloud/pkg/nodeupgradejobcontroller/manager"
 	"github.com/kubeedge/kubeedge/common/constants"
 	commontypes "github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
 	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
 	crdinformers "github.com/kubeedge/kubeedge/pkg/client/informers/externalversions"
+	appslisters "github.com/kubeedge/kubeedge/pkg/client/listers/apps/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/maintenancewindow"
 )
 
 type DownstreamController struct {
-	kubeClient   kubernetes.Interface
-	informer     k8sinformer.SharedInformerFactory
-	crdClient    crdClientset.Interface
-	messageLayer messagelayer.MessageLayer
+	kubeClient      kubernetes.Interface
+	informer        k8sinformer.SharedInformerFactory
+	crdClient       crdClientset.Interface
+	messageLayer    messagelayer.MessageLayer
+	nodeGroupLister appslisters.NodeGroupLister
 
 	nodeUpgradeJobManager *manager.NodeUpgradeJobManager
+	nodeJobManager        *manager.NodeJobManager
 }
 
 // Start DownstreamController
@@ -60,6 +66,7 @@ func (dc *DownstreamController) Start() error {
 	klog.Info("Start NodeUpgradeJob Downstream Controller")
 
 	go dc.syncNodeUpgradeJob()
+	go dc.syncNodeJob()
 
 	return nil
 }
@@ -147,36 +154,85 @@ func (dc *DownstreamController) nodeUpgradeJobAdded(upgrade *v1alpha1.NodeUpgrad
 
 	klog.Infof("Filtered finished, the below nodes are to upgrade\n%v\n", nodesToUpgrade)
 
+	go dc.runNodeUpgradeJob(upgrade, nodesToUpgrade)
+}
+
+// runNodeUpgradeJob runs the phases of the NodeUpgradeJob in order:
+// pre-flight checks, image pre-pull, canary and upgrade.
+func (dc *DownstreamController) runNodeUpgradeJob(upgrade *v1alpha1.NodeUpgradeJob, nodes []string) {
+	if err := initNodeUpgradeJobStatus(dc.crdClient, upgrade.Name, nodes); err != nil {
+		// the NodeUpgradeJob can not tell whether all the nodes are upgraded without the nodes recorded
+		klog.Errorf("Failed to record the nodes to upgrade of NodeUpgradeJob %s: %v", upgrade.Name, err)
+		return
+	}
+	if upgrade.Spec.PreflightChecks != nil {
+		nodes = dc.processPreUpgrade(upgrade, nodes, v1alpha1.PreflightPhase)
+	}
+	if upgrade.Spec.PrePull {
+		nodes = dc.processPreUpgrade(upgrade, nodes, v1alpha1.PrePullPhase)
+	}
+
+	if canary := upgrade.Spec.Canary; canary != nil && len(nodes) != 0 {
+		canaryNodes := nodes[:getCanaryNodesNumber(canary, len(nodes))]
+		nodes = nodes[len(canaryNodes):]
+
+		klog.Infof("NodeUpgradeJob %s begins canary phase with nodes %v", upgrade.Name, canaryNodes)
+		if err := patchNodeUpgradeJobPhase(dc.crdClient, upgrade.Name, v1alpha1.CanaryPhase, ""); err != nil {
+			klog.Errorf("Failed to mark NodeUpgradeJob %s canary phase: %v", upgrade.Name, err)
+		}
+		dc.upgradeNodes(upgrade, canaryNodes)
+
+		failed := dc.getFailedNodes(upgrade.Name, canaryNodes)
+		if len(failed) > int(canary.FailureThreshold) {
+			reason := fmt.Sprintf("%d canary nodes %v failed to upgrade, exceeding the failure threshold %d",
+				len(failed), failed, canary.FailureThreshold)
+			klog.Errorf("NodeUpgradeJob %s is halted: %s", upgrade.Name, reason)
+			if err := patchNodeUpgradeJobPhase(dc.crdClient, upgrade.Name, v1alpha1.CanaryPhase, reason); err != nil {
+				klog.Errorf("Failed to mark NodeUpgradeJob %s halted: %v", upgrade.Name, err)
+			}
+			return
+		}
+	}
+
+	if err := patchNodeUpgradeJobPhase(dc.crdClient, upgrade.Name, v1alpha1.UpgradingPhase, ""); err != nil {
+		klog.Errorf("Failed to mark NodeUpgradeJob %s upgrade phase: %v", upgrade.Name, err)
+	}
+	dc.upgradeNodes(upgrade, nodes)
+}
+
+// upgradeNodes upgrades the nodes, and returns after all the nodes complete upgrading or timeout
+func (dc *DownstreamController) upgradeNodes(upgrade *v1alpha1.NodeUpgradeJob, nodes []string) {
+	if len(nodes) == 0 {
+		return
+	}
+
 	// upgrade most `UpgradeJob.Spec.Concurrency` nodes once a time
-	nodesChan := make(chan string, upgrade.Spec.Concurrency)
+	nodesChan := make(chan string, getConcurrency(upgrade.Spec.Concurrency))
 
 	// select nodes to do upgrade operation
-	go dc.selectConcurrentNodes(nodesChan, nodesToUpgrade, upgrade)
+	go dc.selectConcurrentNodes(nodesChan, nodes, upgrade)
 
-	go func() {
-		for node := range nodesChan {
-			dc.processUpgrade(node, upgrade)
-		}
-	}()
+	for node := range nodesChan {
+		dc.processUpgrade(node, upgrade)
+	}
+
+	// every node will complete upgrading in timeout, even if no feedback is received from edge
+	err := wait.Poll(10*time.Second, getTimeout(upgrade)+time.Minute, func() (bool, error) {
+		return dc.nodesCompleted(upgrade.Name, nodes), nil
+	})
+	if err != nil {
+		klog.Errorf("failed to wait for nodes %v to complete upgrading: %v", nodes, err)
+	}
 }
 
 // processUpgrade do the upgrade operation on node
 func (dc *DownstreamController) processUpgrade(node string, upgrade *v1alpha1.NodeUpgradeJob) {
 	klog.V(4).Infof("begin to upgrade node %s", node)
-	// if users specify Image, we'll use upgrade Version as its image tag, even though Image contains tag.
-	// if not, we'll use default image: kubeedge/installation-package:${Version}
-	var repo string
-	var err error
-	repo = "kubeedge/installation-package"
-	if upgrade.Spec.Image != "" {
-		repo, err = GetImageRepo(upgrade.Spec.Image)
-		if err != nil {
-			klog.Errorf("Image format is not right: %v", err)
-			return
-		}
+	image, err := getUpgradeImage(upgrade)
+	if err != nil {
+		klog.Errorf("Image format is not right: %v", err)
+		return
 	}
-	imageTag := upgrade.Spec.Version
-	image := fmt.Sprintf("%s:%s", repo, imageTag)
 
 	// send upgrade msg to edge node
 	msg := model.NewMessage("")
@@ -239,61 +295,155 @@ func (dc *DownstreamController) processUpgrade(node string, upgrade *v1alpha1.No
 	}
 }
 
-// selectConcurrentNodes select the nodes to do upgrade operation, and put it into channel nodesChan
+// selectConcurrentNodes select the nodes to do upgrade operation, and put it into channel nodesChan.
+// Nodes out of their maintenance windows are marked waiting, and selected when their windows begin.
 func (dc *DownstreamController) selectConcurrentNodes(nodesChan chan string, allNodes []string, upgrade *v1alpha1.NodeUpgradeJob) {
+	// calculate the number of nodes in upgrading operation
+	upgrading := func() (int, error) {
+		upgradeJob, err := dc.crdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), upgrade.Name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		num := 0
+		for _, status := range upgradeJob.Status.Status {
+			if status.State == v1alpha1.Upgrading {
+				num++
+			}
+		}
+		return num, nil
+	}
+
+	waiting := map[string]bool{}
+	inWindow := func(node string, now time.Time) bool {
+		if dc.inMaintenanceWindow(node, now) {
+			return true
+		}
+		if !waiting[node] {
+			klog.Infof("Node %s is out of its maintenance window, wait to upgrade", node)
+			dc.markNodeWaiting(upgrade, node)
+			waiting[node] = true
+		}
+		return false
+	}
+
 	// the default concurrency is 1
 	// this means that we will upgrade nodes one by one
 	// only when the last one node upgrade finished, we'll continue to upgrade the next one node
-	concurrency := 1
-	if upgrade.Spec.Concurrency != 0 {
-		concurrency = int(upgrade.Spec.Concurrency)
+	err := batchNodes(nodesChan, allNodes, getConcurrency(upgrade.Spec.Concurrency), upgrading, inWindow)
+	if err != nil {
+		klog.Errorf("failed to select all the related nodes to do upgrade operation: %v", err)
 	}
+}
 
-	timeout := int(*upgrade.Spec.TimeoutSeconds) * (len(allNodes) + 1) / concurrency
+// batchNodes puts the nodes into nodesChan in batches, so that at most concurrency nodes are running
+// at the same time, and closes nodesChan after all the nodes are put.
+// running returns the number of running nodes, batching stops if the job is not found.
+// ready returns whether the node can run now, the nodes not ready are retried in the next round.
+func batchNodes(nodesChan chan<- string, nodes []string, concurrency int, running func() (int, error), ready func(node string, now time.Time) bool) error {
+	defer close(nodesChan)
 
-	err := wait.Poll(10*time.Second, time.Duration(timeout)*time.Second, func() (bool, error) {
-		upgradeJob, err := dc.crdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), upgrade.Name, metav1.GetOptions{})
+	// nodes may wait to be ready for a long time, so there's no timeout
+	return wait.PollUntil(10*time.Second, func() (bool, error) {
+		runningNum, err := running()
 		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, err
+			}
 			return false, nil
 		}
 
-		// if all the nodes upgrade is completed, close channel to inform that the upgrade is finished
-		if upgradeJob.Status.State == v1alpha1.Completed {
-			klog.Infof("all the nodes upgrade status are completed")
-			close(nodesChan)
-			return true, nil
-		}
-
-		// calculate the number of nodes in upgrading operation
-		upgradingNum := 0
-		for _, status := range upgradeJob.Status.Status {
-			if status.State == v1alpha1.Upgrading {
-				upgradingNum++
+		// ensure the max number of running nodes is concurrency
+		num := concurrency - runningNum
+		now := time.Now()
+		remaining := []string{}
+		for _, node := range nodes {
+			if num <= 0 || (ready != nil && !ready(node, now)) {
+				remaining = append(remaining, node)
+				continue
 			}
+			nodesChan <- node
+			num--
 		}
+		nodes = remaining
 
-		// ensure the max number of upgrading nodes is Concurrency
-		if upgradingNum < concurrency {
-			for i := 0; i < concurrency-upgradingNum; i++ {
-				nodesChan <- allNodes[i]
-			}
+		return len(nodes) == 0, nil
+	}, beehiveContext.Done())
+}
+
+// inMaintenanceWindow returns whether the node can be upgraded now according to the maintenance
+// windows of the NodeGroup it belongs to. Nodes not in any NodeGroup can be upgraded at any time.
+func (dc *DownstreamController) inMaintenanceWindow(nodeName string, now time.Time) bool {
+	node, err := dc.informer.Core().V1().Nodes().Lister().Get(nodeName)
+	if err != nil {
+		klog.Warningf("Failed to get node %s: %v", nodeName, err)
+		return true
+	}
+	groupName, ok := node.Labels[nodegroup.LabelBelongingTo]
+	if !ok {
+		return true
+	}
+	group, err := dc.nodeGroupLister.Get(groupName)
+	if err != nil {
+		klog.Warningf("Failed to get NodeGroup %s of node %s: %v", groupName, nodeName, err)
+		return true
+	}
+
+	in, err := maintenancewindow.InWindow(group.Spec.MaintenanceWindows, now)
+	if err != nil {
+		// do not upgrade the node until the maintenance windows are fixed
+		klog.Errorf("Invalid maintenance windows of NodeGroup %s: %v", groupName, err)
+		return false
+	}
+	return in
+}
+
+// markNodeWaiting marks the node waiting for its maintenance window
+func (dc *DownstreamController) markNodeWaiting(upgrade *v1alpha1.NodeUpgradeJob, node string) {
+	status := &v1alpha1.UpgradeStatus{
+		NodeName: node,
+		State:    v1alpha1.Waiting,
+	}
+	if err := patchNodeUpgradeJobStatus(dc.crdClient, upgrade, status); err != nil {
+		klog.Errorf("Failed to mark node %s waiting: %v", node, err)
+	}
+}
 
-			allNodes = allNodes[:int(concurrency)-upgradingNum]
+// nodesCompleted returns whether all the nodes complete upgrading
+func (dc *DownstreamController) nodesCompleted(upgradeID string, nodes []string) bool {
+	statuses := dc.getNodeStatuses(upgradeID)
+	for _, node := range nodes {
+		status, ok := statuses[node]
+		if !ok || status.State != v1alpha1.Completed {
+			return false
 		}
+	}
+	return true
+}
 
-		if len(allNodes) == 0 {
-			// all the nodes are to upgrade
-			klog.Infof("The number of nodes need to be upgraded reach 0")
-			close(nodesChan)
-			return true, nil
+// getFailedNodes returns the nodes that fail to upgrade or not complete upgrading
+func (dc *DownstreamController) getFailedNodes(upgradeID string, nodes []string) []string {
+	statuses := dc.getNodeStatuses(upgradeID)
+	failed := []string{}
+	for _, node := range nodes {
+		status, ok := statuses[node]
+		if !ok || status.State != v1alpha1.Completed || status.History.Result != v1alpha1.UpgradeSuccess {
+			failed = append(failed, node)
 		}
-		return false, nil
-	})
+	}
+	return failed
+}
 
-	if err != nil {
-		klog.Errorf("failed to select all the related nodes to do upgrade operation: %v", err)
-		close(nodesChan)
+// getNodeStatuses returns the statuses of nodes in the cached NodeUpgradeJob, the key is node name
+func (dc *DownstreamController) getNodeStatuses(upgradeID string) map[string]v1alpha1.UpgradeStatus {
+	statuses := map[string]v1alpha1.UpgradeStatus{}
+	v, ok := dc.nodeUpgradeJobManager.UpgradeMap.Load(upgradeID)
+	if !ok {
+		return statuses
 	}
+	for _, status := range v.(*v1alpha1.NodeUpgradeJob).Status.Status {
+		statuses[status.NodeName] = status
+	}
+	return statuses
 }
 
 func needUpgrade(node *v1.Node, upgradeVersion string) bool {
@@ -330,10 +480,7 @@ func needUpgrade(node *v1.Node, upgradeVersion string) bool {
 func (dc *DownstreamController) handleNodeUpgradeJobTimeout(node string, upgradeID string, upgradeVersion string, historyID string, timeoutSeconds *uint32) {
 	// by default, if we don't receive upgrade response in 300s, we think it's timeout
 	// if we have specified the timeout in Upgrade, we'll use it as the timeout time
-	var timeout uint32 = 300
-	if timeoutSeconds != nil && *timeoutSeconds != 0 {
-		timeout = *timeoutSeconds
-	}
+	timeout := getTimeoutSeconds(timeoutSeconds)
 
 	receiveFeedback := false
 
@@ -434,13 +581,20 @@ func NewDownstreamController(crdInformerFactory crdinformers.SharedInformerFacto
 		klog.Warningf("Create NodeUpgradeJob manager failed with error: %s", err)
 		return nil, err
 	}
+	nodeJobManager, err := manager.NewNodeJobManager(crdInformerFactory.Operations().V1alpha1().NodeJobs().Informer())
+	if err != nil {
+		klog.Warningf("Create NodeJob manager failed with error: %s", err)
+		return nil, err
+	}
 
 	dc := &DownstreamController{
 		kubeClient:            client.GetKubeClient(),
 		informer:              informers.GetInformersManager().GetKubeInformerFactory(),
 		crdClient:             client.GetCRDClient(),
 		nodeUpgradeJobManager: nodeUpgradeJobManager,
+		nodeJobManager:        nodeJobManager,
 		messageLayer:          messagelayer.NodeUpgradeJobControllerMessageLayer(),
+		nodeGroupLister:       crdInformerFactory.Apps().V1alpha1().NodeGroups().Lister(),
 	}
 	return dc, nil
 }
@@ -0,0 +1,263 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// syncNodeJob is used to get NodeJob events from informer
+func (dc *DownstreamController) syncNodeJob() {
+	for {
+		select {
+		case <-beehiveContext.Done():
+			klog.Info("stop sync NodeJob")
+			return
+		case e := <-dc.nodeJobManager.Events():
+			job, ok := e.Object.(*v1alpha1.NodeJob)
+			if !ok {
+				klog.Warningf("object type: %T unsupported", e.Object)
+				continue
+			}
+			switch e.Type {
+			case watch.Added:
+				dc.nodeJobAdded(job)
+			case watch.Deleted:
+				dc.nodeJobManager.JobMap.Delete(job.Name)
+			case watch.Modified:
+				// spec fields are not allowed to update, only the cache needs to be updated
+				if _, ok := dc.nodeJobManager.JobMap.Load(job.Name); !ok {
+					dc.nodeJobAdded(job)
+					continue
+				}
+				dc.nodeJobManager.JobMap.Store(job.Name, job)
+			default:
+				klog.Warningf("NodeJob event type: %s unsupported", e.Type)
+			}
+		}
+	}
+}
+
+func buildNodeJobResource(jobID, nodeID string) string {
+	return fmt.Sprintf("%s%s%s%s%s%s%s", constants.NodeJobOperation, constants.ResourceSep, jobID, constants.ResourceSep, "node", constants.ResourceSep, nodeID)
+}
+
+// nodeJobAdded is used to process addition of new NodeJob in apiserver
+func (dc *DownstreamController) nodeJobAdded(job *v1alpha1.NodeJob) {
+	klog.V(4).Infof("add NodeJob: %v", job)
+	dc.nodeJobManager.JobMap.Store(job.Name, job)
+
+	// the NodeJob has been processed before cloudcore restarts, don't run it again
+	if job.Status.State != v1alpha1.NodeJobInitialValue {
+		klog.Infof("NodeJob %s is already %s, don't run it again", job.Name, job.Status.State)
+		return
+	}
+
+	nodes, unavailable, err := dc.selectNodeJobNodes(job)
+	if err != nil {
+		klog.Errorf("Failed to select nodes of NodeJob %s: %v", job.Name, err)
+		return
+	}
+	klog.Infof("NodeJob %s runs action %s on nodes %v", job.Name, job.Spec.Action, nodes)
+
+	if err = initNodeJobStatus(dc.crdClient, job.Name, nodes, unavailable); err != nil {
+		klog.Errorf("Failed to init NodeJob %s status: %v", job.Name, err)
+		return
+	}
+
+	go dc.runNodeJob(job, nodes)
+}
+
+// selectNodeJobNodes returns the edge nodes to run the NodeJob, and the nodes that are specified
+// by NodeNames but unable to run it, with the reasons.
+func (dc *DownstreamController) selectNodeJobNodes(job *v1alpha1.NodeJob) ([]string, map[string]string, error) {
+	nodes := []string{}
+	unavailable := map[string]string{}
+
+	if len(job.Spec.NodeNames) != 0 {
+		for _, name := range RemoveDuplicateElement(job.Spec.NodeNames) {
+			node, err := dc.informer.Core().V1().Nodes().Lister().Get(name)
+			if err != nil {
+				unavailable[name] = fmt.Sprintf("failed to get node: %v", err)
+				continue
+			}
+			if reason := nodeJobUnavailableReason(node); reason != "" {
+				unavailable[name] = reason
+				continue
+			}
+			nodes = append(nodes, name)
+		}
+		return nodes, unavailable, nil
+	}
+
+	if job.Spec.LabelSelector == nil {
+		return nil, nil, fmt.Errorf("neither NodeNames nor LabelSelector is specified")
+	}
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.LabelSelector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("LabelSelector(%s) is not valid: %v", job.Spec.LabelSelector, err)
+	}
+	list, err := dc.informer.Core().V1().Nodes().Lister().List(selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get nodes with label %s: %v", selector.String(), err)
+	}
+	for _, node := range list {
+		// the selector may select nodes that are not edge nodes, just ignore them
+		if !isEdgeNode(node) {
+			continue
+		}
+		if reason := nodeJobUnavailableReason(node); reason != "" {
+			unavailable[node.Name] = reason
+			continue
+		}
+		nodes = append(nodes, node.Name)
+	}
+	return nodes, unavailable, nil
+}
+
+// nodeJobUnavailableReason returns the reason why the node cannot run NodeJob, and empty string if it can
+func nodeJobUnavailableReason(node *v1.Node) string {
+	if !isEdgeNode(node) {
+		return "node is not an edge node"
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady && condition.Status != v1.ConditionTrue {
+			return "node is not ready"
+		}
+	}
+	return ""
+}
+
+// runNodeJob runs the action of NodeJob on the nodes in batches
+func (dc *DownstreamController) runNodeJob(job *v1alpha1.NodeJob, nodes []string) {
+	if len(nodes) == 0 {
+		return
+	}
+
+	running := func() (int, error) {
+		latest, err := dc.crdClient.OperationsV1alpha1().NodeJobs().Get(context.TODO(), job.Name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		num := 0
+		for _, status := range latest.Status.Status {
+			if status.State == v1alpha1.NodeJobRunning {
+				num++
+			}
+		}
+		return num, nil
+	}
+
+	concurrency := getConcurrency(job.Spec.Concurrency)
+	nodesChan := make(chan string, concurrency)
+	go func() {
+		if err := batchNodes(nodesChan, nodes, concurrency, running, nil); err != nil {
+			klog.Errorf("failed to select all the nodes to run NodeJob %s: %v", job.Name, err)
+		}
+	}()
+
+	for node := range nodesChan {
+		dc.processNodeJob(node, job)
+	}
+}
+
+// processNodeJob sends the action of NodeJob to the edge node
+func (dc *DownstreamController) processNodeJob(node string, job *v1alpha1.NodeJob) {
+	klog.V(4).Infof("begin to run NodeJob %s on node %s", job.Name, node)
+
+	// mark the node running before sending the message, to avoid overwriting the result reported by edge
+	status := &v1alpha1.NodeJobNodeStatus{
+		NodeName: node,
+		State:    v1alpha1.NodeJobRunning,
+		Time:     time.Now().Format(ISO8601UTC),
+	}
+	if err := patchNodeJobNodeStatus(dc.crdClient, job.Name, status); err != nil {
+		klog.Errorf("Failed to mark node %s running in NodeJob %s: %v", node, job.Name, err)
+	}
+
+	timeoutSeconds := getTimeoutSeconds(job.Spec.TimeoutSeconds)
+	req := commontypes.NodeJobRequest{
+		JobID:          job.Name,
+		Action:         job.Spec.Action,
+		Parameters:     job.Spec.Parameters,
+		TimeoutSeconds: timeoutSeconds,
+	}
+	msg := model.NewMessage("").
+		BuildRouter(modules.NodeUpgradeJobControllerModuleName, modules.NodeUpgradeJobControllerModuleGroup,
+			buildNodeJobResource(job.Name, node), constants.NodeJobOperation).
+		FillBody(req)
+	if err := dc.messageLayer.Send(*msg); err != nil {
+		klog.Errorf("Failed to send NodeJob message %v due to error %v", msg.GetID(), err)
+		dc.sendNodeJobResponse(node, job.Name, fmt.Sprintf("failed to send NodeJob message: %v", err))
+		return
+	}
+
+	go dc.handleNodeJobTimeout(node, job.Name, time.Duration(timeoutSeconds)*time.Second)
+}
+
+// handleNodeJobTimeout marks the node failed if cloud doesn't receive the result from the edge node in time
+func (dc *DownstreamController) handleNodeJobTimeout(node, jobID string, timeout time.Duration) {
+	err := wait.Poll(10*time.Second, timeout, func() (bool, error) {
+		v, ok := dc.nodeJobManager.JobMap.Load(jobID)
+		if !ok {
+			return false, fmt.Errorf("NodeJob %s not exist", jobID)
+		}
+		for _, status := range v.(*v1alpha1.NodeJob).Status.Status {
+			if status.NodeName == node {
+				return status.State == v1alpha1.NodeJobCompleted, nil
+			}
+		}
+		return false, nil
+	})
+	if err != wait.ErrWaitTimeout {
+		return
+	}
+
+	klog.Errorf("NOT receive node(%s) NodeJob(%s) feedback response", node, jobID)
+	dc.sendNodeJobResponse(node, jobID, "timeout to get NodeJob response from edge, maybe error due to cloud or edge")
+}
+
+// sendNodeJobResponse sends the failed response of the node to upstream controller directly,
+// to let it update the NodeJob status
+func (dc *DownstreamController) sendNodeJobResponse(node, jobID, reason string) {
+	resp := commontypes.NodeJobResponse{
+		JobID:     jobID,
+		NodeName:  node,
+		Succeeded: false,
+		Message:   reason,
+	}
+	msg := model.NewMessage("").
+		BuildRouter(modules.NodeUpgradeJobControllerModuleName, modules.NodeUpgradeJobControllerModuleGroup,
+			buildNodeJobResource(jobID, node), constants.NodeJobOperation).
+		FillBody(resp)
+	beehiveContext.Send(modules.NodeUpgradeJobControllerModuleName, *msg)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// processPreUpgrade runs pre-flight checks or pulls the image on all the nodes at the same time,
+// and returns the nodes that succeed in time. The failed nodes are marked skipped.
+func (dc *DownstreamController) processPreUpgrade(upgrade *v1alpha1.NodeUpgradeJob, nodes []string, phase v1alpha1.UpgradePhase) []string {
+	if len(nodes) == 0 {
+		return nodes
+	}
+
+	klog.Infof("NodeUpgradeJob %s begins %s phase with nodes %v", upgrade.Name, phase, nodes)
+	if err := patchNodeUpgradeJobPhase(dc.crdClient, upgrade.Name, phase, ""); err != nil {
+		klog.Errorf("Failed to mark NodeUpgradeJob %s %s phase: %v", upgrade.Name, phase, err)
+	}
+
+	operation := constants.NodeUpgradePreflightOperation
+	if phase == v1alpha1.PrePullPhase {
+		operation = constants.NodeUpgradePrePullOperation
+	}
+	req, err := buildPreUpgradeRequest(upgrade)
+	if err != nil {
+		klog.Errorf("Failed to build NodeUpgradeJob %s %s request: %v", upgrade.Name, phase, err)
+		return nil
+	}
+
+	sent := []string{}
+	for _, node := range nodes {
+		msg := model.NewMessage("").
+			BuildRouter(modules.NodeUpgradeJobControllerModuleName, modules.NodeUpgradeJobControllerModuleGroup,
+				buildUpgradeResource(upgrade.Name, node), operation).
+			FillBody(req)
+		if err := dc.messageLayer.Send(*msg); err != nil {
+			klog.Errorf("Failed to send %s message to node %s: %v", operation, node, err)
+			dc.skipNode(upgrade, node, operation, fmt.Sprintf("failed to send %s message: %v", operation, err))
+			continue
+		}
+		sent = append(sent, node)
+	}
+
+	// wait for the results reported by edge nodes
+	results := map[string]*v1alpha1.PreUpgradeResult{}
+	_ = wait.Poll(5*time.Second, getTimeout(upgrade), func() (bool, error) {
+		statuses := dc.getNodeStatuses(upgrade.Name)
+		for _, node := range sent {
+			status := statuses[node]
+			result := status.Preflight
+			if phase == v1alpha1.PrePullPhase {
+				result = status.PrePull
+			}
+			if result != nil {
+				results[node] = result
+			}
+		}
+		return len(results) == len(sent), nil
+	})
+
+	succeeded := []string{}
+	for _, node := range sent {
+		result, ok := results[node]
+		if !ok {
+			dc.skipNode(upgrade, node, operation, fmt.Sprintf("timeout to get %s result from edge", operation))
+			continue
+		}
+		if result.Succeeded {
+			succeeded = append(succeeded, node)
+		}
+	}
+	klog.Infof("NodeUpgradeJob %s finishes %s phase, nodes %v succeeded", upgrade.Name, phase, succeeded)
+	return succeeded
+}
+
+// skipNode marks the node skipped for the failure of pre-flight checks or image pre-pull
+func (dc *DownstreamController) skipNode(upgrade *v1alpha1.NodeUpgradeJob, node, operation, reason string) {
+	result := &v1alpha1.PreUpgradeResult{
+		Succeeded: false,
+		Reason:    reason,
+		Time:      time.Now().Format(ISO8601UTC),
+	}
+	if err := patchPreUpgradeResult(dc.crdClient, upgrade, node, operation, result); err != nil {
+		klog.Errorf("Failed to mark node %s skipped in NodeUpgradeJob %s: %v", node, upgrade.Name, err)
+	}
+}
+
+// buildPreUpgradeRequest builds the request of pre-flight checks and image pre-pull sent to edge nodes
+func buildPreUpgradeRequest(upgrade *v1alpha1.NodeUpgradeJob) (*commontypes.NodePreUpgradeRequest, error) {
+	image, err := getUpgradeImage(upgrade)
+	if err != nil {
+		return nil, err
+	}
+	req := &commontypes.NodePreUpgradeRequest{
+		UpgradeID: upgrade.Name,
+		Version:   upgrade.Spec.Version,
+		Image:     image,
+	}
+	if checks := upgrade.Spec.PreflightChecks; checks != nil {
+		req.MinAvailableDiskMB = checks.MinAvailableDiskMB
+		req.MinAvailableMemoryMB = checks.MinAvailableMemoryMB
+		req.MaxMinorVersionSkew = checks.MaxMinorVersionSkew
+	}
+	return req, nil
+}
@@ -21,12 +21,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
-	jsonpatch "github.com/evanphx/json-patch"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	apimachineryType "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	k8sinformer "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
@@ -35,6 +36,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/nodeupgradejobcontroller/config"
+	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
 	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
@@ -98,6 +100,11 @@ func (uc *UpstreamController) updateNodeUpgradeJobStatus() {
 		case msg := <-uc.nodeUpgradeJobStatusChan:
 			klog.V(4).Infof("Message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 
+			if msg.GetOperation() == constants.NodeJobOperation {
+				uc.updateNodeJobResult(msg)
+				continue
+			}
+
 			// get nodeID and upgradeID from Upgrade msg:
 			nodeID := getNodeName(msg.GetResource())
 			upgradeID := getUpgradeID(msg.GetResource())
@@ -114,6 +121,12 @@ func (uc *UpstreamController) updateNodeUpgradeJobStatus() {
 				continue
 			}
 
+			switch msg.GetOperation() {
+			case constants.NodeUpgradePreflightOperation, constants.NodeUpgradePrePullOperation:
+				uc.updatePreUpgradeStatus(upgrade, nodeID, msg)
+				continue
+			}
+
 			data, err := msg.GetContentData()
 			if err != nil {
 				klog.Errorf("failed to get node upgrade content data: %v", err)
@@ -177,46 +190,234 @@ func (uc *UpstreamController) updateNodeUpgradeJobStatus() {
 	}
 }
 
-// patchNodeUpgradeJobStatus call patch api to patch update NodeUpgradeJob status
+// updatePreUpgradeStatus records the result of pre-flight checks or image pre-pull reported by the edge node
+func (uc *UpstreamController) updatePreUpgradeStatus(upgrade *v1alpha1.NodeUpgradeJob, nodeID string, msg model.Message) {
+	data, err := msg.GetContentData()
+	if err != nil {
+		klog.Errorf("failed to get node pre-upgrade content data: %v", err)
+		return
+	}
+	resp := &types.NodePreUpgradeResponse{}
+	if err = json.Unmarshal(data, resp); err != nil {
+		klog.Errorf("Failed to unmarshal node pre-upgrade response: %v", err)
+		return
+	}
+
+	result := &v1alpha1.PreUpgradeResult{
+		Succeeded: resp.Succeeded,
+		Reason:    resp.Reason,
+		Time:      time.Now().Format(ISO8601UTC),
+	}
+	for _, check := range resp.Checks {
+		result.Checks = append(result.Checks, v1alpha1.PreflightCheckResult{
+			Name:    check.Name,
+			Passed:  check.Passed,
+			Message: check.Message,
+		})
+	}
+	if err = patchPreUpgradeResult(uc.crdClient, upgrade, nodeID, msg.GetOperation(), result); err != nil {
+		klog.Errorf("Failed to update NodeUpgradeJob %s %s result of node %s: %v", upgrade.Name, msg.GetOperation(), nodeID, err)
+	}
+}
+
+// updateNodeJobResult records the result of NodeJob action reported by the edge node
+func (uc *UpstreamController) updateNodeJobResult(msg model.Message) {
+	// the resource of NodeJob is like nodejob/${JobID}/node/${NodeID}
+	nodeID := getNodeName(msg.GetResource())
+	jobID := getUpgradeID(msg.GetResource())
+
+	data, err := msg.GetContentData()
+	if err != nil {
+		klog.Errorf("failed to get NodeJob content data: %v", err)
+		return
+	}
+	resp := &types.NodeJobResponse{}
+	if err = json.Unmarshal(data, resp); err != nil {
+		klog.Errorf("Failed to unmarshal NodeJob response: %v", err)
+		return
+	}
+
+	result := v1alpha1.NodeJobFailed
+	if resp.Succeeded {
+		result = v1alpha1.NodeJobSuccess
+	}
+	status := &v1alpha1.NodeJobNodeStatus{
+		NodeName: nodeID,
+		State:    v1alpha1.NodeJobCompleted,
+		Result:   result,
+		Message:  resp.Message,
+		Artifact: resp.Artifact,
+		Time:     time.Now().Format(ISO8601UTC),
+	}
+	if err = patchNodeJobNodeStatus(uc.crdClient, jobID, status); err != nil {
+		klog.Errorf("Failed to mark node %s completed in NodeJob %s: %v", nodeID, jobID, err)
+	}
+}
+
+// patchNodeUpgradeJobStatus updates the status of the edge node in NodeUpgradeJob.
+// The latest NodeUpgradeJob is used and the update is retried on conflict,
+// to avoid overwriting the statuses reported by other edge nodes at the same time.
 func patchNodeUpgradeJobStatus(crdClient crdClientset.Interface, upgrade *v1alpha1.NodeUpgradeJob, status *v1alpha1.UpgradeStatus) error {
-	oldValue := upgrade.DeepCopy()
+	return updateNodeUpgradeJobStatus(crdClient, upgrade.Name, func(job *v1alpha1.NodeUpgradeJob) *v1alpha1.NodeUpgradeJob {
+		newValue := UpdateNodeUpgradeJobStatus(job, status.DeepCopy())
+		updateNodeUpgradeJobState(newValue)
+		return newValue
+	})
+}
 
-	newValue := UpdateNodeUpgradeJobStatus(oldValue, status)
+// updateNodeUpgradeJobState sets the total state of NodeUpgradeJob according to the state of each edge node
+func updateNodeUpgradeJobState(job *v1alpha1.NodeUpgradeJob) {
+	// the halted NodeUpgradeJob will never be completed
+	if job.Status.State == v1alpha1.Halted {
+		return
+	}
 
 	// after mark each node upgrade state, we also need to judge whether all edge node upgrade is completed
-	// if all edge node is in completed state, we should set the total state to completed
-	var completed int
-	for _, v := range newValue.Status.Status {
-		if v.State == v1alpha1.Completed {
+	// if all edge node is in completed or skipped state in the upgrade phase, we should set the total state to completed,
+	// all the nodes to upgrade are recorded by initNodeUpgradeJobStatus, so the nodes not upgraded yet are counted
+	// if no edge node is upgrading but some are waiting for their maintenance windows, the total state is paused
+	var completed, upgrading, waiting int
+	for _, v := range job.Status.Status {
+		switch v.State {
+		case v1alpha1.Completed, v1alpha1.Skipped:
 			completed++
+		case v1alpha1.Upgrading:
+			upgrading++
+		case v1alpha1.Waiting:
+			waiting++
 		}
 	}
-	if completed == len(newValue.Status.Status) {
-		newValue.Status.State = v1alpha1.Completed
-	} else {
-		newValue.Status.State = v1alpha1.Upgrading
+	switch {
+	case completed == len(job.Status.Status) &&
+		(job.Status.Phase == "" || job.Status.Phase == v1alpha1.UpgradingPhase):
+		job.Status.State = v1alpha1.Completed
+	case upgrading == 0 && waiting != 0:
+		job.Status.State = v1alpha1.Paused
+	default:
+		job.Status.State = v1alpha1.Upgrading
 	}
+}
 
-	oldData, err := json.Marshal(oldValue)
-	if err != nil {
-		return fmt.Errorf("failed to marshal the old NodeUpgradeJob(%s): %v", oldValue.Name, err)
+// patchNodeUpgradeJobPhase updates the phase of NodeUpgradeJob, the NodeUpgradeJob is halted if reason is not empty
+func patchNodeUpgradeJobPhase(crdClient crdClientset.Interface, name string, phase v1alpha1.UpgradePhase, reason string) error {
+	return updateNodeUpgradeJobStatus(crdClient, name, func(job *v1alpha1.NodeUpgradeJob) *v1alpha1.NodeUpgradeJob {
+		job.Status.Phase = phase
+		job.Status.Reason = reason
+		if reason != "" {
+			job.Status.State = v1alpha1.Halted
+		} else {
+			updateNodeUpgradeJobState(job)
+		}
+		return job
+	})
+}
+
+// patchPreUpgradeResult records the result of pre-flight checks or image pre-pull of the edge node,
+// the node is skipped if it fails
+func patchPreUpgradeResult(crdClient crdClientset.Interface, upgrade *v1alpha1.NodeUpgradeJob, node, operation string, result *v1alpha1.PreUpgradeResult) error {
+	status := &v1alpha1.UpgradeStatus{
+		NodeName: node,
+	}
+	if operation == constants.NodeUpgradePreflightOperation {
+		status.Preflight = result
+	} else {
+		status.PrePull = result
+	}
+	if !result.Succeeded {
+		status.State = v1alpha1.Skipped
 	}
+	return patchNodeUpgradeJobStatus(crdClient, upgrade, status)
+}
 
-	newData, err := json.Marshal(newValue)
+// updateNodeUpgradeJobStatus gets the latest NodeUpgradeJob, and updates its status with the result of mutate
+func updateNodeUpgradeJobStatus(crdClient crdClientset.Interface, name string, mutate func(job *v1alpha1.NodeUpgradeJob) *v1alpha1.NodeUpgradeJob) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		job, err := crdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		_, err = crdClient.OperationsV1alpha1().NodeUpgradeJobs().UpdateStatus(context.TODO(), mutate(job), metav1.UpdateOptions{})
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to marshal the new NodeUpgradeJob(%s): %v", newValue.Name, err)
+		return fmt.Errorf("failed to update NodeUpgradeJob(%s) status: %v", name, err)
 	}
+	return nil
+}
 
-	patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
-	if err != nil {
-		return fmt.Errorf("failed to create a merge patch: %v", err)
+// initNodeUpgradeJobStatus records all the nodes to upgrade in the status of NodeUpgradeJob before
+// any phase begins, so that the NodeUpgradeJob is only completed when all of them are completed or skipped.
+func initNodeUpgradeJobStatus(crdClient crdClientset.Interface, name string, nodes []string) error {
+	return updateNodeUpgradeJobStatus(crdClient, name, func(job *v1alpha1.NodeUpgradeJob) *v1alpha1.NodeUpgradeJob {
+		recorded := sets.NewString()
+		for _, status := range job.Status.Status {
+			recorded.Insert(status.NodeName)
+		}
+		for _, node := range nodes {
+			if !recorded.Has(node) {
+				job.Status.Status = append(job.Status.Status, v1alpha1.UpgradeStatus{NodeName: node})
+			}
+		}
+		return job
+	})
+}
+
+// initNodeJobStatus initializes the status of the nodes to run NodeJob,
+// and marks the unavailable nodes completed with failure.
+func initNodeJobStatus(crdClient crdClientset.Interface, name string, nodes []string, unavailable map[string]string) error {
+	return updateNodeJobStatus(crdClient, name, func(job *v1alpha1.NodeJob) *v1alpha1.NodeJob {
+		job.Status.Status = make([]v1alpha1.NodeJobNodeStatus, 0, len(nodes)+len(unavailable))
+		for _, node := range nodes {
+			job.Status.Status = append(job.Status.Status, v1alpha1.NodeJobNodeStatus{NodeName: node})
+		}
+		now := time.Now().Format(ISO8601UTC)
+		for _, node := range sets.StringKeySet(unavailable).List() {
+			job.Status.Status = append(job.Status.Status, v1alpha1.NodeJobNodeStatus{
+				NodeName: node,
+				State:    v1alpha1.NodeJobCompleted,
+				Result:   v1alpha1.NodeJobFailed,
+				Message:  unavailable[node],
+				Time:     now,
+			})
+		}
+		updateNodeJobState(job)
+		return job
+	})
+}
+
+// patchNodeJobNodeStatus updates the status of the edge node in NodeJob, and the total state of NodeJob
+func patchNodeJobNodeStatus(crdClient crdClientset.Interface, name string, status *v1alpha1.NodeJobNodeStatus) error {
+	return updateNodeJobStatus(crdClient, name, func(job *v1alpha1.NodeJob) *v1alpha1.NodeJob {
+		newValue := UpdateNodeJobNodeStatus(job, status.DeepCopy())
+		updateNodeJobState(newValue)
+		return newValue
+	})
+}
+
+// updateNodeJobState sets the total state of NodeJob, it is completed only if all the nodes are completed
+func updateNodeJobState(job *v1alpha1.NodeJob) {
+	for _, status := range job.Status.Status {
+		if status.State != v1alpha1.NodeJobCompleted {
+			job.Status.State = v1alpha1.NodeJobRunning
+			return
+		}
 	}
+	job.Status.State = v1alpha1.NodeJobCompleted
+}
 
-	_, err = crdClient.OperationsV1alpha1().NodeUpgradeJobs().Patch(context.TODO(), newValue.Name, apimachineryType.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+// updateNodeJobStatus gets the latest NodeJob, and updates its status with the result of mutate
+func updateNodeJobStatus(crdClient crdClientset.Interface, name string, mutate func(job *v1alpha1.NodeJob) *v1alpha1.NodeJob) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		job, err := crdClient.OperationsV1alpha1().NodeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		_, err = crdClient.OperationsV1alpha1().NodeJobs().UpdateStatus(context.TODO(), mutate(job), metav1.UpdateOptions{})
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to patch update NodeUpgradeJob status: %v", err)
+		return fmt.Errorf("failed to update NodeJob(%s) status: %v", name, err)
 	}
-
 	return nil
 }
 
@@ -38,6 +38,9 @@ const (
 	NodeUpgrade = "upgrade"
 
 	ISO8601UTC = "2006-01-02T15:04:05Z"
+
+	// DefaultTimeoutSeconds is the default timeout of upgrading one edge node
+	DefaultTimeoutSeconds = 300
 )
 
 // filterVersion returns true only if the edge node version already on the upgrade req
@@ -113,8 +116,21 @@ func UpdateNodeUpgradeJobStatus(old *v1alpha1.NodeUpgradeJob, status *v1alpha1.U
 	for index := range upgrade.Status.Status {
 		// If Node's Upgrade info exist, just overwrite
 		if upgrade.Status.Status[index].NodeName == status.NodeName {
-			// The input status no upgradeTime, we need set it with old value
-			status.History.UpgradeTime = upgrade.Status.Status[index].History.UpgradeTime
+			// The input status no upgradeTime, we need set it with old value,
+			// or the current time if the node is only recorded to upgrade
+			if status.History.UpgradeTime == "" {
+				status.History.UpgradeTime = upgrade.Status.Status[index].History.UpgradeTime
+			}
+			if status.History.UpgradeTime == "" {
+				status.History.UpgradeTime = time.Now().Format(ISO8601UTC)
+			}
+			// The results before upgrading are reported separately, keep them if not set in the input status
+			if status.Preflight == nil {
+				status.Preflight = upgrade.Status.Status[index].Preflight
+			}
+			if status.PrePull == nil {
+				status.PrePull = upgrade.Status.Status[index].PrePull
+			}
 			upgrade.Status.Status[index] = *status
 			return upgrade
 		}
@@ -130,6 +146,25 @@ func UpdateNodeUpgradeJobStatus(old *v1alpha1.NodeUpgradeJob, status *v1alpha1.U
 	return upgrade
 }
 
+// UpdateNodeJobNodeStatus updates the status of the edge node in NodeJob
+// return the updated result
+func UpdateNodeJobNodeStatus(old *v1alpha1.NodeJob, status *v1alpha1.NodeJobNodeStatus) *v1alpha1.NodeJob {
+	// return value job cannot populate the input parameter old
+	job := old.DeepCopy()
+
+	if status.Time == "" {
+		status.Time = time.Now().Format(ISO8601UTC)
+	}
+	for index := range job.Status.Status {
+		if job.Status.Status[index].NodeName == status.NodeName {
+			job.Status.Status[index] = *status
+			return job
+		}
+	}
+	job.Status.Status = append(job.Status.Status, *status)
+	return job
+}
+
 // mergeAnnotationUpgradeHistory constructs the new history based on the origin history
 // and we'll only keep 3 records
 func mergeAnnotationUpgradeHistory(origin, fromVersion, toVersion string) string {
@@ -156,3 +191,52 @@ func GetImageRepo(image string) (string, error) {
 
 	return named.Name(), nil
 }
+
+// getTimeoutSeconds returns the timeout of upgrading one edge node, 0 means the default value
+func getTimeoutSeconds(timeoutSeconds *uint32) uint32 {
+	if timeoutSeconds != nil && *timeoutSeconds != 0 {
+		return *timeoutSeconds
+	}
+	return DefaultTimeoutSeconds
+}
+
+// getTimeout returns the timeout of upgrading one edge node in the NodeUpgradeJob
+func getTimeout(upgrade *v1alpha1.NodeUpgradeJob) time.Duration {
+	return time.Duration(getTimeoutSeconds(upgrade.Spec.TimeoutSeconds)) * time.Second
+}
+
+// getConcurrency returns the max number of edge nodes that can be upgraded or run NodeJob at the same time,
+// 0 means the default value
+func getConcurrency(concurrency int32) int {
+	if concurrency > 0 {
+		return int(concurrency)
+	}
+	return 1
+}
+
+// getCanaryNodesNumber returns the number of nodes to upgrade in the canary phase
+func getCanaryNodesNumber(canary *v1alpha1.CanaryStrategy, total int) int {
+	num := 1
+	if canary.Nodes > 0 {
+		num = int(canary.Nodes)
+	}
+	if num > total {
+		num = total
+	}
+	return num
+}
+
+// getUpgradeImage returns the image used to upgrade edge nodes.
+// if users specify Image, we'll use upgrade Version as its image tag, even though Image contains tag.
+// if not, we'll use default image: kubeedge/installation-package:${Version}
+func getUpgradeImage(upgrade *v1alpha1.NodeUpgradeJob) (string, error) {
+	repo := "kubeedge/installation-package"
+	if upgrade.Spec.Image != "" {
+		var err error
+		repo, err = GetImageRepo(upgrade.Spec.Image)
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s:%s", repo, upgrade.Spec.Version), nil
+}
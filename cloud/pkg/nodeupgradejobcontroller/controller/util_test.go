@@ -17,10 +17,14 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
 )
 
 func TestFilterVersion(t *testing.T) {
@@ -254,3 +258,219 @@ func TestGetImageRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateUpgradeStatusKeepPreUpgradeResults(t *testing.T) {
+	preflight := &v1alpha1.PreUpgradeResult{Succeeded: true}
+	prePull := &v1alpha1.PreUpgradeResult{Succeeded: true}
+	upgrade := &v1alpha1.NodeUpgradeJob{
+		Status: v1alpha1.NodeUpgradeJobStatus{
+			Status: []v1alpha1.UpgradeStatus{
+				{
+					NodeName:  "edge-node",
+					Preflight: preflight,
+				},
+			},
+		},
+	}
+
+	upgrade = UpdateNodeUpgradeJobStatus(upgrade, &v1alpha1.UpgradeStatus{NodeName: "edge-node", PrePull: prePull})
+	upgrade = UpdateNodeUpgradeJobStatus(upgrade, &v1alpha1.UpgradeStatus{NodeName: "edge-node", State: v1alpha1.Upgrading})
+
+	status := upgrade.Status.Status[0]
+	if status.State != v1alpha1.Upgrading || !reflect.DeepEqual(status.Preflight, preflight) || !reflect.DeepEqual(status.PrePull, prePull) {
+		t.Errorf("pre-upgrade results are not kept, got: %+v", status)
+	}
+}
+
+func TestUpdateNodeUpgradeJobState(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   v1alpha1.NodeUpgradeJobStatus
+		expected v1alpha1.UpgradeState
+	}{
+		{
+			name: "case1: all nodes completed or skipped",
+			status: v1alpha1.NodeUpgradeJobStatus{
+				Status: []v1alpha1.UpgradeStatus{{State: v1alpha1.Completed}, {State: v1alpha1.Skipped}},
+			},
+			expected: v1alpha1.Completed,
+		},
+		{
+			name: "case2: node is upgrading",
+			status: v1alpha1.NodeUpgradeJobStatus{
+				Phase:  v1alpha1.UpgradingPhase,
+				Status: []v1alpha1.UpgradeStatus{{State: v1alpha1.Completed}, {State: v1alpha1.Upgrading}},
+			},
+			expected: v1alpha1.Upgrading,
+		},
+		{
+			name: "case3: canary nodes completed",
+			status: v1alpha1.NodeUpgradeJobStatus{
+				Phase:  v1alpha1.CanaryPhase,
+				Status: []v1alpha1.UpgradeStatus{{State: v1alpha1.Completed}},
+			},
+			expected: v1alpha1.Upgrading,
+		},
+		{
+			name: "case3.1: upgrade phase begins after canary nodes completed",
+			status: v1alpha1.NodeUpgradeJobStatus{
+				Phase:  v1alpha1.UpgradingPhase,
+				Status: []v1alpha1.UpgradeStatus{{State: v1alpha1.Completed}, {State: v1alpha1.InitialValue}},
+			},
+			expected: v1alpha1.Upgrading,
+		},
+		{
+			name: "case4: nodes are waiting for maintenance windows",
+			status: v1alpha1.NodeUpgradeJobStatus{
+				Phase:  v1alpha1.UpgradingPhase,
+				Status: []v1alpha1.UpgradeStatus{{State: v1alpha1.Completed}, {State: v1alpha1.Waiting}},
+			},
+			expected: v1alpha1.Paused,
+		},
+		{
+			name: "case5: halted",
+			status: v1alpha1.NodeUpgradeJobStatus{
+				State:  v1alpha1.Halted,
+				Phase:  v1alpha1.CanaryPhase,
+				Status: []v1alpha1.UpgradeStatus{{State: v1alpha1.Completed}},
+			},
+			expected: v1alpha1.Halted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job := &v1alpha1.NodeUpgradeJob{Status: test.status}
+			updateNodeUpgradeJobState(job)
+			if job.Status.State != test.expected {
+				t.Errorf("Got = %v, Want = %v", job.Status.State, test.expected)
+			}
+		})
+	}
+}
+
+func TestInitNodeUpgradeJobStatus(t *testing.T) {
+	job := &v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade"},
+		Status: v1alpha1.NodeUpgradeJobStatus{
+			Status: []v1alpha1.UpgradeStatus{{NodeName: "node1", State: v1alpha1.Skipped}},
+		},
+	}
+	// the object is created by the client, since the group of the resource of the fake client
+	// does not match the one of the scheme
+	crdClient := fake.NewSimpleClientset()
+	if _, err := crdClient.OperationsV1alpha1().NodeUpgradeJobs().Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := initNodeUpgradeJobStatus(crdClient, job.Name, []string{"node1", "node2"}); err != nil {
+		t.Fatal(err)
+	}
+	// entering the upgrade phase does not complete the job before node2 is upgraded
+	if err := patchNodeUpgradeJobPhase(crdClient, job.Name, v1alpha1.UpgradingPhase, ""); err != nil {
+		t.Fatal(err)
+	}
+	got, err := crdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), job.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []v1alpha1.UpgradeStatus{
+		{NodeName: "node1", State: v1alpha1.Skipped},
+		{NodeName: "node2", State: v1alpha1.InitialValue},
+	}
+	if !reflect.DeepEqual(got.Status.Status, expected) {
+		t.Errorf("Got = %v, Want = %v", got.Status.Status, expected)
+	}
+	if got.Status.State != v1alpha1.Upgrading {
+		t.Errorf("Got = %v, Want = %v", got.Status.State, v1alpha1.Upgrading)
+	}
+}
+
+func TestGetCanaryNodesNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		canary   *v1alpha1.CanaryStrategy
+		total    int
+		expected int
+	}{
+		{name: "case1: default", canary: &v1alpha1.CanaryStrategy{}, total: 3, expected: 1},
+		{name: "case2: specified", canary: &v1alpha1.CanaryStrategy{Nodes: 2}, total: 3, expected: 2},
+		{name: "case3: more than total", canary: &v1alpha1.CanaryStrategy{Nodes: 5}, total: 3, expected: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := getCanaryNodesNumber(test.canary, test.total); got != test.expected {
+				t.Errorf("Got = %v, Want = %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateNodeJobNodeStatus(t *testing.T) {
+	old := &v1alpha1.NodeJob{
+		Status: v1alpha1.NodeJobStatus{
+			Status: []v1alpha1.NodeJobNodeStatus{
+				{NodeName: "edge-1", State: v1alpha1.NodeJobRunning, Time: "2023-01-01T00:00:00Z"},
+				{NodeName: "edge-2"},
+			},
+		},
+	}
+
+	completed := &v1alpha1.NodeJobNodeStatus{
+		NodeName: "edge-1",
+		State:    v1alpha1.NodeJobCompleted,
+		Result:   v1alpha1.NodeJobSuccess,
+		Artifact: "cloudcore:/var/lib/kubeedge/nodejob/job/edge-1-logs.tar.gz",
+		Time:     "2023-01-01T00:01:00Z",
+	}
+	job := UpdateNodeJobNodeStatus(old, completed)
+	if !reflect.DeepEqual(job.Status.Status[0], *completed) {
+		t.Errorf("Got = %v, Want = %v", job.Status.Status[0], *completed)
+	}
+	if old.Status.Status[0].State != v1alpha1.NodeJobRunning {
+		t.Errorf("the input NodeJob should not be modified")
+	}
+
+	job = UpdateNodeJobNodeStatus(old, &v1alpha1.NodeJobNodeStatus{NodeName: "edge-3", State: v1alpha1.NodeJobRunning})
+	if len(job.Status.Status) != 3 || job.Status.Status[2].NodeName != "edge-3" || job.Status.Status[2].Time == "" {
+		t.Errorf("expect the status of edge-3 to be appended with time, got %v", job.Status.Status)
+	}
+}
+
+func TestUpdateNodeJobState(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   []v1alpha1.NodeJobNodeStatus
+		expected v1alpha1.NodeJobState
+	}{
+		{
+			name:     "case1: no node to run",
+			expected: v1alpha1.NodeJobCompleted,
+		},
+		{
+			name:     "case2: all nodes completed",
+			status:   []v1alpha1.NodeJobNodeStatus{{State: v1alpha1.NodeJobCompleted}, {State: v1alpha1.NodeJobCompleted}},
+			expected: v1alpha1.NodeJobCompleted,
+		},
+		{
+			name:     "case3: node is running",
+			status:   []v1alpha1.NodeJobNodeStatus{{State: v1alpha1.NodeJobCompleted}, {State: v1alpha1.NodeJobRunning}},
+			expected: v1alpha1.NodeJobRunning,
+		},
+		{
+			name:     "case4: node has not run",
+			status:   []v1alpha1.NodeJobNodeStatus{{State: v1alpha1.NodeJobCompleted}, {}},
+			expected: v1alpha1.NodeJobRunning,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job := &v1alpha1.NodeJob{Status: v1alpha1.NodeJobStatus{Status: test.status}}
+			updateNodeJobState(job)
+			if job.Status.State != test.expected {
+				t.Errorf("Got = %v, Want = %v", job.Status.State, test.expected)
+			}
+		})
+	}
+}
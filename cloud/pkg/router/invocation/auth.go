@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package invocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// authenticate returns the user of the bearer token of the request, reviewed by kube-apiserver
+func (s *Server) authenticate(r *http.Request) (*authenticationv1.UserInfo, error) {
+	authorization := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	if token == "" || token == authorization {
+		return nil, errors.New("bearer token is required")
+	}
+
+	review, err := s.kubeClient.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to review the token: %v", err)
+	}
+	if !review.Status.Authenticated {
+		return nil, errors.New("invalid bearer token")
+	}
+	return &review.Status.User, nil
+}
+
+// authorize checks whether the user may invoke the http services on the node with the verb,
+// by the access to the subresource "services" of the node
+func (s *Server) authorize(ctx context.Context, user *authenticationv1.UserInfo, verb, node string) error {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	review, err := s.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:        verb,
+				Resource:    "nodes",
+				Subresource: "services",
+				Name:        node,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to review the access: %v", err)
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("user %q cannot %s resource \"nodes/services\" of node %q", user.Username, verb, node)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package invocation
+
+import (
+	"sync"
+	"time"
+
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+// result is the result of an async invocation
+type result struct {
+	username string
+	// response is nil while the invocation is pending
+	response *commonType.HTTPResponse
+	expire   time.Time
+}
+
+// resultStore keeps the results of the async invocations for ttl after they complete
+type resultStore struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	results map[string]*result
+}
+
+func newResultStore(ttl time.Duration) *resultStore {
+	return &resultStore{
+		ttl:     ttl,
+		results: make(map[string]*result),
+	}
+}
+
+// add adds the pending invocation of the user, which completes within timeout
+func (rs *resultStore) add(id, username string, timeout time.Duration) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	now := time.Now()
+	for id, r := range rs.results {
+		if now.After(r.expire) {
+			delete(rs.results, id)
+		}
+	}
+	rs.results[id] = &result{username: username, expire: now.Add(timeout + rs.ttl)}
+}
+
+func (rs *resultStore) complete(id string, response *commonType.HTTPResponse) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	if r, ok := rs.results[id]; ok {
+		r.response = response
+		r.expire = time.Now().Add(rs.ttl)
+	}
+}
+
+// get returns the response of the invocation, which is nil while the invocation is pending.
+// The invocation is only found by the user who invoked it
+func (rs *resultStore) get(id, username string) (*commonType.HTTPResponse, bool) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	r, ok := rs.results[id]
+	if !ok || r.username != username || time.Now().After(r.expire) {
+		return nil, false
+	}
+	return r.response, true
+}
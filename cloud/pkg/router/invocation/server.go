@@ -0,0 +1,391 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package invocation
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/client-go/kubernetes"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider/servicebus"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+const (
+	// nodesPrefix is the prefix of the API invoking the http services on the edge nodes,
+	// /nodes/{node}/services/{target}/{path}
+	nodesPrefix = "/nodes/"
+	// invocationsPrefix is the prefix of the API getting the results of the async invocations,
+	// /invocations/{id}
+	invocationsPrefix = "/invocations/"
+)
+
+// hopHeaders are the headers of a single connection, which are not forwarded. The Authorization
+// header authenticates the caller to cloudcore and is not forwarded to the edge node either
+var hopHeaders = []string{
+	"Authorization",
+	"Connection",
+	"Keep-Alive",
+	"Prefer",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// Server serves the API invoking the http services on the edge nodes, by the servicebus of the edge nodes
+type Server struct {
+	config     v1alpha1.ServiceInvocation
+	kubeClient kubernetes.Interface
+	results    *resultStore
+	// newTarget returns the target of the http service on the edge node
+	newTarget func(scheme, host, port, path string) provider.Target
+}
+
+// invocation is a request to the http service on the edge node
+type invocation struct {
+	node   string
+	scheme string
+	host   string
+	port   string
+	// path is the path and the query of the request to the http service
+	path string
+}
+
+func NewServer(config v1alpha1.ServiceInvocation, kubeClient kubernetes.Interface) *Server {
+	return &Server{
+		config:     config,
+		kubeClient: kubeClient,
+		results:    newResultStore(time.Duration(config.AsyncResultTTL) * time.Second),
+		newTarget: func(scheme, host, port, path string) provider.Target {
+			return servicebus.NewTarget(scheme, host, port, path)
+		},
+	}
+}
+
+// Start serves the API over https with the certificate of cloudhub
+func (s *Server) Start() {
+	<-cloudhub.CertsPrepared
+	cert, err := tls.X509KeyPair(pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: hubconfig.Config.Cert}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: hubconfig.Config.Key}))
+	if err != nil {
+		klog.Errorf("failed to load the certificate of the service invocation server: %v", err)
+		return
+	}
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", s.config.Address, s.config.Port),
+		Handler: s,
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		},
+	}
+	klog.Infof("service invocation server listening in %d...", s.config.Port)
+	if err := server.ListenAndServeTLS("", ""); err != nil {
+		klog.Errorf("start service invocation server failed, err: %v", err)
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, err := s.authenticate(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, nodesPrefix):
+		s.invokeService(w, r, user)
+	case strings.HasPrefix(r.URL.Path, invocationsPrefix):
+		s.getResult(w, r, user)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// invokeService forwards the request to the http service on the edge node, and writes the response,
+// or the id of the invocation if the request prefers to be responded asynchronously
+func (s *Server) invokeService(w http.ResponseWriter, r *http.Request, user *authenticationv1.UserInfo) {
+	inv, err := parseInvocation(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.authorize(r.Context(), user, verbOf(r.Method), inv.node); err != nil {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.config.MaxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("the request body is larger than %d bytes", s.config.MaxBodySize))
+			return
+		}
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to read the request body: %v", err))
+		return
+	}
+
+	timeout, async := s.parsePrefer(r.Header.Values("Prefer"))
+	header := r.Header.Clone()
+	for _, h := range hopHeaders {
+		header.Del(h)
+	}
+	target := s.newTarget(inv.scheme, inv.host, inv.port, inv.path)
+
+	if async {
+		id := uuid.New().String()
+		s.results.add(id, user.Username, timeout)
+		go func() {
+			resp, _ := s.invoke(context.Background(), target, inv.node, r.Method, header, body, timeout)
+			s.results.complete(id, resp)
+		}()
+		w.Header().Set("Location", invocationsPrefix+id)
+		writeJSON(w, http.StatusAccepted, map[string]string{"id": id, "status": "pending"})
+		return
+	}
+
+	resp, err := s.invoke(r.Context(), target, inv.node, r.Method, header, body, timeout)
+	if err != nil {
+		klog.Warningf("client disconnected while invoking %s on node %s: %v", inv.path, inv.node, err)
+		return
+	}
+	writeResponse(w, resp)
+}
+
+// getResult writes the result of the async invocation of the user
+func (s *Server) getResult(w http.ResponseWriter, r *http.Request, user *authenticationv1.UserInfo) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, invocationsPrefix)
+	resp, found := s.results.get(id, user.Username)
+	switch {
+	case !found:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("invocation %s not found", id))
+	case resp == nil:
+		writeJSON(w, http.StatusAccepted, map[string]string{"id": id, "status": "pending"})
+	default:
+		writeResponse(w, resp)
+	}
+}
+
+// invoke sends the request to the edge node by the target, and waits for the response until timeout.
+// It only fails if ctx is done
+func (s *Server) invoke(ctx context.Context, target provider.Target, node, method string, header http.Header,
+	body []byte, timeout time.Duration) (*commonType.HTTPResponse, error) {
+	if body == nil {
+		body = []byte{}
+	}
+	messageID := uuid.New().String()
+	data := map[string]interface{}{
+		"messageID": messageID,
+		"nodeName":  node,
+		"param":     "",
+		"method":    method,
+		"header":    header,
+		"data":      body,
+		// the response larger than the max body size is not reassembled
+		"maxBodySize": s.config.MaxBodySize,
+	}
+	// stop is buffered, so that neither the response nor the timeout blocks if both happen
+	stop := make(chan struct{}, 2)
+	respch := make(chan interface{}, 1)
+	errch := make(chan error, 1)
+	go func() {
+		resp, err := target.GoToTarget(data, stop)
+		if err != nil {
+			errch <- err
+			return
+		}
+		respch <- resp
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case resp := <-respch:
+		return s.parseResponse(messageID, resp), nil
+	case err := <-errch:
+		klog.Errorf("failed to invoke the service on node %s, msg id: %s, err: %v", node, messageID, err)
+		return errorResponse(http.StatusInternalServerError, err.Error()), nil
+	case <-timer.C:
+		stop <- struct{}{}
+		klog.Warningf("invoking the service on node %s timed out, msg id: %s", node, messageID)
+		return errorResponse(http.StatusGatewayTimeout, "wait to get response time out"), nil
+	case <-ctx.Done():
+		stop <- struct{}{}
+		return nil, ctx.Err()
+	}
+}
+
+// parseResponse parses the response message of servicebus
+func (s *Server) parseResponse(messageID string, resp interface{}) *commonType.HTTPResponse {
+	msg, ok := resp.(*model.Message)
+	if !ok || msg == nil {
+		return errorResponse(http.StatusGatewayTimeout, "wait to get response time out")
+	}
+	content, err := msg.GetContentData()
+	if err != nil {
+		klog.Errorf("get message %s data err: %v", messageID, err)
+		return errorResponse(http.StatusBadGateway, "invalid response")
+	}
+	var response commonType.HTTPResponse
+	if err := json.Unmarshal(content, &response); err != nil {
+		klog.Errorf("message %s content can not convert to HTTPResponse: %v", messageID, err)
+		return errorResponse(http.StatusBadGateway, "invalid response")
+	}
+	if int64(len(response.Body)) > s.config.MaxBodySize {
+		return errorResponse(http.StatusBadGateway, fmt.Sprintf("the response body is larger than %d bytes", s.config.MaxBodySize))
+	}
+	return &response
+}
+
+// parsePrefer returns the timeout and whether to respond asynchronously, by the Prefer header
+// of RFC 7240, e.g. "Prefer: respond-async, wait=120"
+func (s *Server) parsePrefer(values []string) (time.Duration, bool) {
+	timeout, async := s.config.Timeout, false
+	for _, value := range values {
+		for _, pref := range strings.Split(value, ",") {
+			pref = strings.ToLower(strings.TrimSpace(pref))
+			switch {
+			case pref == "respond-async":
+				async = true
+			case strings.HasPrefix(pref, "wait="):
+				if wait, err := strconv.ParseUint(strings.TrimPrefix(pref, "wait="), 10, 32); err == nil && wait > 0 {
+					timeout = uint32(wait)
+				}
+			}
+		}
+	}
+	if timeout > s.config.MaxTimeout {
+		timeout = s.config.MaxTimeout
+	}
+	return time.Duration(timeout) * time.Second, async
+}
+
+// parseInvocation parses the request path /nodes/{node}/services/{target}/{path}, where
+// the target is "{port}", "{host}:{port}" or "{scheme}:{host}:{port}"
+func parseInvocation(r *http.Request) (*invocation, error) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.EscapedPath(), nodesPrefix), "/", 4)
+	if len(parts) < 3 || parts[0] == "" || parts[1] != "services" || parts[2] == "" {
+		return nil, fmt.Errorf("the path must be %s{node}/services/{target}/{path}", nodesPrefix)
+	}
+
+	inv := &invocation{node: parts[0], path: "/"}
+	if len(parts) == 4 {
+		inv.path += parts[3]
+	}
+	if r.URL.RawQuery != "" {
+		inv.path += "?" + r.URL.RawQuery
+	}
+
+	target := strings.Split(parts[2], ":")
+	switch len(target) {
+	case 1:
+		inv.port = target[0]
+	case 2:
+		inv.host, inv.port = target[0], target[1]
+	case 3:
+		inv.scheme, inv.host, inv.port = target[0], target[1], target[2]
+	default:
+		return nil, fmt.Errorf("the target %s must be {port}, {host}:{port} or {scheme}:{host}:{port}", parts[2])
+	}
+	if port, err := strconv.Atoi(inv.port); err != nil || port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid port %s", inv.port)
+	}
+	if inv.scheme != "" && inv.scheme != "http" && inv.scheme != "https" {
+		return nil, fmt.Errorf("the scheme must be http or https")
+	}
+	if len(target) > 1 && inv.host == "" {
+		return nil, fmt.Errorf("the host must not be empty")
+	}
+	return inv, nil
+}
+
+// verbOf returns the verb of the http method to authorize the request
+func verbOf(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return "get"
+	}
+}
+
+func errorResponse(code int, msg string) *commonType.HTTPResponse {
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	return &commonType.HTTPResponse{Header: header, StatusCode: code, Body: []byte(msg)}
+}
+
+func writeResponse(w http.ResponseWriter, resp *commonType.HTTPResponse) {
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	for _, h := range hopHeaders {
+		w.Header().Del(h)
+	}
+	w.Header().Del("Content-Length")
+	w.WriteHeader(resp.StatusCode)
+	if _, err := w.Write(resp.Body); err != nil {
+		klog.Errorf("response body write error: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeResponse(w, errorResponse(code, msg))
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	body, _ := json.Marshal(v)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	writeResponse(w, &commonType.HTTPResponse{Header: header, StatusCode: code, Body: body})
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package invocation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+// fakeTarget responds the request by the handler, or never responds if the handler is nil
+type fakeTarget struct {
+	resource string
+	handler  func(data map[string]interface{}) *commonType.HTTPResponse
+}
+
+func (t *fakeTarget) Name() string { return "fake" }
+
+func (t *fakeTarget) GoToTarget(data map[string]interface{}, stop chan struct{}) (interface{}, error) {
+	if t.handler == nil {
+		<-stop
+		return nil, nil
+	}
+	return model.NewMessage("").FillBody(t.handler(data)), nil
+}
+
+func newTestServer(t *testing.T, handler func(data map[string]interface{}) *commonType.HTTPResponse) (*Server, *fakeTarget) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "alice-token" {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "alice", Groups: []string{"operators"}},
+			}
+		}
+		if review.Spec.Token == "bob-token" {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "bob"},
+			}
+		}
+		return true, review, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "alice" && attrs.Resource == "nodes" &&
+			attrs.Subresource == "services" && attrs.Name == "edge-node"
+		return true, review, nil
+	})
+
+	s := NewServer(v1alpha1.ServiceInvocation{
+		Enable:         true,
+		Timeout:        1,
+		MaxTimeout:     2,
+		MaxBodySize:    16,
+		AsyncResultTTL: 60,
+	}, client)
+	target := &fakeTarget{handler: handler}
+	s.newTarget = func(scheme, host, port, path string) provider.Target {
+		target.resource = scheme + "|" + host + "|" + port + "|" + path
+		return target
+	}
+	return s, target
+}
+
+func echo(data map[string]interface{}) *commonType.HTTPResponse {
+	header := http.Header{}
+	header.Set("X-Method", data["method"].(string))
+	header.Set("X-Authorization", data["header"].(http.Header).Get("Authorization"))
+	return &commonType.HTTPResponse{Header: header, StatusCode: http.StatusOK, Body: data["data"].([]byte)}
+}
+
+func serve(s *Server, method, path, token string, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	return w
+}
+
+func TestInvokeService(t *testing.T) {
+	s, target := newTestServer(t, echo)
+
+	cases := []struct {
+		name     string
+		token    string
+		path     string
+		body     string
+		code     int
+		resource string
+	}{
+		{"no token", "", "/nodes/edge-node/services/8080/status", "", http.StatusUnauthorized, ""},
+		{"invalid token", "wrong", "/nodes/edge-node/services/8080/status", "", http.StatusUnauthorized, ""},
+		{"forbidden user", "bob-token", "/nodes/edge-node/services/8080/status", "", http.StatusForbidden, ""},
+		{"forbidden node", "alice-token", "/nodes/other-node/services/8080/status", "", http.StatusForbidden, ""},
+		{"invalid target", "alice-token", "/nodes/edge-node/services/http/status", "", http.StatusBadRequest, ""},
+		{"body too large", "alice-token", "/nodes/edge-node/services/8080/status", strings.Repeat("a", 17), http.StatusRequestEntityTooLarge, ""},
+		{"port", "alice-token", "/nodes/edge-node/services/8080/api/v1/status?verbose=true", "ping", http.StatusOK,
+			"||8080|/api/v1/status?verbose=true"},
+		{"scheme host and port", "alice-token", "/nodes/edge-node/services/https:camera.default.svc:443/snapshot", "", http.StatusOK,
+			"https|camera.default.svc|443|/snapshot"},
+	}
+
+	for _, c := range cases {
+		target.resource = ""
+		w := serve(s, http.MethodPost, c.path, c.token, c.body, nil)
+		if w.Code != c.code {
+			t.Errorf("%s: expected code %d, but got %d: %s", c.name, c.code, w.Code, w.Body.String())
+			continue
+		}
+		if target.resource != c.resource {
+			t.Errorf("%s: expected target %q, but got %q", c.name, c.resource, target.resource)
+		}
+		if c.code == http.StatusOK {
+			if w.Body.String() != c.body || w.Header().Get("X-Method") != http.MethodPost {
+				t.Errorf("%s: expected the request carried transparently, but got body %q and method %q",
+					c.name, w.Body.String(), w.Header().Get("X-Method"))
+			}
+			if w.Header().Get("X-Authorization") != "" {
+				t.Errorf("%s: expected the token of the caller not forwarded", c.name)
+			}
+		}
+	}
+}
+
+func TestInvokeServiceTimeout(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+
+	start := time.Now()
+	w := serve(s, http.MethodGet, "/nodes/edge-node/services/8080/status", "alice-token", "", map[string]string{"Prefer": "wait=30"})
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("expected code %d, but got %d", http.StatusGatewayTimeout, w.Code)
+	}
+	// the timeout of the request is limited by the max timeout
+	if elapsed := time.Since(start); elapsed < 2*time.Second || elapsed > 5*time.Second {
+		t.Errorf("expected timeout after the max timeout 2s, but got %v", elapsed)
+	}
+}
+
+func TestInvokeServiceAsync(t *testing.T) {
+	done := make(chan struct{})
+	s, _ := newTestServer(t, func(data map[string]interface{}) *commonType.HTTPResponse {
+		<-done
+		return echo(data)
+	})
+
+	w := serve(s, http.MethodPut, "/nodes/edge-node/services/8080/config", "alice-token", "on", map[string]string{"Prefer": "respond-async"})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected code %d, but got %d", http.StatusAccepted, w.Code)
+	}
+	var accepted map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &accepted); err != nil || accepted["id"] == "" {
+		t.Fatalf("expected the id of the invocation, but got %s", w.Body.String())
+	}
+	location := w.Header().Get("Location")
+	if location != invocationsPrefix+accepted["id"] {
+		t.Errorf("expected location %s, but got %s", invocationsPrefix+accepted["id"], location)
+	}
+
+	if w := serve(s, http.MethodGet, location, "alice-token", "", nil); w.Code != http.StatusAccepted {
+		t.Errorf("expected the pending invocation, but got code %d", w.Code)
+	}
+	close(done)
+	if !waitFor(func() bool {
+		return serve(s, http.MethodGet, location, "alice-token", "", nil).Code == http.StatusOK
+	}) {
+		t.Fatalf("expected the invocation completed")
+	}
+	if w := serve(s, http.MethodGet, location, "alice-token", "", nil); w.Body.String() != "on" || w.Header().Get("X-Method") != http.MethodPut {
+		t.Errorf("expected the response of the invocation, but got %q", w.Body.String())
+	}
+	if w := serve(s, http.MethodGet, location, "bob-token", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected the invocation not found by other users, but got code %d", w.Code)
+	}
+}
+
+func waitFor(condition func() bool) bool {
+	for i := 0; i < 100; i++ {
+		if condition() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
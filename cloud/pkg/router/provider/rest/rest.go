@@ -114,6 +114,7 @@ func (r *Rest) Forward(target provider.Target, data interface{}) (interface{}, e
 	res["nodeName"] = strings.Split(request.RequestURI, "/")[1]
 	res["header"] = request.Header
 	res["method"] = request.Method
+	res["maxBodySize"] = int64(listener.MaxMessageBytes)
 	stop := make(chan struct{})
 	respch := make(chan interface{})
 	errch := make(chan error)
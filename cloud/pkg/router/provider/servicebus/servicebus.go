@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"path"
 	"strings"
@@ -26,8 +27,12 @@ type servicebusFactory struct{}
 type ServiceBus struct {
 	targetPath  string
 	servicePort string
-	nodeName    string
-	TargetURL   string
+	// serviceHost and serviceScheme are the host and the scheme of the target on the edge node,
+	// the target is at 127.0.0.1 over http if serviceHost is empty
+	serviceHost   string
+	serviceScheme string
+	nodeName      string
+	TargetURL     string
 }
 
 func init() {
@@ -114,12 +119,31 @@ func (sf *servicebusFactory) GetTarget(ep *v1.RuleEndpoint, targetResource map[s
 		return nil
 	}
 	cli := &ServiceBus{
-		targetPath:  targetPath,
-		servicePort: ep.Spec.Properties["service_port"],
+		targetPath:    targetPath,
+		servicePort:   ep.Spec.Properties["service_port"],
+		serviceHost:   ep.Spec.Properties["service_host"],
+		serviceScheme: ep.Spec.Properties["service_scheme"],
+	}
+	if cli.serviceScheme == "" {
+		cli.serviceScheme = "http"
 	}
 	return cli
 }
 
+// NewTarget returns the target of the http service at the port and the path on the edge node,
+// the host is 127.0.0.1 and the scheme is http if they are empty
+func NewTarget(scheme, host, port, targetPath string) *ServiceBus {
+	if scheme == "" {
+		scheme = "http"
+	}
+	return &ServiceBus{
+		targetPath:    targetPath,
+		servicePort:   port,
+		serviceHost:   host,
+		serviceScheme: scheme,
+	}
+}
+
 func (sb *ServiceBus) GoToTarget(data map[string]interface{}, stop chan struct{}) (interface{}, error) {
 	var response *model.Message
 	messageID, ok := data["messageID"].(string)
@@ -137,7 +161,7 @@ func (sb *ServiceBus) GoToTarget(data map[string]interface{}, stop chan struct{}
 
 	msg := model.NewMessage("")
 	msg.BuildHeader(messageID, "", msg.GetTimestamp())
-	resource := "node/" + nodeName + "/" + sb.servicePort + ":"
+	resource := "node/" + nodeName + "/" + sb.target()
 	if !ok || param == "" {
 		resource = resource + sb.targetPath
 	} else {
@@ -148,12 +172,26 @@ func (sb *ServiceBus) GoToTarget(data map[string]interface{}, stop chan struct{}
 	msg.SetRoute(modules.RouterSourceServiceBus, modules.UserGroup)
 	beehiveContext.Send(modules.CloudHubModuleName, *msg)
 	if stop != nil {
+		// the body of the response may be streamed in several messages, it is limited by maxBodySize if set
+		maxBodySize, _ := data["maxBodySize"].(int64)
+		assembler := newResponseAssembler(maxBodySize)
 		listener.MessageHandlerInstance.SetCallback(messageID, func(message *model.Message) {
-			response = message
-			stop <- struct{}{}
+			if reassembled, ok := assembler.add(message); ok {
+				response = reassembled
+				stop <- struct{}{}
+			}
 		})
 		<-stop
 		listener.MessageHandlerInstance.DelCallback(messageID)
 	}
 	return response, nil
 }
+
+// target returns the resource prefix identifying the target on the edge node, "<port>:" for the
+// target at 127.0.0.1, or "<scheme>://<host>:<port>" otherwise
+func (sb *ServiceBus) target() string {
+	if sb.serviceHost == "" {
+		return sb.servicePort + ":"
+	}
+	return sb.serviceScheme + "://" + net.JoinHostPort(sb.serviceHost, sb.servicePort)
+}
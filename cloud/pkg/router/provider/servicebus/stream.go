@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebus
+
+import (
+	"encoding/json"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+// responseAssembler reassembles the response streamed by the servicebus of the edge node, whose body
+// is sent in chunks, one message per chunk
+type responseAssembler struct {
+	// maxBodySize is the max size of the reassembled body, the chunks beyond it are dropped
+	// and the body is cut at maxBodySize+1 bytes, so that it is still known to be too large.
+	// The body is not limited if it is not positive
+	maxBodySize int64
+
+	lock   sync.Mutex
+	first  *model.Message
+	header commonType.HTTPResponse
+	chunks map[int][]byte
+	size   int64
+	// last is the index of the last chunk, -1 until the last chunk is received
+	last int
+}
+
+func newResponseAssembler(maxBodySize int64) *responseAssembler {
+	return &responseAssembler{
+		maxBodySize: maxBodySize,
+		chunks:      make(map[int][]byte),
+		last:        -1,
+	}
+}
+
+// add adds the chunk of the message, and returns the reassembled response message once all the chunks
+// are received. The chunks may be received out of order. A message of the edge node that doesn't stream
+// the responses is returned as is
+func (a *responseAssembler) add(msg *model.Message) (*model.Message, bool) {
+	content, err := msg.GetContentData()
+	if err != nil {
+		return msg, true
+	}
+	var chunk commonType.HTTPResponse
+	if err := json.Unmarshal(content, &chunk); err != nil || (chunk.Seq == 0 && !chunk.More) {
+		return msg, true
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if _, ok := a.chunks[chunk.Seq]; ok {
+		klog.Warningf("duplicated chunk %d of the response of message %s", chunk.Seq, msg.GetParentID())
+		return nil, false
+	}
+	if chunk.Seq == 0 {
+		a.first, a.header = msg, chunk
+	}
+	if !chunk.More {
+		a.last = chunk.Seq
+	}
+	body := chunk.Body
+	if a.maxBodySize > 0 && a.size+int64(len(body)) > a.maxBodySize {
+		body = body[:a.maxBodySize+1-a.size]
+	}
+	a.chunks[chunk.Seq] = body
+	a.size += int64(len(body))
+	if a.first == nil || a.last < 0 || len(a.chunks) != a.last+1 {
+		return nil, false
+	}
+
+	response := commonType.HTTPResponse{
+		Header:     a.header.Header,
+		StatusCode: a.header.StatusCode,
+		Body:       make([]byte, 0, a.size),
+	}
+	for seq := 0; seq <= a.last; seq++ {
+		response.Body = append(response.Body, a.chunks[seq]...)
+	}
+	reassembled := *a.first
+	reassembled.Content = response
+	return &reassembled, true
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebus
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+func chunkMessage(response commonType.HTTPResponse) *model.Message {
+	data, _ := json.Marshal(response)
+	return model.NewMessage("request").FillBody(data)
+}
+
+func TestResponseAssembler(t *testing.T) {
+	header := http.Header{"Content-Type": []string{"text/plain"}}
+	cases := []struct {
+		name        string
+		maxBodySize int64
+		chunks      []commonType.HTTPResponse
+		body        string
+	}{
+		{
+			name:   "not streamed",
+			chunks: []commonType.HTTPResponse{{Header: header, StatusCode: http.StatusOK, Body: []byte("abc")}},
+			body:   "abc",
+		},
+		{
+			name: "in order",
+			chunks: []commonType.HTTPResponse{
+				{Header: header, StatusCode: http.StatusOK, Body: []byte("abcd"), More: true},
+				{Body: []byte("efgh"), Seq: 1, More: true},
+				{Body: []byte("ij"), Seq: 2},
+			},
+			body: "abcdefghij",
+		},
+		{
+			name: "out of order",
+			chunks: []commonType.HTTPResponse{
+				{Body: []byte("ij"), Seq: 2},
+				{Header: header, StatusCode: http.StatusOK, Body: []byte("abcd"), More: true},
+				{Body: []byte("efgh"), Seq: 1, More: true},
+			},
+			body: "abcdefghij",
+		},
+		{
+			name:        "too large",
+			maxBodySize: 5,
+			chunks: []commonType.HTTPResponse{
+				{Header: header, StatusCode: http.StatusOK, Body: []byte("abcd"), More: true},
+				{Body: []byte("efgh"), Seq: 1, More: true},
+				{Body: []byte("ij"), Seq: 2},
+			},
+			body: "abcdef",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := newResponseAssembler(c.maxBodySize)
+			var reassembled *model.Message
+			for i, chunk := range c.chunks {
+				msg, ok := a.add(chunkMessage(chunk))
+				if ok != (i == len(c.chunks)-1) {
+					t.Fatalf("expected the response reassembled only by the last chunk, but got %v by chunk %d", ok, i)
+				}
+				reassembled = msg
+			}
+
+			data, err := reassembled.GetContentData()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var response commonType.HTTPResponse
+			if err := json.Unmarshal(data, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "text/plain" ||
+				string(response.Body) != c.body || response.More {
+				t.Errorf("expected the response with body %q, but got %+v", c.body, response)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	routerconfig "github.com/kubeedge/kubeedge/cloud/pkg/router/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/invocation"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
 	// init eventbus
 	_ "github.com/kubeedge/kubeedge/cloud/pkg/router/provider/eventbus"
@@ -49,5 +51,8 @@ func (r *router) Enable() bool {
 
 func (r *router) Start() {
 	klog.Info("In router module, start...")
+	if c := routerconfig.Config.ServiceInvocation; c != nil && c.Enable {
+		go invocation.NewServer(*c, client.GetKubeClient()).Start()
+	}
 	listener.Process(r.Name())
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
 	routerv1 "github.com/kubeedge/kubeedge/pkg/apis/rules/v1"
@@ -112,8 +113,11 @@ func addRule(rule *routerv1.Rule) error {
 	if err := source.RegisterListener(func(data interface{}) (interface{}, error) {
 		//TODO Use goroutine pool later
 		var execResult ExecResult
+		start := time.Now()
 		resp, err := source.Forward(target, data)
+		result := monitor.ResultSuccess
 		if err != nil {
+			result = monitor.ResultFailure
 			// rule.Status.Fail++
 			// record error info for rule
 			errMsg := ErrorMsg{Detail: err.Error(), Timestamp: time.Now()}
@@ -121,6 +125,8 @@ func addRule(rule *routerv1.Rule) error {
 		} else {
 			execResult = ExecResult{RuleID: rule.Name, ProjectID: rule.Namespace, Status: "SUCCESS"}
 		}
+		monitor.RuleExecutions.WithLabelValues(result).Inc()
+		monitor.ObserveSince(monitor.RuleExecutionDuration.WithLabelValues(result), start)
 		ResultChannel <- execResult
 		return resp, nil
 	}); err != nil {
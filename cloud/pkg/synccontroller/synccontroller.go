@@ -19,6 +19,7 @@ import (
 	keclient "github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller/config"
 	configv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/apis/reliablesyncs/v1alpha1"
@@ -125,9 +126,12 @@ func (sctl *SyncController) reconcileObjectSyncs() {
 		klog.Errorf("Failed to list all the ObjectSyncs: %v", err)
 	}
 
+	counts := make(map[string]int)
 	for _, sync := range allObjectSyncs {
+		counts[monitor.NodeLabel(getNodeName(sync.Name))]++
 		sctl.reconcileObjectSync(sync)
 	}
+	monitor.SetObjectSyncs("ObjectSync", counts)
 }
 
 // reconcileClusterObjectSyncs compare the version of the resource that has been sent
@@ -139,9 +143,12 @@ func (sctl *SyncController) reconcileClusterObjectSyncs() {
 		klog.Errorf("Failed to list all the ClusterObjectSyncs: %v", err)
 	}
 
+	counts := make(map[string]int)
 	for _, sync := range allClusterObjectSyncs {
+		counts[monitor.NodeLabel(getNodeName(sync.Name))]++
 		sctl.reconcileClusterObjectSync(sync)
 	}
+	monitor.SetObjectSyncs("ClusterObjectSync", counts)
 }
 
 func (sctl *SyncController) deleteObjectSyncs() {
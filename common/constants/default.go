@@ -31,7 +31,16 @@ const (
 	DefaultCAURL                = "/ca.crt"
 	DefaultCertURL              = "/edge.crt"
 	DefaultNodeUpgradeURL       = "/nodeupgrade"
+	DefaultNodeJobArtifactURL   = "/nodejob/artifact"
 	DefaultServiceAccountIssuer = "https://kubernetes.default.svc.cluster.local"
+	// DefaultOfflineTokenIssuerURL is used by edge nodes to apply for the delegation certificate
+	// of the offline ServiceAccount token issuer
+	DefaultOfflineTokenIssuerURL = "/offlinetokenissuer.crt"
+	// OfflineTokenIssuerPrefix is the prefix of the issuer of tokens minted on edge nodes and
+	// the common name of their delegation certificates, followed by the node name
+	OfflineTokenIssuerPrefix = "kubeedge:offline-token-issuer:"
+	// DefaultOfflineTokenMaxExpirationSeconds is the default max lifetime of tokens minted on edge nodes
+	DefaultOfflineTokenMaxExpirationSeconds = 600
 
 	// Edged
 	DefaultDockerAddress       = "unix:///var/run/docker.sock"
@@ -61,6 +70,23 @@ const (
 	DefaultRemoteQueryTimeout = 60
 	DefaultMetaServerAddr     = "127.0.0.1:10550"
 
+	// MonitorServer
+	DefaultEdgeMonitorServerAddr = "127.0.0.1:10352"
+
+	// Tracing
+	DefaultTracingEndpoint               = "localhost:4317"
+	DefaultTracingSamplingRatePerMillion = 10000
+	DefaultTracingMaxBufferedSpans       = 2048
+	// MQTT bridge
+	DefaultMqttBridgeMaxBufferedMessages = 1000
+	// ServiceBus
+	DefaultServiceBusMaxBodySize = 5 * 1e6
+	// ServiceInvocation
+	DefaultServiceInvocationPort        = 10005
+	DefaultServiceInvocationMaxBodySize = 12 * (1 << 20)
+	// EdgeCoreMetricsPath is the path of the cloudstream tunnel to scrape the metrics of edgecore
+	EdgeCoreMetricsPath = "/metrics/edgecore"
+
 	// Config
 	DefaultKubeContentType         = "application/vnd.kubernetes.protobuf"
 	DefaultKubeNamespace           = v1.NamespaceAll
@@ -118,6 +144,37 @@ const (
 	DefaultNodeUpgradeJobEventBuffer  = 1
 	DefaultNodeUpgradeJobWorkers      = 1
 
+	// DynamicController
+	DefaultMaxWatchesPerNode = 100
+	// SubscriptionLabelPrefix is the prefix of the node labels that declare the resources
+	// an edge node may watch, e.g. "subscription.kubeedge.io/deployments.apps: 5"
+	SubscriptionLabelPrefix = "subscription.kubeedge.io/"
+
+	// NodeUpgradeJob operations done on edge nodes before upgrading
+	NodeUpgradePreflightOperation = "preflight"
+	NodeUpgradePrePullOperation   = "prepull"
+
+	// NodeJob
+	NodeJobOperation = "nodejob"
+	// DefaultNodeJobArtifactDir is the directory on cloudcore to store the artifacts uploaded by NodeJob
+	DefaultNodeJobArtifactDir = "/var/lib/kubeedge/nodejob"
+	// DefaultNodeJobArtifactLimit is the max size of an artifact uploaded by NodeJob
+	DefaultNodeJobArtifactLimit = 100 * 1024 * 1024
+	// DefaultNodeJobTimeoutSeconds is the timeout of running the action on one edge node if it is not set
+	DefaultNodeJobTimeoutSeconds = 300
+
+	// Audit
+	// AuditGroup is the message group of the audit events forwarded from edge nodes to cloudcore
+	AuditGroup = "audit"
+	// DefaultCloudAuditLogPath is the default audit log file of cloudcore
+	DefaultCloudAuditLogPath = "/var/log/kubeedge/cloudcore-audit.log"
+	// DefaultAuditLogMaxAge is the default max days to retain the rotated audit log files
+	DefaultAuditLogMaxAge = 7
+	// DefaultAuditLogMaxBackups is the default max number of the rotated audit log files to retain
+	DefaultAuditLogMaxBackups = 10
+	// DefaultAuditLogMaxSize is the default max size in megabytes of the audit log file before it gets rotated
+	DefaultAuditLogMaxSize = 100
+
 	// Resource sep
 	ResourceSep = "/"
 
@@ -133,6 +190,13 @@ const (
 	CSIOperationTypeDeleteVolume              = "deletevolume"
 	CSIOperationTypeControllerPublishVolume   = "controllerpublishvolume"
 	CSIOperationTypeControllerUnpublishVolume = "controllerunpublishvolume"
+	CSIOperationTypeControllerExpandVolume    = "controllerexpandvolume"
+	CSIOperationTypeCreateSnapshot            = "createsnapshot"
+	CSIOperationTypeDeleteSnapshot            = "deletesnapshot"
+	CSIOperationTypeListSnapshots             = "listsnapshots"
+	CSIOperationTypeGetCapacity               = "getcapacity"
+	CSIOperationTypeListVolumes               = "listvolumes"
+	CSIOperationTypeControllerGetCapabilities = "controllergetcapabilities"
 	CSISyncMsgRespTimeout                     = 1 * time.Minute
 
 	ServerAddress = "127.0.0.1"
@@ -155,4 +219,13 @@ const (
 
 	// DefaultManifestsDir edge node default static pod path
 	DefaultManifestsDir = "/etc/kubeedge/manifests"
+
+	// KubeEdgeBinaryDir is where the binaries of KubeEdge are installed on the nodes
+	KubeEdgeBinaryDir = "/usr/local/bin"
+	// EdgeCoreBinaryName is the name of the edgecore binary and its systemd service
+	EdgeCoreBinaryName = "edgecore"
+	// KeadmBinaryName is the name of the keadm binary
+	KeadmBinaryName = "keadm"
+	// SystemdBootPath exists as a directory if the node is booted with systemd
+	SystemdBootPath = "/run/systemd/system"
 )
@@ -19,6 +19,11 @@ const (
 	DefaultMqttCertFile = "/etc/kubeedge/certs/server.crt"
 	DefaultMqttKeyFile  = "/etc/kubeedge/certs/server.key"
 
+	DefaultOfflineTokenIssuerKeyFile  = "/etc/kubeedge/certs/sa-issuer.key"
+	DefaultOfflineTokenIssuerCertFile = "/etc/kubeedge/certs/sa-issuer.crt"
+
+	DefaultMetaServerAuditLogPath = "/var/log/kubeedge/metaserver-audit.log"
+
 	// Bootstrap file, contains token used by edgecore to apply for ca/cert
 	BootstrapFile = "/etc/kubeedge/bootstrap-edgecore.conf"
 
@@ -22,6 +22,11 @@ const (
 	DefaultMqttCertFile = "c:\\etc\\kubeedge\\certs\\server.crt"
 	DefaultMqttKeyFile  = "c:\\etc\\kubeedge\\certs\\server.key"
 
+	DefaultOfflineTokenIssuerKeyFile  = "c:\\etc\\kubeedge\\certs\\sa-issuer.key"
+	DefaultOfflineTokenIssuerCertFile = "c:\\etc\\kubeedge\\certs\\sa-issuer.crt"
+
+	DefaultMetaServerAuditLogPath = "c:\\var\\log\\kubeedge\\metaserver-audit.log"
+
 	// Bootstrap file, contains token used by edgecore to apply for ca/cert
 	BootstrapFile = "c:\\etc\\kubeedge\\bootstrap-edgecore.conf"
 
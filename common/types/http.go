@@ -15,6 +15,11 @@ type HTTPResponse struct {
 	Header     http.Header `json:"header"`
 	StatusCode int         `json:"status_code"`
 	Body       []byte      `json:"body"`
+	// Seq is the index of the chunk of a body streamed in several messages,
+	// Header and StatusCode are only carried by the first chunk
+	Seq int `json:"seq,omitempty"`
+	// More indicates that more chunks of the body follow
+	More bool `json:"more,omitempty"`
 }
 
 const (
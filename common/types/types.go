@@ -48,6 +48,52 @@ type NodeUpgradeJobResponse struct {
 	Reason      string
 }
 
+// NodePreUpgradeRequest is pre-flight or image pre-pull msg coming from cloud to edge
+type NodePreUpgradeRequest struct {
+	UpgradeID string
+	Version   string
+	Image     string
+	// MinAvailableDiskMB, MinAvailableMemoryMB and MaxMinorVersionSkew are only used by pre-flight checks,
+	// zero or nil value means no check.
+	MinAvailableDiskMB   int64
+	MinAvailableMemoryMB int64
+	MaxMinorVersionSkew  *int32
+}
+
+// NodePreUpgradeResponse is used to report the result of pre-flight checks or image pre-pull from edge to cloud
+type NodePreUpgradeResponse struct {
+	UpgradeID string
+	NodeName  string
+	Succeeded bool
+	Reason    string
+	Checks    []NodePreflightCheck
+}
+
+// NodePreflightCheck is the result of one pre-flight check on edge
+type NodePreflightCheck struct {
+	Name    string
+	Passed  bool
+	Message string
+}
+
+// NodeJobRequest is the msg coming from cloud to edge to run a NodeJob action
+type NodeJobRequest struct {
+	JobID          string
+	Action         string
+	Parameters     map[string]string
+	TimeoutSeconds uint32
+}
+
+// NodeJobResponse is used to report the result of a NodeJob action from edge to cloud
+type NodeJobResponse struct {
+	JobID     string
+	NodeName  string
+	Succeeded bool
+	Message   string
+	// Artifact is where the artifact uploaded to cloudcore is stored, empty if no artifact
+	Artifact string
+}
+
 // ObjectResp is the object that api-server response
 type ObjectResp struct {
 	Object metaV1.Object
@@ -18,6 +18,7 @@ import (
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin"
 	"github.com/kubeedge/kubeedge/edge/pkg/edged"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub"
@@ -30,6 +31,7 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2/validation"
 	"github.com/kubeedge/kubeedge/pkg/features"
+	"github.com/kubeedge/kubeedge/pkg/tracing"
 	"github.com/kubeedge/kubeedge/pkg/util"
 	"github.com/kubeedge/kubeedge/pkg/util/flag"
 	utilvalidation "github.com/kubeedge/kubeedge/pkg/util/validation"
@@ -126,6 +128,24 @@ offering HTTP client capabilities to components of cloud to reach HTTP servers r
 				klog.Infof("Get IP address by custom interface successfully, %s: %s", config.Modules.Edged.CustomInterfaceName, config.Modules.Edged.NodeIP)
 			}
 
+			// start monitor server
+			if config.MonitorServer != nil && config.MonitorServer.Enable {
+				go monitor.ServeMonitor(*config.MonitorServer)
+			}
+
+			// start tracing of the messages
+			if config.Tracing != nil && config.Tracing.Enable {
+				err := tracing.Init("edgecore", tracing.Options{
+					Endpoint:               config.Tracing.Endpoint,
+					SamplingRatePerMillion: config.Tracing.SamplingRatePerMillion,
+					MaxBufferedSpans:       int(config.Tracing.MaxBufferedSpans),
+				}, tracing.NodeNameKey.String(config.Modules.Edged.HostnameOverride))
+				if err != nil {
+					klog.Exit(err)
+				}
+				defer tracing.Shutdown()
+			}
+
 			registerModules(config)
 			// start all modules
 			core.Run()
@@ -7,6 +7,7 @@ import (
 // constant defining node connection types
 const (
 	ResourceTypeNodeConnection = "node/connection"
+	ResourceTypeMqttBridge     = "mqttbridge"
 	SourceNodeConnection       = "edgehub"
 	OperationNodeConnection    = "connect"
 	OperationSubscribe         = "subscribe"
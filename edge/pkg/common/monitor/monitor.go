@@ -0,0 +1,254 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	beehivecontext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/pkg/util/profiling"
+)
+
+const (
+	metricNamespace = "KubeEdge"
+
+	// EdgeHubSubsystem - subsystem name used by EdgeHub
+	EdgeHubSubsystem = "EdgeHub"
+	// MetaManagerSubsystem - subsystem name used by MetaManager
+	MetaManagerSubsystem = "MetaManager"
+	// MetaServerSubsystem - subsystem name used by MetaServer
+	MetaServerSubsystem = "MetaServer"
+	// EventBusSubsystem - subsystem name used by EventBus
+	EventBusSubsystem = "EventBus"
+	// DeviceTwinSubsystem - subsystem name used by DeviceTwin
+	DeviceTwinSubsystem = "DeviceTwin"
+)
+
+// The values of the labels of metrics
+const (
+	DirectionUpstream   = "upstream"
+	DirectionDownstream = "downstream"
+
+	BrokerInternal = "internal"
+	BrokerExternal = "external"
+
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	CloudConnected = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: EdgeHubSubsystem,
+			Name:      "cloud_connected",
+			Help:      "Whether edgecore is connected to cloudcore, 1 for connected and 0 for disconnected",
+		},
+	)
+
+	CloudReconnects = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: EdgeHubSubsystem,
+			Name:      "cloud_reconnects_total",
+			Help:      "Number of the connections to cloudcore established again after the previous one was broken",
+		},
+	)
+
+	EdgeHubMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: EdgeHubSubsystem,
+			Name:      "messages_total",
+			Help:      "Number of messages sent to or received from cloudcore",
+		},
+		[]string{"direction"},
+	)
+
+	EdgeHubThrottleDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: EdgeHubSubsystem,
+			Name:      "throttle_duration_seconds",
+			Help:      "Duration that the messages to cloudcore waited for the client-side rate limiter",
+			Buckets:   []float64{.001, .005, .01, .05, .1, .5, 1, 5, 10},
+		},
+	)
+
+	MetaDBOperationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: MetaManagerSubsystem,
+			Name:      "db_operation_duration_seconds",
+			Help:      "Duration of the operations on the meta tables of the local database",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+		[]string{"operation"},
+	)
+
+	MetaServerWatches = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: MetaServerSubsystem,
+			Name:      "watches",
+			Help:      "Number of the active watches served by metaserver",
+		},
+	)
+
+	EventBusPublishes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: EventBusSubsystem,
+			Name:      "publishes_total",
+			Help:      "Number of messages published to the internal or external MQTT broker",
+		},
+		[]string{"broker", "result"},
+	)
+
+	DMICallDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: DeviceTwinSubsystem,
+			Name:      "dmi_call_duration_seconds",
+			Help:      "Duration of the DMI calls between edgecore and the mappers by the method and gRPC code",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method", "code"},
+	)
+)
+
+// collectors are all the metrics of edgecore
+var collectors = []prometheus.Collector{
+	CloudConnected,
+	CloudReconnects,
+	EdgeHubMessages,
+	EdgeHubThrottleDuration,
+	MetaDBOperationDuration,
+	MetaServerWatches,
+	EventBusPublishes,
+	DMICallDuration,
+}
+
+var registerOnce sync.Once
+
+// registerMetrics register all metrics.
+func registerMetrics() {
+	registerOnce.Do(func() {
+		prometheus.MustRegister(collectors...)
+	})
+}
+
+// ObserveSince records the seconds elapsed since start
+func ObserveSince(observer prometheus.Observer, start time.Time) {
+	observer.Observe(time.Since(start).Seconds())
+}
+
+// ObserveDMICall records the duration of the DMI call of the gRPC full method
+func ObserveDMICall(fullMethod string, err error, start time.Time) {
+	ObserveSince(DMICallDuration.WithLabelValues(path.Base(fullMethod), status.Code(err).String()), start)
+}
+
+// Result returns the value of the result label by err
+func Result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
+
+var (
+	addressLock sync.RWMutex
+	// address is the bind address of the monitor server, empty if it is not serving
+	address string
+)
+
+// Address returns the bind address of the monitor server,
+// and false if the monitor server is not enabled.
+func Address() (string, bool) {
+	addressLock.RLock()
+	defer addressLock.RUnlock()
+	return address, address != ""
+}
+
+// healthz reports that edgecore is alive
+func healthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// readyz reports whether edgecore is connected to cloudcore
+func readyz(w http.ResponseWriter, _ *http.Request) {
+	if !cloudconnection.IsConnected() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(cloudconnection.CloudDisconnected))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// newServeMux creates the handlers of the monitor server
+func newServeMux(config v1alpha2.MonitorServer) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", healthz)
+	mux.HandleFunc("/readyz", readyz)
+	if config.EnableProfiling {
+		profiling.InstallHandler(mux)
+	}
+	return mux
+}
+
+// ServeMonitor serves the metrics and the health of edgecore.
+func ServeMonitor(config v1alpha2.MonitorServer) {
+	registerMetrics()
+
+	s := http.Server{
+		Addr:    config.BindAddress,
+		Handler: newServeMux(config),
+	}
+
+	go func() {
+		ctx := beehivecontext.GetContext()
+		<-ctx.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := s.Shutdown(ctx); err != nil {
+			klog.Errorf("Server shutdown failed: %v", err)
+		}
+	}()
+
+	addressLock.Lock()
+	address = config.BindAddress
+	addressLock.Unlock()
+
+	klog.Infof("starting monitor server on addr: %s", config.BindAddress)
+	klog.Exit(s.ListenAndServe())
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestServeMux(t *testing.T) {
+	cases := []struct {
+		name       string
+		profiling  bool
+		path       string
+		statusCode int
+	}{
+		{name: "metrics", path: "/metrics", statusCode: http.StatusOK},
+		{name: "healthz", path: "/healthz", statusCode: http.StatusOK},
+		{name: "pprof disabled", path: "/debug/pprof/", statusCode: http.StatusNotFound},
+		{name: "pprof enabled", profiling: true, path: "/debug/pprof/", statusCode: http.StatusOK},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mux := newServeMux(v1alpha2.MonitorServer{EnableProfiling: c.profiling})
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.path, nil))
+			if w.Code != c.statusCode {
+				t.Errorf("expected status code %d of %s, but got %d", c.statusCode, c.path, w.Code)
+			}
+		})
+	}
+}
+
+func TestObserveDMICall(t *testing.T) {
+	defer DMICallDuration.Reset()
+
+	ObserveDMICall("/v1beta1.DeviceMapperService/RegisterDevice", nil, time.Now())
+	ObserveDMICall("/v1beta1.DeviceMapperService/RegisterDevice", status.Error(codes.Unavailable, "mapper is down"), time.Now())
+	ObserveDMICall("/v1beta1.DeviceManagerService/ReportDeviceStatus", errors.New("unknown"), time.Now())
+
+	if count := testutil.CollectAndCount(DMICallDuration); count != 3 {
+		t.Errorf("expected 3 series, but got %d", count)
+	}
+	for _, labels := range [][]string{
+		{"RegisterDevice", "OK"},
+		{"RegisterDevice", "Unavailable"},
+		{"ReportDeviceStatus", "Unknown"},
+	} {
+		if _, err := DMICallDuration.GetMetricWithLabelValues(labels...); err != nil {
+			t.Errorf("expected series %v: %v", labels, err)
+		}
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	defer cloudconnection.SetConnected(false)
+
+	cases := []struct {
+		name       string
+		handler    http.HandlerFunc
+		connected  bool
+		statusCode int
+	}{
+		{
+			name:       "healthz when disconnected",
+			handler:    healthz,
+			connected:  false,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "readyz when disconnected",
+			handler:    readyz,
+			connected:  false,
+			statusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "readyz when connected",
+			handler:    readyz,
+			connected:  true,
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cloudconnection.SetConnected(c.connected)
+			w := httptest.NewRecorder()
+			c.handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != c.statusCode {
+				t.Errorf("expected status code %d, but got %d", c.statusCode, w.Code)
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/klog/v2"
 
 	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtcommon"
 	"github.com/kubeedge/kubeedge/pkg/apis/devices/v1beta1"
 	dmiapi "github.com/kubeedge/kubeedge/pkg/apis/dmi/v1beta1"
@@ -60,7 +61,8 @@ func (dc *DMIClient) connect() error {
 		return net.Dial(deviceconst.UnixNetworkType, addr)
 	}
 
-	conn, err := grpc.Dial(dc.socket, grpc.WithInsecure(), grpc.WithDialer(dialer))
+	conn, err := grpc.Dial(dc.socket, grpc.WithInsecure(), grpc.WithDialer(dialer),
+		grpc.WithUnaryInterceptor(observeDMICall))
 	if err != nil {
 		klog.Errorf("did not connect: %v\n", err)
 		return err
@@ -77,6 +79,15 @@ func (dc *DMIClient) connect() error {
 	return nil
 }
 
+// observeDMICall records the duration of the DMI calls to the mappers
+func observeDMICall(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	monitor.ObserveDMICall(method, err, start)
+	return err
+}
+
 func (dc *DMIClient) close() {
 	dc.Conn.Close()
 	dc.CancelFunc()
@@ -37,6 +37,7 @@ import (
 	"github.com/kubeedge/kubeedge/common/constants"
 	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dmiclient"
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtcommon"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
@@ -183,7 +184,7 @@ func StartDMIServer(cache *DMICache) {
 
 	limiter := rate.NewLimiter(rate.Every(Limit*time.Millisecond), Burst)
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(grpc.UnaryInterceptor(observeDMICall))
 	pb.RegisterDeviceManagerServiceServer(s, &server{
 		limiter:  limiter,
 		dmiCache: cache,
@@ -197,6 +198,15 @@ func StartDMIServer(cache *DMICache) {
 	klog.Infoln("success to start DMI Server")
 }
 
+// observeDMICall records the duration of the DMI calls from the mappers
+func observeDMICall(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	monitor.ObserveDMICall(info.FullMethod, err, start)
+	return resp, err
+}
+
 func saveMapper(mapper *pb.MapperInfo) error {
 	content, err := json.Marshal(mapper)
 	if err != nil {
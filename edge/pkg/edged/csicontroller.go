@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edged
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/klog/v2"
+	registerapi "k8s.io/kubelet/pkg/apis/pluginregistration/v1"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/config"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+const (
+	// defaultCSIControllerDriverName is the CSI driver that the controller requests from cloud are sent to
+	// if the request does not name the driver, it is the same driver that csiplugin.Controller sends
+	// CreateVolume and ControllerPublishVolume to.
+	defaultCSIControllerDriverName = "csi-hostpath"
+	// csiDriverNameUnset is the namespace of the volume resources from the cloud that don't name the driver
+	csiDriverNameUnset = "default"
+	// csiControllerTimeout must be shorter than CSISyncMsgRespTimeout, so that the error of the
+	// CSI driver is sent back to cloud before the request times out.
+	csiControllerTimeout = 45 * time.Second
+	// pluginInfoTimeout is the timeout of getting plugin info from a registration socket,
+	// the sockets of stopped drivers may be left in the registration dir.
+	pluginInfoTimeout = 5 * time.Second
+)
+
+// csiController forwards the controller requests that csiplugin.Controller does not support
+// to the CSI driver registered to edged.
+type csiController struct {
+	// registrationDir is where the CSI drivers put their plugin registration sockets
+	registrationDir string
+	driverName      string
+}
+
+// newCSIController returns the csiController sending the requests to the CSI driver driverName,
+// which is the namespace of the volume resource from the cloud
+func newCSIController(rootDir, driverName string) *csiController {
+	if driverName == "" || driverName == csiDriverNameUnset {
+		driverName = defaultCSIControllerDriverName
+	}
+	return &csiController{
+		registrationDir: filepath.Join(rootDir, kubeletconfig.DefaultKubeletPluginsRegistrationDirName),
+		driverName:      driverName,
+	}
+}
+
+// isCSIControllerOperation returns true if the operation is handled by csiController.
+func isCSIControllerOperation(op string) bool {
+	switch op {
+	case constants.CSIOperationTypeControllerExpandVolume,
+		constants.CSIOperationTypeCreateSnapshot,
+		constants.CSIOperationTypeDeleteSnapshot,
+		constants.CSIOperationTypeListSnapshots,
+		constants.CSIOperationTypeGetCapacity,
+		constants.CSIOperationTypeListVolumes,
+		constants.CSIOperationTypeControllerGetCapabilities:
+		return true
+	}
+	return false
+}
+
+// Handle sends the request in content to the CSI driver, and returns the response
+// marshaled by jsonpb, since some responses contain oneof fields that can not be
+// unmarshalled by encoding/json.
+func (c *csiController) Handle(op string, content []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), csiControllerTimeout)
+	defer cancel()
+
+	endpoint, err := c.driverEndpoint(ctx)
+	if err != nil {
+		return "", err
+	}
+	conn, err := dialCSISocket(ctx, endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to CSI driver %s at %s: %v", c.driverName, endpoint, err)
+	}
+	defer conn.Close()
+
+	res, err := callCSIController(ctx, csi.NewControllerClient(conn), op, content)
+	if err != nil {
+		return "", err
+	}
+	m := jsonpb.Marshaler{}
+	return m.MarshalToString(res)
+}
+
+func callCSIController(ctx context.Context, client csi.ControllerClient, op string, content []byte) (proto.Message, error) {
+	unmarshal := func(req proto.Message) error {
+		if err := jsonpb.Unmarshal(bytes.NewReader(content), req); err != nil {
+			return fmt.Errorf("failed to unmarshal %s request: %v", op, err)
+		}
+		return nil
+	}
+
+	switch op {
+	case constants.CSIOperationTypeControllerExpandVolume:
+		req := &csi.ControllerExpandVolumeRequest{}
+		if err := unmarshal(req); err != nil {
+			return nil, err
+		}
+		return client.ControllerExpandVolume(ctx, req)
+	case constants.CSIOperationTypeCreateSnapshot:
+		req := &csi.CreateSnapshotRequest{}
+		if err := unmarshal(req); err != nil {
+			return nil, err
+		}
+		return client.CreateSnapshot(ctx, req)
+	case constants.CSIOperationTypeDeleteSnapshot:
+		req := &csi.DeleteSnapshotRequest{}
+		if err := unmarshal(req); err != nil {
+			return nil, err
+		}
+		return client.DeleteSnapshot(ctx, req)
+	case constants.CSIOperationTypeListSnapshots:
+		req := &csi.ListSnapshotsRequest{}
+		if err := unmarshal(req); err != nil {
+			return nil, err
+		}
+		return client.ListSnapshots(ctx, req)
+	case constants.CSIOperationTypeGetCapacity:
+		req := &csi.GetCapacityRequest{}
+		if err := unmarshal(req); err != nil {
+			return nil, err
+		}
+		return client.GetCapacity(ctx, req)
+	case constants.CSIOperationTypeListVolumes:
+		req := &csi.ListVolumesRequest{}
+		if err := unmarshal(req); err != nil {
+			return nil, err
+		}
+		return client.ListVolumes(ctx, req)
+	case constants.CSIOperationTypeControllerGetCapabilities:
+		req := &csi.ControllerGetCapabilitiesRequest{}
+		if err := unmarshal(req); err != nil {
+			return nil, err
+		}
+		return client.ControllerGetCapabilities(ctx, req)
+	}
+	return nil, fmt.Errorf("unsupported CSI controller operation %s", op)
+}
+
+// driverEndpoint finds the endpoint of the CSI driver from the plugin registration sockets,
+// the same way that the plugin manager of kubelet registers the CSI drivers.
+func (c *csiController) driverEndpoint(ctx context.Context) (string, error) {
+	entries, err := os.ReadDir(c.registrationDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read plugin registration dir %s: %v", c.registrationDir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sock") {
+			continue
+		}
+		socket := filepath.Join(c.registrationDir, entry.Name())
+		info, err := getPluginInfo(ctx, socket)
+		if err != nil {
+			klog.V(4).Infof("failed to get plugin info from %s: %v", socket, err)
+			continue
+		}
+		if info.Type != registerapi.CSIPlugin || info.Name != c.driverName {
+			continue
+		}
+		if info.Endpoint == "" {
+			return socket, nil
+		}
+		return info.Endpoint, nil
+	}
+	return "", fmt.Errorf("CSI driver %s is not registered in %s", c.driverName, c.registrationDir)
+}
+
+func getPluginInfo(ctx context.Context, socket string) (*registerapi.PluginInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, pluginInfoTimeout)
+	defer cancel()
+
+	conn, err := dialCSISocket(ctx, socket)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return registerapi.NewRegistrationClient(conn).GetInfo(ctx, &registerapi.InfoRequest{})
+}
+
+func dialCSISocket(ctx context.Context, socket string) (*grpc.ClientConn, error) {
+	if !strings.HasPrefix(socket, "unix://") {
+		socket = "unix://" + socket
+	}
+	return grpc.DialContext(ctx, socket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock())
+}
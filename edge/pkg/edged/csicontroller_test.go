@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edged
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/jsonpb"
+	"google.golang.org/grpc"
+	registerapi "k8s.io/kubelet/pkg/apis/pluginregistration/v1"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/config"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+// testCSIDriverName is a vendor CSI driver, other than the default one
+const testCSIDriverName = "vendor.csi.example.com"
+
+type fakeRegistrationServer struct {
+	info registerapi.PluginInfo
+}
+
+func (s *fakeRegistrationServer) GetInfo(context.Context, *registerapi.InfoRequest) (*registerapi.PluginInfo, error) {
+	return &s.info, nil
+}
+
+func (s *fakeRegistrationServer) NotifyRegistrationStatus(context.Context, *registerapi.RegistrationStatus) (*registerapi.RegistrationStatusResponse, error) {
+	return &registerapi.RegistrationStatusResponse{}, nil
+}
+
+type fakeControllerServer struct {
+	csi.UnimplementedControllerServer
+}
+
+func (s *fakeControllerServer) GetCapacity(_ context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
+	return &csi.GetCapacityResponse{AvailableCapacity: int64(len(req.GetParameters())) * 1024}, nil
+}
+
+func (s *fakeControllerServer) ControllerGetCapabilities(context.Context, *csi.ControllerGetCapabilitiesRequest) (*csi.ControllerGetCapabilitiesResponse, error) {
+	return &csi.ControllerGetCapabilitiesResponse{
+		Capabilities: []*csi.ControllerServiceCapability{{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_EXPAND_VOLUME},
+			},
+		}},
+	}, nil
+}
+
+func serveUnixSocket(t *testing.T, socket string, register func(*grpc.Server)) {
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen on %s: %v", socket, err)
+	}
+	server := grpc.NewServer()
+	register(server)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+}
+
+func TestCSIController(t *testing.T) {
+	rootDir := t.TempDir()
+	registrationDir := filepath.Join(rootDir, kubeletconfig.DefaultKubeletPluginsRegistrationDirName)
+	if err := os.MkdirAll(registrationDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	endpoint := filepath.Join(rootDir, "csi.sock")
+
+	serveUnixSocket(t, filepath.Join(registrationDir, "other-reg.sock"), func(s *grpc.Server) {
+		registerapi.RegisterRegistrationServer(s, &fakeRegistrationServer{info: registerapi.PluginInfo{
+			Type: registerapi.DevicePlugin, Name: testCSIDriverName, Endpoint: "/not/exist.sock",
+		}})
+	})
+	serveUnixSocket(t, filepath.Join(registrationDir, "vendor-reg.sock"), func(s *grpc.Server) {
+		registerapi.RegisterRegistrationServer(s, &fakeRegistrationServer{info: registerapi.PluginInfo{
+			Type: registerapi.CSIPlugin, Name: testCSIDriverName, Endpoint: endpoint,
+		}})
+	})
+	serveUnixSocket(t, endpoint, func(s *grpc.Server) {
+		csi.RegisterControllerServer(s, &fakeControllerServer{})
+	})
+
+	c := newCSIController(rootDir, testCSIDriverName)
+
+	res, err := c.Handle(constants.CSIOperationTypeGetCapacity, []byte(`{"parameters":{"a":"b","c":"d"}}`))
+	if err != nil {
+		t.Fatalf("failed to get capacity: %v", err)
+	}
+	capacity := &csi.GetCapacityResponse{}
+	if err = jsonpb.UnmarshalString(res, capacity); err != nil {
+		t.Fatalf("failed to unmarshal get capacity response %s: %v", res, err)
+	}
+	if capacity.AvailableCapacity != 2048 {
+		t.Errorf("expect available capacity 2048, got %d", capacity.AvailableCapacity)
+	}
+
+	res, err = c.Handle(constants.CSIOperationTypeControllerGetCapabilities, []byte(`{}`))
+	if err != nil {
+		t.Fatalf("failed to get capabilities: %v", err)
+	}
+	caps := &csi.ControllerGetCapabilitiesResponse{}
+	if err = jsonpb.UnmarshalString(res, caps); err != nil {
+		t.Fatalf("failed to unmarshal capabilities response %s: %v", res, err)
+	}
+	if len(caps.Capabilities) != 1 || caps.Capabilities[0].GetRpc().GetType() != csi.ControllerServiceCapability_RPC_EXPAND_VOLUME {
+		t.Errorf("unexpected capabilities %v", caps.Capabilities)
+	}
+
+	_, err = c.Handle(constants.CSIOperationTypeCreateSnapshot, []byte(`{"name":"snap","source_volume_id":"vol"}`))
+	if err == nil || !strings.Contains(err.Error(), "Unimplemented") {
+		t.Errorf("expect Unimplemented error of the CSI driver, got %v", err)
+	}
+
+	c.driverName = "not-registered"
+	if _, err = c.Handle(constants.CSIOperationTypeListVolumes, []byte(`{}`)); err == nil {
+		t.Errorf("expect error for the driver not registered")
+	}
+}
+
+func TestNewCSIControllerDriverName(t *testing.T) {
+	cases := map[string]string{
+		"":                defaultCSIControllerDriverName,
+		"default":         defaultCSIControllerDriverName,
+		testCSIDriverName: testCSIDriverName,
+	}
+	for namespace, driverName := range cases {
+		if c := newCSIController(t.TempDir(), namespace); c.driverName != driverName {
+			t.Errorf("expect driver %s for namespace %q, got %s", driverName, namespace, c.driverName)
+		}
+	}
+}
@@ -226,7 +226,7 @@ func (e *edged) syncPod(podCfg *config.PodConfig) {
 			continue
 		}
 
-		_, resType, resID, err := util.ParseResourceEdge(result.GetResource(), result.GetOperation())
+		namespace, resType, resID, err := util.ParseResourceEdge(result.GetResource(), result.GetOperation())
 		if err != nil {
 			klog.Errorf("failed to parse the Resource: %v", err)
 			continue
@@ -264,9 +264,13 @@ func (e *edged) syncPod(podCfg *config.PodConfig) {
 			}
 		case constants.CSIResourceTypeVolume:
 			klog.Infof("volume operation type: %s", op)
-			res, err := e.handleVolume(op, content)
+			// the namespace of a volume resource is the name of the CSI driver
+			res, err := e.handleVolume(op, namespace, content)
 			if err != nil {
 				klog.Errorf("handle volume failed: %v", err)
+				resp := result.NewRespByMessage(&result, err.Error())
+				resp.Router.Operation = model.ResponseErrorOperation
+				beehiveContext.SendResp(*resp)
 			} else {
 				resp := result.NewRespByMessage(&result, res)
 				beehiveContext.SendResp(*resp)
@@ -371,7 +375,7 @@ func (e *edged) handlePodListFromEdgeController(content []byte, updatesChan chan
 	return nil
 }
 
-func (e *edged) handleVolume(op string, content []byte) (interface{}, error) {
+func (e *edged) handleVolume(op, driverName string, content []byte) (interface{}, error) {
 	switch op {
 	case constants.CSIOperationTypeCreateVolume:
 		return e.createVolume(content)
@@ -382,6 +386,10 @@ func (e *edged) handleVolume(op string, content []byte) (interface{}, error) {
 	case constants.CSIOperationTypeControllerUnpublishVolume:
 		return e.controllerUnpublishVolume(content)
 	}
+	if isCSIControllerOperation(op) {
+		klog.V(4).Infof("start csi controller operation: %s", op)
+		return newCSIController(edgedconfig.Config.RootDirectory, driverName).Handle(op, content)
+	}
 	return nil, nil
 }
 
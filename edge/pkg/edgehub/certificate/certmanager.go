@@ -69,7 +69,8 @@ func NewCertManager(edgehub v1alpha2.EdgeHub, nodename string) CertManager {
 			Organization: []string{"kubeEdge"},
 			Locality:     []string{"Hangzhou"},
 			Province:     []string{"Zhejiang"},
-			CommonName:   "kubeedge.io",
+			// cloudcore identifies the edge node by the common name of its certificate
+			CommonName: nodename,
 		},
 	}
 	return CertManager{
@@ -13,7 +13,8 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/certificate"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
-	// register Upgrade handler
+	// register Upgrade and NodeJob handlers
+	_ "github.com/kubeedge/kubeedge/edge/pkg/edgehub/nodejob"
 	_ "github.com/kubeedge/kubeedge/edge/pkg/edgehub/upgrade"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
 )
@@ -26,6 +27,9 @@ type EdgeHub struct {
 	rateLimiter   flowcontrol.RateLimiter
 	keeperLock    sync.RWMutex
 	enable        bool
+	// connectedBefore indicates whether edgehub has ever connected to cloud,
+	// so that the following connections are counted as reconnects
+	connectedBefore bool
 }
 
 var _ core.Module = (*EdgeHub)(nil)
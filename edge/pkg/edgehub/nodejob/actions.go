@@ -0,0 +1,180 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodejob
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2/validation"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util"
+)
+
+func init() {
+	RegisterAction(v1alpha1.NodeJobActionUpdateConfig, ActionFunc(updateConfig))
+	RegisterAction(v1alpha1.NodeJobActionRestartEdgeCore, ActionFunc(restartEdgeCore))
+	RegisterAction(v1alpha1.NodeJobActionReboot, ActionFunc(reboot))
+	RegisterAction(v1alpha1.NodeJobActionCollectLogs, ActionFunc(collectLogs))
+}
+
+// updateConfig applies the JSON merge patch to the EdgeCore config, and restarts EdgeCore after reporting.
+// The origin config is backed up in the same directory.
+func updateConfig(req *commontypes.NodeJobRequest) (*Result, error) {
+	patch, ok := req.Parameters[v1alpha1.NodeJobParamPatch]
+	if !ok {
+		return nil, fmt.Errorf("parameter %q is required", v1alpha1.NodeJobParamPatch)
+	}
+	if !util.HasSystemd() {
+		return nil, fmt.Errorf("EdgeCore can only be restarted by systemd to make the config effective")
+	}
+
+	configFile := options.GetEdgeCoreOptions().ConfigFile
+	info, err := os.Stat(configFile)
+	if err != nil {
+		return nil, err
+	}
+	origin, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %v", configFile, err)
+	}
+	updated, err := patchEdgeCoreConfig(origin, []byte(patch))
+	if err != nil {
+		return nil, err
+	}
+	if err = validateEdgeCoreConfig(updated); err != nil {
+		return nil, err
+	}
+
+	backup := fmt.Sprintf("%s.%s.bak", configFile, time.Now().Format("20060102150405"))
+	if err = os.WriteFile(backup, origin, info.Mode()); err != nil {
+		return nil, fmt.Errorf("failed to back up config to %s: %v", backup, err)
+	}
+	if err = os.WriteFile(configFile, updated, info.Mode()); err != nil {
+		return nil, fmt.Errorf("failed to write config %s: %v", configFile, err)
+	}
+
+	return &Result{
+		Message:     fmt.Sprintf("config is updated and the origin config is backed up to %s, EdgeCore is restarting", backup),
+		AfterReport: restartEdgeCoreService,
+	}, nil
+}
+
+// patchEdgeCoreConfig applies the JSON merge patch to the EdgeCore config in YAML
+func patchEdgeCoreConfig(origin, patch []byte) ([]byte, error) {
+	originJSON, err := yaml.YAMLToJSON(origin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to json: %v", err)
+	}
+	patched, err := jsonpatch.MergePatch(originJSON, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patch: %v", err)
+	}
+	return yaml.JSONToYAML(patched)
+}
+
+// validateEdgeCoreConfig validates the EdgeCore config in YAML, the same as EdgeCore starts
+func validateEdgeCoreConfig(data []byte) error {
+	config := v1alpha2.NewDefaultEdgeCoreConfig()
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	if errs := validation.ValidateEdgeCoreConfiguration(config); len(errs) > 0 {
+		return fmt.Errorf("invalid config: %v", errs.ToAggregate())
+	}
+	return nil
+}
+
+// restartEdgeCore restarts EdgeCore after reporting
+func restartEdgeCore(req *commontypes.NodeJobRequest) (*Result, error) {
+	if !util.HasSystemd() {
+		return nil, fmt.Errorf("EdgeCore can only be restarted by systemd")
+	}
+	return &Result{
+		Message:     "EdgeCore is restarting",
+		AfterReport: restartEdgeCoreService,
+	}, nil
+}
+
+func restartEdgeCoreService() {
+	klog.Infof("Begin to restart EdgeCore")
+	// systemd stops EdgeCore during restarting, so the output cannot be checked
+	if err := exec.Command("systemctl", "restart", constants.EdgeCoreBinaryName).Start(); err != nil {
+		klog.Errorf("Failed to restart EdgeCore: %v", err)
+	}
+}
+
+// reboot reboots the host after reporting
+func reboot(req *commontypes.NodeJobRequest) (*Result, error) {
+	return &Result{
+		Message: "node is rebooting",
+		AfterReport: func() {
+			klog.Infof("Begin to reboot the node")
+			if out, err := exec.Command("reboot").CombinedOutput(); err != nil {
+				klog.Errorf("Failed to reboot the node: %v, %s", err, out)
+			}
+		},
+	}, nil
+}
+
+// collectLogs collects the log bundle by `keadm debug collect`, the bundle is uploaded to cloudcore
+func collectLogs(req *commontypes.NodeJobRequest) (*Result, error) {
+	dir, err := os.MkdirTemp("", fmt.Sprintf("nodejob-%s-", req.JobID))
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			klog.Warningf("Failed to remove %s: %v", dir, err)
+		}
+	}
+
+	args := []string{"debug", "collect", "--config", options.GetEdgeCoreOptions().ConfigFile, "--output-path", dir}
+	if req.Parameters[v1alpha1.NodeJobParamDetail] == "true" {
+		args = append(args, "--detail")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), actionTimeout(req))
+	defer cancel()
+	keadm := filepath.Join(constants.KubeEdgeBinaryDir, constants.KeadmBinaryName)
+	if out, err := exec.CommandContext(ctx, keadm, args...).CombinedOutput(); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("run %s %v failed: %v, %s", keadm, args, err, out)
+	}
+
+	bundles, err := filepath.Glob(filepath.Join(dir, "*.tar.gz"))
+	if err != nil || len(bundles) != 1 {
+		cleanup()
+		return nil, fmt.Errorf("failed to find the log bundle in %s: %v", dir, err)
+	}
+	return &Result{
+		Message:      "log bundle is collected",
+		ArtifactPath: bundles[0],
+		AfterReport:  cleanup,
+	}, nil
+}
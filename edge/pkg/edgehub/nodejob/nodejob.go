@@ -0,0 +1,225 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodejob
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	edgemodules "github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
+	edgehubhttp "github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/http"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/msghandler"
+	edgehubconfig "github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
+)
+
+// afterReportDelay is the time to wait for the result to be sent to cloud before running AfterReport,
+// since AfterReport may stop EdgeCore
+const afterReportDelay = 5 * time.Second
+
+func init() {
+	msghandler.RegisterHandler(&nodeJobHandler{})
+}
+
+// Action runs the action of NodeJob on the edge node
+type Action interface {
+	Run(req *commontypes.NodeJobRequest) (*Result, error)
+}
+
+// ActionFunc is an adapter to allow the use of ordinary functions as Action
+type ActionFunc func(req *commontypes.NodeJobRequest) (*Result, error)
+
+// Run calls f(req)
+func (f ActionFunc) Run(req *commontypes.NodeJobRequest) (*Result, error) {
+	return f(req)
+}
+
+// Result is the result of the action that succeeds
+type Result struct {
+	// Message is reported to cloud as the output of the action
+	Message string
+	// ArtifactPath is the local file uploaded to cloudcore before reporting the result
+	ArtifactPath string
+	// AfterReport runs after the result is reported to cloud, such as restarting EdgeCore
+	AfterReport func()
+}
+
+var (
+	actions = make(map[string]Action)
+	mutex   sync.Mutex
+)
+
+// RegisterAction registers the action of NodeJob, the action with the same name is overwritten
+func RegisterAction(name string, action Action) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	actions[name] = action
+}
+
+func getAction(name string) (Action, bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	action, ok := actions[name]
+	return action, ok
+}
+
+type nodeJobHandler struct{}
+
+func (nh *nodeJobHandler) Filter(message *model.Message) bool {
+	return message.GetGroup() == modules.NodeUpgradeJobControllerModuleGroup &&
+		message.GetOperation() == constants.NodeJobOperation
+}
+
+func (nh *nodeJobHandler) Process(message *model.Message, clientHub clients.Adapter) error {
+	req := &commontypes.NodeJobRequest{}
+	data, err := message.GetContentData()
+	if err != nil {
+		return fmt.Errorf("failed to get content data: %v", err)
+	}
+	if err = json.Unmarshal(data, req); err != nil {
+		return fmt.Errorf("unmarshal failed: %v", err)
+	}
+	if req.JobID == "" {
+		return fmt.Errorf("jobID cannot be empty")
+	}
+
+	// actions may take a long time, so do not block receiving other messages from cloud
+	go runAction(req, message.GetResource())
+	return nil
+}
+
+// runAction runs the action, uploads the artifact, and reports the result to cloud
+func runAction(req *commontypes.NodeJobRequest, resource string) {
+	resp := commontypes.NodeJobResponse{
+		JobID:    req.JobID,
+		NodeName: edgehubconfig.Config.NodeName,
+	}
+
+	result, err := run(req)
+	if err == nil && result.ArtifactPath != "" {
+		resp.Artifact, err = uploadArtifact(req.JobID, result.ArtifactPath, actionTimeout(req))
+		if err != nil {
+			err = fmt.Errorf("failed to upload artifact %s: %v", result.ArtifactPath, err)
+		}
+	}
+	if err != nil {
+		resp.Message = err.Error()
+	} else {
+		resp.Succeeded = true
+		resp.Message = result.Message
+	}
+
+	klog.Infof("NodeJob %s action %s result: succeeded %v, message: %s", req.JobID, req.Action, resp.Succeeded, resp.Message)
+	respMsg := model.NewMessage("").
+		BuildRouter(modules.NodeUpgradeJobControllerModuleName, modules.NodeUpgradeJobControllerModuleGroup,
+			resource, constants.NodeJobOperation).
+		FillBody(resp)
+	beehiveContext.Send(edgemodules.EdgeHubModuleName, *respMsg)
+
+	if result != nil && result.AfterReport != nil {
+		time.Sleep(afterReportDelay)
+		result.AfterReport()
+	}
+}
+
+// actionTimeout returns the timeout of the action, or the default timeout if it is not set,
+// so that neither the action nor uploading the artifact may hang forever
+func actionTimeout(req *commontypes.NodeJobRequest) time.Duration {
+	if req.TimeoutSeconds == 0 {
+		return constants.DefaultNodeJobTimeoutSeconds * time.Second
+	}
+	return time.Duration(req.TimeoutSeconds) * time.Second
+}
+
+func run(req *commontypes.NodeJobRequest) (*Result, error) {
+	action, ok := getAction(req.Action)
+	if !ok {
+		return nil, fmt.Errorf("not supported action: %s", req.Action)
+	}
+	result, err := action.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &Result{}
+	}
+	return result, nil
+}
+
+// uploadArtifact uploads the file to cloudcore with the edge certificate,
+// and returns where the file is stored on cloudcore
+func uploadArtifact(jobID, path string, timeout time.Duration) (string, error) {
+	config := edgehubconfig.Config
+	caPEM, err := os.ReadFile(config.TLSCAFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read ca: %v", err)
+	}
+	cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSPrivateKeyFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to load edge certificate: %v", err)
+	}
+	client, err := edgehubhttp.NewHTTPClientWithCA(caPEM, cert)
+	if err != nil {
+		return "", err
+	}
+	// uploading the artifact on slow links may take longer than the default timeout
+	client.Timeout = timeout
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	query := url.Values{}
+	query.Set("job", jobID)
+	query.Set("file", filepath.Base(path))
+	req, err := edgehubhttp.BuildRequest(http.MethodPost,
+		fmt.Sprintf("%s%s?%s", config.HTTPServer, constants.DefaultNodeJobArtifactURL, query.Encode()), f, "", config.NodeName)
+	if err != nil {
+		return "", err
+	}
+	resp, err := edgehubhttp.SendRequest(req, client)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cloudcore responds %d: %s", resp.StatusCode, body)
+	}
+	return string(body), nil
+}
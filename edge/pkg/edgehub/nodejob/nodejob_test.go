@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodejob
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestFilter(t *testing.T) {
+	nh := &nodeJobHandler{}
+
+	tests := []struct {
+		msg  *model.Message
+		want bool
+		name string
+	}{
+		{
+			msg: &model.Message{
+				Router: model.MessageRoute{Group: "nodeupgradejobcontroller", Operation: "nodejob"},
+			},
+			want: true,
+			name: "Node job",
+		},
+		{
+			msg: &model.Message{
+				Router: model.MessageRoute{Group: "nodeupgradejobcontroller", Operation: "upgrade"},
+			},
+			want: false,
+			name: "Node upgrade job",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nh.Filter(tt.msg); got != tt.want {
+				t.Errorf("nodeJobHandler.Filter() retuned unexpected result. got = %v, want = %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	RegisterAction("echo", ActionFunc(func(req *commontypes.NodeJobRequest) (*Result, error) {
+		return &Result{Message: req.Parameters["message"]}, nil
+	}))
+	RegisterAction("nothing", ActionFunc(func(req *commontypes.NodeJobRequest) (*Result, error) {
+		return nil, nil
+	}))
+	RegisterAction("fail", ActionFunc(func(req *commontypes.NodeJobRequest) (*Result, error) {
+		return nil, errors.New("failed")
+	}))
+
+	tests := []struct {
+		name    string
+		req     *commontypes.NodeJobRequest
+		want    string
+		wantErr bool
+	}{
+		{name: "registered action", req: &commontypes.NodeJobRequest{Action: "echo", Parameters: map[string]string{"message": "hello"}}, want: "hello"},
+		{name: "action returns nil result", req: &commontypes.NodeJobRequest{Action: "nothing"}},
+		{name: "action fails", req: &commontypes.NodeJobRequest{Action: "fail"}, wantErr: true},
+		{name: "not registered action", req: &commontypes.NodeJobRequest{Action: "unknown"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := run(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() returned unexpected error: %v, wantErr: %v", err, tt.wantErr)
+			}
+			if err == nil && result.Message != tt.want {
+				t.Errorf("run() returned unexpected message. got = %v, want = %v", result.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionTimeout(t *testing.T) {
+	if timeout := actionTimeout(&commontypes.NodeJobRequest{}); timeout != 300*time.Second {
+		t.Errorf("expected the default timeout 300s, but got %v", timeout)
+	}
+	if timeout := actionTimeout(&commontypes.NodeJobRequest{TimeoutSeconds: 60}); timeout != 60*time.Second {
+		t.Errorf("expected the timeout 60s, but got %v", timeout)
+	}
+}
+
+func TestPatchEdgeCoreConfig(t *testing.T) {
+	origin := []byte(`apiVersion: edgecore.config.kubeedge.io/v1alpha2
+kind: EdgeCore
+modules:
+  edgeHub:
+    heartbeat: 15
+    httpServer: https://127.0.0.1:10002
+  edged:
+    hostnameOverride: edge-1
+`)
+
+	tests := []struct {
+		name    string
+		patch   string
+		check   func(config map[string]interface{}) bool
+		wantErr bool
+	}{
+		{
+			name:  "update field",
+			patch: `{"modules":{"edgeHub":{"heartbeat":30}}}`,
+			check: func(config map[string]interface{}) bool {
+				edgeHub := config["modules"].(map[string]interface{})["edgeHub"].(map[string]interface{})
+				return edgeHub["heartbeat"] == float64(30) && edgeHub["httpServer"] == "https://127.0.0.1:10002"
+			},
+		},
+		{
+			name:  "remove field",
+			patch: `{"modules":{"edged":{"hostnameOverride":null}}}`,
+			check: func(config map[string]interface{}) bool {
+				_, ok := config["modules"].(map[string]interface{})["edged"].(map[string]interface{})["hostnameOverride"]
+				return !ok
+			},
+		},
+		{
+			name:    "invalid patch",
+			patch:   `{"modules":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := patchEdgeCoreConfig(origin, []byte(tt.patch))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("patchEdgeCoreConfig() returned unexpected error: %v, wantErr: %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			config := map[string]interface{}{}
+			if err = yaml.Unmarshal(updated, &config); err != nil {
+				t.Fatalf("failed to unmarshal the updated config: %v", err)
+			}
+			if !tt.check(config) {
+				t.Errorf("unexpected updated config: %s", updated)
+			}
+		})
+	}
+}
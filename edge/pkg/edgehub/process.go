@@ -12,9 +12,11 @@ import (
 	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
 	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/msghandler"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
+	"github.com/kubeedge/kubeedge/pkg/tracing"
 )
 
 var groupMap = map[string]string{
@@ -122,7 +124,10 @@ func (eh *EdgeHub) routeToEdge() {
 		}
 
 		klog.V(4).Infof("[edgehub/routeToEdge] receive msg from cloud, msg:% +v", message)
+		monitor.EdgeHubMessages.WithLabelValues(monitor.DirectionDownstream).Inc()
+		span := tracing.StartMessageSpan(modules.EdgeHubModuleName, &message)
 		err = eh.dispatch(message)
+		tracing.EndSpan(span, err)
 		if err != nil {
 			klog.Errorf("failed to dispatch message, discard: %v", err)
 		}
@@ -156,19 +161,23 @@ func (eh *EdgeHub) routeToCloud() {
 			continue
 		}
 
+		span := tracing.StartMessageSpan(modules.EdgeHubModuleName, &message)
 		err = eh.tryThrottle(message.GetID())
 		if err != nil {
+			tracing.EndSpan(span, err)
 			klog.Errorf("msgID: %s, client rate limiter returned an error: %v ", message.GetID(), err)
 			continue
 		}
 
 		// post message to cloud hub
 		err = eh.sendToCloud(message)
+		tracing.EndSpan(span, err)
 		if err != nil {
 			klog.Errorf("failed to send message to cloud: %v", err)
 			eh.reconnectChan <- struct{}{}
 			return
 		}
+		monitor.EdgeHubMessages.WithLabelValues(monitor.DirectionUpstream).Inc()
 	}
 }
 
@@ -199,6 +208,15 @@ func (eh *EdgeHub) keepalive() {
 func (eh *EdgeHub) pubConnectInfo(isConnected bool) {
 	// update connected info
 	connect.SetConnected(isConnected)
+	if isConnected {
+		monitor.CloudConnected.Set(1)
+		if eh.connectedBefore {
+			monitor.CloudReconnects.Inc()
+		}
+		eh.connectedBefore = true
+	} else {
+		monitor.CloudConnected.Set(0)
+	}
 
 	// var info model.Message
 	content := connect.CloudConnected
@@ -231,6 +249,7 @@ func (eh *EdgeHub) tryThrottle(msgID string) error {
 	}
 
 	latency := time.Since(now)
+	monitor.EdgeHubThrottleDuration.Observe(latency.Seconds())
 
 	message := fmt.Sprintf("Waited for %v due to client-side throttling, msgID: %s", latency, msgID)
 	if latency > longThrottleLatency {
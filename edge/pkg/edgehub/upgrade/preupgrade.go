@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/mem"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	edgemodules "github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/pkg/version"
+)
+
+const (
+	// preflightDiskPath is the path whose filesystem is checked in pre-flight checks,
+	// container runtimes store images under it by default.
+	preflightDiskPath = "/var/lib"
+
+	preflightCheckDisk        = "disk"
+	preflightCheckMemory      = "memory"
+	preflightCheckVersionSkew = "versionSkew"
+
+	mb = 1024 * 1024
+)
+
+// processPreUpgrade runs pre-flight checks or pulls the image before upgrading,
+// and reports the result to cloud asynchronously.
+func processPreUpgrade(message *model.Message) error {
+	req := &commontypes.NodePreUpgradeRequest{}
+	data, err := message.GetContentData()
+	if err != nil {
+		return fmt.Errorf("failed to get content data: %v", err)
+	}
+	if err = json.Unmarshal(data, req); err != nil {
+		return fmt.Errorf("unmarshal failed: %v", err)
+	}
+	if req.UpgradeID == "" {
+		return fmt.Errorf("upgradeID cannot be empty")
+	}
+
+	// pulling image may take a long time, so do not block receiving other messages from cloud
+	go func() {
+		resp := commontypes.NodePreUpgradeResponse{
+			UpgradeID: req.UpgradeID,
+			NodeName:  options.GetEdgeCoreConfig().Modules.Edged.HostnameOverride,
+		}
+		switch message.GetOperation() {
+		case constants.NodeUpgradePreflightOperation:
+			resp.Checks = runPreflightChecks(req)
+			resp.Succeeded = true
+			failed := []string{}
+			for _, check := range resp.Checks {
+				if !check.Passed {
+					resp.Succeeded = false
+					failed = append(failed, check.Name)
+				}
+			}
+			if !resp.Succeeded {
+				resp.Reason = fmt.Sprintf("pre-flight checks %v failed", failed)
+			}
+		case constants.NodeUpgradePrePullOperation:
+			if err := prePullImage(req.Image); err != nil {
+				resp.Reason = err.Error()
+			} else {
+				resp.Succeeded = true
+			}
+		}
+
+		klog.Infof("NodeUpgradeJob %s %s result: succeeded %v, reason: %s", req.UpgradeID, message.GetOperation(), resp.Succeeded, resp.Reason)
+		respMsg := model.NewMessage("").
+			BuildRouter(modules.NodeUpgradeJobControllerModuleName, modules.NodeUpgradeJobControllerModuleGroup,
+				message.GetResource(), message.GetOperation()).
+			FillBody(resp)
+		beehiveContext.Send(edgemodules.EdgeHubModuleName, *respMsg)
+	}()
+	return nil
+}
+
+func prePullImage(image string) error {
+	if image == "" {
+		return fmt.Errorf("image cannot be empty")
+	}
+	container, err := newContainerRuntime()
+	if err != nil {
+		return err
+	}
+	klog.Infof("Begin to pre-pull image %s", image)
+	if err := container.PullImages([]string{image}); err != nil {
+		return fmt.Errorf("pull image failed: %v", err)
+	}
+	return nil
+}
+
+// runPreflightChecks runs the checks specified in the request, checks with zero value are skipped
+func runPreflightChecks(req *commontypes.NodePreUpgradeRequest) []commontypes.NodePreflightCheck {
+	checks := []commontypes.NodePreflightCheck{}
+	if req.MinAvailableDiskMB > 0 {
+		check := commontypes.NodePreflightCheck{Name: preflightCheckDisk}
+		usage, err := disk.Usage(preflightDiskPath)
+		if err != nil {
+			check.Message = fmt.Sprintf("failed to get disk usage of %s: %v", preflightDiskPath, err)
+		} else {
+			available := int64(usage.Free / mb)
+			check.Passed = available >= req.MinAvailableDiskMB
+			check.Message = fmt.Sprintf("available disk of %s is %d MB, required %d MB", preflightDiskPath, available, req.MinAvailableDiskMB)
+		}
+		checks = append(checks, check)
+	}
+	if req.MinAvailableMemoryMB > 0 {
+		check := commontypes.NodePreflightCheck{Name: preflightCheckMemory}
+		memory, err := mem.VirtualMemory()
+		if err != nil {
+			check.Message = fmt.Sprintf("failed to get memory usage: %v", err)
+		} else {
+			available := int64(memory.Available / mb)
+			check.Passed = available >= req.MinAvailableMemoryMB
+			check.Message = fmt.Sprintf("available memory is %d MB, required %d MB", available, req.MinAvailableMemoryMB)
+		}
+		checks = append(checks, check)
+	}
+	if req.MaxMinorVersionSkew != nil {
+		check := commontypes.NodePreflightCheck{Name: preflightCheckVersionSkew}
+		check.Passed, check.Message = checkVersionSkew(version.Get().String(), req.Version, *req.MaxMinorVersionSkew)
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkVersionSkew checks that the major versions are the same and the difference of
+// minor versions does not exceed maxSkew.
+func checkVersionSkew(current, target string, maxSkew int32) (bool, string) {
+	currentVersion, err := versionutil.ParseGeneric(strings.TrimSpace(current))
+	if err != nil {
+		return false, fmt.Sprintf("failed to parse current version %s: %v", current, err)
+	}
+	targetVersion, err := versionutil.ParseGeneric(strings.TrimSpace(target))
+	if err != nil {
+		return false, fmt.Sprintf("failed to parse target version %s: %v", target, err)
+	}
+	if currentVersion.Major() != targetVersion.Major() {
+		return false, fmt.Sprintf("major version of %s and %s are different", current, target)
+	}
+
+	skew := int64(currentVersion.Minor()) - int64(targetVersion.Minor())
+	if skew < 0 {
+		skew = -skew
+	}
+	message := fmt.Sprintf("minor version skew between %s and %s is %d, allowed %d", current, target, skew, maxSkew)
+	return skew <= int64(maxSkew), message
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/common/constants"
 	commontypes "github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
@@ -50,10 +51,16 @@ type upgradeHandler struct{}
 
 func (uh *upgradeHandler) Filter(message *model.Message) bool {
 	name := message.GetGroup()
-	return name == modules.NodeUpgradeJobControllerModuleGroup
+	// NodeJob messages are in the same group, but handled by the nodejob handler
+	return name == modules.NodeUpgradeJobControllerModuleGroup && message.GetOperation() != constants.NodeJobOperation
 }
 
 func (uh *upgradeHandler) Process(message *model.Message, clientHub clients.Adapter) error {
+	switch message.GetOperation() {
+	case constants.NodeUpgradePreflightOperation, constants.NodeUpgradePrePullOperation:
+		return processPreUpgrade(message)
+	}
+
 	upgradeReq := &commontypes.NodeUpgradeJobRequest{}
 	data, err := message.GetContentData()
 	if err != nil {
@@ -106,21 +113,21 @@ func RegisterUpgradeProvider(upgradeToolType string, provider Provider) {
 type keadmUpgrade struct{}
 
 func (*keadmUpgrade) Upgrade(upgradeReq *commontypes.NodeUpgradeJobRequest) error {
-	// get edgecore start options and config
+	// get edgecore start options
 	opts := options.GetEdgeCoreOptions()
-	config := options.GetEdgeCoreConfig()
 
 	// install the requested installer keadm from docker image
 	klog.Infof("Begin to download version %s keadm", upgradeReq.Version)
-	container, err := util.NewContainerRuntime(config.Modules.Edged.ContainerRuntime, config.Modules.Edged.RemoteRuntimeEndpoint, config.Modules.Edged.TailoredKubeletConfig.CgroupDriver)
+	container, err := newContainerRuntime()
 	if err != nil {
-		return fmt.Errorf("failed to new container runtime: %v", err)
+		return err
 	}
 
 	image := upgradeReq.Image
 
 	// TODO: do some verification 1.sha256(pass in using CRD) 2.image signature verification
 	// TODO: release verification mechanism
+	// the image may have been pulled in the pre-pull phase, then it's fast to pull again
 	err = container.PullImages([]string{image})
 	if err != nil {
 		return fmt.Errorf("pull image failed: %v", err)
@@ -151,3 +158,13 @@ func (*keadmUpgrade) Upgrade(upgradeReq *commontypes.NodeUpgradeJobRequest) erro
 
 	return nil
 }
+
+// newContainerRuntime creates the container runtime that edgecore uses
+func newContainerRuntime() (util.ContainerRuntime, error) {
+	config := options.GetEdgeCoreConfig()
+	container, err := util.NewContainerRuntime(config.Modules.Edged.ContainerRuntime, config.Modules.Edged.RemoteRuntimeEndpoint, config.Modules.Edged.TailoredKubeletConfig.CgroupDriver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to new container runtime: %v", err)
+	}
+	return container, nil
+}
@@ -37,6 +37,13 @@ func TestFilter(t *testing.T) {
 			want: true,
 			name: "Node upgrade job controlled",
 		},
+		{
+			msg: &model.Message{
+				Router: model.MessageRoute{Group: "nodeupgradejobcontroller", Operation: "nodejob"},
+			},
+			want: false,
+			name: "Node job",
+		},
 		{
 			msg: &model.Message{
 				Router: model.MessageRoute{Group: "devicecontroller"},
@@ -54,3 +61,27 @@ func TestFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckVersionSkew(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		maxSkew int32
+		want    bool
+	}{
+		{name: "same minor version", current: "v1.13.0", target: "v1.13.1", maxSkew: 0, want: true},
+		{name: "skew allowed", current: "v1.12.1", target: "v1.14.0", maxSkew: 2, want: true},
+		{name: "skew exceeded", current: "v1.11.0", target: "v1.14.0", maxSkew: 2, want: false},
+		{name: "different major version", current: "v1.13.0", target: "v2.13.0", maxSkew: 2, want: false},
+		{name: "invalid version", current: "unknown", target: "v1.13.0", maxSkew: 2, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, message := checkVersionSkew(tt.current, tt.target, tt.maxSkew); got != tt.want {
+				t.Errorf("checkVersionSkew() returned unexpected result. got = %v, want = %v, message: %s", got, tt.want, message)
+			}
+		})
+	}
+}
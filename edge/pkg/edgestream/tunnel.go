@@ -26,6 +26,8 @@ import (
 	"github.com/gorilla/websocket"
 	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/pkg/stream"
 )
 
@@ -101,6 +103,16 @@ func (s *TunnelSession) serveMetricsConnection(m *stream.Message) error {
 		return err
 	}
 
+	// the metrics of edgecore itself are served by the monitor server instead of edged
+	if metricsCon.URL.Path == constants.EdgeCoreMetricsPath {
+		addr, ok := monitor.Address()
+		if !ok {
+			return fmt.Errorf("the monitor server of edgecore is not enabled")
+		}
+		metricsCon.URL.Host = addr
+		metricsCon.URL.Path = "/metrics"
+	}
+
 	s.AddLocalConnection(m.ConnectID, metricsCon)
 	return metricsCon.Serve(s.Tunnel)
 }
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	eventconfig "github.com/kubeedge/kubeedge/edge/pkg/eventbus/config"
+	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/dao"
+	mqttBus "github.com/kubeedge/kubeedge/edge/pkg/eventbus/mqtt"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2/validation"
+)
+
+// startBridges starts the bridges in the config, and the bridges managed by the messages,
+// which replace the ones with the same name in the config
+func startBridges() {
+	bridges := make(map[string]v1alpha2.MqttBridge)
+	for _, bridge := range eventconfig.Config.MqttBridges {
+		bridges[bridge.Name] = bridge
+	}
+	configs, err := dao.QueryAllBridges()
+	if err != nil {
+		klog.Errorf("list mqtt bridges failed: %v", err)
+	} else {
+		for _, config := range *configs {
+			var bridge v1alpha2.MqttBridge
+			if err := json.Unmarshal([]byte(config), &bridge); err != nil {
+				klog.Errorf("unmarshal mqtt bridge %s failed: %v", config, err)
+				continue
+			}
+			bridges[bridge.Name] = bridge
+		}
+	}
+
+	for _, bridge := range bridges {
+		if bridge.ClientID == "" {
+			bridge.ClientID = mqttBus.DefaultBridgeClientID(eventconfig.Config.NodeName, bridge.Name)
+		}
+		if err := mqttServer.AddBridge(bridge); err != nil {
+			klog.Errorf("start mqtt bridge failed: %v", err)
+		}
+	}
+}
+
+// manageBridge adds, updates, deletes or lists the bridges by the message, and responds the result
+func manageBridge(msg *model.Message) {
+	var content interface{}
+	if err := handleBridge(msg); err != nil {
+		klog.Errorf("failed to %s mqtt bridge: %v", msg.GetOperation(), err)
+		content = err.Error()
+	} else if msg.GetOperation() == model.QueryOperation {
+		content = redactBridges(mqttServer.Bridges())
+	} else {
+		content = "OK"
+	}
+
+	resp := msg.NewRespByMessage(msg, content)
+	if msg.IsSync() {
+		beehiveContext.SendResp(*resp)
+		return
+	}
+	beehiveContext.SendToGroup(modules.HubGroup, *resp)
+}
+
+// redactedValue replaces the credentials of the bridges in the responses
+const redactedValue = "******"
+
+// redactBridges hides the passwords and the private keys of the bridges, which are sent to the cloud
+// in the responses
+func redactBridges(bridges []v1alpha2.MqttBridge) []v1alpha2.MqttBridge {
+	for i := range bridges {
+		if bridges[i].Password != "" {
+			bridges[i].Password = redactedValue
+		}
+		if bridges[i].TLS != nil && bridges[i].TLS.TLSMqttPrivateKeyFile != "" {
+			tls := *bridges[i].TLS
+			tls.TLSMqttPrivateKeyFile = redactedValue
+			bridges[i].TLS = &tls
+		}
+	}
+	return bridges
+}
+
+func handleBridge(msg *model.Message) error {
+	if mqttServer == nil {
+		return fmt.Errorf("internal mqtt broker is not enabled")
+	}
+	if msg.GetOperation() == model.QueryOperation {
+		return nil
+	}
+
+	data, err := msg.GetContentData()
+	if err != nil {
+		return err
+	}
+	var bridge v1alpha2.MqttBridge
+	if err := json.Unmarshal(data, &bridge); err != nil {
+		return fmt.Errorf("invalid mqtt bridge: %v", err)
+	}
+
+	switch msg.GetOperation() {
+	case model.InsertOperation, model.UpdateOperation:
+		if errs := validation.ValidateMqttBridge(bridge, field.NewPath("mqttBridge")); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+		if bridge.ClientID == "" {
+			bridge.ClientID = mqttBus.DefaultBridgeClientID(eventconfig.Config.NodeName, bridge.Name)
+		}
+		config, err := json.Marshal(bridge)
+		if err != nil {
+			return err
+		}
+		if err := mqttServer.AddBridge(bridge); err != nil {
+			return err
+		}
+		return dao.InsertOrUpdateBridge(bridge.Name, string(config))
+	case model.DeleteOperation:
+		mqttServer.RemoveBridge(bridge.Name)
+		return dao.DeleteBridgeByName(bridge.Name)
+	default:
+		return fmt.Errorf("unsupported operation %s", msg.GetOperation())
+	}
+}
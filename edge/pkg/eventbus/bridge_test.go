@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestRedactBridges(t *testing.T) {
+	tls := &v1alpha2.EventBusTLS{
+		Enable:                true,
+		TLSMqttCertFile:       "/etc/kubeedge/certs/bridge.crt",
+		TLSMqttPrivateKeyFile: "/etc/kubeedge/certs/bridge.key",
+	}
+	bridges := redactBridges([]v1alpha2.MqttBridge{
+		{Name: "cloud", Username: "edge", Password: "secret", TLS: tls},
+		{Name: "local"},
+	})
+
+	if bridges[0].Password != redactedValue || bridges[0].TLS.TLSMqttPrivateKeyFile != redactedValue {
+		t.Errorf("expected the credentials redacted, but got %+v %+v", bridges[0], bridges[0].TLS)
+	}
+	if bridges[0].Username != "edge" || bridges[0].TLS.TLSMqttCertFile != tls.TLSMqttCertFile {
+		t.Errorf("expected the other fields kept, but got %+v %+v", bridges[0], bridges[0].TLS)
+	}
+	if tls.TLSMqttPrivateKeyFile != "/etc/kubeedge/certs/bridge.key" {
+		t.Errorf("expected the config of the running bridge unchanged, but got %s", tls.TLSMqttPrivateKeyFile)
+	}
+	if bridges[1].Password != "" || bridges[1].TLS != nil {
+		t.Errorf("expected the bridge without credentials unchanged, but got %+v", bridges[1])
+	}
+}
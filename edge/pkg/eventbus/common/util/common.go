@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"os"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"k8s.io/klog/v2"
 
 	eventconfig "github.com/kubeedge/kubeedge/edge/pkg/eventbus/config"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
 )
 
 var (
@@ -57,33 +59,10 @@ func HubClientInit(server, clientID, username, password string) *MQTT.ClientOpti
 	}
 
 	klog.V(4).Infof("Start to set TLS configuration for MQTT client")
-	tlsConfig := &tls.Config{}
-	if eventconfig.Config.TLS.Enable {
-		cert, err := tls.LoadX509KeyPair(eventconfig.Config.TLS.TLSMqttCertFile, eventconfig.Config.TLS.TLSMqttPrivateKeyFile)
-		if err != nil {
-			klog.Errorf("Failed to load x509 key pair: %v", err)
-			return nil
-		}
-
-		caCert, err := os.ReadFile(eventconfig.Config.TLS.TLSMqttCAFile)
-		if err != nil {
-			klog.Errorf("Failed to read TLSMqttCAFile")
-			return nil
-		}
-
-		pool := x509.NewCertPool()
-		if ok := pool.AppendCertsFromPEM(caCert); !ok {
-			klog.Errorf("Cannot parse the certificates")
-			return nil
-		}
-
-		tlsConfig = &tls.Config{
-			RootCAs:            pool,
-			Certificates:       []tls.Certificate{cert},
-			InsecureSkipVerify: false,
-		}
-	} else {
-		tlsConfig = &tls.Config{InsecureSkipVerify: true, ClientAuth: tls.NoClientCert}
+	tlsConfig, err := NewTLSConfig(eventconfig.Config.TLS)
+	if err != nil {
+		klog.Errorf("Failed to set TLS configuration for MQTT client: %v", err)
+		return nil
 	}
 	opts.SetTLSConfig(tlsConfig)
 	klog.V(4).Infof("set TLS configuration for MQTT client successfully")
@@ -91,17 +70,69 @@ func HubClientInit(server, clientID, username, password string) *MQTT.ClientOpti
 	return opts
 }
 
+// NewTLSConfig creates the TLS config of the mqtt client, which skips verifying the server
+// if the TLS is not enabled
+func NewTLSConfig(c *v1alpha2.EventBusTLS) (*tls.Config, error) {
+	if c == nil || !c.Enable {
+		return &tls.Config{InsecureSkipVerify: true, ClientAuth: tls.NoClientCert}, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSMqttCertFile, c.TLSMqttPrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load x509 key pair: %v", err)
+	}
+
+	caCert, err := os.ReadFile(c.TLSMqttCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLSMqttCAFile: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(caCert); !ok {
+		return nil, errors.New("cannot parse the certificates")
+	}
+
+	return &tls.Config{
+		RootCAs:            pool,
+		Certificates:       []tls.Certificate{cert},
+		InsecureSkipVerify: false,
+	}, nil
+}
+
 // LoopConnect connect to mqtt server
 func LoopConnect(clientID string, client MQTT.Client) {
+	LoopConnectUntil(clientID, client, nil)
+}
+
+// LoopConnectUntil connects to mqtt server until it is connected or stopCh is closed, and returns
+// whether it is connected. The client connected after stopCh is closed is disconnected again.
+func LoopConnectUntil(clientID string, client MQTT.Client, stopCh <-chan struct{}) bool {
 	for {
+		select {
+		case <-stopCh:
+			klog.Infof("stop connecting to mqtt server with client id: %s", clientID)
+			return false
+		default:
+		}
 		klog.Infof("start connect to mqtt server with client id: %s", clientID)
 		token := client.Connect()
 		klog.Infof("client %s isconnected: %v", clientID, client.IsConnected())
 		if rs, err := CheckClientToken(token); !rs {
 			klog.Errorf("connect error: %v", err)
 		} else {
-			return
+			select {
+			case <-stopCh:
+				client.Disconnect(0)
+				return false
+			default:
+				return true
+			}
+		}
+		select {
+		case <-stopCh:
+			klog.Infof("stop connecting to mqtt server with client id: %s", clientID)
+			return false
+		case <-time.After(LoopConnectPeriord):
 		}
-		time.Sleep(LoopConnectPeriord)
 	}
 }
@@ -222,3 +222,23 @@ func TestLoopConnect(t *testing.T) {
 		})
 	}
 }
+
+// TestLoopConnectUntil checks LoopConnectUntil stops connecting to unreachable MQTT broker
+func TestLoopConnectUntil(t *testing.T) {
+	client := MQTT.NewClient(HubClientInit("tcp://127.0.0.1:1882", "12345", "test_user", "123456789"))
+	stopCh := make(chan struct{})
+	result := make(chan bool)
+	go func() {
+		result <- LoopConnectUntil("12345", client, stopCh)
+	}()
+
+	close(stopCh)
+	select {
+	case connected := <-result:
+		if connected {
+			t.Errorf("common.TestLoopConnectUntil() connected, want stopped")
+		}
+	case <-time.After(10 * time.Second):
+		t.Errorf("common.TestLoopConnectUntil() did not stop")
+	}
+}
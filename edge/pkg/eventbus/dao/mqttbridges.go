@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dao
+
+import (
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+)
+
+const (
+	MqttBridgesName = "mqtt_bridges"
+)
+
+// MqttBridges is the mqtt bridges managed by the eventbus messages
+type MqttBridges struct {
+	Name   string `orm:"column(name); size(256); pk"`
+	Config string `orm:"column(config); null; type(text)"`
+}
+
+// InsertOrUpdateBridge insert or update mqtt_bridges
+func InsertOrUpdateBridge(name, config string) error {
+	_, err := dbm.DBAccess.Raw("INSERT OR REPLACE INTO mqtt_bridges (name, config) VALUES (?,?)", name, config).Exec()
+	klog.V(4).Infof("INSERT result %v", err)
+	return err
+}
+
+// DeleteBridgeByName delete mqtt_bridges by name
+func DeleteBridgeByName(name string) error {
+	num, err := dbm.DBAccess.QueryTable(MqttBridgesName).Filter("name", name).Delete()
+	klog.V(4).Infof("Delete affected Num: %d, %v", num, err)
+	return err
+}
+
+// QueryAllBridges return the configs of all mqtt_bridges
+func QueryAllBridges() (*[]string, error) {
+	bridges := new([]MqttBridges)
+	_, err := dbm.DBAccess.QueryTable(MqttBridgesName).All(bridges)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, v := range *bridges {
+		result = append(result, v.Config)
+	}
+	return &result, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubeedge/kubeedge/edge/mocks/beego"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+)
+
+// TestInsertOrUpdateBridge is function to test InsertOrUpdateBridge
+func TestInsertOrUpdateBridge(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ormerMock := beego.NewMockOrmer(mockCtrl)
+	rawSeterMock := beego.NewMockRawSeter(mockCtrl)
+	dbm.DBAccess = ormerMock
+
+	for _, returnErr := range []error{nil, errFailedDBOperation} {
+		rawSeterMock.EXPECT().Exec().Return(nil, returnErr).Times(1)
+		ormerMock.EXPECT().Raw(gomock.Any(), gomock.Any()).Return(rawSeterMock).Times(1)
+		if err := InsertOrUpdateBridge("site", `{"name":"site"}`); err != returnErr {
+			t.Errorf("Insert or Update Bridge Case failed : wanted %v and got %v", returnErr, err)
+		}
+	}
+}
+
+// TestDeleteBridgeByName is function to test DeleteBridgeByName
+func TestDeleteBridgeByName(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ormerMock := beego.NewMockOrmer(mockCtrl)
+	querySeterMock := beego.NewMockQuerySeter(mockCtrl)
+	dbm.DBAccess = ormerMock
+
+	for _, returnErr := range []error{nil, errFailedDBOperation} {
+		querySeterMock.EXPECT().Filter(gomock.Any(), gomock.Any()).Return(querySeterMock).Times(1)
+		querySeterMock.EXPECT().Delete().Return(int64(1), returnErr).Times(1)
+		ormerMock.EXPECT().QueryTable(gomock.Any()).Return(querySeterMock).Times(1)
+		if err := DeleteBridgeByName("site"); err != returnErr {
+			t.Errorf("Delete Bridge By Name Case failed : wanted %v and got %v", returnErr, err)
+		}
+	}
+}
@@ -10,8 +10,10 @@ import (
 
 	"github.com/kubeedge/beehive/pkg/core"
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
 	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/common/util"
 	eventconfig "github.com/kubeedge/kubeedge/edge/pkg/eventbus/config"
 	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/dao"
@@ -39,6 +41,7 @@ func Register(eventbus *v1alpha2.EventBus, nodeName string) {
 	eventconfig.InitConfigure(eventbus, nodeName)
 	core.Register(newEventbus(eventbus.Enable))
 	orm.RegisterModel(new(dao.SubTopics))
+	orm.RegisterModel(new(dao.MqttBridges))
 }
 
 func (*eventbus) Name() string {
@@ -77,7 +80,8 @@ func (eb *eventbus) Start() {
 			int(eventconfig.Config.MqttSessionQueueSize),
 			eventconfig.Config.MqttServerInternal,
 			eventconfig.Config.MqttRetain,
-			int(eventconfig.Config.MqttQOS))
+			int(eventconfig.Config.MqttQOS),
+			eventconfig.Config.MqttServerAuth)
 		mqttServer.InitInternalTopics()
 		err := mqttServer.Run()
 		if err != nil {
@@ -85,6 +89,7 @@ func (eb *eventbus) Start() {
 			os.Exit(1)
 		}
 		klog.Infof("Launch internal mqtt broker %v successfully", eventconfig.Config.MqttServerInternal)
+		startBridges()
 	}
 
 	eb.pubCloudMsgToEdge()
@@ -94,8 +99,10 @@ func pubMQTT(topic string, payload []byte) {
 	token := mqttBus.MQTTHub.PubCli.Publish(topic, 1, false, payload)
 	if token.WaitTimeout(util.TokenWaitTime) && token.Error() != nil {
 		klog.Errorf("Error in pubMQTT with topic: %s, %v", topic, token.Error())
+		monitor.EventBusPublishes.WithLabelValues(monitor.BrokerExternal, monitor.ResultFailure).Inc()
 	} else {
 		klog.Infof("Success in pubMQTT with topic: %s", topic)
+		monitor.EventBusPublishes.WithLabelValues(monitor.BrokerExternal, monitor.ResultSuccess).Inc()
 	}
 }
 
@@ -115,6 +122,14 @@ func (eb *eventbus) pubCloudMsgToEdge() {
 		operation := accessInfo.GetOperation()
 		resource := accessInfo.GetResource()
 		switch operation {
+		// the bridges are managed with the operations not used by the topics, so publishing
+		// to the topic "mqttbridge" is not taken as managing the bridges
+		case model.InsertOperation, model.UpdateOperation, model.DeleteOperation, model.QueryOperation:
+			if resource != messagepkg.ResourceTypeMqttBridge {
+				klog.Warningf("Unsupported operation %s of resource %s", operation, resource)
+				continue
+			}
+			manageBridge(&accessInfo)
 		case messagepkg.OperationSubscribe:
 			eb.subscribe(resource)
 			klog.Infof("Edge-hub-cli subscribe topic to %s", resource)
@@ -179,6 +194,7 @@ func (eb *eventbus) publish(topic string, payload []byte) {
 	if eventconfig.Config.MqttMode <= v1alpha2.MqttModeBoth {
 		// pub msg to internal mqtt broker.
 		mqttServer.Publish(topic, payload)
+		monitor.EventBusPublishes.WithLabelValues(monitor.BrokerInternal, monitor.ResultSuccess).Inc()
 	}
 }
 
@@ -0,0 +1,375 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/256dpi/gomqtt/broker"
+	"github.com/256dpi/gomqtt/packet"
+	"github.com/256dpi/gomqtt/transport"
+	"k8s.io/klog/v2"
+
+	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	metadao "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/pkg/apis/devices/v1beta1"
+	pb "github.com/kubeedge/kubeedge/pkg/apis/dmi/v1beta1"
+)
+
+const (
+	// anyUser is the username of the ACL granted to all the clients
+	anyUser = "*"
+
+	// deviceTopicPrefix is the prefix of the topics of a device, followed by the device name
+	deviceTopicPrefix = "$hw/events/device/"
+
+	// deviceIndexTTL is how long the protocols of the mappers and the devices are cached
+	deviceIndexTTL = 30 * time.Second
+)
+
+// topicACL is the topics granted to a client
+type topicACL struct {
+	publish   []string
+	subscribe []string
+}
+
+// deviceIndex is the protocols of the registered mappers and the devices, a mapper owns the
+// devices of its protocol
+type deviceIndex struct {
+	mappers map[string]string
+	devices map[string]string
+	expire  time.Time
+}
+
+// authBackend authenticates the clients of the internal mqtt broker with username and password
+// or client certificate, and drops the publishes and subscriptions not granted by the ACLs.
+type authBackend struct {
+	*broker.MemoryBackend
+
+	users map[string]string
+	acls  map[string]*topicACL
+
+	// internal is the client publishing the messages of the edge modules, which is always allowed
+	internal *broker.Client
+
+	// clients maps the authenticated clients to their usernames
+	clients sync.Map
+
+	// deviceACLs indicates whether the mappers may access the topics of their devices
+	deviceACLs bool
+	// loadDeviceIndex loads the protocols of the mappers and the devices from the database
+	loadDeviceIndex func() (*deviceIndex, error)
+
+	// deviceLock protects devices, the database is not queried while holding it
+	deviceLock sync.Mutex
+	devices    *deviceIndex
+	// refreshLock makes the expired index loaded once by the concurrent checks
+	refreshLock sync.Mutex
+}
+
+func newAuthBackend(backend *broker.MemoryBackend, internal *broker.Client, auth v1alpha2.MqttServerAuth) *authBackend {
+	a := &authBackend{
+		MemoryBackend:   backend,
+		users:           make(map[string]string),
+		acls:            make(map[string]*topicACL),
+		internal:        internal,
+		deviceACLs:      auth.EnableDeviceACLs,
+		loadDeviceIndex: loadDeviceIndex,
+	}
+	for _, user := range auth.Users {
+		a.users[user.Username] = user.Password
+	}
+	for _, acl := range auth.ACLs {
+		t, ok := a.acls[acl.Username]
+		if !ok {
+			t = &topicACL{}
+			a.acls[acl.Username] = t
+		}
+		t.publish = append(t.publish, acl.Publish...)
+		t.subscribe = append(t.subscribe, acl.Subscribe...)
+	}
+	return a
+}
+
+// Authenticate accepts the client presenting a verified client certificate, whose common name
+// is taken as the username, or the client with the right username and password.
+func (a *authBackend) Authenticate(client *broker.Client, user, password string) (bool, error) {
+	// check whether the backend is closing
+	if _, err := a.MemoryBackend.Authenticate(client, user, password); err != nil {
+		return false, err
+	}
+
+	username := ""
+	if cn := certCommonName(client); cn != "" && (user == "" || user == cn) {
+		username = cn
+	} else if expected, ok := a.users[user]; ok && user != "" &&
+		subtle.ConstantTimeCompare([]byte(expected), []byte(password)) == 1 {
+		username = user
+	}
+	if username == "" {
+		klog.Warningf("mqtt client %s with username %q is not authorized", client.ID(), user)
+		return false, nil
+	}
+
+	a.clients.Store(client, username)
+	return true, nil
+}
+
+// Subscribe drops the subscriptions not granted to the client
+func (a *authBackend) Subscribe(client *broker.Client, subs []packet.Subscription, ack broker.Ack) error {
+	allowed := make([]packet.Subscription, 0, len(subs))
+	for _, sub := range subs {
+		if !a.canSubscribe(client, sub.Topic) {
+			klog.Warningf("mqtt client %s is not allowed to subscribe topic %s", client.ID(), sub.Topic)
+			continue
+		}
+		allowed = append(allowed, sub)
+	}
+	return a.MemoryBackend.Subscribe(client, allowed, ack)
+}
+
+// Publish drops the message if the topic is not granted to the client
+func (a *authBackend) Publish(client *broker.Client, msg *packet.Message, ack broker.Ack) error {
+	if !a.canPublish(client, msg.Topic) {
+		klog.Warningf("mqtt client %s is not allowed to publish topic %s", client.ID(), msg.Topic)
+		if ack != nil {
+			ack()
+		}
+		return nil
+	}
+	return a.MemoryBackend.Publish(client, msg, ack)
+}
+
+// Log skips the published events of the dropped messages, so they are not dispatched to the edge modules
+func (a *authBackend) Log(event broker.LogEvent, client *broker.Client, pkt packet.Generic, msg *packet.Message, err error) {
+	if event == broker.MessagePublished && msg != nil && !a.canPublish(client, msg.Topic) {
+		return
+	}
+	a.MemoryBackend.Log(event, client, pkt, msg, err)
+}
+
+// Terminate forgets the username of the client
+func (a *authBackend) Terminate(client *broker.Client) error {
+	a.clients.Delete(client)
+	return a.MemoryBackend.Terminate(client)
+}
+
+func (a *authBackend) canPublish(client *broker.Client, topic string) bool {
+	if client == a.internal {
+		return true
+	}
+	username, ok := a.username(client)
+	if !ok {
+		return false
+	}
+	for _, user := range []string{username, anyUser} {
+		if acl, ok := a.acls[user]; ok && matchAny(acl.publish, topic) {
+			return true
+		}
+	}
+	return a.isOwnDeviceTopic(username, topic)
+}
+
+func (a *authBackend) canSubscribe(client *broker.Client, filter string) bool {
+	username, ok := a.username(client)
+	if !ok {
+		return false
+	}
+	for _, user := range []string{username, anyUser} {
+		if acl, ok := a.acls[user]; ok && matchAny(acl.subscribe, filter) {
+			return true
+		}
+	}
+	return a.isOwnDeviceTopic(username, filter)
+}
+
+func (a *authBackend) username(client *broker.Client) (string, bool) {
+	v, ok := a.clients.Load(client)
+	if !ok {
+		return "", false
+	}
+	return v.(string), true
+}
+
+// isOwnDeviceTopic checks whether the topic belongs to a device of the protocol of the mapper
+func (a *authBackend) isOwnDeviceTopic(mapper, topic string) bool {
+	if !a.deviceACLs || !strings.HasPrefix(topic, deviceTopicPrefix) {
+		return false
+	}
+	device := strings.SplitN(strings.TrimPrefix(topic, deviceTopicPrefix), "/", 2)[0]
+	if device == "" || device == "+" || device == "#" {
+		return false
+	}
+
+	index, err := a.deviceIndex()
+	if err != nil {
+		klog.Errorf("failed to check whether device %s belongs to mapper %s: %v", device, mapper, err)
+		return false
+	}
+	protocol := index.mappers[mapper]
+	return protocol != "" && index.devices[device] == protocol
+}
+
+// deviceIndex returns the cached index, and reloads it from the database once it expires
+func (a *authBackend) deviceIndex() (*deviceIndex, error) {
+	if index := a.cachedDeviceIndex(); index != nil {
+		return index, nil
+	}
+
+	a.refreshLock.Lock()
+	defer a.refreshLock.Unlock()
+	// the index may have been reloaded while waiting for the lock
+	if index := a.cachedDeviceIndex(); index != nil {
+		return index, nil
+	}
+	index, err := a.loadDeviceIndex()
+	if err != nil {
+		return nil, err
+	}
+	index.expire = time.Now().Add(deviceIndexTTL)
+
+	a.deviceLock.Lock()
+	a.devices = index
+	a.deviceLock.Unlock()
+	return index, nil
+}
+
+// cachedDeviceIndex returns nil if the index is not loaded or expired
+func (a *authBackend) cachedDeviceIndex() *deviceIndex {
+	a.deviceLock.Lock()
+	defer a.deviceLock.Unlock()
+	if a.devices == nil || !time.Now().Before(a.devices.expire) {
+		return nil
+	}
+	return a.devices
+}
+
+// loadDeviceIndex loads the protocols of the registered mappers and the devices
+func loadDeviceIndex() (*deviceIndex, error) {
+	index := &deviceIndex{
+		mappers: make(map[string]string),
+		devices: make(map[string]string),
+	}
+	metas, err := metadao.QueryMeta("type", deviceconst.ResourceTypeDeviceMapper)
+	if err != nil {
+		return nil, err
+	}
+	for _, meta := range *metas {
+		var info pb.MapperInfo
+		if err := json.Unmarshal([]byte(meta), &info); err != nil {
+			continue
+		}
+		index.mappers[info.Name] = info.Protocol
+	}
+
+	metas, err = metadao.QueryMeta("type", deviceconst.ResourceTypeDevice)
+	if err != nil {
+		return nil, err
+	}
+	for _, meta := range *metas {
+		var d v1beta1.Device
+		if err := json.Unmarshal([]byte(meta), &d); err != nil {
+			continue
+		}
+		index.devices[d.Name] = d.Spec.Protocol.ProtocolName
+	}
+	return index, nil
+}
+
+// matchAny checks whether the topic matches any of the topic filters
+func matchAny(filters []string, topic string) bool {
+	for _, filter := range filters {
+		if matchTopic(filter, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTopic checks whether the topic, or the topic filter of a subscription, is covered by the
+// topic filter. The "+" of the filter matches one level but not "#", and "#" matches the rest.
+func matchTopic(filter, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level == "+" {
+			if topicLevels[i] == "#" {
+				return false
+			}
+			continue
+		}
+		if level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
+
+// certCommonName returns the common name of the verified client certificate of the client
+func certCommonName(client *broker.Client) string {
+	conn, ok := client.Conn().(*transport.NetConn)
+	if !ok {
+		return ""
+	}
+	tlsConn, ok := conn.UnderlyingConn().(*tls.Conn)
+	if !ok {
+		return ""
+	}
+	state := tlsConn.ConnectionState()
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return state.VerifiedChains[0][0].Subject.CommonName
+}
+
+// newServerTLSConfig creates the TLS config of the internal mqtt broker, which verifies the
+// client certificates if given
+func newServerTLSConfig(auth v1alpha2.MqttServerAuth) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(auth.TLSCertFile, auth.TLSPrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the certificate of mqtt server: %v", err)
+	}
+	ca, err := os.ReadFile(auth.TLSCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CA file of mqtt server: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("failed to parse the CA file %s of mqtt server", auth.TLSCAFile)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/256dpi/gomqtt/broker"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestMatchTopic(t *testing.T) {
+	cases := []struct {
+		filter string
+		topic  string
+		match  bool
+	}{
+		{"$hw/events/device/+/twin/update", "$hw/events/device/dev1/twin/update", true},
+		{"$hw/events/device/+/twin/update", "$hw/events/device/dev1/twin/update/delta", false},
+		{"$hw/events/device/+/twin/update", "$hw/events/device/dev1/twin", false},
+		{"$hw/events/device/#", "$hw/events/device/dev1/twin/update", true},
+		{"$hw/events/device/#", "$hw/events/node/node1/membership/get", false},
+		{"$hw/events/device/+/#", "$hw/events/device/#", false},
+		{"$hw/events/device/+/twin/#", "$hw/events/device/+/twin/update", true},
+		{"#", "$hw/events/device/dev1/twin/update", true},
+	}
+
+	for _, c := range cases {
+		if match := matchTopic(c.filter, c.topic); match != c.match {
+			t.Errorf("expected %v for filter %s and topic %s, but got %v", c.match, c.filter, c.topic, match)
+		}
+	}
+}
+
+func TestAuthBackend(t *testing.T) {
+	internal := &broker.Client{}
+	a := newAuthBackend(broker.NewMemoryBackend(), internal, v1alpha2.MqttServerAuth{
+		Enable: true,
+		Users: []v1alpha2.MqttUser{
+			{Username: "app", Password: "app-secret"},
+			{Username: "modbus-mapper", Password: "mapper-secret"},
+		},
+		ACLs: []v1alpha2.MqttACL{
+			{Username: "app", Publish: []string{"app/+/data"}},
+			{Username: "*", Subscribe: []string{"$hw/events/node/+/membership/#"}},
+		},
+		EnableDeviceACLs: true,
+	})
+	loads := 0
+	a.loadDeviceIndex = func() (*deviceIndex, error) {
+		loads++
+		return &deviceIndex{
+			mappers: map[string]string{"modbus-mapper": "modbus"},
+			devices: map[string]string{"thermometer": "modbus", "camera": "onvif"},
+		}, nil
+	}
+
+	stranger, app, mapper := &broker.Client{}, &broker.Client{}, &broker.Client{}
+	if ok, err := a.Authenticate(stranger, "app", "wrong"); ok || err != nil {
+		t.Errorf("expected the client with wrong password rejected, but got %v, %v", ok, err)
+	}
+	if ok, err := a.Authenticate(app, "app", "app-secret"); !ok || err != nil {
+		t.Fatalf("expected the app authenticated, but got %v, %v", ok, err)
+	}
+	if ok, err := a.Authenticate(mapper, "modbus-mapper", "mapper-secret"); !ok || err != nil {
+		t.Fatalf("expected the mapper authenticated, but got %v, %v", ok, err)
+	}
+
+	cases := []struct {
+		name   string
+		client *broker.Client
+		topic  string
+		allow  bool
+	}{
+		{"internal client", internal, "$hw/events/device/thermometer/twin/update", true},
+		{"unauthenticated client", stranger, "app/1/data", false},
+		{"granted topic", app, "app/1/data", true},
+		{"device topic of other mapper", app, "$hw/events/device/thermometer/twin/update", false},
+		{"device topic of the mapper", mapper, "$hw/events/device/thermometer/twin/update", true},
+		{"device topic not of the mapper", mapper, "$hw/events/device/camera/twin/update", false},
+		{"topic not granted", mapper, "app/1/data", false},
+	}
+	for _, c := range cases {
+		if allow := a.canPublish(c.client, c.topic); allow != c.allow {
+			t.Errorf("%s: expected %v to publish %s, but got %v", c.name, c.allow, c.topic, allow)
+		}
+	}
+
+	if !a.canSubscribe(app, "$hw/events/node/node1/membership/get/result") {
+		t.Errorf("expected the topic granted to all the clients could be subscribed")
+	}
+	if a.canSubscribe(mapper, "$hw/events/device/+/twin/update/delta") {
+		t.Errorf("expected the mapper could not subscribe the topics of all the devices")
+	}
+	if loads != 1 {
+		t.Errorf("expected the device index loaded once, but got %d", loads)
+	}
+	// the expired index is reloaded
+	a.devices.expire = time.Now()
+	if !a.canPublish(mapper, "$hw/events/device/thermometer/twin/update") || loads != 2 {
+		t.Errorf("expected the expired device index reloaded, but got %d loads", loads)
+	}
+
+	if err := a.Terminate(app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.canPublish(app, "app/1/data") {
+		t.Errorf("expected the terminated client not allowed")
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/256dpi/gomqtt/broker"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/common/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+// echoTTL is how long a message sent to the remote broker is remembered, to drop it when
+// it comes back by the subscriptions of the same bridge
+const echoTTL = 10 * time.Second
+
+// bridgeMessage is a message to the remote broker
+type bridgeMessage struct {
+	topic   string
+	qos     byte
+	payload []byte
+}
+
+// Bridge mirrors the topics between the internal mqtt broker and a remote mqtt broker
+type Bridge struct {
+	config v1alpha2.MqttBridge
+	server *Server
+	client MQTT.Client
+
+	// local is the client publishing the messages from the remote broker to the internal broker,
+	// so that they are not mirrored back to the same remote broker
+	local *broker.Client
+
+	lock sync.Mutex
+	// buffer is the messages to the remote broker while it is unreachable
+	buffer      []bridgeMessage
+	maxBuffered int
+	// sent is the fingerprints of the messages recently sent to the remote broker
+	sent map[[sha256.Size]byte]time.Time
+
+	// stopCh stops connecting to the remote broker when the bridge is removed or replaced,
+	// connectDone is closed when the connect loop returns
+	stopCh      chan struct{}
+	connectDone chan struct{}
+}
+
+func newBridge(server *Server, config v1alpha2.MqttBridge) (*Bridge, error) {
+	b := &Bridge{
+		config:      config,
+		server:      server,
+		local:       &broker.Client{},
+		maxBuffered: int(config.MaxBufferedMessages),
+		sent:        make(map[[sha256.Size]byte]time.Time),
+		stopCh:      make(chan struct{}),
+		connectDone: make(chan struct{}),
+	}
+	if b.maxBuffered == 0 {
+		b.maxBuffered = constants.DefaultMqttBridgeMaxBufferedMessages
+	}
+
+	tlsConfig, err := util.NewTLSConfig(config.TLS)
+	if err != nil {
+		return nil, err
+	}
+	opts := MQTT.NewClientOptions().AddBroker(config.Server).SetClientID(config.ClientID).
+		SetCleanSession(true).SetAutoReconnect(true).SetTLSConfig(tlsConfig)
+	if config.Username != "" {
+		opts.SetUsername(config.Username)
+		opts.SetPassword(config.Password)
+	}
+	opts.OnConnect = b.onConnect
+	opts.OnConnectionLost = func(_ MQTT.Client, err error) {
+		klog.Warningf("mqtt bridge %s lost the connection to %s: %v", config.Name, config.Server, err)
+	}
+	b.client = MQTT.NewClient(opts)
+	return b, nil
+}
+
+// start connects to the remote broker, it retries until connected or the bridge is stopped
+func (b *Bridge) start() {
+	go func() {
+		defer close(b.connectDone)
+		util.LoopConnectUntil(b.config.ClientID, b.client, b.stopCh)
+	}()
+}
+
+// stop stops connecting to the remote broker before disconnecting, so that the bridge doesn't
+// connect later and compete with its replacement for the same client ID
+func (b *Bridge) stop() {
+	close(b.stopCh)
+	b.client.Disconnect(250)
+}
+
+// onConnect subscribes the topics mirrored from the remote broker, and flushes the buffered messages
+func (b *Bridge) onConnect(client MQTT.Client) {
+	klog.Infof("mqtt bridge %s connected to %s", b.config.Name, b.config.Server)
+	for _, t := range b.config.Topics {
+		if t.Direction == v1alpha2.MqttBridgeOut {
+			continue
+		}
+		topic := t.RemotePrefix + t.Pattern
+		token := client.Subscribe(topic, t.QOS, b.onRemoteMessage)
+		if rs, err := util.CheckClientToken(token); !rs {
+			klog.Errorf("mqtt bridge %s failed to subscribe topic %s: %v", b.config.Name, topic, err)
+		}
+	}
+
+	b.lock.Lock()
+	buffer := b.buffer
+	b.buffer = nil
+	b.lock.Unlock()
+	if len(buffer) > 0 {
+		klog.Infof("mqtt bridge %s flushes %d buffered messages", b.config.Name, len(buffer))
+	}
+	for _, msg := range buffer {
+		b.publish(msg)
+	}
+}
+
+// forward mirrors the message of the internal broker to the remote broker if the topic matches
+func (b *Bridge) forward(topic string, payload []byte) {
+	for _, t := range b.config.Topics {
+		if t.Direction == v1alpha2.MqttBridgeIn || !strings.HasPrefix(topic, t.LocalPrefix) {
+			continue
+		}
+		trimmed := strings.TrimPrefix(topic, t.LocalPrefix)
+		if !matchTopic(t.Pattern, trimmed) {
+			continue
+		}
+		b.publish(bridgeMessage{topic: t.RemotePrefix + trimmed, qos: t.QOS, payload: payload})
+		return
+	}
+}
+
+// publish sends the message to the remote broker, or buffers it if the remote broker is unreachable
+func (b *Bridge) publish(msg bridgeMessage) {
+	if !b.client.IsConnectionOpen() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		if len(b.buffer) >= b.maxBuffered {
+			klog.Warningf("mqtt bridge %s drops the message of topic %s since the buffer is full", b.config.Name, b.buffer[0].topic)
+			b.buffer = b.buffer[1:]
+		}
+		b.buffer = append(b.buffer, msg)
+		return
+	}
+
+	b.rememberSent(msg.topic, msg.payload)
+	b.client.Publish(msg.topic, msg.qos, false, msg.payload)
+}
+
+// onRemoteMessage mirrors the message of the remote broker to the internal broker
+func (b *Bridge) onRemoteMessage(_ MQTT.Client, msg MQTT.Message) {
+	if b.isEcho(msg.Topic(), msg.Payload()) {
+		klog.V(4).Infof("mqtt bridge %s drops the echo of topic %s", b.config.Name, msg.Topic())
+		return
+	}
+	for _, t := range b.config.Topics {
+		if t.Direction == v1alpha2.MqttBridgeOut || !strings.HasPrefix(msg.Topic(), t.RemotePrefix) {
+			continue
+		}
+		trimmed := strings.TrimPrefix(msg.Topic(), t.RemotePrefix)
+		if !matchTopic(t.Pattern, trimmed) {
+			continue
+		}
+		b.server.publishFromBridge(b, t.LocalPrefix+trimmed, msg.Payload())
+		return
+	}
+}
+
+// rememberSent remembers the message sent to the remote broker if it may come back by the
+// subscriptions of the bridge
+func (b *Bridge) rememberSent(topic string, payload []byte) {
+	subscribed := false
+	for _, t := range b.config.Topics {
+		if t.Direction != v1alpha2.MqttBridgeOut && strings.HasPrefix(topic, t.RemotePrefix) &&
+			matchTopic(t.Pattern, strings.TrimPrefix(topic, t.RemotePrefix)) {
+			subscribed = true
+			break
+		}
+	}
+	if !subscribed {
+		return
+	}
+
+	now := time.Now()
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for fp, expire := range b.sent {
+		if now.After(expire) {
+			delete(b.sent, fp)
+		}
+	}
+	b.sent[fingerprint(topic, payload)] = now.Add(echoTTL)
+}
+
+// isEcho checks whether the message from the remote broker was sent by the bridge
+func (b *Bridge) isEcho(topic string, payload []byte) bool {
+	fp := fingerprint(topic, payload)
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	expire, ok := b.sent[fp]
+	if !ok {
+		return false
+	}
+	delete(b.sent, fp)
+	return time.Now().Before(expire)
+}
+
+func fingerprint(topic string, payload []byte) [sha256.Size]byte {
+	return sha256.Sum256(append([]byte(topic+"\x00"), payload...))
+}
+
+// DefaultBridgeClientID returns the default client id of the bridge connecting to the remote broker
+func DefaultBridgeClientID(nodeName, bridgeName string) string {
+	return fmt.Sprintf("kubeedge-bridge-%s-%s", nodeName, bridgeName)
+}
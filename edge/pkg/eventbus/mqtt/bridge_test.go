@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/256dpi/gomqtt/broker"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+// fakeMessage is the message from the remote broker
+type fakeMessage struct {
+	topic   string
+	payload []byte
+}
+
+func (m *fakeMessage) Duplicate() bool   { return false }
+func (m *fakeMessage) Qos() byte         { return 0 }
+func (m *fakeMessage) Retained() bool    { return false }
+func (m *fakeMessage) Topic() string     { return m.topic }
+func (m *fakeMessage) MessageID() uint16 { return 0 }
+func (m *fakeMessage) Payload() []byte   { return m.payload }
+func (m *fakeMessage) Ack()              {}
+
+func newTestBridge(t *testing.T, server *Server, maxBuffered int32) *Bridge {
+	bridge, err := newBridge(server, v1alpha2.MqttBridge{
+		Name:     "site",
+		Server:   "tcp://127.0.0.1:1",
+		ClientID: DefaultBridgeClientID("edge-node", "site"),
+		Topics: []v1alpha2.MqttBridgeTopic{
+			{Pattern: "sensors/#", Direction: v1alpha2.MqttBridgeOut, QOS: 1, RemotePrefix: "plant1/"},
+			{Pattern: "commands/+", Direction: v1alpha2.MqttBridgeIn, LocalPrefix: "site/", RemotePrefix: "plant1/"},
+		},
+		MaxBufferedMessages: maxBuffered,
+	})
+	if err != nil {
+		t.Fatalf("failed to create bridge: %v", err)
+	}
+	return bridge
+}
+
+func TestBridgeForward(t *testing.T) {
+	bridge := newTestBridge(t, NewMqttServer(100, "tcp://127.0.0.1:1884", false, 0, nil), 2)
+
+	bridge.forward("sensors/temperature", []byte("1"))
+	bridge.forward("commands/reboot", []byte("2"))
+	bridge.forward("sensors/humidity", []byte("3"))
+	bridge.forward("sensors/pressure", []byte("4"))
+
+	// the remote broker is unreachable, so the latest messages are buffered
+	if len(bridge.buffer) != 2 {
+		t.Fatalf("expected 2 buffered messages, but got %d", len(bridge.buffer))
+	}
+	for i, topic := range []string{"plant1/sensors/humidity", "plant1/sensors/pressure"} {
+		if bridge.buffer[i].topic != topic || bridge.buffer[i].qos != 1 {
+			t.Errorf("expected buffered message of topic %s with qos 1, but got %+v", topic, bridge.buffer[i])
+		}
+	}
+}
+
+func TestBridgeEcho(t *testing.T) {
+	bridge := newTestBridge(t, NewMqttServer(100, "tcp://127.0.0.1:1884", false, 0, nil), 0)
+
+	// the messages out of the subscriptions of the bridge are not remembered
+	bridge.rememberSent("plant1/sensors/temperature", []byte("1"))
+	if len(bridge.sent) != 0 {
+		t.Errorf("expected no message remembered, but got %d", len(bridge.sent))
+	}
+
+	bridge.rememberSent("plant1/commands/reboot", []byte("now"))
+	if !bridge.isEcho("plant1/commands/reboot", []byte("now")) {
+		t.Errorf("expected the message sent by the bridge is an echo")
+	}
+	if bridge.isEcho("plant1/commands/reboot", []byte("now")) {
+		t.Errorf("expected the echo is dropped only once")
+	}
+}
+
+func TestBridgeRemoteMessage(t *testing.T) {
+	server := NewMqttServer(100, "tcp://127.0.0.1:1884", false, 0, nil)
+	server.backend = broker.NewMemoryBackend()
+	bridge := newTestBridge(t, server, 0)
+	other := newTestBridge(t, server, 0)
+	server.bridges = map[string]*Bridge{"site": bridge, "other": other}
+
+	bridge.onRemoteMessage(nil, &fakeMessage{topic: "plant1/commands/reboot", payload: []byte("now")})
+	bridge.onRemoteMessage(nil, &fakeMessage{topic: "plant1/sensors/temperature", payload: []byte("1")})
+
+	// the messages from the remote broker are not mirrored back by the same bridge
+	if len(bridge.buffer) != 0 {
+		t.Errorf("expected no message mirrored back, but got %+v", bridge.buffer)
+	}
+	// the other bridge does not mirror "site/commands/reboot" since it is out of its topics
+	if len(other.buffer) != 0 {
+		t.Errorf("expected no message mirrored by the other bridge, but got %+v", other.buffer)
+	}
+
+	server.forwardToBridges(server.internal, "sensors/temperature", []byte("1"))
+	if len(bridge.buffer) != 1 || len(other.buffer) != 1 {
+		t.Errorf("expected the message of the edge modules mirrored by both bridges")
+	}
+}
+
+func TestRemoveUnreachableBridge(t *testing.T) {
+	server := NewMqttServer(100, "tcp://127.0.0.1:1884", false, 0, nil)
+	if err := server.AddBridge(v1alpha2.MqttBridge{
+		Name:     "site",
+		Server:   "tcp://127.0.0.1:1",
+		ClientID: DefaultBridgeClientID("edge-node", "site"),
+	}); err != nil {
+		t.Fatalf("failed to add bridge: %v", err)
+	}
+	bridge := server.bridges["site"]
+
+	server.RemoveBridge("site")
+	// the removed bridge stops retrying to connect to the unreachable remote broker
+	select {
+	case <-bridge.connectDone:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the removed bridge to stop connecting")
+	}
+	if bridge.client.IsConnected() {
+		t.Error("expected the removed bridge disconnected")
+	}
+	if len(server.Bridges()) != 0 {
+		t.Errorf("expected no bridges, but got %+v", server.Bridges())
+	}
+}
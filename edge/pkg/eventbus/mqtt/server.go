@@ -17,6 +17,11 @@ limitations under the License.
 package mqtt
 
 import (
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
 	"github.com/256dpi/gomqtt/broker"
 	"github.com/256dpi/gomqtt/packet"
 	"github.com/256dpi/gomqtt/topic"
@@ -24,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/dao"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
 )
 
 // Server serve as an internal mqtt broker.
@@ -52,16 +58,32 @@ type Server struct {
 
 	// A sessionQueueSize will default to 100
 	sessionQueueSize int
+
+	// The client authentication and topic ACLs, the server accepts any client if it is nil
+	auth *v1alpha2.MqttServerAuth
+
+	// The client publishing the messages of the edge modules.
+	internal *broker.Client
+
+	// The bridges mirroring topics to the remote brokers, indexed by name.
+	bridgeLock sync.RWMutex
+	bridges    map[string]*Bridge
 }
 
 // NewMqttServer create an internal mqtt server.
-func NewMqttServer(sqz int, url string, retain bool, qos int) *Server {
+func NewMqttServer(sqz int, url string, retain bool, qos int, auth *v1alpha2.MqttServerAuth) *Server {
+	if auth != nil && !auth.Enable {
+		auth = nil
+	}
 	return &Server{
 		sessionQueueSize: sqz,
 		url:              url,
 		tree:             topic.NewTree(),
 		retain:           retain,
 		qos:              qos,
+		auth:             auth,
+		internal:         &broker.Client{},
+		bridges:          make(map[string]*Bridge),
 	}
 }
 
@@ -69,7 +91,15 @@ func NewMqttServer(sqz int, url string, retain bool, qos int) *Server {
 func (m *Server) Run() error {
 	var err error
 
-	m.server, err = transport.Launch(m.url)
+	launcher := transport.NewLauncher()
+	if m.auth != nil && isSecureURL(m.url) {
+		launcher.TLSConfig, err = newServerTLSConfig(*m.auth)
+		if err != nil {
+			klog.Errorf("Init TLS config failed %v", err)
+			return err
+		}
+	}
+	m.server, err = launcher.Launch(m.url)
 	if err != nil {
 		klog.Errorf("Launch transport failed %v", err)
 		return err
@@ -83,10 +113,16 @@ func (m *Server) Run() error {
 			if len(m.tree.Match(msg.Topic)) > 0 {
 				m.onSubscribe(msg)
 			}
+			m.forwardToBridges(client, msg.Topic, msg.Payload)
 		}
 	}
 
-	engine := broker.NewEngine(m.backend)
+	var backend broker.Backend = m.backend
+	if m.auth != nil {
+		backend = newAuthBackend(m.backend, m.internal, *m.auth)
+		klog.Infof("Client authentication and topic ACLs of internal mqtt broker are enabled")
+	}
+	engine := broker.NewEngine(backend)
 	engine.Accept(m.server)
 
 	return nil
@@ -131,13 +167,95 @@ func (m *Server) RemoveTopic(topic string) {
 
 // Publish will dispatch topic msg to its subscribers directly.
 func (m *Server) Publish(topic string, payload []byte) {
-	client := &broker.Client{}
+	msg := &packet.Message{
+		Topic:   topic,
+		Retain:  m.retain,
+		Payload: payload,
+		QOS:     packet.QOS(m.qos),
+	}
+	m.backend.Publish(m.internal, msg, nil)
+	m.forwardToBridges(m.internal, topic, payload)
+}
 
+// AddBridge starts the bridge to the remote broker, the existing bridge with the same name is replaced.
+func (m *Server) AddBridge(config v1alpha2.MqttBridge) error {
+	bridge, err := newBridge(m, config)
+	if err != nil {
+		return fmt.Errorf("failed to create mqtt bridge %s: %v", config.Name, err)
+	}
+
+	m.bridgeLock.Lock()
+	old, ok := m.bridges[config.Name]
+	m.bridges[config.Name] = bridge
+	m.bridgeLock.Unlock()
+
+	if ok {
+		old.stop()
+	}
+	bridge.start()
+	klog.Infof("Start mqtt bridge %s to %s", config.Name, config.Server)
+	return nil
+}
+
+// RemoveBridge stops the bridge and removes it.
+func (m *Server) RemoveBridge(name string) {
+	m.bridgeLock.Lock()
+	bridge, ok := m.bridges[name]
+	delete(m.bridges, name)
+	m.bridgeLock.Unlock()
+
+	if ok {
+		bridge.stop()
+		klog.Infof("Stop mqtt bridge %s", name)
+	}
+}
+
+// Bridges returns the configs of the bridges sorted by name.
+func (m *Server) Bridges() []v1alpha2.MqttBridge {
+	m.bridgeLock.RLock()
+	defer m.bridgeLock.RUnlock()
+	configs := make([]v1alpha2.MqttBridge, 0, len(m.bridges))
+	for _, bridge := range m.bridges {
+		configs = append(configs, bridge.config)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Name < configs[j].Name
+	})
+	return configs
+}
+
+// forwardToBridges mirrors the message to the remote brokers, except the one it comes from.
+func (m *Server) forwardToBridges(origin *broker.Client, topic string, payload []byte) {
+	m.bridgeLock.RLock()
+	defer m.bridgeLock.RUnlock()
+	for _, bridge := range m.bridges {
+		if bridge.local != origin {
+			bridge.forward(topic, payload)
+		}
+	}
+}
+
+// publishFromBridge publishes the message from the remote broker to the internal broker, and
+// dispatches it to the edge modules if the topic is subscribed.
+func (m *Server) publishFromBridge(bridge *Bridge, topic string, payload []byte) {
 	msg := &packet.Message{
 		Topic:   topic,
 		Retain:  m.retain,
 		Payload: payload,
 		QOS:     packet.QOS(m.qos),
 	}
-	m.backend.Publish(client, msg, nil)
+	m.backend.Publish(bridge.local, msg, nil)
+	if len(m.tree.Match(topic)) > 0 {
+		m.onSubscribe(msg)
+	}
+	m.forwardToBridges(bridge.local, topic, payload)
+}
+
+// isSecureURL checks whether the mqtt url is served over TLS
+func isSecureURL(mqttURL string) bool {
+	u, err := url.ParseRequestURI(mqttURL)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "tls" || u.Scheme == "mqtts" || u.Scheme == "wss"
 }
@@ -11,9 +11,11 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/offlinetoken"
 	policyv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/policy/v1alpha1"
 )
 
@@ -154,8 +156,38 @@ func (c *serviceAccountToken) GetServiceAccountToken(namespace string, name stri
 	tokenReq, err := getTokenLocally(name, namespace, tr)
 	if err != nil {
 		resource := fmt.Sprintf("%s/%s/%s", namespace, model.ResourceTypeServiceAccountToken, name)
-		return getTokenRemotely(resource, tr, c)
+		tokenReq, err = getTokenRemotely(resource, tr, c)
+		if err != nil {
+			// the token is only minted offline when the cloud can't be reached,
+			// the cloud refusing the token must not be bypassed
+			if cloudconnection.IsConnected() {
+				return nil, err
+			}
+			return mintTokenOffline(namespace, name, tr, err)
+		}
+	}
+	return tokenReq, nil
+}
+
+// mintTokenOffline mints the token by the offline token issuer when edge is disconnected from cloud,
+// only the ServiceAccounts allowed by the ServiceAccountAccess synced to the node get tokens
+func mintTokenOffline(namespace, name string, tr *authenticationv1.TokenRequest, remoteErr error) (*authenticationv1.TokenRequest, error) {
+	issuer := offlinetoken.Get()
+	if issuer == nil {
+		return nil, remoteErr
+	}
+	sa, err := newServiceAccount(namespace).Get(name)
+	if err != nil {
+		klog.Errorf("failed to mint token offline for serviceaccount %s/%s: %v", namespace, name, err)
+		return nil, remoteErr
+	}
+	sa.Namespace = namespace
+	tokenReq, err := issuer.Mint(sa, tr)
+	if err != nil {
+		klog.Errorf("failed to mint token offline for serviceaccount %s/%s: %v", namespace, name, err)
+		return nil, remoteErr
 	}
+	klog.V(4).Infof("minted token offline for serviceaccount %s/%s", namespace, name)
 	return tokenReq, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 )
 
 // constant metatable name reference
@@ -28,6 +30,7 @@ type Meta struct {
 
 // SaveMeta save meta to db
 func SaveMeta(meta *Meta) error {
+	defer observeDBOperation("insert", time.Now())
 	num, err := dbm.DBAccess.Insert(meta)
 	klog.V(4).Infof("Insert affected Num: %d, %v", num, err)
 	if err == nil || IsNonUniqueNameError(err) {
@@ -48,6 +51,7 @@ func IsNonUniqueNameError(err error) bool {
 
 // DeleteMetaByKey delete meta by key
 func DeleteMetaByKey(key string) error {
+	defer observeDBOperation("delete", time.Now())
 	num, err := dbm.DBAccess.QueryTable(MetaTableName).Filter("key", key).Delete()
 	klog.V(4).Infof("Delete affected Num: %d, %v", num, err)
 	return err
@@ -55,6 +59,7 @@ func DeleteMetaByKey(key string) error {
 
 // DeleteMetaByKeyAndPodUID delete meta by key and podUID
 func DeleteMetaByKeyAndPodUID(key, podUID string) (int64, error) {
+	defer observeDBOperation("delete", time.Now())
 	sqlStr := fmt.Sprintf("DELETE FROM meta WHERE key = '%s' and value LIKE '%%%s%%'", key, podUID)
 	res, err := dbm.DBAccess.Raw(sqlStr).Exec()
 	if err != nil {
@@ -66,6 +71,7 @@ func DeleteMetaByKeyAndPodUID(key, podUID string) (int64, error) {
 
 // UpdateMeta update meta
 func UpdateMeta(meta *Meta) error {
+	defer observeDBOperation("update", time.Now())
 	num, err := dbm.DBAccess.Update(meta) // will update all field
 	klog.V(4).Infof("Update affected Num: %d, %v", num, err)
 	return err
@@ -73,6 +79,7 @@ func UpdateMeta(meta *Meta) error {
 
 // InsertOrUpdate insert or update meta
 func InsertOrUpdate(meta *Meta) error {
+	defer observeDBOperation("insert_or_update", time.Now())
 	_, err := dbm.DBAccess.Raw("INSERT OR REPLACE INTO meta (key, type, value) VALUES (?,?,?)", meta.Key, meta.Type, meta.Value).Exec() // will update all field
 	klog.V(4).Infof("Update result %v", err)
 	return err
@@ -80,6 +87,7 @@ func InsertOrUpdate(meta *Meta) error {
 
 // UpdateMetaField update special field
 func UpdateMetaField(key string, col string, value interface{}) error {
+	defer observeDBOperation("update", time.Now())
 	num, err := dbm.DBAccess.QueryTable(MetaTableName).Filter("key", key).Update(map[string]interface{}{col: value})
 	klog.V(4).Infof("Update affected Num: %d, %v", num, err)
 	return err
@@ -87,6 +95,7 @@ func UpdateMetaField(key string, col string, value interface{}) error {
 
 // UpdateMetaFields update special fields
 func UpdateMetaFields(key string, cols map[string]interface{}) error {
+	defer observeDBOperation("update", time.Now())
 	num, err := dbm.DBAccess.QueryTable(MetaTableName).Filter("key", key).Update(cols)
 	klog.V(4).Infof("Update affected Num: %d, %v", num, err)
 	return err
@@ -94,6 +103,7 @@ func UpdateMetaFields(key string, cols map[string]interface{}) error {
 
 // QueryMeta return only meta's value, if no error, Meta not null
 func QueryMeta(key string, condition string) (*[]string, error) {
+	defer observeDBOperation("query", time.Now())
 	meta := new([]Meta)
 	_, err := dbm.DBAccess.QueryTable(MetaTableName).Filter(key, condition).All(meta)
 	if err != nil {
@@ -109,6 +119,7 @@ func QueryMeta(key string, condition string) (*[]string, error) {
 
 // QueryAllMeta return all meta, if no error, Meta not null
 func QueryAllMeta(key string, condition string) (*[]Meta, error) {
+	defer observeDBOperation("query", time.Now())
 	meta := new([]Meta)
 	_, err := dbm.DBAccess.QueryTable(MetaTableName).Filter(key, condition).All(meta)
 	if err != nil {
@@ -118,6 +129,11 @@ func QueryAllMeta(key string, condition string) (*[]Meta, error) {
 	return meta, nil
 }
 
+// observeDBOperation records the duration of the operation on the meta table
+func observeDBOperation(operation string, start time.Time) {
+	monitor.ObserveSince(monitor.MetaDBOperationDuration.WithLabelValues(operation), start)
+}
+
 // SaveMQTTMeta saves mqtt container data in sqlites
 // When egdecore starts, edged will start mqtt container
 func SaveMQTTMeta(nodeName string) error {
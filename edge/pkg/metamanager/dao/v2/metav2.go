@@ -1,10 +1,14 @@
 package v2
 
 import (
+	"strings"
+	"time"
+
 	"github.com/beego/beego/orm"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 )
 
 // constant metatable name reference
@@ -12,11 +16,12 @@ const (
 	NewMetaTableName = "meta_v2"
 
 	// column name
-	KEY  = "Key"
-	GVR  = "GroupVersionResource"
-	NS   = "Namespace"
-	NAME = "Name"
-	RV   = "ResourceVersion"
+	KEY   = "Key"
+	GVR   = "GroupVersionResource"
+	NS    = "Namespace"
+	NAME  = "Name"
+	RV    = "ResourceVersion"
+	VALUE = "Value"
 
 	NullNamespace = "null"
 	GroupCore     = "core"
@@ -48,38 +53,63 @@ type MetaV2 struct {
 
 // List a slice of raw data by Group Version Resource Namespace Name
 func RawMetaByGVRNN(gvr schema.GroupVersionResource, namespace string, name string) (*[]MetaV2, error) {
+	defer monitor.ObserveSince(monitor.MetaDBOperationDuration.WithLabelValues("query"), time.Now())
 	objs := new([]MetaV2)
-	var err error
 	// TODO: use getCondition
 	//cond := getCondition(gvr,namespace,name)
 	//klog.Infof("cond:%+v",cond)
 	//_,err = dbm.DBAccess.QueryTable(NewMetaTableName).SetCond(cond).All(objs)
-	if gvr.Empty() {
-		_, err = dbm.DBAccess.QueryTable(NewMetaTableName).All(objs)
-	} else {
-		switch namespace {
-		case NullNamespace, "":
-			switch name {
-			case NullName, "":
-				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).All(objs)
-			default:
-				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).Filter(NAME, name).All(objs)
-			}
-		default:
-			switch name {
-			case NullName, "":
-				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).Filter(NS, namespace).All(objs)
-			default:
-				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).Filter(NS, namespace).Filter(NAME, name).All(objs)
-			}
-		}
+	_, err := queryByGVRNN(gvr, namespace, name).All(objs)
+	if err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
+// RawMetaPageByGVRNN lists at most limit records by Group Version Resource Namespace Name,
+// the records are ordered by key and only the ones whose key is greater than startKey are returned.
+// limit <= 0 means no limit. Only the records whose value contains all the valueTerms are returned.
+func RawMetaPageByGVRNN(gvr schema.GroupVersionResource, namespace string, name string, startKey string, limit int64, valueTerms ...string) (*[]MetaV2, error) {
+	defer monitor.ObserveSince(monitor.MetaDBOperationDuration.WithLabelValues("query"), time.Now())
+	objs := new([]MetaV2)
+	qs := queryByGVRNN(gvr, namespace, name).OrderBy(KEY)
+	for _, term := range valueTerms {
+		qs = qs.Filter(VALUE+"__contains", escapeLike(term))
+	}
+	if startKey != "" {
+		qs = qs.Filter(KEY+"__gt", startKey)
 	}
+	if limit > 0 {
+		qs = qs.Limit(limit)
+	}
+	_, err := qs.All(objs)
 	if err != nil {
 		return nil, err
 	}
 	return objs, nil
 }
 
+// escapeLike escapes the wildcard `_` and the escape character of the term for the LIKE
+// of the contains filter, the wildcard `%` is escaped by the orm itself
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `_`, `\_`).Replace(term)
+}
+
+func queryByGVRNN(gvr schema.GroupVersionResource, namespace string, name string) orm.QuerySeter {
+	qs := dbm.DBAccess.QueryTable(NewMetaTableName)
+	if gvr.Empty() {
+		return qs
+	}
+	qs = qs.Filter(GVR, gvr.String())
+	if namespace != NullNamespace && namespace != "" {
+		qs = qs.Filter(NS, namespace)
+	}
+	if name != NullName && name != "" {
+		qs = qs.Filter(NAME, name)
+	}
+	return qs
+}
+
 func getCondition(gvr schema.GroupVersionResource, namespace string, name string) *orm.Condition {
 	cond := orm.NewCondition()
 	cond.And(GVR, gvr.String())
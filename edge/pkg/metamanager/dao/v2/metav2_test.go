@@ -0,0 +1,37 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		term string
+		want string
+	}{
+		{term: `"nginx"`, want: `"nginx"`},
+		{term: `"my_app"`, want: `"my\_app"`},
+		{term: `"a\b"`, want: `"a\\b"`},
+		// % is escaped by the orm
+		{term: `"50%"`, want: `"50%"`},
+	}
+	for _, tt := range tests {
+		if got := escapeLike(tt.term); got != tt.want {
+			t.Errorf("escapeLike(%s) = %s, want %s", tt.term, got, tt.want)
+		}
+	}
+}
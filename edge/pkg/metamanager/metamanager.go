@@ -13,6 +13,7 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver"
 	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/offlinetoken"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
 )
 
@@ -62,6 +63,7 @@ func (m *metaManager) Enable() bool {
 func (m *metaManager) Start() {
 	if metaserverconfig.Config.Enable {
 		imitator.StorageInit()
+		offlinetoken.Start(beehiveContext.Done())
 		go metaserver.NewMetaServer().Start(beehiveContext.Done())
 	}
 
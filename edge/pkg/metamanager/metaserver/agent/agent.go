@@ -36,6 +36,7 @@ import (
 	edgemodule "github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
 	"github.com/kubeedge/kubeedge/pkg/metaserver"
+	"github.com/kubeedge/kubeedge/pkg/util/pass-through"
 )
 
 var DefaultAgent = NewApplicationAgent()
@@ -75,6 +76,11 @@ func (a *Agent) Generate(ctx context.Context, verb metaserver.ApplicationVerb, o
 	}
 
 	info, _ := apirequest.RequestInfoFrom(ctx)
+	// pass through resource requests are keyed by their raw path,
+	// so that cloud can send them to the apiserver directly
+	if info.IsResourceRequest && passthrough.IsPassThroughResourcePath(info.Path, info.Verb) {
+		key = info.Path
+	}
 
 	app, err := metaserver.NewApplication(ctx, key, verb, metaserverconfig.Config.NodeName, info.Subresource, option, obj)
 	if err != nil {
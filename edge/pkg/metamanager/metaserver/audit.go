@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metaserver
+
+import (
+	"time"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/plugin/pkg/audit/buffered"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/constants"
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
+	"github.com/kubeedge/kubeedge/pkg/metaserver"
+	utilaudit "github.com/kubeedge/kubeedge/pkg/util/audit"
+)
+
+// forwardBatchConfig is the batch config of the audit events forwarded to cloud,
+// the events are dropped if the buffer is full
+var forwardBatchConfig = buffered.BatchConfig{
+	BufferSize:     10000,
+	MaxBatchSize:   400,
+	MaxBatchWait:   30 * time.Second,
+	ThrottleEnable: true,
+	ThrottleQPS:    1,
+	ThrottleBurst:  5,
+	AsyncDelegate:  false,
+}
+
+type metaServerAudit struct {
+	Backend audit.Backend
+	Policy  audit.PolicyRuleEvaluator
+}
+
+// buildAudit builds the audit backend and policy of MetaServer, it returns nil if audit is disabled
+func buildAudit() *metaServerAudit {
+	config := metaserverconfig.Config.Audit
+	if config == nil || !config.Enable {
+		return nil
+	}
+	policy, err := utilaudit.LoadPolicy(config.PolicyFile)
+	if err != nil {
+		klog.Exitf("Failed to load audit policy of metaserver: %v", err)
+	}
+	backend, err := utilaudit.NewLogBackend(config.LogPath, int(config.LogMaxAge), int(config.LogMaxBackups), int(config.LogMaxSize))
+	if err != nil {
+		klog.Exitf("Failed to create audit log of metaserver: %v", err)
+	}
+	if config.ForwardToCloud {
+		backend = audit.Union(backend, buffered.NewBackend(&cloudBackend{nodeName: metaserverconfig.Config.NodeName}, forwardBatchConfig))
+	}
+	return &metaServerAudit{Backend: backend, Policy: policy}
+}
+
+// cloudBackend forwards the audit events to cloudcore through edgehub. The events are
+// dropped when the node is disconnected, since they are still in the local audit log.
+type cloudBackend struct {
+	nodeName string
+}
+
+var _ audit.Backend = &cloudBackend{}
+
+func (b *cloudBackend) ProcessEvents(events ...*auditinternal.Event) bool {
+	if !connect.IsConnected() {
+		klog.V(4).Infof("node is disconnected, %d audit events are not forwarded to cloud", len(events))
+		return true
+	}
+	v1Events, err := utilaudit.ToV1Events(events)
+	if err != nil {
+		audit.HandlePluginError(b.String(), err, events...)
+		return false
+	}
+	list := &auditv1.EventList{Items: v1Events}
+	msg := model.NewMessage("").
+		BuildRouter(metaserver.MetaServerSource, constants.AuditGroup, "node/"+b.nodeName+"/audit", model.UploadOperation).
+		FillBody(list)
+	beehiveContext.Send(modules.EdgeHubModuleName, *msg)
+	return true
+}
+
+func (b *cloudBackend) Run(stopCh <-chan struct{}) error {
+	return nil
+}
+
+func (b *cloudBackend) Shutdown() {}
+
+func (b *cloudBackend) String() string {
+	return "cloud"
+}
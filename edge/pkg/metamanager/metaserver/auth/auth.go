@@ -14,6 +14,7 @@ import (
 	"k8s.io/kubernetes/pkg/serviceaccount"
 
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/client"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/offlinetoken"
 )
 
 type jwtTokenAuthenticator struct {
@@ -123,3 +124,17 @@ func (j *jwtTokenAuthenticator) AuthenticateToken(ctx context.Context, tokenData
 		Audiences: auds,
 	}, true, nil
 }
+
+// OfflineTokenAuthenticator authenticates the tokens minted by the offline token issuer of the node.
+// The issuer is looked up for every token since it becomes ready after the delegation certificate
+// is got from cloudcore, and its key is rotated with the certificate.
+func OfflineTokenAuthenticator(implicitAuds authenticator.Audiences, validator serviceaccount.Validator) authenticator.Token {
+	return authenticator.TokenFunc(func(ctx context.Context, tokenData string) (*authenticator.Response, bool, error) {
+		issuer := offlinetoken.Get()
+		if issuer == nil {
+			return nil, false, nil
+		}
+		return JWTTokenAuthenticator(nil, []string{issuer.Name()}, []interface{}{issuer.PublicKey()},
+			implicitAuds, validator).AuthenticateToken(ctx, tokenData)
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	edgehubconfig "github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/pkg/util/pass-through"
 )
 
 var Config Configure
@@ -25,5 +26,6 @@ func InitConfigure(c *v1alpha2.MetaServer) {
 		if len(Config.MetaServer.APIAudiences) == 0 && len(Config.MetaServer.ServiceAccountIssuers) != 0 {
 			Config.MetaServer.APIAudiences = Config.MetaServer.ServiceAccountIssuers
 		}
+		passthrough.SetPassThroughPaths(Config.MetaServer.PassThroughPaths)
 	})
 }
@@ -12,7 +12,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metainternalversionscheme "k8s.io/apimachinery/pkg/apis/meta/internalversion/scheme"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -112,11 +114,27 @@ func (f *Factory) Update(req *request.RequestInfo) http.Handler {
 }
 
 // PassThrough
-// handel with the pass through request
+// handel with the pass through request, the request body is forwarded for non-get verbs
 func (f *Factory) PassThrough() http.Handler {
 	h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		options := metav1.GetOptions{}
-		result, err := f.storage.PassThrough(req.Context(), &options)
+		var obj runtime.Object
+		if req.Method != http.MethodGet && req.ContentLength != 0 {
+			body, err := limitedReadBody(req, f.scope.MaxRequestBodyBytes)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if len(body) > 0 {
+				u := &unstructured.Unstructured{}
+				if err := u.UnmarshalJSON(body); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				obj = u
+			}
+		}
+		result, err := f.storage.PassThrough(req.Context(), &options, obj)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
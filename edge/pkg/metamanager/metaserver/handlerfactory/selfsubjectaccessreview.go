@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlerfactory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// IsSelfSubjectAccessReview returns whether the request creates a SelfSubjectAccessReview
+func IsSelfSubjectAccessReview(reqInfo *request.RequestInfo) bool {
+	return reqInfo.IsResourceRequest && reqInfo.Verb == "create" &&
+		reqInfo.APIGroup == authorizationv1.GroupName && reqInfo.Resource == "selfsubjectaccessreviews"
+}
+
+// SelfSubjectAccessReview answers the SelfSubjectAccessReview with the authorizer of the metaserver.
+// It can not be passed through to the cloud, where it would be answered for cloudcore rather than
+// the requester. A nil authorizer means the metaserver does not require authorization, so
+// everything is allowed.
+func (f *Factory) SelfSubjectAccessReview(a authorizer.Authorizer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gv := authorizationv1.SchemeGroupVersion
+		body, err := limitedReadBody(req, f.scope.MaxRequestBodyBytes)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, f.scope.Serializer, gv, w, req)
+			return
+		}
+		review := &authorizationv1.SelfSubjectAccessReview{}
+		if err := json.Unmarshal(body, review); err != nil {
+			responsewriters.ErrorNegotiated(errors.NewBadRequest(err.Error()), f.scope.Serializer, gv, w, req)
+			return
+		}
+
+		review.Status = authorizationv1.SubjectAccessReviewStatus{
+			Allowed: true,
+			Reason:  "authorization is not required by the metaserver",
+		}
+		if a != nil {
+			u, ok := request.UserFrom(req.Context())
+			if !ok {
+				responsewriters.ErrorNegotiated(errors.NewBadRequest("no user present on request"), f.scope.Serializer, gv, w, req)
+				return
+			}
+			attrs, err := selfSubjectAccessReviewAttributes(u, &review.Spec)
+			if err != nil {
+				responsewriters.ErrorNegotiated(errors.NewBadRequest(err.Error()), f.scope.Serializer, gv, w, req)
+				return
+			}
+			decision, reason, err := a.Authorize(req.Context(), attrs)
+			review.Status = authorizationv1.SubjectAccessReviewStatus{
+				Allowed: decision == authorizer.DecisionAllow,
+				Denied:  decision == authorizer.DecisionDeny,
+				Reason:  reason,
+			}
+			if err != nil {
+				review.Status.EvaluationError = err.Error()
+			}
+		}
+
+		review.SetGroupVersionKind(gv.WithKind("SelfSubjectAccessReview"))
+		data, err := json.Marshal(review)
+		if err != nil {
+			responsewriters.ErrorNegotiated(errors.NewInternalError(err), f.scope.Serializer, gv, w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(data)
+	})
+}
+
+// selfSubjectAccessReviewAttributes converts the spec of the SelfSubjectAccessReview of u to the
+// attributes of the authorizer
+func selfSubjectAccessReviewAttributes(u user.Info, spec *authorizationv1.SelfSubjectAccessReviewSpec) (authorizer.Attributes, error) {
+	if (spec.ResourceAttributes == nil) == (spec.NonResourceAttributes == nil) {
+		return nil, fmt.Errorf("exactly one of resourceAttributes and nonResourceAttributes must be specified")
+	}
+	if spec.ResourceAttributes != nil {
+		ra := spec.ResourceAttributes
+		return authorizer.AttributesRecord{
+			User:            u,
+			Verb:            ra.Verb,
+			Namespace:       ra.Namespace,
+			APIGroup:        ra.Group,
+			APIVersion:      ra.Version,
+			Resource:        ra.Resource,
+			Subresource:     ra.Subresource,
+			Name:            ra.Name,
+			ResourceRequest: true,
+		}, nil
+	}
+	nra := spec.NonResourceAttributes
+	return authorizer.AttributesRecord{
+		User:            u,
+		Verb:            nra.Verb,
+		Path:            nra.Path,
+		ResourceRequest: false,
+	}, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlerfactory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/scope"
+)
+
+func TestSelfSubjectAccessReview(t *testing.T) {
+	f := &Factory{scope: scope.NewRequestScope()}
+	// alice can only get pods
+	a := authorizer.AuthorizerFunc(func(ctx context.Context, attrs authorizer.Attributes) (authorizer.Decision, string, error) {
+		if attrs.GetUser().GetName() == "alice" && attrs.GetVerb() == "get" && attrs.GetResource() == "pods" {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "", nil
+	})
+
+	cases := []struct {
+		name       string
+		authorizer authorizer.Authorizer
+		user       user.Info
+		body       string
+		code       int
+		allowed    bool
+	}{
+		{
+			name:       "allowed",
+			authorizer: a,
+			user:       &user.DefaultInfo{Name: "alice"},
+			body:       `{"spec":{"resourceAttributes":{"verb":"get","resource":"pods"}}}`,
+			code:       http.StatusCreated,
+			allowed:    true,
+		},
+		{
+			name:       "answered for the requester",
+			authorizer: a,
+			user:       &user.DefaultInfo{Name: "bob"},
+			body:       `{"spec":{"resourceAttributes":{"verb":"get","resource":"pods"}}}`,
+			code:       http.StatusCreated,
+			allowed:    false,
+		},
+		{
+			name:       "no attributes",
+			authorizer: a,
+			user:       &user.DefaultInfo{Name: "alice"},
+			body:       `{"spec":{}}`,
+			code:       http.StatusBadRequest,
+		},
+		{
+			name:    "authorization not required",
+			body:    `{"spec":{"nonResourceAttributes":{"verb":"get","path":"/metrics"}}}`,
+			code:    http.StatusCreated,
+			allowed: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", strings.NewReader(c.body))
+			if c.user != nil {
+				req = req.WithContext(request.WithUser(req.Context(), c.user))
+			}
+			w := httptest.NewRecorder()
+			f.SelfSubjectAccessReview(c.authorizer).ServeHTTP(w, req)
+			if w.Code != c.code {
+				t.Fatalf("expected code %d, but got %d: %s", c.code, w.Code, w.Body.String())
+			}
+			if c.code != http.StatusCreated {
+				return
+			}
+			review := &authorizationv1.SelfSubjectAccessReview{}
+			if err := json.Unmarshal(w.Body.Bytes(), review); err != nil {
+				t.Fatal(err)
+			}
+			if review.Status.Allowed != c.allowed {
+				t.Errorf("expected allowed %v, but got %+v", c.allowed, review.Status)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"sync"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -35,6 +37,7 @@ type Client interface {
 
 	// This set of functions for upper storage
 	List(ctx context.Context, key string) (Resp, error)
+	ListWithOptions(ctx context.Context, key string, opts ListOptions) (Resp, error)
 	Get(ctx context.Context, key string) (Resp, error)
 	Watch(ctx context.Context, key string, ResourceVersion uint64) <-chan watch.Event
 }
@@ -44,6 +47,23 @@ type Resp struct {
 	Kvs *[]v2.MetaV2
 	// synonymous with resource version
 	Revision uint64
+	// More indicates that there are more matched objects after the last one in Kvs,
+	// it is only set by ListWithOptions when the Limit is reached
+	More bool
+}
+
+// ListOptions is used to select and page the objects in ListWithOptions
+type ListOptions struct {
+	// Label selects the objects by labels, nil means everything.
+	// Equality requirements are pushed down to the sql query.
+	Label labels.Selector
+	// Field selects the objects by metadata.name and metadata.namespace, nil means everything.
+	// Exact matches are pushed down to the sql query.
+	Field fields.Selector
+	// StartKey only selects the objects whose key is greater than it
+	StartKey string
+	// Limit is the max number of objects returned, 0 means no limit
+	Limit int64
 }
 
 func newV2Client() Client {
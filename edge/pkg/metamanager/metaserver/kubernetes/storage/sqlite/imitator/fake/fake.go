@@ -20,6 +20,7 @@ type Client struct {
 	GetRevisionF                  func() uint64
 	SetRevisionF                  func(version interface{})
 	ListF                         func(ctx context.Context, key string) (imitator.Resp, error)
+	ListWithOptionsF              func(ctx context.Context, key string, opts imitator.ListOptions) (imitator.Resp, error)
 	GetF                          func(ctx context.Context, key string) (imitator.Resp, error)
 	WatchF                        func(ctx context.Context, key string, ResourceVersion uint64) <-chan watch.Event
 }
@@ -64,6 +65,11 @@ func (c Client) List(ctx context.Context, key string) (imitator.Resp, error) {
 	return c.ListF(ctx, key)
 }
 
+// ListWithOptions fake
+func (c Client) ListWithOptions(ctx context.Context, key string, opts imitator.ListOptions) (imitator.Resp, error) {
+	return c.ListWithOptionsF(ctx, key, opts)
+}
+
 // Get fake
 func (c Client) Get(ctx context.Context, key string) (imitator.Resp, error) {
 	return c.GetF(ctx, key)
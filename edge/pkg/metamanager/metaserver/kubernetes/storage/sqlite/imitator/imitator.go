@@ -8,9 +8,13 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/storage"
 	"k8s.io/klog/v2"
@@ -19,11 +23,15 @@ import (
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator/watchhook"
 	"github.com/kubeedge/kubeedge/pkg/metaserver"
 )
 
+// listBatchSize is the number of records read from meta_v2 at a time by ListWithOptions
+const listBatchSize = 500
+
 // imitator is a storage based on metav2 that imitate the behavior of etcd
 type imitator struct {
 	lock sync.RWMutex
@@ -89,6 +97,7 @@ func (s *imitator) InsertOrUpdateObj(ctx context.Context, obj runtime.Object) er
 func (s *imitator) insertOrReplaceMetaV2(m v2.MetaV2, objRv uint64) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	defer monitor.ObserveSince(monitor.MetaDBOperationDuration.WithLabelValues("insert_or_update"), time.Now())
 	_, err := dbm.DBAccess.Raw("INSERT OR REPLACE INTO meta_v2 (key, groupversionresource, namespace,name,resourceversion,value) VALUES (?,?,?,?,?,?)", m.Key, m.GroupVersionResource, m.Namespace, m.Name, m.ResourceVersion, m.Value).Exec()
 	var maxRetryTimes = 3
 	for i := 1; err != nil; i++ {
@@ -116,6 +125,7 @@ func (s *imitator) InsertOrUpdatePassThroughObj(ctx context.Context, obj []byte,
 func (s *imitator) GetPassThroughObj(ctx context.Context, key string) ([]byte, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	defer monitor.ObserveSince(monitor.MetaDBOperationDuration.WithLabelValues("query"), time.Now())
 	results := new([]v2.MetaV2)
 	_, err := dbm.DBAccess.QueryTable(v2.NewMetaTableName).Filter(v2.KEY, key).All(results)
 	if err != nil {
@@ -136,7 +146,9 @@ func (s *imitator) Delete(ctx context.Context, key string) error {
 		Key: key,
 	}
 	s.lock.Lock()
+	start := time.Now()
 	_, err := dbm.DBAccess.Delete(&m)
+	monitor.ObserveSince(monitor.MetaDBOperationDuration.WithLabelValues("delete"), start)
 	if err != nil {
 		klog.Errorf("[imitator] delete error: %v", err)
 	}
@@ -187,6 +199,130 @@ func (s *imitator) List(ctx context.Context, key string) (Resp, error) {
 	return resp, nil
 }
 
+// ListWithOptions pages through the objects of key in batches, so that at most
+// one batch of unmatched objects is held in memory at a time
+func (s *imitator) ListWithOptions(ctx context.Context, key string, opts ListOptions) (Resp, error) {
+	gvr, ns, name := metaserver.ParseKey(key)
+	// push the exact field requirements down to the sql query
+	if opts.Field != nil {
+		if v, ok := opts.Field.RequiresExactMatch("metadata.namespace"); ok && ns == "" {
+			ns = v
+		}
+		if v, ok := opts.Field.RequiresExactMatch("metadata.name"); ok && name == "" {
+			name = v
+		}
+	}
+
+	// push the equality label requirements down to the sql query as a coarse prefilter only,
+	// the objects are always matched by matchMeta since the terms may also be found out of the labels
+	terms := labelTerms(opts.Label)
+
+	batchSize := int64(listBatchSize)
+	if opts.Limit > 0 && opts.Limit < batchSize {
+		// fetch one more object to know whether there are more
+		batchSize = opts.Limit + 1
+	}
+
+	var resp Resp
+	kvs := make([]v2.MetaV2, 0)
+	startKey := opts.StartKey
+	for {
+		s.lock.RLock()
+		results, err := v2.RawMetaPageByGVRNN(gvr, ns, name, startKey, batchSize, terms...)
+		resp.Revision = s.revision
+		s.lock.RUnlock()
+		if err != nil {
+			return Resp{}, err
+		}
+
+		for _, m := range *results {
+			startKey = m.Key
+			matched, err := matchMeta(&m, opts)
+			if err != nil {
+				klog.Errorf("failed to match obj %v: %v", m.Key, err)
+				continue
+			}
+			if !matched {
+				continue
+			}
+			if opts.Limit > 0 && int64(len(kvs)) == opts.Limit {
+				resp.More = true
+				resp.Kvs = &kvs
+				return resp, nil
+			}
+			kvs = append(kvs, m)
+		}
+
+		if int64(len(*results)) < batchSize {
+			break
+		}
+	}
+	resp.Kvs = &kvs
+	return resp, nil
+}
+
+// labelTerms returns the terms that the json of the objects matching the equality requirements
+// of the label selector must contain. The terms are the quoted label keys and values, which are
+// found whatever the json is formatted as, since neither of them contains any character that may
+// be escaped in json, except for the `/` of the key prefix, so only the name of such a key is kept
+func labelTerms(selector labels.Selector) []string {
+	if selector == nil {
+		return nil
+	}
+	requirements, selectable := selector.Requirements()
+	if !selectable {
+		return nil
+	}
+	var terms []string
+	for _, r := range requirements {
+		switch r.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+		default:
+			continue
+		}
+		values := r.Values()
+		if values.Len() != 1 {
+			continue
+		}
+		key := `"` + r.Key() + `"`
+		if i := strings.LastIndex(r.Key(), "/"); i >= 0 {
+			key = r.Key()[i+1:] + `"`
+		}
+		terms = append(terms, key, `"`+values.List()[0]+`"`)
+	}
+	return terms
+}
+
+// matchMeta checks whether the stored obj matches the label and field selector of opts,
+// only the metadata of the obj is decoded
+func matchMeta(m *v2.MetaV2, opts ListOptions) (bool, error) {
+	if (opts.Label == nil || opts.Label.Empty()) && (opts.Field == nil || opts.Field.Empty()) {
+		return true, nil
+	}
+	var obj struct {
+		Metadata struct {
+			Name      string            `json:"name"`
+			Namespace string            `json:"namespace"`
+			Labels    map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(m.Value), &obj); err != nil {
+		return false, err
+	}
+	if opts.Label != nil && !opts.Label.Matches(labels.Set(obj.Metadata.Labels)) {
+		return false, nil
+	}
+	// only support metadata.name & metadata.namespace
+	fieldSet := fields.Set{
+		"metadata.name":      obj.Metadata.Name,
+		"metadata.namespace": obj.Metadata.Namespace,
+	}
+	if opts.Field != nil && !opts.Field.Matches(fieldSet) {
+		return false, nil
+	}
+	return true, nil
+}
+
 func (s *imitator) GetRevision() uint64 {
 	return s.revision
 }
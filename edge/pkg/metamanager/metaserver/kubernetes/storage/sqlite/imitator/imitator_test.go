@@ -0,0 +1,93 @@
+package imitator
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+)
+
+func TestMatchMeta(t *testing.T) {
+	m := &v2.MetaV2{
+		Key:   "/core/v1/pods/default/pod1",
+		Value: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod1","namespace":"default","labels":{"app":"nginx"}}}`,
+	}
+	tests := []struct {
+		name string
+		opts ListOptions
+		want bool
+	}{
+		{
+			name: "empty selectors match everything",
+			opts: ListOptions{},
+			want: true,
+		}, {
+			name: "label selector matches",
+			opts: ListOptions{Label: labels.SelectorFromSet(labels.Set{"app": "nginx"})},
+			want: true,
+		}, {
+			name: "label selector does not match",
+			opts: ListOptions{Label: labels.SelectorFromSet(labels.Set{"app": "redis"})},
+			want: false,
+		}, {
+			name: "field selector matches",
+			opts: ListOptions{Field: fields.OneTermEqualSelector("metadata.name", "pod1")},
+			want: true,
+		}, {
+			name: "field selector does not match",
+			opts: ListOptions{Field: fields.OneTermNotEqualSelector("metadata.namespace", "default")},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchMeta(m, tt.opts)
+			if err != nil {
+				t.Fatalf("matchMeta() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("matchMeta() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelTerms(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     []string
+	}{
+		{
+			name:     "empty selector",
+			selector: "",
+			want:     nil,
+		}, {
+			name:     "equality requirements",
+			selector: "app=nginx,kubernetes.io/os==linux",
+			want:     []string{`"app"`, `"nginx"`, `os"`, `"linux"`},
+		}, {
+			name:     "in with one value",
+			selector: "tier in (gateway)",
+			want:     []string{`"tier"`, `"gateway"`},
+		}, {
+			name:     "other requirements are not pushed down",
+			selector: "app!=nginx,tier in (gateway,camera),zone",
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := labels.Parse(tt.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := labelTerms(selector); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("labelTerms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/conversion"
-	"k8s.io/apimachinery/pkg/fields"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/storage"
@@ -77,12 +77,28 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 		return fmt.Errorf("need ptr to slice: %v", err)
 	}
 
-	resp, err := s.client.List(context.TODO(), key)
+	keyPrefix := continueKeyPrefix(key)
+	var startKey string
+	if len(opts.Predicate.Continue) > 0 {
+		// the resource version in the continue token is ignored, because meta_v2
+		// keeps no history and the list always returns the latest objects
+		startKey, _, err = storage.DecodeContinue(opts.Predicate.Continue, keyPrefix)
+		if err != nil {
+			return apierrors.NewBadRequest(fmt.Sprintf("invalid continue token: %v", err))
+		}
+	}
 
-	if err != nil || len(*resp.Kvs) == 0 {
+	resp, err := s.client.ListWithOptions(context.TODO(), key, imitator.ListOptions{
+		Label:    opts.Predicate.Label,
+		Field:    opts.Predicate.Field,
+		StartKey: startKey,
+		Limit:    opts.Predicate.Limit,
+	})
+	if err != nil {
 		klog.Error(err)
 		return err
 	}
+
 	unstrList := listObj.(*unstructured.UnstructuredList)
 	for _, v := range *resp.Kvs {
 		var unstrObj unstructured.Unstructured
@@ -90,22 +106,21 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 		if err != nil {
 			return err
 		}
+		unstrList.Items = append(unstrList.Items, unstrObj)
+	}
 
-		labelSet := labels.Set(unstrObj.GetLabels())
-		if !opts.Predicate.Label.Matches(labelSet) {
-			continue
-		}
-
-		// only support metadata.name & metadata.namespace
-		fieldSet := fields.Set{
-			"metadata.name":      unstrObj.GetName(),
-			"metadata.namespace": unstrObj.GetNamespace(),
+	if resp.More && len(*resp.Kvs) > 0 {
+		// continue token requires a non-zero resource version
+		rv := int64(resp.Revision)
+		if rv == 0 {
+			rv = 1
 		}
-		if !opts.Predicate.Field.Matches(fieldSet) {
-			continue
+		lastKey := (*resp.Kvs)[len(*resp.Kvs)-1].Key
+		next, err := storage.EncodeContinue(lastKey, keyPrefix, rv)
+		if err != nil {
+			return err
 		}
-
-		unstrList.Items = append(unstrList.Items, unstrObj)
+		unstrList.SetContinue(next)
 	}
 	rv := strconv.FormatUint(resp.Revision, 10)
 	unstrList.SetResourceVersion(rv)
@@ -115,6 +130,16 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 	return nil
 }
 
+// continueKeyPrefix returns the /{group}/{version}/{resource}/ part of key, which is
+// shared by all the objects of the resource and trimmed from the continue token
+func continueKeyPrefix(key string) string {
+	slices := strings.SplitN(strings.TrimPrefix(key, "/"), "/", 4)
+	if len(slices) < 3 {
+		return key
+	}
+	return "/" + strings.Join(slices[:3], "/") + "/"
+}
+
 func (s *store) GuaranteedUpdate(ctx context.Context, key string, ptrToType runtime.Object, ignoreNotFound bool, precondtions *storage.Preconditions, tryUpdate storage.UpdateFunc, cachedExistingObject runtime.Object) error {
 	panic("Do not call this function")
 }
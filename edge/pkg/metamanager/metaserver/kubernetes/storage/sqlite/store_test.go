@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/storage"
+
+	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
+	fakeclient "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator/fake"
+)
+
+func TestContinueKeyPrefix(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "/core/v1/pods/default/null", want: "/core/v1/pods/"},
+		{key: "/core/v1/pods/null/null", want: "/core/v1/pods/"},
+		{key: "/apps/v1/deployments", want: "/apps/v1/deployments/"},
+		{key: "/core", want: "/core"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := continueKeyPrefix(tt.key); got != tt.want {
+				t.Errorf("continueKeyPrefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetListPagination(t *testing.T) {
+	objs := []v2.MetaV2{
+		newPodMeta("default", "pod1"),
+		newPodMeta("default", "pod2"),
+		newPodMeta("default", "pod3"),
+	}
+	var gotOpts imitator.ListOptions
+	client := fakeclient.Client{
+		ListWithOptionsF: func(ctx context.Context, key string, opts imitator.ListOptions) (imitator.Resp, error) {
+			gotOpts = opts
+			var kvs []v2.MetaV2
+			for _, obj := range objs {
+				if obj.Key <= opts.StartKey {
+					continue
+				}
+				kvs = append(kvs, obj)
+			}
+			more := false
+			if opts.Limit > 0 && int64(len(kvs)) > opts.Limit {
+				kvs = kvs[:opts.Limit]
+				more = true
+			}
+			return imitator.Resp{Kvs: &kvs, Revision: 10, More: more}, nil
+		},
+	}
+	s := &store{client: client, codec: unstructured.UnstructuredJSONScheme, versioner: imitator.Versioner}
+	key := "/core/v1/pods/default/null"
+
+	var names []string
+	var continueToken string
+	for i := 0; i < len(objs); i++ {
+		list := &unstructured.UnstructuredList{}
+		pred := storage.SelectionPredicate{Label: labels.Everything(), Field: fields.Everything(), Limit: 2, Continue: continueToken}
+		if err := s.GetList(context.TODO(), key, storage.ListOptions{Predicate: pred, Recursive: true}, list); err != nil {
+			t.Fatalf("GetList() error = %v", err)
+		}
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		if list.GetResourceVersion() != "10" {
+			t.Errorf("GetList() resourceVersion = %v, want 10", list.GetResourceVersion())
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	if fmt.Sprint(names) != "[pod1 pod2 pod3]" {
+		t.Errorf("GetList() got items %v, want [pod1 pod2 pod3]", names)
+	}
+	if gotOpts.StartKey != objs[1].Key {
+		t.Errorf("GetList() start key of the last page = %v, want %v", gotOpts.StartKey, objs[1].Key)
+	}
+
+	list := &unstructured.UnstructuredList{}
+	pred := storage.SelectionPredicate{Label: labels.Everything(), Field: fields.Everything(), Continue: "invalid"}
+	if err := s.GetList(context.TODO(), key, storage.ListOptions{Predicate: pred}, list); err == nil {
+		t.Errorf("GetList() expected error for invalid continue token")
+	}
+}
+
+func newPodMeta(namespace, name string) v2.MetaV2 {
+	return v2.MetaV2{
+		Key:   fmt.Sprintf("/core/v1/pods/%s/%s", namespace, name),
+		Name:  name,
+		Value: fmt.Sprintf(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"%s","namespace":"%s"}}`, name, namespace),
+	}
+}
@@ -24,15 +24,20 @@ package sqlite
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/storage"
 	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/kubeedge/edge/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/agent"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
@@ -46,6 +51,9 @@ const (
 	outgoingBufSize = 100
 )
 
+// bookmarkInterval is the interval of sending bookmark events to the watchers which allow them
+var bookmarkInterval = time.Minute
+
 type watcher struct {
 	client imitator.Client
 	codec  runtime.Codec
@@ -88,9 +96,11 @@ func (wc *watchChan) Receive(e watch.Event) error {
 // If recursive is true, it watches any children and directories under the key, excluding the root key itself.
 // pred must be non-nil. Only if pred matches the change, it will be returned.
 func (w *watcher) Watch(ctx context.Context, key string, rev int64, recursive bool, pred storage.SelectionPredicate) (watch.Interface, error) {
-	// TODO: support rev != 0
-	if rev != 0 {
-		klog.Warningf("base storage now only support rev == 0, but get rev == %v, force set to 0!", rev)
+	// meta_v2 keeps no history, so watch can only be resumed from a revision which is not
+	// older than the storage revision, e.g. the one from the latest list or bookmark.
+	// Otherwise, the existing objects are resent.
+	if rev != 0 && uint64(rev) < w.client.GetRevision() {
+		klog.Warningf("revision %v is older than the storage revision %v, resend the existing objects", rev, w.client.GetRevision())
 		rev = 0
 	}
 	wc := w.createWatchChan(ctx, key, rev, recursive, pred)
@@ -113,17 +123,23 @@ func (w *watcher) createWatchChan(ctx context.Context, key string, rev int64, re
 	if pred.Empty() {
 		// The filter doesn't filter out any object.
 		wc.internalPred = storage.Everything
+		wc.internalPred.AllowWatchBookmarks = pred.AllowWatchBookmarks
 	}
 	wc.ctx, wc.cancel = context.WithCancel(ctx)
 	return wc
 }
 func (wc *watchChan) run() {
+	monitor.MetaServerWatches.Inc()
+	defer monitor.MetaServerWatches.Dec()
+
+	// initialRev is changed by the sync of startWatching
+	initialRev := wc.initialRev
 	watchClosedCh := make(chan struct{})
 	go wc.startWatching(watchClosedCh)
 
 	var resultChanWG sync.WaitGroup
 	resultChanWG.Add(1)
-	go wc.processEvent(&resultChanWG)
+	go wc.processEvent(&resultChanWG, initialRev)
 
 	select {
 	case err := <-wc.errChan:
@@ -197,6 +213,8 @@ func (wc *watchChan) sync() error {
 		}
 		wc.initialRev = int64(resp.Revision)
 	}
+	// the existing objects are up to date at the revision of the list
+	wc.sendEvent(wc.bookmarkEvent(wc.initialRev))
 	klog.Infof("get storage revision:%v", wc.initialRev)
 	return nil
 }
@@ -244,12 +262,37 @@ func (wc *watchChan) startWatching(watchClosedCh chan struct{}) {
 }
 
 // processEvent processes events from etcd watcher and sends results to resultChan.
-func (wc *watchChan) processEvent(wg *sync.WaitGroup) {
+// The bookmarks are sent with the revision of the last event processed by the watch, rather than
+// the storage revision, which may be ahead of the events not sent to the watch yet.
+func (wc *watchChan) processEvent(wg *sync.WaitGroup, lastRev int64) {
 	defer wg.Done()
 
+	var bookmarkCh <-chan time.Time
+	if wc.internalPred.AllowWatchBookmarks {
+		ticker := time.NewTicker(bookmarkInterval)
+		defer ticker.Stop()
+		bookmarkCh = ticker.C
+	}
+
 	for {
 		select {
+		case <-bookmarkCh:
+			if lastRev == 0 {
+				continue
+			}
+			select {
+			case wc.resultChan <- *wc.bookmarkEvent(lastRev):
+			case <-wc.ctx.Done():
+				return
+			}
 		case e := <-wc.incomingEventChan:
+			if rev := eventRevision(e); rev > lastRev {
+				lastRev = rev
+			}
+			if e.Type == watch.Bookmark {
+				// the revision of the sync, which is not sent to the user
+				continue
+			}
 			var res = e
 			key, err := metaserver.KeyFuncObj(e.Object)
 			if err != nil {
@@ -300,6 +343,28 @@ func (wc *watchChan) processEvent(wg *sync.WaitGroup) {
 	}
 }
 
+// bookmarkEvent returns the bookmark event of the watched resource at rev
+func (wc *watchChan) bookmarkEvent(rev int64) *watch.Event {
+	gvr, _, _ := metaserver.ParseKey(wc.key)
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvr.GroupVersion().WithKind(util.UnsafeResourceToKind(gvr.Resource)))
+	obj.SetResourceVersion(strconv.FormatInt(rev, 10))
+	return &watch.Event{Type: watch.Bookmark, Object: obj}
+}
+
+// eventRevision returns the resource version of the object of the event, or 0 if it is unknown
+func eventRevision(e *watch.Event) int64 {
+	accessor, err := meta.Accessor(e.Object)
+	if err != nil {
+		return 0
+	}
+	rev, err := strconv.ParseInt(accessor.GetResourceVersion(), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return rev
+}
+
 func (wc *watchChan) filter(obj runtime.Object) bool {
 	if wc.internalPred.Empty() {
 		return true
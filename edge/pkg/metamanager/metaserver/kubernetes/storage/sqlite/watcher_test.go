@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+
+	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
+	fakeclient "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator/fake"
+)
+
+func newPod(name, rv string) runtime.Object {
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace("default")
+	pod.SetName(name)
+	pod.SetResourceVersion(rv)
+	return pod
+}
+
+func TestWatchBookmarks(t *testing.T) {
+	oldInterval := bookmarkInterval
+	bookmarkInterval = 10 * time.Millisecond
+	defer func() { bookmarkInterval = oldInterval }()
+
+	events := make(chan watch.Event)
+	client := fakeclient.Client{
+		ListF: func(ctx context.Context, key string) (imitator.Resp, error) {
+			kvs := []v2.MetaV2{{
+				Key:   "/core/v1/pods/default/pod1",
+				Value: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod1","namespace":"default","resourceVersion":"3"}}`,
+			}}
+			return imitator.Resp{Kvs: &kvs, Revision: 5}, nil
+		},
+		WatchF: func(ctx context.Context, key string, rv uint64) <-chan watch.Event {
+			return events
+		},
+		// the storage revision is ahead of the events of the watch
+		GetRevisionF: func() uint64 { return 100 },
+	}
+	w := newWatcher(client, unstructured.UnstructuredJSONScheme)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	pred := storage.SelectionPredicate{Label: labels.Everything(), Field: fields.Everything(), AllowWatchBookmarks: true}
+	wi, err := w.Watch(ctx, "/core/v1/pods/null/null", 0, true, pred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wi.Stop()
+
+	// next returns the next event, and the revision of the bookmarks before it
+	next := func() (watch.Event, string) {
+		var bookmarkRev string
+		for {
+			select {
+			case e := <-wi.ResultChan():
+				if e.Type != watch.Bookmark {
+					return e, bookmarkRev
+				}
+				bookmarkRev = e.Object.(*unstructured.Unstructured).GetResourceVersion()
+				if bookmarkRev == "7" {
+					return e, bookmarkRev
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for the watch event")
+			}
+		}
+	}
+
+	if e, _ := next(); e.Type != watch.Added {
+		t.Fatalf("expected the existing pod added, but got %v", e.Type)
+	}
+	events <- watch.Event{Type: watch.Modified, Object: newPod("pod1", "7")}
+	e, rev := next()
+	if e.Type != watch.Modified {
+		t.Fatalf("expected the pod modified, but got %v", e.Type)
+	}
+	// the bookmarks before the event carry the revision of the list rather than the storage revision
+	if rev != "" && rev != "5" {
+		t.Errorf("expected the bookmark at revision 5 before the event, but got %s", rev)
+	}
+	if _, rev = next(); rev != "7" {
+		t.Errorf("expected the bookmark at revision 7 after the event, but got %s", rev)
+	}
+}
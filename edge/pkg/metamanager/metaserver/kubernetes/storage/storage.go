@@ -116,12 +116,12 @@ func (r *REST) Get(ctx context.Context, name string, options *metav1.GetOptions)
 
 // PassThrough
 // The request is routed to the dynamic controller via the metaServer.
-// If the request is approved, the response will be saved to local storage.
+// If a get request is approved, the response will be saved to local storage.
 // It will be acquired from local data storage if it fails.
-func (r *REST) PassThrough(ctx context.Context, options *metav1.GetOptions) ([]byte, error) {
+func (r *REST) PassThrough(ctx context.Context, options *metav1.GetOptions, obj runtime.Object) ([]byte, error) {
 	info, _ := apirequest.RequestInfoFrom(ctx)
 	resp, err := func() ([]byte, error) {
-		app, err := r.Agent.Generate(ctx, metaserver.ApplicationVerb(info.Verb), *options, nil)
+		app, err := r.Agent.Generate(ctx, metaserver.ApplicationVerb(info.Verb), *options, obj)
 		if err != nil {
 			klog.Errorf("[metaserver/passThrough] failed to generate application: %v", err)
 			return nil, err
@@ -133,13 +133,20 @@ func (r *REST) PassThrough(ctx context.Context, options *metav1.GetOptions) ([]b
 			return nil, err
 		}
 
+		if info.Verb != string(metaserver.Get) {
+			return app.RespBody, nil
+		}
 		err = imitator.DefaultV2Client.InsertOrUpdatePassThroughObj(context.TODO(), app.RespBody, app.Key)
 		if err != nil {
-			klog.Warningf("[metaserver/passThrough] failed to insert version information into database: %v", err)
+			klog.Warningf("[metaserver/passThrough] failed to insert %v into database: %v", app.Key, err)
 		}
 		return app.RespBody, nil
 	}()
 	if err != nil {
+		// only the get requests are cached, others can not be answered offline
+		if info.Verb != string(metaserver.Get) {
+			return nil, err
+		}
 		resp, err = imitator.DefaultV2Client.GetPassThroughObj(ctx, info.Path)
 		if err != nil {
 			klog.Errorf("[metaserver/reststorage] failed to get req at local: %v", err)
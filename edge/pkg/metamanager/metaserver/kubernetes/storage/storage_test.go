@@ -115,7 +115,7 @@ func TestREST_PassThrough(t *testing.T) {
 				Agent: &agent.Agent{Applications: sync.Map{}},
 			}
 			cases = tt.cases
-			got, err := rest.PassThrough(ctx, &metav1.GetOptions{})
+			got, err := rest.PassThrough(ctx, &metav1.GetOptions{}, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PassThrough() error = %v, wantErr %v", err, tt.wantErr)
 				return
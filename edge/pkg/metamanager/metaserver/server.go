@@ -16,8 +16,10 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilwaitgroup "k8s.io/apimachinery/pkg/util/waitgroup"
+	"k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	"k8s.io/apiserver/pkg/authentication/request/union"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
@@ -52,6 +54,7 @@ type MetaServer struct {
 	NegotiatedSerializer  runtime.NegotiatedSerializer
 	Factory               *handlerfactory.Factory
 	Auth                  *metaServerAuth
+	Audit                 *metaServerAudit
 }
 
 type metaServerAuth struct {
@@ -75,10 +78,15 @@ func buildAuth() *metaServerAuth {
 		}
 		allPublicKeys = append(allPublicKeys, publicKeys...)
 	}
+	validator := auth.NewValidator(client.NewGetterFromClient(kubeclientbridge.NewSimpleClientset(client.New())))
 	tokenAuthenticator := auth.JWTTokenAuthenticator(nil,
-		metaserverconfig.Config.ServiceAccountIssuers, allPublicKeys, metaserverconfig.Config.APIAudiences,
-		auth.NewValidator(client.NewGetterFromClient(kubeclientbridge.NewSimpleClientset(client.New()))))
-	newAuthenticator := bearertoken.New(tokenAuthenticator)
+		metaserverconfig.Config.ServiceAccountIssuers, allPublicKeys, metaserverconfig.Config.APIAudiences, validator)
+	var newAuthenticator authenticator.Request = bearertoken.New(tokenAuthenticator)
+	if issuerConfig := metaserverconfig.Config.OfflineTokenIssuer; issuerConfig != nil && issuerConfig.Enable {
+		// accept both the tokens from cloud and the tokens minted on the node
+		newAuthenticator = union.New(newAuthenticator,
+			bearertoken.New(auth.OfflineTokenAuthenticator(metaserverconfig.Config.APIAudiences, validator)))
+	}
 	return &metaServerAuth{newAuthenticator, newAuthorizer}
 }
 
@@ -89,6 +97,7 @@ func NewMetaServer() *MetaServer {
 		NegotiatedSerializer:  serializer.NewNegotiatedSerializer(),
 		Factory:               handlerfactory.NewFactory(),
 		Auth:                  buildAuth(),
+		Audit:                 buildAudit(),
 	}
 	return &ls
 }
@@ -202,6 +211,11 @@ func (ls *MetaServer) startHTTPSServer(stopChan <-chan struct{}) {
 }
 
 func (ls *MetaServer) Start(stopChan <-chan struct{}) {
+	if ls.Audit != nil {
+		if err := ls.Audit.Backend.Run(stopChan); err != nil {
+			klog.Errorf("Failed to run audit backend of metaserver: %v", err)
+		}
+	}
 	if kefeatures.DefaultFeatureGate.Enabled(kefeatures.RequireAuthorization) {
 		ls.startHTTPSServer(stopChan)
 	} else {
@@ -221,7 +235,16 @@ func (ls *MetaServer) BuildBasicHandler() http.Handler {
 			return
 		}
 
-		if reqInfo.IsResourceRequest {
+		if handlerfactory.IsSelfSubjectAccessReview(reqInfo) {
+			var a authorizer.Authorizer
+			if kefeatures.DefaultFeatureGate.Enabled(kefeatures.RequireAuthorization) {
+				a = ls.Auth.Authorizer
+			}
+			ls.Factory.SelfSubjectAccessReview(a).ServeHTTP(w, req)
+			return
+		}
+
+		if reqInfo.IsResourceRequest && !passthrough.IsPassThroughResourcePath(reqInfo.Path, reqInfo.Verb) {
 			switch {
 			case reqInfo.Verb == "get":
 				ls.Factory.Get().ServeHTTP(w, req)
@@ -256,13 +279,29 @@ func BuildHandlerChain(handler http.Handler, ls *MetaServer) http.Handler {
 	cfg := &server.Config{
 		LegacyAPIGroupPrefixes: sets.NewString(server.DefaultLegacyAPIPrefix),
 	}
+	var auditBackend audit.Backend
+	var auditPolicy audit.PolicyRuleEvaluator
+	if ls.Audit != nil {
+		auditBackend, auditPolicy = ls.Audit.Backend, ls.Audit.Policy
+	}
 	if kefeatures.DefaultFeatureGate.Enabled(kefeatures.RequireAuthorization) {
 		handler = genericapifilters.WithAuthorization(handler, ls.Auth.Authorizer, legacyscheme.Codecs)
+		handler = genericapifilters.WithAudit(handler, auditBackend, auditPolicy, ls.LongRunningFunc)
 		failedHandler := genericapifilters.Unauthorized(legacyscheme.Codecs)
+		failedHandler = genericapifilters.WithFailedAuthenticationAudit(failedHandler, auditBackend, auditPolicy)
 		handler = genericapifilters.WithAuthentication(handler, ls.Auth.Authenticator, failedHandler, metaserverconfig.Config.APIAudiences, nil)
+	} else {
+		handler = genericapifilters.WithAudit(handler, auditBackend, auditPolicy, ls.LongRunningFunc)
+	}
+	if ls.Audit != nil {
+		handler = genericapifilters.WithAuditInit(handler)
 	}
 	handler = genericfilters.WithWaitGroup(handler, ls.LongRunningFunc, ls.HandlerChainWaitGroup)
 	handler = genericapifilters.WithRequestInfo(handler, server.NewRequestInfoResolver(cfg))
+	if ls.Audit != nil {
+		// the latency of requests in audit events starts from the time received
+		handler = genericapifilters.WithRequestReceivedTimestamp(handler)
+	}
 	handler = genericfilters.WithPanicRecovery(handler, &apirequest.RequestInfoFactory{})
 	return handler
 }
@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offlinetoken
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	edgehubcertutil "github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/certutil"
+	edgehubhttp "github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/http"
+	edgehubconfig "github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
+	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
+)
+
+// syncPeriod is the period to check the delegation certificate, it is renewed from
+// cloudcore when it is missing or 80% of its lifetime has passed
+const syncPeriod = time.Minute
+
+// Start loads the issuer key and its delegation certificate, and keeps the delegation
+// certificate renewed until stopCh is closed. It does nothing if the issuer is not enabled.
+func Start(stopCh <-chan struct{}) {
+	config := metaserverconfig.Config.OfflineTokenIssuer
+	if config == nil || !config.Enable {
+		return
+	}
+	key, err := loadOrGenerateKey(config.KeyFile)
+	if err != nil {
+		klog.Errorf("failed to load offline token issuer key: %v", err)
+		return
+	}
+	go wait.Until(func() {
+		if err := syncIssuer(key); err != nil {
+			klog.Warningf("failed to sync offline token issuer: %v", err)
+		}
+	}, syncPeriod, stopCh)
+}
+
+// syncIssuer loads the delegation certificate from the local file so that tokens can be minted
+// while the node is disconnected, and applies for a new one if it is missing or needs renewal.
+func syncIssuer(key *ecdsa.PrivateKey) error {
+	config := metaserverconfig.Config.OfflineTokenIssuer
+	nodeName := metaserverconfig.Config.NodeName
+	caPEM, err := os.ReadFile(edgehubconfig.Config.TLSCAFile)
+	if err != nil {
+		return fmt.Errorf("failed to read ca: %v", err)
+	}
+
+	cert, err := loadDelegationCert(config.CertFile, caPEM, nodeName, key)
+	if err != nil {
+		klog.V(4).Infof("delegation certificate of offline token issuer is not usable: %v", err)
+	}
+	if cert == nil || requiresRenewal(cert, time.Now()) {
+		newCert, reqErr := requestDelegationCert(caPEM, nodeName, key)
+		if reqErr == nil {
+			reqErr = verifyDelegationCert(newCert, caPEM, nodeName, key)
+		}
+		if reqErr == nil {
+			reqErr = edgehubcertutil.WriteCert(config.CertFile, newCert)
+		}
+		if reqErr != nil && cert == nil {
+			return reqErr
+		}
+		if reqErr != nil {
+			klog.Warningf("failed to renew delegation certificate of offline token issuer: %v", reqErr)
+		} else {
+			klog.Infof("delegation certificate of offline token issuer is renewed, expires at %v", newCert.NotAfter)
+			cert = newCert
+		}
+	}
+
+	if issuer := Get(); issuer != nil && issuer.notAfter.Equal(cert.NotAfter) {
+		return nil
+	}
+	issuer, err := NewIssuer(nodeName, key, cert, config.MaxExpirationSeconds, metaserverconfig.Config.APIAudiences)
+	if err != nil {
+		return err
+	}
+	set(issuer)
+	return nil
+}
+
+// loadOrGenerateKey loads the ECDSA key from path, a new key is generated and stored if it does not exist
+func loadOrGenerateKey(path string) (*ecdsa.PrivateKey, error) {
+	if _, err := os.Stat(path); err == nil {
+		key, err := keyutil.PrivateKeyFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("key %s is %T, only ECDSA keys are supported", path, key)
+		}
+		return ecKey, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err = edgehubcertutil.WriteKey(path, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// loadDelegationCert loads the delegation certificate from path, it returns nil if the
+// certificate does not exist or is not valid for the key
+func loadDelegationCert(path string, caPEM []byte, nodeName string, key *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	certs, err := certutil.CertsFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = verifyDelegationCert(certs[0], caPEM, nodeName, key); err != nil {
+		return nil, err
+	}
+	return certs[0], nil
+}
+
+// verifyDelegationCert checks the certificate is signed by the CA for the key of the node,
+// with the code signing usage that the delegation certificates are signed with
+func verifyDelegationCert(cert *x509.Certificate, caPEM []byte, nodeName string, key *ecdsa.PrivateKey) error {
+	if cert.Subject.CommonName != IssuerName(nodeName) {
+		return fmt.Errorf("common name %q of delegation certificate is not %q", cert.Subject.CommonName, IssuerName(nodeName))
+	}
+	publicKey, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok || !publicKey.Equal(key.Public()) {
+		return fmt.Errorf("delegation certificate does not match the issuer key")
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("failed to parse ca")
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return fmt.Errorf("failed to verify delegation certificate: %v", err)
+	}
+	return nil
+}
+
+// requiresRenewal returns true if 80% of the lifetime of the certificate has passed
+func requiresRenewal(cert *x509.Certificate, now time.Time) bool {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotBefore.Add(lifetime * 8 / 10))
+}
+
+// requestDelegationCert applies for the delegation certificate of the key from cloudcore
+// with the edge certificate
+func requestDelegationCert(caPEM []byte, nodeName string, key *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	config := edgehubconfig.Config
+	edgeCert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSPrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load edge certificate: %v", err)
+	}
+	client, err := edgehubhttp.NewHTTPClientWithCA(caPEM, edgeCert)
+	if err != nil {
+		return nil, err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: IssuerName(nodeName)},
+	}, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CSR: %v", err)
+	}
+	req, err := edgehubhttp.BuildRequest(http.MethodPost, config.HTTPServer+constants.DefaultOfflineTokenIssuerURL,
+		bytes.NewReader(csr), "", nodeName)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := edgehubhttp.SendRequest(req, client)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, constants.MaxRespBodyLength))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cloudcore responds %d: %s", resp.StatusCode, body)
+	}
+	return x509.ParseCertificate(body)
+}
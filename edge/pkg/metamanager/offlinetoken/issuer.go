@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package offlinetoken mints ServiceAccount tokens on the edge node when they cannot be
+// got from the cloud. The tokens are signed by a per-node key delegated by cloudcore,
+// and they are only accepted by MetaServer of the node.
+package offlinetoken
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/serviceaccount"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+// defaultExpirationSeconds is used when the TokenRequest does not specify the expiration,
+// the same as the default of TokenRequest in kube-apiserver
+const defaultExpirationSeconds int64 = 3600
+
+var (
+	mutex   sync.RWMutex
+	current *Issuer
+)
+
+// Issuer mints ServiceAccount tokens with the key delegated by cloudcore
+type Issuer struct {
+	name                 string
+	publicKey            crypto.PublicKey
+	notAfter             time.Time
+	generator            serviceaccount.TokenGenerator
+	maxExpirationSeconds int64
+	audiences            []string
+}
+
+// IssuerName returns the issuer of the tokens minted on the node, which is also
+// the common name of the delegation certificate
+func IssuerName(nodeName string) string {
+	return constants.OfflineTokenIssuerPrefix + nodeName
+}
+
+// NewIssuer creates the issuer of the node with the key and its delegation certificate,
+// audiences are used when the TokenRequest does not specify audiences
+func NewIssuer(nodeName string, key *ecdsa.PrivateKey, cert *x509.Certificate,
+	maxExpirationSeconds int64, audiences []string) (*Issuer, error) {
+	name := IssuerName(nodeName)
+	generator, err := serviceaccount.JWTTokenGenerator(name, key)
+	if err != nil {
+		return nil, err
+	}
+	if maxExpirationSeconds <= 0 {
+		maxExpirationSeconds = constants.DefaultOfflineTokenMaxExpirationSeconds
+	}
+	return &Issuer{
+		name:                 name,
+		publicKey:            key.Public(),
+		notAfter:             cert.NotAfter,
+		generator:            generator,
+		maxExpirationSeconds: maxExpirationSeconds,
+		audiences:            audiences,
+	}, nil
+}
+
+// Get returns the issuer of the node, it is nil if the offline token issuer is not
+// enabled or the delegation certificate is not ready
+func Get() *Issuer {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+func set(issuer *Issuer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = issuer
+}
+
+// Name returns the issuer name, which is the iss claim of the tokens
+func (i *Issuer) Name() string {
+	return i.name
+}
+
+// PublicKey returns the public key to verify the tokens
+func (i *Issuer) PublicKey() crypto.PublicKey {
+	return i.publicKey
+}
+
+// Mint mints the token of the ServiceAccount for the TokenRequest. The expiration is capped
+// to the max expiration of the issuer and the expiration of the delegation certificate.
+func (i *Issuer) Mint(sa *corev1.ServiceAccount, tr *authenticationv1.TokenRequest) (*authenticationv1.TokenRequest, error) {
+	audiences := tr.Spec.Audiences
+	if len(audiences) == 0 {
+		audiences = i.audiences
+	}
+	if len(audiences) == 0 {
+		return nil, fmt.Errorf("audiences of the token are not specified")
+	}
+
+	expirationSeconds := defaultExpirationSeconds
+	if tr.Spec.ExpirationSeconds != nil {
+		expirationSeconds = *tr.Spec.ExpirationSeconds
+	}
+	if expirationSeconds > i.maxExpirationSeconds {
+		expirationSeconds = i.maxExpirationSeconds
+	}
+	if remaining := int64(time.Until(i.notAfter).Seconds()); expirationSeconds > remaining {
+		expirationSeconds = remaining
+	}
+	if expirationSeconds <= 0 {
+		return nil, fmt.Errorf("delegation certificate of %s has expired", i.name)
+	}
+
+	var pod *core.Pod
+	var secret *core.Secret
+	if ref := tr.Spec.BoundObjectRef; ref != nil {
+		switch ref.Kind {
+		case "Pod":
+			pod = &core.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: sa.Namespace, Name: ref.Name, UID: ref.UID}}
+		case "Secret":
+			secret = &core.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: sa.Namespace, Name: ref.Name, UID: ref.UID}}
+		default:
+			return nil, fmt.Errorf("cannot bind token to object of kind %s", ref.Kind)
+		}
+	}
+
+	coreSA := core.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: sa.Namespace, Name: sa.Name, UID: sa.UID}}
+	public, private := serviceaccount.Claims(coreSA, pod, secret, expirationSeconds, 0, audiences)
+	token, err := i.generator.GenerateToken(public, private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %v", err)
+	}
+
+	out := tr.DeepCopy()
+	out.Spec.Audiences = audiences
+	out.Spec.ExpirationSeconds = &expirationSeconds
+	out.Status = authenticationv1.TokenRequestStatus{
+		Token:               token,
+		ExpirationTimestamp: metav1.Time{Time: public.Expiry.Time()},
+	}
+	return out, nil
+}
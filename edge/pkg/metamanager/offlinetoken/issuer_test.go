@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offlinetoken
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2/jwt"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "KubeEdge"}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: cert.Raw}),
+	}
+}
+
+func (ca *testCA) sign(t *testing.T, cn string, key *ecdsa.PrivateKey, usage x509.ExtKeyUsage, notBefore, notAfter time.Time) *x509.Certificate {
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestVerifyDelegationCert(t *testing.T) {
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	now := time.Now()
+	cn := IssuerName("edge-1")
+
+	tests := []struct {
+		name    string
+		cert    *x509.Certificate
+		wantErr bool
+	}{
+		{name: "valid", cert: ca.sign(t, cn, key, x509.ExtKeyUsageCodeSigning, now.Add(-time.Hour), now.Add(time.Hour))},
+		{name: "other node", cert: ca.sign(t, IssuerName("edge-2"), key, x509.ExtKeyUsageCodeSigning, now.Add(-time.Hour), now.Add(time.Hour)), wantErr: true},
+		{name: "other key", cert: ca.sign(t, cn, otherKey, x509.ExtKeyUsageCodeSigning, now.Add(-time.Hour), now.Add(time.Hour)), wantErr: true},
+		{name: "client cert", cert: ca.sign(t, cn, key, x509.ExtKeyUsageClientAuth, now.Add(-time.Hour), now.Add(time.Hour)), wantErr: true},
+		{name: "expired", cert: ca.sign(t, cn, key, x509.ExtKeyUsageCodeSigning, now.Add(-2*time.Hour), now.Add(-time.Hour)), wantErr: true},
+		{name: "other ca", cert: otherCA.sign(t, cn, key, x509.ExtKeyUsageCodeSigning, now.Add(-time.Hour), now.Add(time.Hour)), wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := verifyDelegationCert(test.cert, ca.pem, "edge-1", key)
+			if (err != nil) != test.wantErr {
+				t.Errorf("unexpected error: %v, wantErr: %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequiresRenewal(t *testing.T) {
+	notBefore := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notBefore.Add(10 * time.Hour)}
+	if requiresRenewal(cert, notBefore.Add(7*time.Hour)) {
+		t.Errorf("expect no renewal before 80%% of the lifetime")
+	}
+	if !requiresRenewal(cert, notBefore.Add(9*time.Hour)) {
+		t.Errorf("expect renewal after 80%% of the lifetime")
+	}
+}
+
+func TestLoadOrGenerateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sa-issuer.key")
+	key, err := loadOrGenerateKey(path)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	loaded, err := loadOrGenerateKey(path)
+	if err != nil {
+		t.Fatalf("failed to load key: %v", err)
+	}
+	if !key.Equal(loaded) {
+		t.Errorf("expect the generated key to be loaded")
+	}
+}
+
+func TestMint(t *testing.T) {
+	ca := newTestCA(t)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	now := time.Now()
+	cert := ca.sign(t, IssuerName("edge-1"), key, x509.ExtKeyUsageCodeSigning, now.Add(-time.Hour), now.Add(time.Hour))
+	issuer, err := NewIssuer("edge-1", key, cert, 600, []string{"https://kubernetes.default.svc.cluster.local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", UID: "sa-uid"}}
+	expirationSeconds := int64(3600)
+
+	tests := []struct {
+		name        string
+		tr          *authenticationv1.TokenRequest
+		wantAud     []string
+		wantExpire  int64
+		wantPodName string
+		wantErr     bool
+	}{
+		{
+			name: "bound to pod with expiration capped",
+			tr: &authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{
+				Audiences:         []string{"api"},
+				ExpirationSeconds: &expirationSeconds,
+				BoundObjectRef:    &authenticationv1.BoundObjectReference{Kind: "Pod", APIVersion: "v1", Name: "app-0", UID: "pod-uid"},
+			}},
+			wantAud:     []string{"api"},
+			wantExpire:  600,
+			wantPodName: "app-0",
+		},
+		{
+			name:       "default audiences",
+			tr:         &authenticationv1.TokenRequest{},
+			wantAud:    []string{"https://kubernetes.default.svc.cluster.local"},
+			wantExpire: 600,
+		},
+		{
+			name: "unsupported bound object",
+			tr: &authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{
+				BoundObjectRef: &authenticationv1.BoundObjectReference{Kind: "Node", Name: "edge-1"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := issuer.Mint(sa, test.tr)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v, wantErr: %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if *got.Spec.ExpirationSeconds != test.wantExpire {
+				t.Errorf("expect expiration %d, got %d", test.wantExpire, *got.Spec.ExpirationSeconds)
+			}
+
+			tok, err := jwt.ParseSigned(got.Status.Token)
+			if err != nil {
+				t.Fatalf("failed to parse token: %v", err)
+			}
+			public := jwt.Claims{}
+			private := struct {
+				Kubernetes struct {
+					Namespace string `json:"namespace"`
+					Svcacct   struct {
+						UID string `json:"uid"`
+					} `json:"serviceaccount"`
+					Pod *struct {
+						Name string `json:"name"`
+					} `json:"pod"`
+				} `json:"kubernetes.io"`
+			}{}
+			if err = tok.Claims(issuer.PublicKey(), &public, &private); err != nil {
+				t.Fatalf("failed to verify token: %v", err)
+			}
+			if public.Issuer != "kubeedge:offline-token-issuer:edge-1" || public.Subject != "system:serviceaccount:default:app" {
+				t.Errorf("unexpected issuer %q or subject %q", public.Issuer, public.Subject)
+			}
+			if !reflect.DeepEqual([]string(public.Audience), test.wantAud) {
+				t.Errorf("expect audiences %v, got %v", test.wantAud, public.Audience)
+			}
+			if !public.Expiry.Time().Equal(got.Status.ExpirationTimestamp.Time) {
+				t.Errorf("expect expiration timestamp %v, got %v", public.Expiry.Time(), got.Status.ExpirationTimestamp)
+			}
+			if private.Kubernetes.Namespace != "default" || private.Kubernetes.Svcacct.UID != "sa-uid" {
+				t.Errorf("unexpected private claims %+v", private.Kubernetes)
+			}
+			if test.wantPodName != "" && (private.Kubernetes.Pod == nil || private.Kubernetes.Pod.Name != test.wantPodName) {
+				t.Errorf("expect token bound to pod %s, got %+v", test.wantPodName, private.Kubernetes.Pod)
+			}
+		})
+	}
+}
+
+func TestMintCappedByDelegationCert(t *testing.T) {
+	ca := newTestCA(t)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	now := time.Now()
+	cert := ca.sign(t, IssuerName("edge-1"), key, x509.ExtKeyUsageCodeSigning, now.Add(-time.Hour), now.Add(time.Minute))
+	issuer, err := NewIssuer("edge-1", key, cert, 600, []string{"api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+	got, err := issuer.Mint(sa, &authenticationv1.TokenRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.ExpirationTimestamp.Time.After(cert.NotAfter) {
+		t.Errorf("expect the token to expire before the delegation certificate %v, got %v", cert.NotAfter, got.Status.ExpirationTimestamp)
+	}
+}
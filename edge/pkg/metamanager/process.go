@@ -20,6 +20,7 @@ import (
 	metaManagerConfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/config"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
+	"github.com/kubeedge/kubeedge/pkg/tracing"
 )
 
 // Constants to check metamanager processes
@@ -383,16 +384,26 @@ func (m *metaManager) processVolume(message model.Message) {
 	klog.Info("process volume started")
 	back, err := beehiveContext.SendSync(modules.EdgedModuleName, message, constants.CSISyncMsgRespTimeout)
 	klog.Infof("process volume get: req[%+v], back[%+v], err[%+v]", message, back, err)
-	if err != nil {
+	var resp *model.Message
+	switch {
+	case err != nil:
 		klog.Errorf("process volume send to edged failed: %v", err)
+		resp = message.NewRespByMessage(&message, err.Error())
+		resp.Router.Operation = model.ResponseErrorOperation
+	case back.GetOperation() == model.ResponseErrorOperation:
+		resp = message.NewRespByMessage(&message, back.GetContent())
+		resp.Router.Operation = model.ResponseErrorOperation
+	default:
+		resp = message.NewRespByMessage(&message, back.GetContent())
 	}
-
-	resp := message.NewRespByMessage(&message, back.GetContent())
 	sendToCloud(resp)
 	klog.Infof("process volume send to cloud resp[%+v]", resp)
 }
 
 func (m *metaManager) process(message model.Message) {
+	span := tracing.StartMessageSpan(modules.MetaManagerModuleName, &message)
+	defer span.End()
+
 	operation := message.GetOperation()
 
 	switch operation {
@@ -411,7 +422,14 @@ func (m *metaManager) process(message model.Message) {
 	case constants.CSIOperationTypeCreateVolume,
 		constants.CSIOperationTypeDeleteVolume,
 		constants.CSIOperationTypeControllerPublishVolume,
-		constants.CSIOperationTypeControllerUnpublishVolume:
+		constants.CSIOperationTypeControllerUnpublishVolume,
+		constants.CSIOperationTypeControllerExpandVolume,
+		constants.CSIOperationTypeCreateSnapshot,
+		constants.CSIOperationTypeDeleteSnapshot,
+		constants.CSIOperationTypeListSnapshots,
+		constants.CSIOperationTypeGetCapacity,
+		constants.CSIOperationTypeListVolumes,
+		constants.CSIOperationTypeControllerGetCapabilities:
 		m.processVolume(message)
 	default:
 		klog.Errorf("metamanager not supported operation: %v", operation)
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebus
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+// newServerTLSConfig returns the TLS config of the http server, the client certificates are
+// verified if they are given and the CA file is set
+func newServerTLSConfig(c *v1alpha2.ServiceBusTLS) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.TLSCAFile == "" {
+		return config, nil
+	}
+	pool, err := loadCertPool(c.TLSCAFile)
+	if err != nil {
+		return nil, err
+	}
+	config.ClientCAs = pool
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	return config, nil
+}
+
+// newUpstreamTLSConfig returns the TLS config verifying the HTTPS targets, with the system CAs
+// if the CA file is not set
+func newUpstreamTLSConfig(caFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile == "" {
+		return config, nil
+	}
+	pool, err := loadCertPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file %s: %v", caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificate found in CA file %s", caFile)
+	}
+	return pool, nil
+}
+
+// loadTokens reads the bearer tokens from the file, one token per line
+func loadTokens(tokenFile string) ([][]byte, error) {
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file %s: %v", tokenFile, err)
+	}
+	var tokens [][]byte
+	for _, line := range strings.Split(string(data), "\n") {
+		if token := strings.TrimSpace(line); token != "" && !strings.HasPrefix(token, "#") {
+			tokens = append(tokens, []byte(token))
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no token found in token file %s", tokenFile)
+	}
+	return tokens, nil
+}
+
+// withAuth only passes the requests with a verified client certificate or a valid bearer token
+// to the handler, it passes all the requests if neither certCheck nor tokens is set
+func withAuth(h http.Handler, certCheck bool, tokens [][]byte) http.Handler {
+	if !certCheck && len(tokens) == 0 {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if certCheck && req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+			h.ServeHTTP(w, req)
+			return
+		}
+		if token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); token != "" {
+			for _, t := range tokens {
+				if subtle.ConstantTimeCompare([]byte(token), t) == 1 {
+					h.ServeHTTP(w, req)
+					return
+				}
+			}
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write(marshalResult(&serverResponse{Code: http.StatusUnauthorized, Msg: "unauthorized"}))
+	})
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebus
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+func TestWithAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cases := []struct {
+		name      string
+		certCheck bool
+		tokens    [][]byte
+		header    string
+		verified  bool
+		code      int
+	}{
+		{"no auth", false, nil, "", false, http.StatusOK},
+		{"valid token", false, [][]byte{[]byte("secret")}, "Bearer secret", false, http.StatusOK},
+		{"invalid token", false, [][]byte{[]byte("secret")}, "Bearer wrong", false, http.StatusUnauthorized},
+		{"no token", false, [][]byte{[]byte("secret")}, "", false, http.StatusUnauthorized},
+		{"verified client certificate", true, nil, "", true, http.StatusOK},
+		{"no client certificate", true, nil, "", false, http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if c.header != "" {
+			req.Header.Set("Authorization", c.header)
+		}
+		if c.verified {
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+		}
+		w := httptest.NewRecorder()
+		withAuth(ok, c.certCheck, c.tokens).ServeHTTP(w, req)
+		if w.Code != c.code {
+			t.Errorf("%s: expected code %d, but got %d", c.name, c.code, w.Code)
+		}
+	}
+}
+
+func TestLoadTokens(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(file, []byte("# app tokens\nfirst\n\n  second  \n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+	tokens, err := loadTokens(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens) != 2 || string(tokens[0]) != "first" || string(tokens[1]) != "second" {
+		t.Errorf("expected tokens first and second, but got %q", tokens)
+	}
+}
+
+func TestReadBody(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), 10)
+
+	if data, err := readBody(bytes.NewReader(body), -1, 0); err != nil || len(data) != 10 {
+		t.Errorf("expected the whole body within the default limit, but got %d bytes, %v", len(data), err)
+	}
+	if _, err := readBody(bytes.NewReader(body), constants.DefaultServiceBusMaxBodySize+1, 0); !errors.Is(err, errBodyTooLarge) {
+		t.Errorf("expected errBodyTooLarge for the body larger than the default limit, but got %v", err)
+	}
+	if data, err := readBody(bytes.NewReader(body), 10, 10); err != nil || len(data) != 10 {
+		t.Errorf("expected the whole body within limit, but got %d bytes, %v", len(data), err)
+	}
+	if _, err := readBody(bytes.NewReader(body), -1, 5); !errors.Is(err, errBodyTooLarge) {
+		t.Errorf("expected errBodyTooLarge for the body of unknown length, but got %v", err)
+	}
+	if _, err := readBody(bytes.NewReader(body), 10, 5); !errors.Is(err, errBodyTooLarge) {
+		t.Errorf("expected errBodyTooLarge for the body of known length, but got %v", err)
+	}
+}
@@ -1,12 +1,14 @@
 package servicebus
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 	"sync/atomic"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/kubeedge/beehive/pkg/core"
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/constants"
 	commonType "github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 	servicebusConfig "github.com/kubeedge/kubeedge/edge/pkg/servicebus/config"
@@ -31,10 +34,12 @@ var (
 )
 
 const (
-	sourceType  = "router_servicebus"
-	maxBodySize = 5 * 1e6
+	sourceType = "router_servicebus"
 )
 
+// errBodyTooLarge is the error of a body larger than the max body size
+var errBodyTooLarge = errors.New("body too large")
+
 // servicebus struct
 type servicebus struct {
 	enable bool
@@ -59,6 +64,11 @@ type serverResponse struct {
 var htc = new(http.Client)
 var uc = new(util.URLClient)
 
+// sendToCloud sends the response message to the cloud
+var sendToCloud = func(msg beehiveModel.Message) {
+	beehiveContext.SendToGroup(modules.HubGroup, msg)
+}
+
 func newServicebus(enable bool, server string, port, timeout int) *servicebus {
 	return &servicebus{
 		enable:  enable,
@@ -90,7 +100,24 @@ func (sb *servicebus) Enable() bool {
 func (sb *servicebus) Start() {
 	// no need to call TopicInit now, we have fixed topic
 	htc.Timeout = time.Second * 10
+	if sb.timeout > 0 {
+		htc.Timeout = time.Duration(sb.timeout) * time.Second
+	}
+	upstreamTLS, err := newUpstreamTLSConfig(servicebusConfig.Config.UpstreamCAFile)
+	if err != nil {
+		klog.Errorf("servicebus failed to load the upstream CA, the system CAs are used: %v", err)
+		upstreamTLS, _ = newUpstreamTLSConfig("")
+	}
+	// the targets are always dialed directly, a proxy would bypass the resolution of the Services
+	// and the checks of the allowed targets
+	htc.Transport = &http.Transport{
+		DialContext:         dialTarget,
+		TLSClientConfig:     upstreamTLS,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	htc.CheckRedirect = checkRedirect
 	uc.Client = htc
+	uc.TLS = upstreamTLS
 	if !dao.IsTableEmpty() {
 		if atomic.CompareAndSwapInt32(&inited, 0, 1) {
 			go server(c)
@@ -134,11 +161,14 @@ func processMessage(msg *beehiveModel.Message) {
 			c <- struct{}{}
 		}
 	default:
-		r := strings.Split(resource, ":")
-		if len(r) != 2 {
-			m := "the format of resource " + resource + " is incorrect"
+		targetURL, err := resolveTarget(resource)
+		if err != nil {
+			m := err.Error()
 			klog.Warningf(m)
 			code := http.StatusBadRequest
+			if errors.Is(err, errTargetNotAllowed) {
+				code = http.StatusForbidden
+			}
 			if response, err := buildErrorResponse(msg.GetID(), m, code); err == nil {
 				beehiveContext.SendToGroup(modules.HubGroup, response)
 			}
@@ -168,7 +198,6 @@ func processMessage(msg *beehiveModel.Message) {
 
 		//send message with resource to the edge part
 		operation := httpRequest.Method
-		targetURL := "http://127.0.0.1:" + r[0] + r[1]
 		resp, err := uc.HTTPDo(operation, targetURL, httpRequest.Header, httpRequest.Body)
 		if err != nil {
 			m := "error to call service"
@@ -180,24 +209,7 @@ func processMessage(msg *beehiveModel.Message) {
 			return
 		}
 		defer resp.Body.Close()
-		resBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
-		if err != nil {
-			if err.Error() == "http: request body too large" {
-				err = fmt.Errorf("response body too large")
-			}
-			m := "error to receive response, err: " + err.Error()
-			code := http.StatusInternalServerError
-			klog.Errorf(m, err)
-			if response, err := buildErrorResponse(msg.GetID(), m, code); err == nil {
-				beehiveContext.SendToGroup(modules.HubGroup, response)
-			}
-			return
-		}
-
-		response := commonType.HTTPResponse{Header: resp.Header, StatusCode: resp.StatusCode, Body: resBody}
-		responseMsg := beehiveModel.NewMessage(msg.GetID()).SetRoute(modules.ServiceBusModuleName, modules.UserGroup).
-			SetResourceOperation("", beehiveModel.UploadOperation).FillBody(response)
-		beehiveContext.SendToGroup(modules.HubGroup, *responseMsg)
+		streamResponse(msg.GetID(), resp, servicebusConfig.Config.MaxBodySize)
 	}
 }
 
@@ -211,12 +223,32 @@ func server(stopChan <-chan struct{}) {
 		timeout, _ = time.ParseDuration("10s")
 	}
 
-	h := buildBasicHandler(timeout)
-	// TODO we should add tls for servicebus http server later
+	tlsEnabled := servicebusConfig.Config.TLS != nil && servicebusConfig.Config.TLS.Enable
+	certCheck := tlsEnabled && servicebusConfig.Config.TLS.TLSCAFile != ""
+	var tokens [][]byte
+	if servicebusConfig.Config.TokenFile != "" {
+		if tokens, err = loadTokens(servicebusConfig.Config.TokenFile); err != nil {
+			klog.Errorf("servicebus failed to start the server: %v", err)
+			atomic.StoreInt32(&inited, 0)
+			return
+		}
+		if !tlsEnabled {
+			klog.Warning("servicebus accepts the bearer tokens over plain http, please enable tls")
+		}
+	}
+
+	h := withAuth(buildBasicHandler(timeout), certCheck, tokens)
 	s := http.Server{
 		Addr:    fmt.Sprintf("%s:%d", servicebusConfig.Config.Server, servicebusConfig.Config.Port),
 		Handler: h,
 	}
+	if tlsEnabled {
+		if s.TLSConfig, err = newServerTLSConfig(servicebusConfig.Config.TLS); err != nil {
+			klog.Errorf("servicebus failed to start the server: %v", err)
+			atomic.StoreInt32(&inited, 0)
+			return
+		}
+	}
 	go func() {
 		<-stopChan
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -228,6 +260,10 @@ func server(stopChan <-chan struct{}) {
 	}()
 
 	klog.Infof("[servicebus]start to listen and server at %v", s.Addr)
+	if tlsEnabled {
+		utilruntime.HandleError(s.ListenAndServeTLS(servicebusConfig.Config.TLS.TLSCertFile, servicebusConfig.Config.TLS.TLSPrivateKeyFile))
+		return
+	}
 	utilruntime.HandleError(s.ListenAndServe())
 }
 
@@ -235,11 +271,14 @@ func buildBasicHandler(timeout time.Duration) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		sReq := &serverRequest{}
 		sResp := &serverResponse{}
-		req.Body = http.MaxBytesReader(w, req.Body, maxBodySize)
-		byteData, err := io.ReadAll(req.Body)
+		byteData, err := readBody(req.Body, req.ContentLength, servicebusConfig.Config.MaxBodySize)
 		if err != nil {
 			sResp.Code = http.StatusBadRequest
 			sResp.Msg = "can't read data from body of the http's request"
+			if errors.Is(err, errBodyTooLarge) {
+				sResp.Code = http.StatusRequestEntityTooLarge
+				sResp.Msg = "the body of the http's request is too large"
+			}
 			w.Write(marshalResult(sResp))
 			return
 		}
@@ -279,6 +318,79 @@ func buildBasicHandler(timeout time.Duration) http.Handler {
 	})
 }
 
+// streamResponse sends the response of the target to the cloud. The body is streamed in chunks of at most
+// chunkSize bytes, one message per chunk, so that a large body is neither held in memory nor rejected.
+// The default chunk size is used if it is not positive
+func streamResponse(parentID string, resp *http.Response, chunkSize int64) {
+	if chunkSize <= 0 {
+		chunkSize = constants.DefaultServiceBusMaxBodySize
+	}
+	body := bufio.NewReader(resp.Body)
+	for seq := 0; ; seq++ {
+		var chunk bytes.Buffer
+		if resp.ContentLength > 0 && resp.ContentLength < chunkSize {
+			chunk.Grow(int(resp.ContentLength))
+		}
+		more := false
+		_, err := chunk.ReadFrom(io.LimitReader(body, chunkSize))
+		if err == nil {
+			// the next chunk follows if there is anything left
+			if _, err = body.Peek(1); err == nil {
+				more = true
+			} else if err == io.EOF {
+				err = nil
+			}
+		}
+		if err != nil {
+			if seq == 0 {
+				m := "error to receive response, err: " + err.Error()
+				klog.Errorf(m)
+				if response, err := buildErrorResponse(parentID, m, http.StatusInternalServerError); err == nil {
+					sendToCloud(response)
+				}
+				return
+			}
+			// the status has been sent, so the body can only be ended early
+			klog.Errorf("failed to receive the response body of message %s, the body is truncated: %v", parentID, err)
+		}
+
+		response := commonType.HTTPResponse{Body: chunk.Bytes(), Seq: seq, More: more}
+		if seq == 0 {
+			response.Header, response.StatusCode = resp.Header, resp.StatusCode
+		}
+		responseMsg := beehiveModel.NewMessage(parentID).SetRoute(modules.ServiceBusModuleName, modules.UserGroup).
+			SetResourceOperation("", beehiveModel.UploadOperation).FillBody(response)
+		sendToCloud(*responseMsg)
+		if !more {
+			return
+		}
+	}
+}
+
+// readBody reads the whole body, growing the buffer once by the content length if it is known.
+// The request of the http server is a JSON object carried in a single message, so it is always limited:
+// a body larger than the limit fails with errBodyTooLarge instead of being truncated, and the default
+// limit is used if it is not positive
+func readBody(body io.Reader, contentLength, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		limit = constants.DefaultServiceBusMaxBodySize
+	}
+	if contentLength > limit {
+		return nil, errBodyTooLarge
+	}
+	var buf bytes.Buffer
+	if contentLength > 0 {
+		buf.Grow(int(contentLength))
+	}
+	if _, err := buf.ReadFrom(io.LimitReader(body, limit+1)); err != nil {
+		return nil, err
+	}
+	if int64(buf.Len()) > limit {
+		return nil, errBodyTooLarge
+	}
+	return buf.Bytes(), nil
+}
+
 func buildErrorResponse(parentID string, content string, statusCode int) (beehiveModel.Message, error) {
 	h := http.Header{}
 	h.Add("Server", "kubeedge-edgecore")
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebus
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestStreamResponse(t *testing.T) {
+	var sent []commonType.HTTPResponse
+	oldSendToCloud := sendToCloud
+	sendToCloud = func(msg beehiveModel.Message) {
+		if msg.GetParentID() != "request" {
+			t.Errorf("expected the parent id request, but got %s", msg.GetParentID())
+		}
+		data, err := msg.GetContentData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var response commonType.HTTPResponse
+		if err := json.Unmarshal(data, &response); err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, response)
+	}
+	defer func() { sendToCloud = oldSendToCloud }()
+
+	cases := []struct {
+		name   string
+		body   string
+		chunks []string
+	}{
+		{name: "empty", body: "", chunks: []string{""}},
+		{name: "single chunk", body: "abc", chunks: []string{"abc"}},
+		{name: "exact chunks", body: "abcdefgh", chunks: []string{"abcd", "efgh"}},
+		{name: "partial last chunk", body: "abcdefghij", chunks: []string{"abcd", "efgh", "ij"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sent = nil
+			streamResponse("request", &http.Response{
+				StatusCode:    http.StatusOK,
+				Header:        http.Header{"Content-Type": []string{"text/plain"}},
+				Body:          io.NopCloser(strings.NewReader(c.body)),
+				ContentLength: -1,
+			}, 4)
+
+			if len(sent) != len(c.chunks) {
+				t.Fatalf("expected %d chunks, but got %d", len(c.chunks), len(sent))
+			}
+			for i, chunk := range sent {
+				if chunk.Seq != i || chunk.More != (i < len(c.chunks)-1) || !bytes.Equal(chunk.Body, []byte(c.chunks[i])) {
+					t.Errorf("expected chunk %d %q, but got %+v", i, c.chunks[i], chunk)
+				}
+				// only the first chunk carries the status and the header
+				if (i == 0) != (chunk.StatusCode == http.StatusOK && chunk.Header.Get("Content-Type") == "text/plain") {
+					t.Errorf("unexpected status and header of chunk %d: %+v", i, chunk)
+				}
+			}
+		})
+	}
+}
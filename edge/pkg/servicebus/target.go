@@ -0,0 +1,206 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+	servicebusConfig "github.com/kubeedge/kubeedge/edge/pkg/servicebus/config"
+)
+
+// errTargetNotAllowed is the error of a target not allowed to forward the requests to
+var errTargetNotAllowed = errors.New("target not allowed")
+
+// maxRedirects is the max number of redirects followed for a request, the same as the default of http.Client
+const maxRedirects = 10
+
+var (
+	servicesGVR  = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	endpointsGVR = schema.GroupVersionResource{Version: "v1", Resource: "endpoints"}
+
+	// lookupMeta queries the objects in the edge metaserver cache
+	lookupMeta = v2.RawMetaByGVRNN
+
+	dialer = &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+)
+
+// resolveTarget returns the url of the target by the resource of the request from the cloud,
+// which is "<port>:<path>" for the target at 127.0.0.1, or "<scheme>://<host>:<port><path>"
+func resolveTarget(resource string) (string, error) {
+	if !strings.HasPrefix(resource, "http://") && !strings.HasPrefix(resource, "https://") {
+		// the path and the query may contain ":"
+		r := strings.SplitN(resource, ":", 2)
+		if len(r) != 2 || !strings.HasPrefix(r[1], "/") {
+			return "", fmt.Errorf("the format of resource %s is incorrect", resource)
+		}
+		// the port must be a number, so that the resource can't be turned into a url of another host
+		if port, err := strconv.ParseUint(r[0], 10, 16); err != nil || port == 0 {
+			return "", fmt.Errorf("the port of resource %s is incorrect", resource)
+		}
+		return "http://127.0.0.1:" + r[0] + r[1], nil
+	}
+
+	u, err := url.Parse(resource)
+	if err != nil {
+		return "", fmt.Errorf("the format of resource %s is incorrect: %v", resource, err)
+	}
+	if !isAllowedTarget(u.Hostname(), targetPort(u)) {
+		return "", fmt.Errorf("%w: %s", errTargetNotAllowed, u.Host)
+	}
+	return resource, nil
+}
+
+// targetPort returns the port of the url, or the default port of its scheme
+func targetPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if u.Scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
+
+// checkRedirect only follows the redirects to the allowed targets, so that an allowed target
+// can't redirect the requests from the cloud to other hosts
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if !isAllowedTarget(req.URL.Hostname(), targetPort(req.URL)) {
+		return fmt.Errorf("%w: redirect to %s", errTargetNotAllowed, req.URL.Host)
+	}
+	return nil
+}
+
+// isAllowedTarget checks whether the requests from the cloud may be forwarded to the host and port
+func isAllowedTarget(host, port string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	if _, _, ok := parseServiceHost(host); ok {
+		return true
+	}
+	for _, target := range servicebusConfig.Config.AllowedTargets {
+		if h, p, err := net.SplitHostPort(target); err == nil {
+			if strings.EqualFold(h, host) && p == port {
+				return true
+			}
+		} else if strings.EqualFold(target, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseServiceHost parses the host of a Kubernetes Service, "<service>.<namespace>.svc" or
+// "<service>.<namespace>.svc.cluster.local"
+func parseServiceHost(host string) (name, namespace string, ok bool) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".cluster.local")
+	parts := strings.Split(host, ".")
+	if len(parts) != 3 || parts[2] != "svc" || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// dialTarget dials the target, the Kubernetes Services are dialed at one of their endpoints,
+// so that the Host header and the TLS server name are still the host of the Service
+func dialTarget(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if name, namespace, ok := parseServiceHost(host); ok {
+		if addr, err = resolveService(namespace, name, port); err != nil {
+			return nil, err
+		}
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+// resolveService returns the address of a ready endpoint of the Service port, from the edge
+// metaserver cache
+func resolveService(namespace, name, port string) (string, error) {
+	servicePort, err := strconv.Atoi(port)
+	if err != nil {
+		return "", fmt.Errorf("invalid port %s of service %s/%s", port, namespace, name)
+	}
+
+	var service v1.Service
+	if err := getMeta(servicesGVR, namespace, name, &service); err != nil {
+		return "", err
+	}
+	portName, found := "", false
+	for _, p := range service.Spec.Ports {
+		if int(p.Port) == servicePort {
+			portName, found = p.Name, true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("service %s/%s has no port %d", namespace, name, servicePort)
+	}
+
+	var endpoints v1.Endpoints
+	if err := getMeta(endpointsGVR, namespace, name, &endpoints); err != nil {
+		return "", err
+	}
+	var addrs []string
+	for _, subset := range endpoints.Subsets {
+		for _, p := range subset.Ports {
+			if p.Name != portName {
+				continue
+			}
+			for _, address := range subset.Addresses {
+				addrs = append(addrs, net.JoinHostPort(address.IP, strconv.Itoa(int(p.Port))))
+			}
+		}
+	}
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("service %s/%s has no ready endpoint of port %d", namespace, name, servicePort)
+	}
+	return addrs[rand.Intn(len(addrs))], nil
+}
+
+func getMeta(gvr schema.GroupVersionResource, namespace, name string, obj interface{}) error {
+	metas, err := lookupMeta(gvr, namespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to query %s %s/%s: %v", gvr.Resource, namespace, name, err)
+	}
+	if len(*metas) == 0 {
+		return fmt.Errorf("%s %s/%s not found in the edge cache", gvr.Resource, namespace, name)
+	}
+	return json.Unmarshal([]byte((*metas)[0].Value), obj)
+}
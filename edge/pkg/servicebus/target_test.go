@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebus
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+	servicebusConfig "github.com/kubeedge/kubeedge/edge/pkg/servicebus/config"
+)
+
+func TestResolveTarget(t *testing.T) {
+	servicebusConfig.Config.AllowedTargets = []string{"192.168.1.10", "camera.local:8443"}
+	defer func() { servicebusConfig.Config.AllowedTargets = nil }()
+
+	cases := []struct {
+		resource string
+		url      string
+		err      error
+	}{
+		{"8080:/api/v1/status", "http://127.0.0.1:8080/api/v1/status", nil},
+		{"http://localhost:8080/status", "http://localhost:8080/status", nil},
+		{"http://192.168.1.10:9000/status", "http://192.168.1.10:9000/status", nil},
+		{"https://camera.local:8443/snapshot", "https://camera.local:8443/snapshot", nil},
+		{"https://camera.local/snapshot", "", errTargetNotAllowed},
+		{"http://192.168.1.11:9000/status", "", errTargetNotAllowed},
+		{"https://camera.default.svc:443/snapshot", "https://camera.default.svc:443/snapshot", nil},
+		{"8080:/status:now", "http://127.0.0.1:8080/status:now", nil},
+		{"8080:/api?since=12:00", "http://127.0.0.1:8080/api?since=12:00", nil},
+		{"8080:@evil/x", "", nil},
+		{"8080@evil:/x", "", nil},
+		{"+8080:/x", "", nil},
+		{"8080:", "", nil},
+	}
+
+	for _, c := range cases {
+		url, err := resolveTarget(c.resource)
+		if c.url == "" {
+			if err == nil || (c.err != nil && !errors.Is(err, c.err)) {
+				t.Errorf("expected error %v for resource %s, but got %v", c.err, c.resource, err)
+			}
+			continue
+		}
+		if err != nil || url != c.url {
+			t.Errorf("expected url %s for resource %s, but got %s, %v", c.url, c.resource, url, err)
+		}
+	}
+}
+
+func TestResolveService(t *testing.T) {
+	service := v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "camera", Namespace: "default"},
+		Spec: v1.ServiceSpec{Ports: []v1.ServicePort{
+			{Name: "http", Port: 80},
+			{Name: "https", Port: 443},
+		}},
+	}
+	endpoints := v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "camera", Namespace: "default"},
+		Subsets: []v1.EndpointSubset{{
+			Addresses: []v1.EndpointAddress{{IP: "10.0.0.5"}},
+			Ports:     []v1.EndpointPort{{Name: "http", Port: 8080}, {Name: "https", Port: 8443}},
+		}},
+	}
+	lookupMeta = func(gvr schema.GroupVersionResource, namespace string, name string) (*[]v2.MetaV2, error) {
+		if namespace != "default" || name != "camera" {
+			return &[]v2.MetaV2{}, nil
+		}
+		obj := interface{}(service)
+		if gvr == endpointsGVR {
+			obj = endpoints
+		}
+		value, _ := json.Marshal(obj)
+		return &[]v2.MetaV2{{Value: string(value)}}, nil
+	}
+	defer func() { lookupMeta = v2.RawMetaByGVRNN }()
+
+	if addr, err := resolveService("default", "camera", "443"); err != nil || addr != "10.0.0.5:8443" {
+		t.Errorf("expected address 10.0.0.5:8443, but got %s, %v", addr, err)
+	}
+	if _, err := resolveService("default", "camera", "8080"); err == nil {
+		t.Errorf("expected error for the port not of the service")
+	}
+	if _, err := resolveService("default", "missing", "80"); err == nil {
+		t.Errorf("expected error for the service not in the cache")
+	}
+}
+
+func TestParseServiceHost(t *testing.T) {
+	cases := []struct {
+		host      string
+		name      string
+		namespace string
+		ok        bool
+	}{
+		{"camera.default.svc", "camera", "default", true},
+		{"camera.default.svc.cluster.local", "camera", "default", true},
+		{"camera.default", "", "", false},
+		{"camera.local", "", "", false},
+		{"10.0.0.5", "", "", false},
+	}
+
+	for _, c := range cases {
+		name, namespace, ok := parseServiceHost(c.host)
+		if name != c.name || namespace != c.namespace || ok != c.ok {
+			t.Errorf("expected %s, %s, %v for host %s, but got %s, %s, %v", c.name, c.namespace, c.ok, c.host, name, namespace, ok)
+		}
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	servicebusConfig.Config.AllowedTargets = []string{"192.168.1.10"}
+	defer func() { servicebusConfig.Config.AllowedTargets = nil }()
+
+	cases := []struct {
+		url string
+		via int
+		err bool
+	}{
+		{"http://192.168.1.10/login", 1, false},
+		{"http://127.0.0.1:8080/status", 1, false},
+		{"http://169.254.169.254/latest/meta-data", 1, true},
+		{"http://192.168.1.10/login", maxRedirects, true},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, c.url, nil)
+		err := checkRedirect(req, make([]*http.Request, c.via))
+		if (err != nil) != c.err {
+			t.Errorf("redirect to %s after %d requests: expected error %v, but got %v", c.url, c.via, c.err, err)
+		}
+	}
+}
@@ -86,6 +86,8 @@ func NewKubeedgeCommand() *cobra.Command {
 	// beta cmds
 	cmds.AddCommand(beta.NewBeta())
 	cmds.AddCommand(edge.NewEdgeUpgrade())
+	cmds.AddCommand(edge.NewEdgeBackup())
+	cmds.AddCommand(edge.NewEdgeRestore())
 
 	return cmds
 }
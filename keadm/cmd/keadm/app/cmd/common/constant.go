@@ -96,6 +96,12 @@ const (
 	// Forced install
 	Force = "force"
 
+	// BackupArchive sets the path of the archive of the edge node backup
+	BackupArchive = "archive"
+
+	// CACertHash sets the sha256 hash of the CA certificate of the cluster, which is the first part of the token
+	CACertHash = "ca-cert-hash"
+
 	// Skip CRDs
 	SkipCRDs = "skip-crds"
 
@@ -167,6 +173,9 @@ const (
 
 	ArgDiagnoseInstall  = "install"
 	DescDiagnoseInstall = "Diagnose install"
+
+	ArgDiagnoseConnection  = "connection"
+	DescDiagnoseConnection = "Diagnose connection to cloudcore"
 	/****/
 
 	ArgCheckAll     = "all"
@@ -245,5 +254,9 @@ var (
 			Use:  ArgDiagnoseInstall,
 			Desc: DescDiagnoseInstall,
 		},
+		{
+			Use:  ArgDiagnoseConnection,
+			Desc: DescDiagnoseConnection,
+		},
 	}
 )
@@ -86,6 +86,26 @@ type CollectOptions struct {
 	LogPath    string
 }
 
+// BackupOptions has the kubeedge edge backup information filled by CLI
+type BackupOptions struct {
+	Config string
+	Output string
+}
+
+// RestoreOptions has the kubeedge edge restore information filled by CLI
+type RestoreOptions struct {
+	Archive         string
+	KubeEdgeVersion string
+	ImageRepository string
+	WithMQTT        bool
+	CloudCoreIPPort string
+	CertPort        string
+	// CertPath and CACertHash are the trust anchors of the cluster provided out of band,
+	// the CA certificate in the archive must be the same one
+	CertPath   string
+	CACertHash string
+}
+
 type ResetOptions struct {
 	Kubeconfig  string
 	Force       bool
@@ -103,6 +123,9 @@ type DiagnoseOptions struct {
 	Config       string
 	CheckOptions *CheckOptions
 	DBPath       string
+	// Force opens the session to cloudhub even if edgecore is running or it is unknown whether edgecore
+	// is running, the messages pending for the node may be dropped when the session of edgecore is replaced
+	Force bool
 }
 
 type DiagnoseObject struct {
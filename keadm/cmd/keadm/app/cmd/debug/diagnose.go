@@ -32,6 +32,9 @@ keadm debug diagnose install
 
 # Diagnose node installation conditions and specify the detected ip
 keadm debug diagnose install -i 192.168.1.2
+
+# Diagnose the connection to cloudcore with the certificates of the node
+keadm debug diagnose connection
 `
 )
 
@@ -72,6 +75,13 @@ func NewSubDiagnose(object Diagnose) *cobra.Command {
 		cmd.Flags().StringVarP(&do.CheckOptions.IP, "ip", "i", do.CheckOptions.IP, "specify test ip")
 		cmd.Flags().StringVarP(&do.CheckOptions.CloudHubServer, "cloud-hub-server", "s", do.CheckOptions.CloudHubServer, "specify cloudhub server")
 		cmd.Flags().StringVarP(&do.CheckOptions.Runtime, "runtime", "r", do.CheckOptions.Runtime, "specify the runtime")
+	case common.ArgDiagnoseConnection:
+		cmd.Flags().StringVarP(&do.Config, common.EdgecoreConfig, "c", do.Config,
+			fmt.Sprintf("Specify configuration file, default is %s", common.EdgecoreConfigPath))
+		cmd.Flags().IntVarP(&do.CheckOptions.Timeout, "timeout", "t", do.CheckOptions.Timeout, "specify the timeout in seconds of each check")
+		cmd.Flags().BoolVar(&do.Force, "force", do.Force,
+			"open the session to cloudhub even if edgecore is running, which interrupts the session of edgecore, "+
+				"and the messages pending for the node may be dropped")
 	}
 	return cmd
 }
@@ -106,6 +116,8 @@ func (da Diagnose) ExecuteDiagnose(use string, ops *common.DiagnoseOptions, args
 		}
 	case common.ArgDiagnoseInstall:
 		err = DiagnoseInstall(ops.CheckOptions)
+	case common.ArgDiagnoseConnection:
+		err = DiagnoseConnection(ops)
 	}
 
 	if err != nil {
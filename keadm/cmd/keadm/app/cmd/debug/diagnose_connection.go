@@ -0,0 +1,446 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/constants"
+	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/viaduct/pkg/api"
+	"github.com/kubeedge/viaduct/pkg/client"
+	"github.com/kubeedge/viaduct/pkg/comm"
+	"github.com/kubeedge/viaduct/pkg/conn"
+)
+
+const (
+	resultPass = "PASS"
+	resultWarn = "WARN"
+	resultFail = "FAIL"
+	resultSkip = "SKIP"
+
+	// certExpiryWarning is the remaining validity under which a certificate is reported with a warning
+	certExpiryWarning = 30 * 24 * time.Hour
+	// tunnelConnectPath is the path of cloudstream where edgestream opens the tunnel
+	tunnelConnectPath = "/v1/kubeedge/connect"
+
+	pingCount              = 5
+	throughputMessageSize  = 64 * 1024
+	throughputMessageCount = 32
+)
+
+// connectionReport is the result of the checks of the connection to cloudcore
+type connectionReport struct {
+	items []reportItem
+}
+
+type reportItem struct {
+	check  string
+	result string
+	detail string
+}
+
+func (r *connectionReport) add(check, result, detail string) {
+	r.items = append(r.items, reportItem{check: check, result: result, detail: detail})
+}
+
+func (r *connectionReport) failed() int {
+	failed := 0
+	for _, item := range r.items {
+		if item.result == resultFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (r *connectionReport) print(w io.Writer) {
+	tw := printers.GetNewTabWriter(w)
+	defer tw.Flush()
+	fmt.Fprintln(tw, "CHECK\tRESULT\tDETAIL")
+	for _, item := range r.items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", item.check, item.result, item.detail)
+	}
+}
+
+// DiagnoseConnection checks the certificates of the node and the endpoints of cloudcore in the edgecore config,
+// and measures the round-trip time and the throughput of a session to cloudhub
+func DiagnoseConnection(ops *common.DiagnoseOptions) error {
+	edgeconfig, err := util.ParseEdgecoreConfig(ops.Config)
+	if err != nil {
+		return fmt.Errorf("parse Edgecore config %s failed: %v", ops.Config, err)
+	}
+	edgehub := edgeconfig.Modules.EdgeHub
+	if edgehub == nil {
+		return fmt.Errorf("edgehub is not configured in %s", ops.Config)
+	}
+	timeout := time.Duration(ops.CheckOptions.Timeout) * time.Second
+
+	report := &connectionReport{}
+	roots, cert := checkCertificates(report, "node certificate", edgehub.TLSCAFile, edgehub.TLSCertFile, edgehub.TLSPrivateKeyFile)
+
+	if edgehub.WebSocket != nil && edgehub.WebSocket.Enable {
+		checkTLSEndpoint(report, "cloudhub websocket", edgehub.WebSocket.Server, "/", roots, cert, timeout)
+	}
+	if edgehub.HTTPServer == "" {
+		report.add("cloudhub "+constants.DefaultCertURL, resultFail, "httpServer is not configured")
+	} else if serverURL, err := url.Parse(edgehub.HTTPServer); err != nil {
+		report.add("cloudhub "+constants.DefaultCertURL, resultFail, fmt.Sprintf("invalid httpServer %s: %v", edgehub.HTTPServer, err))
+	} else {
+		// the client certificate is not sent, since cloudhub signs a new certificate for a request with it
+		checkTLSEndpoint(report, "cloudhub "+constants.DefaultCertURL, serverURL.Host, constants.DefaultCertURL, roots, nil, timeout)
+	}
+
+	if stream := edgeconfig.Modules.EdgeStream; stream != nil && stream.Enable {
+		tunnelRoots, tunnelCert := checkCertificates(report, "tunnel certificate",
+			stream.TLSTunnelCAFile, stream.TLSTunnelCertFile, stream.TLSTunnelPrivateKeyFile)
+		// the tunnel is not upgraded without the websocket headers, so the tunnel of edgestream is kept
+		checkTLSEndpoint(report, "cloudstream tunnel", stream.TunnelServer, tunnelConnectPath, tunnelRoots, tunnelCert, timeout)
+	} else {
+		report.add("cloudstream tunnel", resultSkip, "edgestream is disabled")
+	}
+
+	checkSession(report, edgeconfig, roots, cert, ops.Force, timeout)
+
+	report.print(os.Stdout)
+	if failed := report.failed(); failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(report.items))
+	}
+	return nil
+}
+
+// checkCertificates checks the certificate of the node is signed by the CA and valid,
+// and returns the CA and the certificate loaded
+func checkCertificates(report *connectionReport, check, caFile, certFile, keyFile string) (*x509.CertPool, *tls.Certificate) {
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		report.add(check, resultFail, fmt.Sprintf("failed to read CA: %v", err))
+		return nil, nil
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		report.add(check, resultFail, fmt.Sprintf("no certificate found in CA %s", caFile))
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		report.add(check, resultFail, fmt.Sprintf("failed to load key pair: %v", err))
+		return roots, nil
+	}
+	chain, err := parseChain(cert.Certificate)
+	if err != nil {
+		report.add(check, resultFail, fmt.Sprintf("failed to parse %s: %v", certFile, err))
+		return roots, nil
+	}
+
+	result, detail := verifyCertificate(chain, roots, "", x509.ExtKeyUsageClientAuth, time.Now())
+	report.add(check, result, fmt.Sprintf("CN=%s, %s", chain[0].Subject.CommonName, detail))
+	return roots, &cert
+}
+
+// checkTLSEndpoint checks the certificate of the endpoint, and whether the endpoint responds a request
+// over TLS with the client certificate, which is rejected by the endpoint if it is not trusted
+func checkTLSEndpoint(report *connectionReport, check, address, path string, roots *x509.CertPool, cert *tls.Certificate, timeout time.Duration) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		report.add(check, resultFail, fmt.Sprintf("invalid server %q: %v", address, err))
+		return
+	}
+
+	// the certificate of the endpoint is verified below to report the details
+	tlsConfig := &tls.Config{InsecureSkipVerify: true} // #nosec G402
+	if cert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*cert}
+	}
+	start := time.Now()
+	tlsConn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", address, tlsConfig)
+	if err != nil {
+		report.add(check, resultFail, fmt.Sprintf("failed to connect %s: %v", address, err))
+		return
+	}
+	defer tlsConn.Close()
+	handshake := time.Since(start)
+
+	if roots == nil {
+		report.add(check+" certificate", resultSkip, "no CA to verify the certificate")
+	} else {
+		result, detail := verifyCertificate(tlsConn.ConnectionState().PeerCertificates, roots, host,
+			x509.ExtKeyUsageServerAuth, time.Now())
+		report.add(check+" certificate", result, detail)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "https://"+address+path, nil)
+	if err != nil {
+		report.add(check, resultFail, err.Error())
+		return
+	}
+	req.Close = true
+	if err := tlsConn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		report.add(check, resultFail, err.Error())
+		return
+	}
+	if err := req.Write(tlsConn); err != nil {
+		report.add(check, resultFail, fmt.Sprintf("failed to send request to %s: %v", address, err))
+		return
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(tlsConn), req)
+	if err != nil {
+		report.add(check, resultFail, fmt.Sprintf("no response from %s: %v", address, err))
+		return
+	}
+	resp.Body.Close()
+	report.add(check, resultPass, fmt.Sprintf("%s responded %q, TLS handshake in %v",
+		address, resp.Status, handshake.Round(time.Millisecond)))
+}
+
+// verifyCertificate verifies the chain is signed by the roots for the usage and the host if it is not empty,
+// and reports the validity of the leaf certificate
+func verifyCertificate(chain []*x509.Certificate, roots *x509.CertPool, host string, usage x509.ExtKeyUsage, now time.Time) (string, string) {
+	if len(chain) == 0 {
+		return resultFail, "no certificate"
+	}
+	leaf := chain[0]
+	if now.Before(leaf.NotBefore) {
+		return resultFail, fmt.Sprintf("not valid until %s", leaf.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(leaf.NotAfter) {
+		return resultFail, fmt.Sprintf("expired at %s", leaf.NotAfter.Format(time.RFC3339))
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+		CurrentTime:   now,
+	})
+	var hostErr x509.HostnameError
+	if errors.As(err, &hostErr) {
+		return resultFail, fmt.Sprintf("%s is not in the SANs %s", host, strings.Join(subjectAltNames(leaf), ","))
+	}
+	if err != nil {
+		return resultFail, fmt.Sprintf("not trusted by the CA: %v", err)
+	}
+
+	remaining := leaf.NotAfter.Sub(now)
+	detail := fmt.Sprintf("expires in %d days at %s", int(remaining.Hours()/24), leaf.NotAfter.Format(time.RFC3339))
+	if host != "" {
+		detail = fmt.Sprintf("SANs %s, %s", strings.Join(subjectAltNames(leaf), ","), detail)
+	}
+	if remaining < certExpiryWarning {
+		return resultWarn, detail
+	}
+	return resultPass, detail
+}
+
+func subjectAltNames(cert *x509.Certificate) []string {
+	names := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
+
+func parseChain(raw [][]byte) ([]*x509.Certificate, error) {
+	chain := make([]*x509.Certificate, 0, len(raw))
+	for _, der := range raw {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+	return chain, nil
+}
+
+// checkSession opens a session to cloudhub as edgehub does, and measures the round-trip time and the
+// upload throughput of the session. Cloudhub replaces the session of the node with the new one, so the
+// session is only opened when edgecore is not running unless forced
+func checkSession(report *connectionReport, edgeconfig *v1alpha2.EdgeCoreConfig, roots *x509.CertPool, cert *tls.Certificate, force bool, timeout time.Duration) {
+	const check = "cloudhub session"
+	if roots == nil || cert == nil {
+		report.add(check, resultSkip, "the node certificate is not available")
+		return
+	}
+	if !force {
+		// the session is not opened if it is unknown whether edgecore is running
+		running, err := util.GetOSInterface().IsKubeEdgeProcessRunning(util.KubeEdgeBinaryName)
+		if err != nil {
+			report.add(check, resultSkip, fmt.Sprintf("failed to check whether edgecore is running: %v, use --force to open the session anyway", err))
+			return
+		}
+		if running {
+			report.add(check, resultSkip, "edgecore is running and its session would be replaced, stop edgecore or use --force")
+			return
+		}
+	}
+
+	edgehub := edgeconfig.Modules.EdgeHub
+	nodeName := edgeconfig.Modules.Edged.HostnameOverride
+	header := http.Header{}
+	header.Set("node_id", nodeName)
+	header.Set("project_id", edgehub.ProjectID)
+
+	var sessionClient *client.Client
+	switch {
+	case edgehub.WebSocket != nil && edgehub.WebSocket.Enable:
+		sessionClient = &client.Client{
+			Options: client.Options{
+				Type:             api.ProtocolTypeWS,
+				Addr:             strings.Join([]string{"wss:/", edgehub.WebSocket.Server, edgehub.ProjectID, nodeName, "events"}, "/"),
+				ConnUse:          api.UseTypeMessage,
+				HandshakeTimeout: timeout,
+				TLSConfig:        &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{*cert}},
+			},
+			ExOpts: api.WSClientOption{Header: header},
+		}
+	case edgehub.Quic != nil && edgehub.Quic.Enable:
+		sessionClient = &client.Client{
+			Options: client.Options{
+				Type:             api.ProtocolTypeQuic,
+				Addr:             edgehub.Quic.Server,
+				HandshakeTimeout: timeout,
+				// the same as edgehub, which does not verify the certificate of cloudhub over quic
+				TLSConfig: &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{*cert}, InsecureSkipVerify: true}, // #nosec G402
+			},
+			ExOpts: api.QuicClientOption{Header: header},
+		}
+	default:
+		report.add(check, resultFail, "websocket and quic are both disabled")
+		return
+	}
+
+	start := time.Now()
+	connection, err := sessionClient.Connect()
+	if err != nil {
+		report.add(check, resultFail, fmt.Sprintf("%s handshake with %s failed: %v", sessionClient.Type, sessionClient.Addr, err))
+		return
+	}
+	defer connection.Close()
+	report.add(check, resultPass, fmt.Sprintf("%s handshake with %s in %v",
+		sessionClient.Type, sessionClient.Addr, time.Since(start).Round(time.Millisecond)))
+
+	// cloudhub answers the ping of viaduct over websocket only, quic delivers it to cloudhub as a message
+	isWebSocket := sessionClient.Type == api.ProtocolTypeWS
+	if isWebSocket {
+		result, detail := pingResult(connection, timeout)
+		report.add("round-trip time", result, detail)
+	} else {
+		report.add("round-trip time", resultSkip, "ping is not supported over quic")
+	}
+	result, detail := throughputResult(connection, isWebSocket, timeout)
+	report.add("upload throughput", result, detail)
+}
+
+// ping sends a ping of viaduct, which is answered by the connection of cloudhub without processing
+func ping(connection conn.Connection, timeout time.Duration) (time.Duration, error) {
+	msg := model.NewMessage("").BuildRouter(modules.EdgeHubModuleName, "", comm.ControlActionPing, comm.ControlTypePing)
+	if err := connection.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	if _, err := connection.WriteMessageSync(msg); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+func pingResult(connection conn.Connection, timeout time.Duration) (string, string) {
+	var rtts []time.Duration
+	var lastErr error
+	for i := 0; i < pingCount; i++ {
+		rtt, err := ping(connection, timeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		rtts = append(rtts, rtt)
+	}
+	if len(rtts) == 0 {
+		return resultFail, fmt.Sprintf("no response of %d pings: %v", pingCount, lastErr)
+	}
+
+	minRTT, maxRTT, sum := rtts[0], rtts[0], time.Duration(0)
+	for _, rtt := range rtts {
+		if rtt < minRTT {
+			minRTT = rtt
+		}
+		if rtt > maxRTT {
+			maxRTT = rtt
+		}
+		sum += rtt
+	}
+	detail := fmt.Sprintf("min/avg/max %v/%v/%v, %d of %d pings lost", minRTT.Round(time.Microsecond),
+		(sum / time.Duration(len(rtts))).Round(time.Microsecond), maxRTT.Round(time.Microsecond), pingCount-len(rtts), pingCount)
+	if len(rtts) < pingCount {
+		return resultWarn, detail
+	}
+	return resultPass, detail
+}
+
+// throughputResult sends keepalive messages, which cloudhub only counts, and measures the rate.
+// Over websocket, a ping after the messages is answered after cloudhub has read all of them
+func throughputResult(connection conn.Connection, isWebSocket bool, timeout time.Duration) (string, string) {
+	payload := strings.Repeat("k", throughputMessageSize)
+	start := time.Now()
+	for i := 0; i < throughputMessageCount; i++ {
+		msg := model.NewMessage("").
+			BuildRouter(modules.EdgeHubModuleName, "resource", "node", messagepkg.OperationKeepalive).
+			FillBody(payload)
+		if err := connection.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			return resultFail, err.Error()
+		}
+		if err := connection.WriteMessageAsync(msg); err != nil {
+			return resultFail, fmt.Sprintf("failed to send message: %v", err)
+		}
+	}
+	note := "sent"
+	if isWebSocket {
+		if _, err := ping(connection, timeout); err != nil {
+			return resultFail, fmt.Sprintf("no response after the messages: %v", err)
+		}
+		note = "received by cloudhub"
+	}
+	elapsed := time.Since(start)
+
+	size := throughputMessageSize * throughputMessageCount
+	return resultPass, fmt.Sprintf("%.2f Mbit/s, %d KiB %s in %v", float64(size*8)/elapsed.Seconds()/1e6,
+		size/1024, note, elapsed.Round(time.Millisecond))
+}
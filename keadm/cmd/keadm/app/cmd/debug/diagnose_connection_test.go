@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCert(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, template *x509.Certificate) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	now := time.Now()
+	ca, caKey := newTestCert(t, nil, nil, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "KubeEdge"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(10 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	other, _ := newTestCert(t, nil, nil, &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "other"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	server, _ := newTestCert(t, ca, caKey, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "cloudcore"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(365 * 24 * time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("192.168.1.10")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(other)
+
+	cases := []struct {
+		name   string
+		roots  *x509.CertPool
+		host   string
+		now    time.Time
+		result string
+		detail string
+	}{
+		{"valid", roots, "192.168.1.10", now, resultPass, "SANs 192.168.1.10, expires in 36"},
+		{"expiring", roots, "192.168.1.10", now.Add(350 * 24 * time.Hour), resultWarn, "expires in 1"},
+		{"expired", roots, "192.168.1.10", now.Add(400 * 24 * time.Hour), resultFail, "expired at"},
+		{"host not in SANs", roots, "cloudcore.example.com", now, resultFail, "cloudcore.example.com is not in the SANs 192.168.1.10"},
+		{"untrusted", otherRoots, "192.168.1.10", now, resultFail, "not trusted by the CA"},
+	}
+	for _, c := range cases {
+		result, detail := verifyCertificate([]*x509.Certificate{server}, c.roots, c.host, x509.ExtKeyUsageServerAuth, c.now)
+		if result != c.result || !strings.Contains(detail, c.detail) {
+			t.Errorf("%s: expected %s %q, but got %s %q", c.name, c.result, c.detail, result, detail)
+		}
+	}
+}
+
+func TestCheckTLSEndpoint(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/edge.crt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	report := &connectionReport{}
+	checkTLSEndpoint(report, "cloudhub /edge.crt", server.Listener.Addr().String(), "/edge.crt", roots, nil, time.Second)
+	if len(report.items) != 2 || report.failed() != 0 {
+		t.Fatalf("expected the certificate and the endpoint passed, but got %+v", report.items)
+	}
+	if !strings.Contains(report.items[1].detail, "401 Unauthorized") {
+		t.Errorf("expected the response of the endpoint reported, but got %q", report.items[1].detail)
+	}
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	report = &connectionReport{}
+	checkTLSEndpoint(report, "cloudhub /edge.crt", "localhost:"+port, "/edge.crt", roots, nil, time.Second)
+	if len(report.items) != 2 || report.items[0].result != resultFail || report.items[1].result != resultPass {
+		t.Errorf("expected the certificate without localhost in SANs failed, but got %+v", report.items)
+	}
+
+	report = &connectionReport{}
+	checkTLSEndpoint(report, "cloudhub /edge.crt", "127.0.0.1:1", "/edge.crt", roots, nil, time.Second)
+	if report.failed() != 1 {
+		t.Errorf("expected the unreachable endpoint failed, but got %+v", report.items)
+	}
+}
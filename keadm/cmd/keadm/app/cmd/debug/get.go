@@ -38,6 +38,7 @@ import (
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
 	edgecoreCfg "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
 )
@@ -62,28 +63,41 @@ keadm debug get configmap web -n default
 # List the complete information of the configmap with the specified name in the yaml output format
 keadm debug get configmap web -n default -o yaml
 # List the complete information of all available resources of edge nodes using the specified format (default: yaml)
-keadm debug get all -o yaml`
+keadm debug get all -o yaml
+# List the desired and reported values of the twins of the device with the specified name
+keadm debug get devicetwin sensor-01 -o wide
+# List the mappers registered to EdgeCore
+keadm debug get mapper`
 
 	// availableResources Convert flag to currently supports available Resource types in EdgeCore database.
 	availableResources = map[string]string{
-		"all":        ResourceTypeAll,
-		"po":         model.ResourceTypePod,
-		"pod":        model.ResourceTypePod,
-		"pods":       model.ResourceTypePod,
-		"no":         model.ResourceTypeNode,
-		"node":       model.ResourceTypeNode,
-		"nodes":      model.ResourceTypeNode,
-		"svc":        constants.ResourceTypeService,
-		"service":    constants.ResourceTypeService,
-		"services":   constants.ResourceTypeService,
-		"secret":     model.ResourceTypeSecret,
-		"secrets":    model.ResourceTypeSecret,
-		"cm":         model.ResourceTypeConfigmap,
-		"configmap":  model.ResourceTypeConfigmap,
-		"configmaps": model.ResourceTypeConfigmap,
-		"ep":         constants.ResourceTypeEndpoints,
-		"endpoint":   constants.ResourceTypeEndpoints,
-		"endpoints":  constants.ResourceTypeEndpoints,
+		"all":         ResourceTypeAll,
+		"po":          model.ResourceTypePod,
+		"pod":         model.ResourceTypePod,
+		"pods":        model.ResourceTypePod,
+		"no":          model.ResourceTypeNode,
+		"node":        model.ResourceTypeNode,
+		"nodes":       model.ResourceTypeNode,
+		"svc":         constants.ResourceTypeService,
+		"service":     constants.ResourceTypeService,
+		"services":    constants.ResourceTypeService,
+		"secret":      model.ResourceTypeSecret,
+		"secrets":     model.ResourceTypeSecret,
+		"cm":          model.ResourceTypeConfigmap,
+		"configmap":   model.ResourceTypeConfigmap,
+		"configmaps":  model.ResourceTypeConfigmap,
+		"ep":          constants.ResourceTypeEndpoints,
+		"endpoint":    constants.ResourceTypeEndpoints,
+		"endpoints":   constants.ResourceTypeEndpoints,
+		"dev":         ResourceTypeDevice,
+		"device":      ResourceTypeDevice,
+		"devices":     ResourceTypeDevice,
+		"twin":        ResourceTypeDeviceTwin,
+		"twins":       ResourceTypeDeviceTwin,
+		"devicetwin":  ResourceTypeDeviceTwin,
+		"devicetwins": ResourceTypeDeviceTwin,
+		"mapper":      ResourceTypeMapper,
+		"mappers":     ResourceTypeMapper,
 	}
 )
 
@@ -168,6 +182,9 @@ type GetOptions struct {
 func (g *GetOptions) Run(args []string) error {
 	resType := args[0]
 	resNames := args[1:]
+	if isDeviceResource(availableResources[resType]) {
+		return g.runDevice(availableResources[resType], resNames)
+	}
 	results, err := g.queryDataFromDatabase(availableResources[resType], resNames)
 	if err != nil {
 		return err
@@ -244,6 +261,9 @@ func (g *GetOptions) Validate(args []string) error {
 	if args[0] == ResourceTypeAll && len(args) >= 2 {
 		return fmt.Errorf("you must specify only one resource. ")
 	}
+	if isDeviceResource(availableResources[args[0]]) && len(g.LabelSelector) > 0 {
+		return fmt.Errorf("label selector is not supported by resource type: %v. ", args[0])
+	}
 
 	return nil
 }
@@ -464,7 +484,7 @@ func InitDB(driverName, dbName, dataSource string) error {
 		dataSource); err != nil {
 		return fmt.Errorf("failed to register db: %v ", err)
 	}
-	orm.RegisterModel(new(dao.Meta))
+	orm.RegisterModel(new(dao.Meta), new(dtclient.Device), new(dtclient.DeviceAttr), new(dtclient.DeviceTwin))
 
 	// create orm
 	dbm.DBAccess = orm.NewOrm()
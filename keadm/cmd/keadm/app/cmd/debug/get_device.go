@@ -0,0 +1,335 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/yaml"
+
+	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dttype"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	pb "github.com/kubeedge/kubeedge/pkg/apis/dmi/v1beta1"
+)
+
+const (
+	// ResourceTypeDevice defines resource type device
+	ResourceTypeDevice = "device"
+	// ResourceTypeDeviceTwin defines resource type devicetwin
+	ResourceTypeDeviceTwin = "devicetwin"
+	// ResourceTypeMapper defines resource type mapper
+	ResourceTypeMapper = deviceconst.ResourceTypeDeviceMapper
+)
+
+// DeviceInfo is a device with its attributes and twins saved by devicetwin
+type DeviceInfo struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	State       string            `json:"state,omitempty"`
+	LastOnline  string            `json:"lastOnline,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	Twins       []TwinInfo        `json:"twins,omitempty"`
+}
+
+// TwinInfo is the desired and the reported value of a device twin
+type TwinInfo struct {
+	Device             string              `json:"device"`
+	Property           string              `json:"property"`
+	Type               string              `json:"type,omitempty"`
+	Desired            string              `json:"desired"`
+	Reported           string              `json:"reported"`
+	Synced             bool                `json:"synced"`
+	DesiredUpdateTime  string              `json:"desiredUpdateTime,omitempty"`
+	ReportedUpdateTime string              `json:"reportedUpdateTime,omitempty"`
+	DesiredVersion     *dttype.TwinVersion `json:"desiredVersion,omitempty"`
+	ReportedVersion    *dttype.TwinVersion `json:"reportedVersion,omitempty"`
+
+	// lastUpdate is the latest update time of the desired and the reported value
+	lastUpdate time.Time
+}
+
+// isDeviceResource returns whether the resource type is saved by devicetwin rather than metamanager
+func isDeviceResource(resType string) bool {
+	return resType == ResourceTypeDevice || resType == ResourceTypeDeviceTwin || resType == ResourceTypeMapper
+}
+
+// runDevice prints the devices, the device twins or the mappers saved in the edge database
+func (g *GetOptions) runDevice(resType string, resNames []string) error {
+	var results interface{}
+	var count int
+	var err error
+	switch resType {
+	case ResourceTypeDevice:
+		var devices []DeviceInfo
+		devices, err = getDevicesFromDatabase(resNames)
+		results, count = devices, len(devices)
+	case ResourceTypeDeviceTwin:
+		var devices []DeviceInfo
+		devices, err = getDevicesFromDatabase(resNames)
+		var twins []TwinInfo
+		for _, device := range devices {
+			twins = append(twins, device.Twins...)
+		}
+		results, count = twins, len(twins)
+	case ResourceTypeMapper:
+		var mappers []*pb.MapperInfo
+		mappers, err = getMappersFromDatabase(resNames)
+		results, count = mappers, len(mappers)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch *g.PrintFlags.OutputFormat {
+	case "json":
+		data, err := json.MarshalIndent(results, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	case "yaml":
+		data, err := yaml.Marshal(results)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if count == 0 {
+		_, err := fmt.Println("No resources found.")
+		return err
+	}
+	w := printers.GetNewTabWriter(os.Stdout)
+	defer w.Flush()
+	wide := *g.PrintFlags.OutputFormat == FormatTypeWIDE
+	noHeaders := *g.PrintFlags.NoHeaders
+	switch resType {
+	case ResourceTypeDevice:
+		printDevices(w, results.([]DeviceInfo), wide, noHeaders)
+	case ResourceTypeDeviceTwin:
+		printTwins(w, results.([]TwinInfo), wide, noHeaders)
+	case ResourceTypeMapper:
+		printMappers(w, results.([]*pb.MapperInfo), wide, noHeaders)
+	}
+	return nil
+}
+
+// getDevicesFromDatabase returns the devices whose id or name contains one of resNames,
+// or all the devices if resNames is empty
+func getDevicesFromDatabase(resNames []string) ([]DeviceInfo, error) {
+	devices, err := dtclient.QueryDeviceAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query devices: %v", err)
+	}
+
+	var results []DeviceInfo
+	for _, device := range *devices {
+		if len(resNames) > 0 && !isExistName(resNames, device.ID) && !isExistName(resNames, device.Name) {
+			continue
+		}
+		info := DeviceInfo{
+			ID:          device.ID,
+			Name:        device.Name,
+			Description: device.Description,
+			State:       device.State,
+			LastOnline:  device.LastOnline,
+		}
+
+		attrs, err := dtclient.QueryDeviceAttr("deviceid", device.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query attributes of device %s: %v", device.ID, err)
+		}
+		if len(*attrs) > 0 {
+			info.Attributes = make(map[string]string, len(*attrs))
+			for _, attr := range *attrs {
+				info.Attributes[attr.Name] = attr.Value
+			}
+		}
+
+		twins, err := dtclient.QueryDeviceTwin("deviceid", device.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query twins of device %s: %v", device.ID, err)
+		}
+		for _, twin := range *twins {
+			info.Twins = append(info.Twins, newTwinInfo(device.Name, twin))
+		}
+		sort.Slice(info.Twins, func(i, j int) bool { return info.Twins[i].Property < info.Twins[j].Property })
+		results = append(results, info)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results, nil
+}
+
+// newTwinInfo converts the twin saved by devicetwin, whose metas and versions are JSON strings
+func newTwinInfo(device string, twin dtclient.DeviceTwin) TwinInfo {
+	info := TwinInfo{
+		Device:   device,
+		Property: twin.Name,
+		Type:     twin.AttrType,
+		Desired:  twin.Expected,
+		Reported: twin.Actual,
+		// a twin without desired value is only reported by the device
+		Synced: twin.Expected == "" || twin.Expected == twin.Actual,
+	}
+
+	desiredUpdate := parseValueTime(twin.ExpectedMeta)
+	reportedUpdate := parseValueTime(twin.ActualMeta)
+	if !desiredUpdate.IsZero() {
+		info.DesiredUpdateTime = desiredUpdate.Format(time.RFC3339)
+	}
+	if !reportedUpdate.IsZero() {
+		info.ReportedUpdateTime = reportedUpdate.Format(time.RFC3339)
+	}
+	info.lastUpdate = desiredUpdate
+	if reportedUpdate.After(desiredUpdate) {
+		info.lastUpdate = reportedUpdate
+	}
+
+	info.DesiredVersion = parseTwinVersion(twin.ExpectedVersion)
+	info.ReportedVersion = parseTwinVersion(twin.ActualVersion)
+	return info
+}
+
+// parseValueTime returns the time of the value meta, whose timestamp is in milliseconds
+func parseValueTime(meta string) time.Time {
+	var valueMeta dttype.ValueMetadata
+	if meta == "" || json.Unmarshal([]byte(meta), &valueMeta) != nil || valueMeta.Timestamp == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(valueMeta.Timestamp)
+}
+
+func parseTwinVersion(version string) *dttype.TwinVersion {
+	if version == "" {
+		return nil
+	}
+	twinVersion := &dttype.TwinVersion{}
+	if err := json.Unmarshal([]byte(version), twinVersion); err != nil {
+		return nil
+	}
+	return twinVersion
+}
+
+// getMappersFromDatabase returns the mappers registered to edgecore whose name contains one of resNames,
+// or all the mappers if resNames is empty
+func getMappersFromDatabase(resNames []string) ([]*pb.MapperInfo, error) {
+	metas, err := dao.QueryAllMeta("type", ResourceTypeMapper)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mappers: %v", err)
+	}
+
+	var results []*pb.MapperInfo
+	for _, meta := range *metas {
+		mapper := &pb.MapperInfo{}
+		if err := json.Unmarshal([]byte(meta.Value), mapper); err != nil {
+			return nil, fmt.Errorf("failed to parse mapper %s: %v", meta.Key, err)
+		}
+		if len(resNames) > 0 && !isExistName(resNames, mapper.Name) {
+			continue
+		}
+		results = append(results, mapper)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results, nil
+}
+
+func printDevices(w io.Writer, devices []DeviceInfo, wide, noHeaders bool) {
+	if !noHeaders {
+		headers := []string{"NAME", "STATE", "LAST ONLINE", "ATTRIBUTES", "TWINS"}
+		if wide {
+			headers = append(headers, "ID", "DESCRIPTION")
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+	for _, device := range devices {
+		row := []string{device.Name, valueOrNone(device.State), valueOrNone(device.LastOnline),
+			fmt.Sprint(len(device.Attributes)), fmt.Sprint(len(device.Twins))}
+		if wide {
+			row = append(row, device.ID, valueOrNone(device.Description))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+}
+
+func printTwins(w io.Writer, twins []TwinInfo, wide, noHeaders bool) {
+	if !noHeaders {
+		headers := []string{"DEVICE", "PROPERTY", "DESIRED", "REPORTED", "SYNCED", "LAST UPDATE"}
+		if wide {
+			headers = append(headers, "TYPE", "DESIRED UPDATE", "REPORTED UPDATE", "DESIRED VERSION", "REPORTED VERSION")
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+	for _, twin := range twins {
+		lastUpdate := "<none>"
+		if !twin.lastUpdate.IsZero() {
+			lastUpdate = twin.lastUpdate.Format(time.RFC3339)
+		}
+		synced := "Yes"
+		if !twin.Synced {
+			synced = "No"
+		}
+		row := []string{twin.Device, twin.Property, valueOrNone(twin.Desired), valueOrNone(twin.Reported), synced, lastUpdate}
+		if wide {
+			row = append(row, valueOrNone(twin.Type), valueOrNone(twin.DesiredUpdateTime), valueOrNone(twin.ReportedUpdateTime),
+				formatTwinVersion(twin.DesiredVersion), formatTwinVersion(twin.ReportedVersion))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+}
+
+func printMappers(w io.Writer, mappers []*pb.MapperInfo, wide, noHeaders bool) {
+	if !noHeaders {
+		headers := []string{"NAME", "PROTOCOL", "VERSION", "STATE"}
+		if wide {
+			headers = append(headers, "API VERSION", "ADDRESS")
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+	for _, mapper := range mappers {
+		row := []string{mapper.Name, valueOrNone(mapper.Protocol), valueOrNone(mapper.Version), valueOrNone(mapper.State)}
+		if wide {
+			row = append(row, valueOrNone(mapper.ApiVersion), valueOrNone(string(mapper.Address)))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+}
+
+func formatTwinVersion(version *dttype.TwinVersion) string {
+	if version == nil {
+		return "<none>"
+	}
+	return fmt.Sprintf("cloud=%d,edge=%d", version.CloudVersion, version.EdgeVersion)
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -16,6 +16,10 @@ package debug
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dttype"
 )
 
 func TestSplitSelectorParameters(t *testing.T) {
@@ -67,3 +71,40 @@ func TestSplitSelectorParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTwinInfo(t *testing.T) {
+	twin := dtclient.DeviceTwin{
+		DeviceID:        "sensor-01",
+		Name:            "temperature",
+		Expected:        "25",
+		Actual:          "23",
+		ExpectedMeta:    `{"timestamp":1690000000000}`,
+		ActualMeta:      `{"timestamp":1690000060000}`,
+		ExpectedVersion: `{"cloud":2,"edge":0}`,
+		ActualVersion:   `{"cloud":0,"edge":5}`,
+		AttrType:        "int",
+	}
+
+	info := newTwinInfo("sensor", twin)
+	if info.Device != "sensor" || info.Property != "temperature" || info.Type != "int" {
+		t.Errorf("unexpected twin %+v", info)
+	}
+	if info.Synced {
+		t.Errorf("expected twin with different desired and reported value not synced")
+	}
+	if !info.lastUpdate.Equal(time.UnixMilli(1690000060000)) {
+		t.Errorf("expected last update at the reported time, but got %v", info.lastUpdate)
+	}
+	if info.DesiredUpdateTime != time.UnixMilli(1690000000000).Format(time.RFC3339) {
+		t.Errorf("unexpected desired update time %s", info.DesiredUpdateTime)
+	}
+	if !reflect.DeepEqual(info.ReportedVersion, &dttype.TwinVersion{EdgeVersion: 5}) {
+		t.Errorf("unexpected reported version %+v", info.ReportedVersion)
+	}
+
+	twin.Expected, twin.ExpectedMeta, twin.ExpectedVersion = "", "", ""
+	info = newTwinInfo("sensor", twin)
+	if !info.Synced || info.DesiredUpdateTime != "" || info.DesiredVersion != nil {
+		t.Errorf("expected twin without desired value synced, but got %+v", info)
+	}
+}
@@ -0,0 +1,350 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edge
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	// register the driver of the edgecore database
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/cobra"
+
+	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+var (
+	edgeBackupLongDescription = `
+"keadm backup" command saves the identity and the state of the edge node into a single archive,
+which is signed by the private key of the node. It includes the edgecore configuration,
+the certificates, the metadata database and the mapper registrations.
+The archive contains the private key of the node, so keep it safe.
+`
+	edgeBackupExample = `
+keadm backup --output /tmp/edge-node-1.tar.gz
+`
+)
+
+const (
+	// backupFormatVersion is the version of the layout of the backup archive
+	backupFormatVersion = 1
+
+	backupManifestName  = "manifest.json"
+	backupSignatureName = "manifest.sig"
+	backupFilesDir      = "files/"
+
+	// the names of the files in the backup archive
+	backupFileConfig     = "edgecore.yaml"
+	backupFileCA         = "ca.crt"
+	backupFileCert       = "server.crt"
+	backupFileKey        = "server.key"
+	backupFileTunnelCA   = "tunnel-ca.crt"
+	backupFileTunnelCert = "tunnel-server.crt"
+	backupFileTunnelKey  = "tunnel-server.key"
+	backupFileDatabase   = "edgecore.db"
+)
+
+// BackupManifest describes the files in the backup archive, it is signed by the private key of the node
+type BackupManifest struct {
+	Version   int       `json:"version"`
+	NodeName  string    `json:"nodeName"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Files are the files of the node, restored to their paths
+	Files []BackupFile `json:"files"`
+	// Mappers are the mappers registered to edgecore, which are restored with the database
+	Mappers []json.RawMessage `json:"mappers,omitempty"`
+}
+
+// BackupFile is a file of the node in the backup archive
+type BackupFile struct {
+	Name   string      `json:"name"`
+	Path   string      `json:"path"`
+	Mode   os.FileMode `json:"mode"`
+	SHA256 string      `json:"sha256"`
+}
+
+// NewEdgeBackup returns KubeEdge edge backup command.
+func NewEdgeBackup() *cobra.Command {
+	backupOptions := newBackupOptions()
+	cmd := &cobra.Command{
+		Use:          "backup",
+		Short:        "Backup the identity and the state of the edge node into a signed archive",
+		Long:         edgeBackupLongDescription,
+		Example:      edgeBackupExample,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := backup(backupOptions.Config, backupOptions.Output)
+			if err != nil {
+				return fmt.Errorf("edge node backup failed: %v", err)
+			}
+			fmt.Printf("Backup of node %s with %d files and %d mappers is saved to %s\n",
+				manifest.NodeName, len(manifest.Files), len(manifest.Mappers), backupOptions.Output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&backupOptions.Config, common.EdgecoreConfig, "c", backupOptions.Config,
+		fmt.Sprintf("Specify configuration file, default is %s", common.EdgecoreConfigPath))
+	cmd.Flags().StringVarP(&backupOptions.Output, "output", "o", backupOptions.Output,
+		"The path of the backup archive")
+	return cmd
+}
+
+func newBackupOptions() *common.BackupOptions {
+	return &common.BackupOptions{
+		Config: common.EdgecoreConfigPath,
+		Output: "kubeedge-backup.tar.gz",
+	}
+}
+
+// backup saves the files of the node in the edgecore config into the archive at output
+func backup(config, output string) (*BackupManifest, error) {
+	edgeconfig, err := util.ParseEdgecoreConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("parse edgecore config %s failed: %v", config, err)
+	}
+	edgehub := edgeconfig.Modules.EdgeHub
+	keyPair, err := tls.LoadX509KeyPair(edgehub.TLSCertFile, edgehub.TLSPrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load the certificate of the node failed: %v", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "kubeedge-backup")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// the database is copied by sqlite, so it is consistent even if edgecore is writing it
+	dataSource := v1alpha2.DataBaseDataSource
+	if edgeconfig.DataBase != nil && edgeconfig.DataBase.DataSource != "" {
+		dataSource = edgeconfig.DataBase.DataSource
+	}
+	snapshot := filepath.Join(tmpDir, backupFileDatabase)
+	mappers, err := snapshotDatabase(dataSource, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := []struct {
+		name, path, source string
+	}{
+		// edgecore is always started with the default config after restore
+		{backupFileConfig, common.EdgecoreConfigPath, config},
+		{backupFileCA, edgehub.TLSCAFile, edgehub.TLSCAFile},
+		{backupFileCert, edgehub.TLSCertFile, edgehub.TLSCertFile},
+		{backupFileKey, edgehub.TLSPrivateKeyFile, edgehub.TLSPrivateKeyFile},
+		{backupFileDatabase, dataSource, snapshot},
+	}
+	if stream := edgeconfig.Modules.EdgeStream; stream != nil && stream.Enable {
+		sources = append(sources, []struct {
+			name, path, source string
+		}{
+			{backupFileTunnelCA, stream.TLSTunnelCAFile, stream.TLSTunnelCAFile},
+			{backupFileTunnelCert, stream.TLSTunnelCertFile, stream.TLSTunnelCertFile},
+			{backupFileTunnelKey, stream.TLSTunnelPrivateKeyFile, stream.TLSTunnelPrivateKeyFile},
+		}...)
+	}
+
+	manifest := &BackupManifest{
+		Version:   backupFormatVersion,
+		NodeName:  edgeconfig.Modules.Edged.HostnameOverride,
+		CreatedAt: time.Now().UTC(),
+		Mappers:   mappers,
+	}
+	files := make(map[string]string)
+	saved := make(map[string]bool)
+	for _, source := range sources {
+		// the tunnel certificates are the same files as the certificates of edgehub by default
+		if saved[source.path] {
+			continue
+		}
+		saved[source.path] = true
+
+		info, err := os.Stat(source.source)
+		if err != nil {
+			return nil, fmt.Errorf("stat %s failed: %v", source.source, err)
+		}
+		sum, err := fileSHA256(source.source)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, BackupFile{
+			Name:   source.name,
+			Path:   source.path,
+			Mode:   info.Mode().Perm(),
+			SHA256: sum,
+		})
+		files[source.name] = source.source
+	}
+
+	if err := writeBackupArchive(output, manifest, files, keyPair.PrivateKey); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// snapshotDatabase copies the database to path, and returns the mappers registered in it
+func snapshotDatabase(dataSource, path string) ([]json.RawMessage, error) {
+	// sql.Open creates the database if it does not exist
+	if !util.FileExists(dataSource) {
+		return nil, fmt.Errorf("database %s does not exist", dataSource)
+	}
+	db, err := sql.Open(v1alpha2.DataBaseDriverName, dataSource)
+	if err != nil {
+		return nil, fmt.Errorf("open database %s failed: %v", dataSource, err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("VACUUM INTO ?", path); err != nil {
+		return nil, fmt.Errorf("copy database %s failed: %v", dataSource, err)
+	}
+
+	rows, err := db.Query("SELECT value FROM meta WHERE type = ?", deviceconst.ResourceTypeDeviceMapper)
+	if err != nil {
+		return nil, fmt.Errorf("query mappers failed: %v", err)
+	}
+	defer rows.Close()
+	var mappers []json.RawMessage
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		if !json.Valid([]byte(value)) {
+			return nil, fmt.Errorf("invalid mapper registration %q", value)
+		}
+		mappers = append(mappers, json.RawMessage(value))
+	}
+	return mappers, rows.Err()
+}
+
+// writeBackupArchive writes the manifest, its signature by the key and the files into a gzipped tarball.
+// files maps the names in the manifest to the files to write
+func writeBackupArchive(output string, manifest *BackupManifest, files map[string]string, key crypto.PrivateKey) error {
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	signature, err := signBackupManifest(manifestData, key)
+	if err != nil {
+		return err
+	}
+
+	// the archive contains the private key of the node
+	out, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("create archive %s failed: %v", output, err)
+	}
+	defer out.Close()
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	writeEntry := func(name string, size int64, content io.Reader) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    size,
+			ModTime: manifest.CreatedAt,
+		}); err != nil {
+			return err
+		}
+		_, err := io.Copy(tw, content)
+		return err
+	}
+	if err := writeEntry(backupManifestName, int64(len(manifestData)), bytes.NewReader(manifestData)); err != nil {
+		return err
+	}
+	if err := writeEntry(backupSignatureName, int64(len(signature)), bytes.NewReader(signature)); err != nil {
+		return err
+	}
+	for _, file := range manifest.Files {
+		f, err := os.Open(files[file.Name])
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		if err == nil {
+			err = writeEntry(backupFilesDir+file.Name, info.Size(), f)
+		}
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("write %s into archive failed: %v", file.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func signBackupManifest(data []byte, key crypto.PrivateKey) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	digest := sha256.Sum256(data)
+	return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// verifyBackupManifest verifies the signature of the manifest by the certificate of the node
+func verifyBackupManifest(data, signature []byte, cert *x509.Certificate) error {
+	var algorithm x509.SignatureAlgorithm
+	switch cert.PublicKeyAlgorithm {
+	case x509.ECDSA:
+		algorithm = x509.ECDSAWithSHA256
+	case x509.RSA:
+		algorithm = x509.SHA256WithRSA
+	default:
+		return fmt.Errorf("unsupported public key algorithm %v", cert.PublicKeyAlgorithm)
+	}
+	return cert.CheckSignature(algorithm, data, signature)
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("read %s failed: %v", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,408 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edge
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestNode creates the certificates, the database and the edgecore config of a node in dir,
+// and returns the path of the config
+func newTestNode(t *testing.T, dir string) string {
+	now := time.Now()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "KubeEdge"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "edge-node"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}, caTemplate, &nodeKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(nodeKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writePEM := func(name, blockType string, der []byte) {
+		data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writePEM("rootCA.crt", "CERTIFICATE", caDER)
+	writePEM("server.crt", "CERTIFICATE", nodeDER)
+	writePEM("server.key", "EC PRIVATE KEY", keyDER)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "edgecore.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		"CREATE TABLE meta (key TEXT PRIMARY KEY, type TEXT, value TEXT)",
+		`INSERT INTO meta VALUES ('modbus', 'devicemapper', '{"name":"modbus","protocol":"modbus"}')`,
+		`INSERT INTO meta VALUES ('default/pod/nginx', 'pod', '{}')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := filepath.Join(dir, "edgecore.yaml")
+	data := fmt.Sprintf(`apiVersion: edgecore.config.kubeedge.io/v1alpha2
+kind: EdgeCore
+database:
+  dataSource: %[1]s/edgecore.db
+modules:
+  edged:
+    hostnameOverride: edge-node
+  edgeHub:
+    tlsCaFile: %[1]s/rootCA.crt
+    tlsCertFile: %[1]s/server.crt
+    tlsPrivateKeyFile: %[1]s/server.key
+  edgeStream:
+    enable: false
+`, dir)
+	if err := os.WriteFile(config, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	config := newTestNode(t, dir)
+	output := filepath.Join(dir, "backup.tar.gz")
+
+	manifest, err := backup(config, output)
+	if err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	if manifest.NodeName != "edge-node" || len(manifest.Files) != 5 {
+		t.Errorf("expected 5 files of node edge-node, but got %s %+v", manifest.NodeName, manifest.Files)
+	}
+	if len(manifest.Mappers) != 1 || !strings.Contains(string(manifest.Mappers[0]), `"modbus"`) {
+		t.Errorf("expected the modbus mapper, but got %s", manifest.Mappers)
+	}
+	info, err := os.Stat(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the archive is only readable by the owner, but got %v", info.Mode().Perm())
+	}
+
+	archive, err := readBackupArchive(output)
+	if err != nil {
+		t.Fatalf("read archive failed: %v", err)
+	}
+	if err := archive.verify(time.Now()); err != nil {
+		t.Fatalf("verify archive failed: %v", err)
+	}
+	if err := archive.verify(time.Now().Add(2 * time.Hour)); err == nil {
+		t.Errorf("expected the expired node certificate is rejected")
+	}
+
+	// the files are restored to their paths
+	restoreDir := t.TempDir()
+	for _, file := range archive.manifest.Files {
+		path := filepath.Join(restoreDir, filepath.Base(file.Path))
+		// restoring twice leaves the same files
+		for i := 0; i < 2; i++ {
+			if err := writeFileAtomic(path, archive.files[file.Name], file.Mode); err != nil {
+				t.Fatalf("restore %s failed: %v", file.Name, err)
+			}
+		}
+		sum, err := fileSHA256(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sum != file.SHA256 {
+			t.Errorf("expected %s restored with checksum %s, but got %s", file.Name, file.SHA256, sum)
+		}
+	}
+	entries, err := os.ReadDir(restoreDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(archive.manifest.Files) {
+		t.Errorf("expected no temporary files left, but got %d entries", len(entries))
+	}
+}
+
+func TestBackupTampered(t *testing.T) {
+	dir := t.TempDir()
+	config := newTestNode(t, dir)
+	archive, err := func() (*backupArchive, error) {
+		output := filepath.Join(dir, "backup.tar.gz")
+		if _, err := backup(config, output); err != nil {
+			return nil, err
+		}
+		return readBackupArchive(output)
+	}()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		tamper func(a *backupArchive)
+		err    string
+	}{
+		{
+			name: "file modified",
+			tamper: func(a *backupArchive) {
+				a.files[backupFileConfig] = append([]byte("# modified\n"), a.files[backupFileConfig]...)
+			},
+			err: "checksum of edgecore.yaml mismatches",
+		},
+		{
+			name: "manifest modified",
+			tamper: func(a *backupArchive) {
+				a.manifestData = []byte(strings.Replace(string(a.manifestData), "edge-node", "other-node", 1))
+			},
+			err: "invalid signature",
+		},
+		{
+			name: "file missing",
+			tamper: func(a *backupArchive) {
+				delete(a.files, backupFileDatabase)
+			},
+			err: "edgecore.db is missing",
+		},
+		{
+			name: "key of another node",
+			tamper: func(a *backupArchive) {
+				key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				der, _ := x509.MarshalECPrivateKey(key)
+				a.files[backupFileKey] = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+			},
+			err: "invalid node certificate",
+		},
+	}
+	for _, c := range cases {
+		tampered := &backupArchive{
+			manifest:     archive.manifest,
+			manifestData: archive.manifestData,
+			signature:    archive.signature,
+			files:        make(map[string][]byte),
+		}
+		for name, data := range archive.files {
+			tampered.files[name] = data
+		}
+		c.tamper(tampered)
+		err := tampered.verify(time.Now())
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: expected error %q, but got %v", c.name, c.err, err)
+		}
+	}
+}
+
+func TestRestoreTargetPaths(t *testing.T) {
+	config := []byte(`database:
+  dataSource: /var/lib/kubeedge/edgecore.db
+modules:
+  edged:
+    hostnameOverride: edge-node
+  edgeHub:
+    tlsCaFile: /etc/kubeedge/ca/rootCA.crt
+    tlsCertFile: /etc/kubeedge/certs/server.crt
+    tlsPrivateKeyFile: /etc/kubeedge/certs/server.key
+`)
+	files := []BackupFile{
+		{Name: backupFileConfig, Path: "/etc/kubeedge/config/edgecore.yaml"},
+		{Name: backupFileCA, Path: "/etc/kubeedge/ca/rootCA.crt"},
+		{Name: backupFileCert, Path: "/etc/kubeedge/certs/server.crt"},
+		{Name: backupFileKey, Path: "/etc/kubeedge/certs/server.key"},
+		{Name: backupFileDatabase, Path: "/var/lib/kubeedge/edgecore.db"},
+	}
+
+	cases := []struct {
+		name   string
+		config string
+		file   *BackupFile
+		err    string
+	}{
+		{name: "valid"},
+		{
+			name: "path not in config",
+			file: &BackupFile{Name: backupFileKey, Path: "/etc/cron.d/kubeedge"},
+			err:  "not the one in the edgecore config",
+		},
+		{
+			name: "unknown file",
+			file: &BackupFile{Name: "edgecore", Path: "/usr/local/bin/edgecore"},
+			err:  "not the one in the edgecore config",
+		},
+		{
+			name:   "certificate out of the certificate directories",
+			config: strings.Replace(string(config), "/etc/kubeedge/certs/server.key", "/etc/cron.d/kubeedge", 1),
+			err:    "server.key path /etc/cron.d/kubeedge is not in",
+		},
+		{
+			name:   "certificate escaping the certificate directories",
+			config: strings.Replace(string(config), "/etc/kubeedge/certs/server.key", "/etc/kubeedge/certs/../manifests/pod.yaml", 1),
+			err:    "is not in",
+		},
+		{
+			name:   "database out of the database directory",
+			config: strings.Replace(string(config), "/var/lib/kubeedge/edgecore.db", "/var/lib/kubeedge/edgemqtt/config/mosquitto.conf", 1),
+			err:    "database path",
+		},
+	}
+	for _, c := range cases {
+		archive := &backupArchive{files: map[string][]byte{backupFileConfig: config}}
+		if c.config != "" {
+			archive.files[backupFileConfig] = []byte(c.config)
+		}
+		archive.manifest.Files = append(archive.manifest.Files, files...)
+		if c.file != nil {
+			archive.manifest.Files = append(archive.manifest.Files, *c.file)
+		}
+
+		edgeconfig, err := archive.edgeCoreConfig()
+		if err != nil {
+			t.Fatalf("%s: parse config failed: %v", c.name, err)
+		}
+		paths, err := archive.targetPaths(edgeconfig)
+		if c.err == "" {
+			if err != nil || paths[backupFileKey] != "/etc/kubeedge/certs/server.key" {
+				t.Errorf("%s: unexpected paths %v, err: %v", c.name, paths, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: expected error %q, but got %v", c.name, c.err, err)
+		}
+	}
+}
+
+func TestRestoreTrustAnchor(t *testing.T) {
+	dir := t.TempDir()
+	config := newTestNode(t, dir)
+	output := filepath.Join(dir, "backup.tar.gz")
+	if _, err := backup(config, output); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := readBackupArchive(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := archive.caCertificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(ca.Raw)
+	caHash := hex.EncodeToString(sum[:])
+
+	// a CA of another cluster
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "KubeEdge"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	otherDER, err := x509.CreateCertificate(rand.Reader, otherTemplate, otherTemplate, &otherKey.PublicKey, otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA := filepath.Join(dir, "other.crt")
+	if err := os.WriteFile(otherCA, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	otherSum := sha256.Sum256(otherDER)
+
+	cases := []struct {
+		name     string
+		certPath string
+		caHash   string
+		err      string
+	}{
+		{name: "ca hash", caHash: caHash},
+		{name: "ca cert", certPath: filepath.Join(dir, "rootCA.crt")},
+		{name: "no trust anchor", err: "no trust anchor"},
+		{name: "ca hash of another cluster", caHash: hex.EncodeToString(otherSum[:]), err: "does not match"},
+		{name: "ca cert of another cluster", certPath: otherCA, err: "does not match"},
+		{name: "ca cert of another cluster with the right hash", certPath: otherCA, caHash: caHash, err: "does not match"},
+	}
+	for _, c := range cases {
+		err := archive.verifyTrustAnchor(c.certPath, c.caHash)
+		if c.err == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", c.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: expected error %q, but got %v", c.name, c.err, err)
+		}
+	}
+}
+
+func TestCloudCoreHTTPServer(t *testing.T) {
+	server, err := cloudCoreHTTPServer("192.168.20.50:10000", "")
+	if err != nil || server != "https://192.168.20.50:10002" {
+		t.Errorf("unexpected server %s, err: %v", server, err)
+	}
+	if _, err := cloudCoreHTTPServer("192.168.20.50", ""); err == nil {
+		t.Errorf("expected the address without port is rejected")
+	}
+}
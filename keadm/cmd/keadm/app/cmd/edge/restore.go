@@ -0,0 +1,530 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edge
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+var (
+	edgeRestoreLongDescription = `
+"keadm restore" command brings up the edge node from an archive created by "keadm backup",
+so a replacement machine joins the cluster as the same node without a new token.
+The archive is verified by its signature and by the CA of the cluster before anything is written.
+The CA must be pinned out of band by --ca-cert-hash, the first part of the token of the cluster,
+or by --certPath, a copy of the CA certificate of the cluster, and it is checked against
+the cloudcore at --cloudcore-ipport. The files are only restored to the paths in the edgecore
+config of the archive, which must be in the certificate and database directories of KubeEdge.
+Running it again with the same archive is safe.
+`
+	edgeRestoreExample = `
+keadm restore --archive /tmp/edge-node-1.tar.gz --cloudcore-ipport=192.168.20.50:10000 --ca-cert-hash=<the first part of the token> --kubeedge-version=1.13.0
+`
+)
+
+// maxBackupEntrySize limits the size of a single file read from the backup archive
+const maxBackupEntrySize = 1 << 30
+
+// restoreFileMode limits the permissions of the restored files
+const restoreFileMode os.FileMode = 0644
+
+var (
+	// restoreCertDirs are the directories that the certificates may be restored to
+	restoreCertDirs = []string{filepath.Dir(constants.DefaultCAFile), filepath.Dir(constants.DefaultCertFile)}
+	// restoreDatabaseDir is the directory that the database may be restored to
+	restoreDatabaseDir = filepath.Dir(v1alpha2.DataBaseDataSource)
+)
+
+// backupArchive is the content of a backup archive
+type backupArchive struct {
+	manifest     BackupManifest
+	manifestData []byte
+	signature    []byte
+	files        map[string][]byte
+}
+
+// NewEdgeRestore returns KubeEdge edge restore command.
+func NewEdgeRestore() *cobra.Command {
+	restoreOptions := newRestoreOptions()
+	step := common.NewStep()
+	cmd := &cobra.Command{
+		Use:          "restore",
+		Short:        "Restore the edge node from an archive created by backup, without a new token",
+		Long:         edgeRestoreLongDescription,
+		Example:      edgeRestoreExample,
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if restoreOptions.CertPath == "" && restoreOptions.CACertHash == "" {
+				return fmt.Errorf("one of --%s and --%s is required to verify the archive", common.CACertHash, common.CertPath)
+			}
+			step.Printf("Check KubeEdge edgecore process status")
+			running, err := util.IsKubeEdgeProcessRunning(util.KubeEdgeBinaryName)
+			if err != nil {
+				return fmt.Errorf("check KubeEdge edgecore process status failed: %v", err)
+			}
+			if running {
+				return fmt.Errorf("EdgeCore is already running on this node, please run reset to clean up first")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ver, err := util.GetCurrentVersion(restoreOptions.KubeEdgeVersion)
+			if err != nil {
+				return fmt.Errorf("edge node restore failed: %v", err)
+			}
+			restoreOptions.KubeEdgeVersion = ver
+
+			if err := restore(restoreOptions, step); err != nil {
+				return fmt.Errorf("edge node restore failed: %v", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&restoreOptions.Archive, common.BackupArchive, restoreOptions.Archive,
+		"The path of the archive created by keadm backup")
+	if err := cmd.MarkFlagRequired(common.BackupArchive); err != nil {
+		fmt.Printf("mark flag required failed with error: %v\n", err)
+	}
+	cmd.Flags().StringVarP(&restoreOptions.CloudCoreIPPort, common.CloudCoreIPPort, "e", restoreOptions.CloudCoreIPPort,
+		"IP:Port address of KubeEdge CloudCore, the CA of the cluster is validated against it")
+	if err := cmd.MarkFlagRequired(common.CloudCoreIPPort); err != nil {
+		fmt.Printf("mark flag required failed with error: %v\n", err)
+	}
+	cmd.Flags().StringVarP(&restoreOptions.CertPort, common.CertPort, "s", restoreOptions.CertPort,
+		"The port where to get the CA certificate of the cluster, the default value is 10002")
+	cmd.Flags().StringVar(&restoreOptions.CACertHash, common.CACertHash, restoreOptions.CACertHash,
+		"The sha256 hash of the CA certificate of the cluster, which is the first part of the token")
+	cmd.Flags().StringVar(&restoreOptions.CertPath, common.CertPath, restoreOptions.CertPath,
+		"The path of the CA certificate of the cluster copied from the cloud out of band")
+	cmd.Flags().StringVar(&restoreOptions.KubeEdgeVersion, common.KubeEdgeVersion, restoreOptions.KubeEdgeVersion,
+		"Use this key to download and use the required KubeEdge version")
+	cmd.Flags().StringVar(&restoreOptions.ImageRepository, common.ImageRepository, restoreOptions.ImageRepository,
+		`Use this key to decide which image repository to pull images from.`)
+	cmd.Flags().BoolVar(&restoreOptions.WithMQTT, "with-mqtt", restoreOptions.WithMQTT,
+		`Use this key to set whether to install and start MQTT Broker by default`)
+	return cmd
+}
+
+func newRestoreOptions() *common.RestoreOptions {
+	return &common.RestoreOptions{
+		WithMQTT: true,
+	}
+}
+
+func restore(opt *common.RestoreOptions, step *common.Step) error {
+	step.Printf("Verify the backup archive %s", opt.Archive)
+	archive, err := readBackupArchive(opt.Archive)
+	if err != nil {
+		return err
+	}
+	if err := archive.verify(time.Now()); err != nil {
+		return fmt.Errorf("verify backup archive failed: %v", err)
+	}
+
+	// everything in the archive is only trusted after its CA is pinned to the trust anchor
+	if err := archive.verifyTrustAnchor(opt.CertPath, opt.CACertHash); err != nil {
+		return fmt.Errorf("verify backup archive failed: %v", err)
+	}
+
+	edgeconfig, err := archive.edgeCoreConfig()
+	if err != nil {
+		return err
+	}
+	paths, err := archive.targetPaths(edgeconfig)
+	if err != nil {
+		return fmt.Errorf("verify backup archive failed: %v", err)
+	}
+
+	step.Printf("Validate the archive against the CA of the cluster")
+	httpServer, err := cloudCoreHTTPServer(opt.CloudCoreIPPort, opt.CertPort)
+	if err != nil {
+		return err
+	}
+	if err := archive.validateClusterCA(httpServer); err != nil {
+		return err
+	}
+
+	step.Printf("Create the necessary directories")
+	if err := createDirs(); err != nil {
+		return err
+	}
+
+	// edgecore is installed by the previous run if restore is retried
+	if util.FileExists(filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName)) {
+		step.Printf("EdgeCore is already installed, skip pulling images")
+		if opt.WithMQTT {
+			if err := createMQTTConfigFile(); err != nil {
+				return fmt.Errorf("create MQTT config file failed: %v", err)
+			}
+		}
+	} else {
+		// the container runtime is the same as the one of the backup node
+		joinOptions := newOption()
+		joinOptions.KubeEdgeVersion = opt.KubeEdgeVersion
+		joinOptions.ImageRepository = opt.ImageRepository
+		joinOptions.WithMQTT = opt.WithMQTT
+		edged := edgeconfig.Modules.Edged
+		if edged.ContainerRuntime != "" {
+			joinOptions.RuntimeType = edged.ContainerRuntime
+		}
+		if edged.RemoteRuntimeEndpoint != "" {
+			joinOptions.RemoteRuntimeEndpoint = edged.RemoteRuntimeEndpoint
+		}
+		if edged.TailoredKubeletConfig != nil && edged.TailoredKubeletConfig.CgroupDriver != "" {
+			joinOptions.CGroupDriver = edged.TailoredKubeletConfig.CgroupDriver
+		}
+		if err := request(joinOptions, step); err != nil {
+			return err
+		}
+	}
+
+	step.Printf("Restore the certificates, the configuration and the database of node %s", archive.manifest.NodeName)
+	for _, file := range archive.manifest.Files {
+		path := paths[file.Name]
+		if err := writeFileAtomic(path, archive.files[file.Name], file.Mode&restoreFileMode); err != nil {
+			return fmt.Errorf("restore %s failed: %v", path, err)
+		}
+	}
+
+	step.Printf("Generate systemd service file")
+	if err := common.GenerateServiceFile(util.KubeEdgeBinaryName, filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName), opt.WithMQTT); err != nil {
+		return fmt.Errorf("create systemd service file failed: %v", err)
+	}
+
+	step.Printf("Run EdgeCore daemon")
+	if err := runEdgeCore(opt.WithMQTT); err != nil {
+		return fmt.Errorf("start edgecore failed: %v", err)
+	}
+	return nil
+}
+
+// readBackupArchive reads the manifest, the signature and the files from the archive
+func readBackupArchive(path string) (*backupArchive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open archive %s failed: %v", path, err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("read archive %s failed: %v", path, err)
+	}
+	defer gr.Close()
+
+	archive := &backupArchive{files: make(map[string][]byte)}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive %s failed: %v", path, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected entry %s in archive", header.Name)
+		}
+		if header.Size > maxBackupEntrySize {
+			return nil, fmt.Errorf("entry %s in archive is too large", header.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("read %s from archive failed: %v", header.Name, err)
+		}
+
+		switch {
+		case header.Name == backupManifestName:
+			archive.manifestData = data
+		case header.Name == backupSignatureName:
+			archive.signature = data
+		case strings.HasPrefix(header.Name, backupFilesDir):
+			archive.files[strings.TrimPrefix(header.Name, backupFilesDir)] = data
+		default:
+			return nil, fmt.Errorf("unexpected entry %s in archive", header.Name)
+		}
+	}
+
+	if archive.manifestData == nil || archive.signature == nil {
+		return nil, fmt.Errorf("archive %s is not created by keadm backup", path)
+	}
+	if err := json.Unmarshal(archive.manifestData, &archive.manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest failed: %v", err)
+	}
+	if archive.manifest.Version != backupFormatVersion {
+		return nil, fmt.Errorf("unsupported archive version %d", archive.manifest.Version)
+	}
+	return archive, nil
+}
+
+// verify checks that the archive is complete and untouched, and the node certificate
+// in it is issued by the CA in it and valid at now
+func (a *backupArchive) verify(now time.Time) error {
+	for _, name := range []string{backupFileConfig, backupFileCA, backupFileCert, backupFileKey, backupFileDatabase} {
+		if _, ok := a.files[name]; !ok {
+			return fmt.Errorf("%s is missing", name)
+		}
+	}
+
+	keyPair, err := tls.X509KeyPair(a.files[backupFileCert], a.files[backupFileKey])
+	if err != nil {
+		return fmt.Errorf("invalid node certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("invalid node certificate: %v", err)
+	}
+	// the signature is checked before the content of the manifest is trusted
+	if err := verifyBackupManifest(a.manifestData, a.signature, cert); err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+
+	for _, file := range a.manifest.Files {
+		data, ok := a.files[file.Name]
+		if !ok {
+			return fmt.Errorf("%s is missing", file.Name)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != file.SHA256 {
+			return fmt.Errorf("the checksum of %s mismatches", file.Name)
+		}
+	}
+
+	ca, err := a.caCertificate()
+	if err != nil {
+		return err
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return fmt.Errorf("node certificate is not valid: %v", err)
+	}
+	return nil
+}
+
+func (a *backupArchive) caCertificate() (*x509.Certificate, error) {
+	block, _ := pem.Decode(a.files[backupFileCA])
+	if block == nil {
+		return nil, errors.New("invalid CA certificate: no PEM data")
+	}
+	ca, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate: %v", err)
+	}
+	return ca, nil
+}
+
+func (a *backupArchive) edgeCoreConfig() (*v1alpha2.EdgeCoreConfig, error) {
+	edgeconfig := &v1alpha2.EdgeCoreConfig{}
+	if err := yaml.Unmarshal(a.files[backupFileConfig], edgeconfig); err != nil {
+		return nil, fmt.Errorf("parse the edgecore config in the archive failed: %v", err)
+	}
+	if edgeconfig.Modules == nil || edgeconfig.Modules.EdgeHub == nil || edgeconfig.Modules.Edged == nil {
+		return nil, errors.New("the edgecore config in the archive has no edgehub or edged module")
+	}
+	return edgeconfig, nil
+}
+
+// targetPaths returns the paths to restore the files in the archive to, by their names.
+// Any node of the cluster is able to sign an archive, so the paths in the manifest are not trusted:
+// they must be the paths in the edgecore config, which must be in the directories managed by KubeEdge.
+func (a *backupArchive) targetPaths(edgeconfig *v1alpha2.EdgeCoreConfig) (map[string]string, error) {
+	edgehub := edgeconfig.Modules.EdgeHub
+	certs := map[string]string{
+		backupFileCA:   edgehub.TLSCAFile,
+		backupFileCert: edgehub.TLSCertFile,
+		backupFileKey:  edgehub.TLSPrivateKeyFile,
+	}
+	if stream := edgeconfig.Modules.EdgeStream; stream != nil && stream.Enable {
+		certs[backupFileTunnelCA] = stream.TLSTunnelCAFile
+		certs[backupFileTunnelCert] = stream.TLSTunnelCertFile
+		certs[backupFileTunnelKey] = stream.TLSTunnelPrivateKeyFile
+	}
+	dataSource := v1alpha2.DataBaseDataSource
+	if edgeconfig.DataBase != nil && edgeconfig.DataBase.DataSource != "" {
+		dataSource = edgeconfig.DataBase.DataSource
+	}
+
+	// edgecore is always started with the default config
+	paths := map[string]string{backupFileConfig: common.EdgecoreConfigPath}
+	for name, path := range certs {
+		if !inDirs(path, restoreCertDirs) {
+			return nil, fmt.Errorf("%s path %s is not in %v", name, path, restoreCertDirs)
+		}
+		paths[name] = filepath.Clean(path)
+	}
+	if !filepath.IsAbs(dataSource) || filepath.Dir(filepath.Clean(dataSource)) != restoreDatabaseDir {
+		return nil, fmt.Errorf("database path %s is not in %s", dataSource, restoreDatabaseDir)
+	}
+	paths[backupFileDatabase] = filepath.Clean(dataSource)
+
+	for _, file := range a.manifest.Files {
+		if path, ok := paths[file.Name]; !ok || filepath.Clean(file.Path) != path {
+			return nil, fmt.Errorf("the path %s of %s is not the one in the edgecore config", file.Path, file.Name)
+		}
+	}
+	return paths, nil
+}
+
+// inDirs returns whether path is an absolute path under one of dirs
+func inDirs(path string, dirs []string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, filepath.Clean(path))
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyTrustAnchor checks that the CA certificate in the archive is the one of the cluster, which
+// is pinned by the CA certificate at certPath or the hash of the CA certificate provided out of band.
+// The node certificate and the signature of the archive are verified with the CA certificate, so
+// an archive created with another CA is rejected.
+func (a *backupArchive) verifyTrustAnchor(certPath, caCertHash string) error {
+	if certPath == "" && caCertHash == "" {
+		return errors.New("no trust anchor of the cluster is provided")
+	}
+	ca, err := a.caCertificate()
+	if err != nil {
+		return err
+	}
+	if caCertHash != "" {
+		sum := sha256.Sum256(ca.Raw)
+		if !strings.EqualFold(hex.EncodeToString(sum[:]), caCertHash) {
+			return errors.New("the hash of the CA certificate does not match the one of the cluster")
+		}
+	}
+	if certPath != "" {
+		data, err := os.ReadFile(certPath)
+		if err != nil {
+			return fmt.Errorf("read the CA certificate of the cluster failed: %v", err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return fmt.Errorf("invalid CA certificate %s: no PEM data", certPath)
+		}
+		if !bytes.Equal(block.Bytes, ca.Raw) {
+			return fmt.Errorf("the CA certificate does not match the one of the cluster at %s", certPath)
+		}
+	}
+	return nil
+}
+
+// cloudCoreHTTPServer returns the address of the https server of cloudcore, as keadm join does
+func cloudCoreHTTPServer(cloudCoreIPPort, certPort string) (string, error) {
+	host, _, err := net.SplitHostPort(cloudCoreIPPort)
+	if err != nil {
+		return "", fmt.Errorf("invalid cloudcore address %s: %v", cloudCoreIPPort, err)
+	}
+	if certPort == "" {
+		certPort = "10002"
+	}
+	return "https://" + net.JoinHostPort(host, certPort), nil
+}
+
+// validateClusterCA checks that cloudcore at httpServer is served by a certificate of the CA in the archive,
+// and the CA it publishes is the same one. httpServer is given by the user, it is never taken from the archive.
+func (a *backupArchive) validateClusterCA(httpServer string) error {
+	ca, err := a.caCertificate()
+	if err != nil {
+		return err
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+		},
+	}
+
+	resp, err := client.Get(httpServer + constants.DefaultCAURL)
+	if err != nil {
+		return fmt.Errorf("the CA of the cluster at %s does not match the archive: %v", httpServer, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get the CA of the cluster from %s failed: %s", httpServer, resp.Status)
+	}
+	clusterCA, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("get the CA of the cluster from %s failed: %v", httpServer, err)
+	}
+	if !bytes.Equal(clusterCA, ca.Raw) {
+		return fmt.Errorf("the CA of the cluster at %s does not match the archive", httpServer)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces the file at path, so an interrupted restore never leaves a partial file
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/kubeedge/kubeedge/common/constants"
 	types "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/pkg/util"
 	pkgversion "github.com/kubeedge/kubeedge/pkg/version"
 )
 
@@ -58,9 +59,9 @@ const (
 	KubeEdgePath         = "/etc/kubeedge/"
 	KubeEdgeBackupPath   = "/etc/kubeedge/backup/"
 	KubeEdgeUpgradePath  = "/etc/kubeedge/upgrade/"
-	KubeEdgeUsrBinPath   = "/usr/local/bin"
-	KubeEdgeBinaryName   = "edgecore"
-	KeadmBinaryName      = "keadm"
+	KubeEdgeUsrBinPath   = constants.KubeEdgeBinaryDir
+	KubeEdgeBinaryName   = constants.EdgeCoreBinaryName
+	KeadmBinaryName      = constants.KeadmBinaryName
 
 	KubeCloudBinaryName = "cloudcore"
 
@@ -75,7 +76,7 @@ const (
 
 	EdgeRootDir = "/var/lib/edged"
 
-	SystemdBootPath = "/run/systemd/system"
+	SystemdBootPath = constants.SystemdBootPath
 
 	KubeEdgeCRDDownloadURL = "https://raw.githubusercontent.com/kubeedge/kubeedge/release-%s/build/crds"
 
@@ -573,20 +574,8 @@ func isEdgeCoreServiceRunning(serviceName string) (bool, error) {
 }
 
 // HasSystemd checks if systemd exist.
-// if command run failed, then check it by sd_booted.
 func HasSystemd() bool {
-	cmd := "file /sbin/init"
-
-	if err := NewCommand(cmd).Exec(); err == nil {
-		return true
-	}
-	// checks whether `SystemdBootPath` exists and is a directory
-	// reference http://www.freedesktop.org/software/systemd/man/sd_booted.html
-	fi, err := os.Lstat(SystemdBootPath)
-	if err != nil {
-		return false
-	}
-	return fi.IsDir()
+	return util.HasSystemd()
 }
 
 // computeSHA512Checksum returns the SHA512 checksum of the given file
@@ -17,8 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EdgeApplicationSpec defines the desired state of EdgeApplication
@@ -28,6 +30,35 @@ type EdgeApplicationSpec struct {
 	WorkloadTemplate ResourceTemplate `json:"workloadTemplate,omitempty"`
 	// WorkloadScope represents which node groups the workload will be deployed in.
 	WorkloadScope WorkloadScope `json:"workloadScope"`
+	// RolloutStrategy represents how the changes of the workload are rolled out to
+	// the target node groups. If not set, the changes are applied to all the target
+	// node groups at once.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+}
+
+// RolloutStrategy represents the strategy to roll out the workload to the target node groups
+// progressively. The target node groups are split into waves in the order of
+// WorkloadScope.TargetNodeGroups, and the next wave starts only after all the workloads of the
+// current wave become available.
+// The rollout can be promoted or aborted manually with the annotation RolloutActionAnnotation.
+type RolloutStrategy struct {
+	// MaxUnavailable is the maximum number of target node groups that can be updated
+	// at the same time, that is the size of each wave. Value can be an absolute number
+	// (ex: 5) or a percentage of the target node groups (ex: 10%). Percentage is rounded up.
+	// Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// PauseBetweenWaves indicates whether the rollout should be paused after each wave
+	// becomes available, until it is promoted manually.
+	// +optional
+	PauseBetweenWaves bool `json:"pauseBetweenWaves,omitempty"`
+	// ProgressDeadlineSeconds is the maximum time in seconds for the workloads of a wave to
+	// become available, the rollout will be paused if the deadline is exceeded.
+	// There's no deadline if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
 }
 
 // WorkloadScope represents which node groups the workload should be deployed in.
@@ -62,6 +93,124 @@ type Overriders struct {
 	// ImageOverriders represents the rules dedicated to handling image overrides.
 	// +optional
 	ImageOverriders []ImageOverrider `json:"imageOverriders,omitempty"`
+	// EnvOverriders represents the rules dedicated to handling container env overrides.
+	// +optional
+	EnvOverriders []EnvOverrider `json:"envOverriders,omitempty"`
+	// ResourcesOverriders represents the rules dedicated to handling container resources overrides.
+	// +optional
+	ResourcesOverriders []ResourcesOverrider `json:"resourcesOverriders,omitempty"`
+	// CommandOverriders represents the rules dedicated to handling container command overrides.
+	// +optional
+	CommandOverriders []CommandArgsOverrider `json:"commandOverriders,omitempty"`
+	// ArgsOverriders represents the rules dedicated to handling container args overrides.
+	// +optional
+	ArgsOverriders []CommandArgsOverrider `json:"argsOverriders,omitempty"`
+	// TolerationsOverrider represents the rules dedicated to handling pod tolerations overrides.
+	// +optional
+	TolerationsOverrider *TolerationsOverrider `json:"tolerationsOverrider,omitempty"`
+	// AffinityOverrider represents the rules dedicated to handling pod affinity overrides.
+	// +optional
+	AffinityOverrider *AffinityOverrider `json:"affinityOverrider,omitempty"`
+	// JSONPatchOverriders represents the JSON patches that apply on the whole manifest,
+	// they are applied after all the other overriders.
+	// +optional
+	JSONPatchOverriders []JSONPatchOverrider `json:"jsonPatchOverriders,omitempty"`
+	// StrategicMergePatch represents a strategic merge patch that applies on the whole manifest,
+	// it is applied after the JSONPatchOverriders. Only the kinds registered in client-go scheme
+	// are supported.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	StrategicMergePatch *runtime.RawExtension `json:"strategicMergePatch,omitempty"`
+}
+
+// EnvOverrider represents the rules dedicated to handling container env overrides.
+type EnvOverrider struct {
+	// ContainerName represents the name of the container that the env override applies to.
+	// +required
+	ContainerName string `json:"containerName"`
+
+	// Operator represents the operator which will apply on the env.
+	// add: add the env vars, the ones with the same name are replaced.
+	// remove: remove the env vars with the same name.
+	// replace: replace the whole env of the container.
+	// +kubebuilder:validation:Enum=add;remove;replace
+	// +required
+	Operator OverriderOperator `json:"operator"`
+
+	// Value to be applied to env.
+	// Only the names are used when operator is 'remove'.
+	// +optional
+	Value []corev1.EnvVar `json:"value,omitempty"`
+}
+
+// ResourcesOverrider represents the rules dedicated to handling container resources overrides.
+type ResourcesOverrider struct {
+	// ContainerName represents the name of the container that the resources override applies to.
+	// +required
+	ContainerName string `json:"containerName"`
+
+	// Value replaces the resources of the container.
+	// +required
+	Value corev1.ResourceRequirements `json:"value"`
+}
+
+// CommandArgsOverrider represents the rules dedicated to handling container command or args overrides.
+type CommandArgsOverrider struct {
+	// ContainerName represents the name of the container that the override applies to.
+	// +required
+	ContainerName string `json:"containerName"`
+
+	// Operator represents the operator which will apply on the command or args.
+	// add: append the values.
+	// remove: remove the items which equal to the values.
+	// replace: replace the whole command or args of the container.
+	// +kubebuilder:validation:Enum=add;remove;replace
+	// +required
+	Operator OverriderOperator `json:"operator"`
+
+	// Value to be applied to command or args.
+	// +optional
+	Value []string `json:"value,omitempty"`
+}
+
+// TolerationsOverrider represents the rules dedicated to handling pod tolerations overrides.
+type TolerationsOverrider struct {
+	// Operator represents the operator which will apply on the tolerations.
+	// add: add the tolerations, the ones with the same key, operator and effect are replaced.
+	// remove: remove the tolerations with the same key, operator and effect.
+	// replace: replace the whole tolerations of the pod.
+	// +kubebuilder:validation:Enum=add;remove;replace
+	// +required
+	Operator OverriderOperator `json:"operator"`
+
+	// Value to be applied to tolerations.
+	// +optional
+	Value []corev1.Toleration `json:"value,omitempty"`
+}
+
+// AffinityOverrider represents the rules dedicated to handling pod affinity overrides.
+type AffinityOverrider struct {
+	// Value replaces the affinity of the pod, nil value removes the affinity.
+	// +optional
+	Value *corev1.Affinity `json:"value,omitempty"`
+}
+
+// JSONPatchOverrider represents a JSON patch operation that applies on the manifest.
+type JSONPatchOverrider struct {
+	// Path indicates the path of target field, e.g. /spec/template/spec/hostNetwork
+	// +required
+	Path string `json:"path"`
+
+	// Operator represents the operator which will apply on the target field.
+	// +kubebuilder:validation:Enum=add;remove;replace
+	// +required
+	Operator OverriderOperator `json:"operator"`
+
+	// Value to be applied to the target field.
+	// Must be empty when operator is 'remove'.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Value *runtime.RawExtension `json:"value,omitempty"`
 }
 
 // ImageOverrider represents the rules dedicated to handling image overrides.
@@ -151,8 +300,66 @@ type EdgeApplicationStatus struct {
 	// WorkloadStatus contains running statuses of generated resources.
 	// +optional
 	WorkloadStatus []ManifestStatus `json:"workloadStatus,omitempty"`
+	// RolloutStatus represents the progress of the rollout, it's only set when
+	// RolloutStrategy is specified.
+	// +optional
+	RolloutStatus *RolloutStatus `json:"rolloutStatus,omitempty"`
+}
+
+// RolloutStatus represents the progress of the rollout.
+type RolloutStatus struct {
+	// Revision is the hash of the workload template and workload scope under rollout.
+	// A new rollout starts when it changes.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+	// Phase is the phase of the rollout.
+	// +kubebuilder:validation:Enum=Progressing;Paused;Completed;Aborted
+	// +optional
+	Phase RolloutPhase `json:"phase,omitempty"`
+	// CurrentWave is the index of the wave under rollout, starting from 0.
+	// +optional
+	CurrentWave int32 `json:"currentWave"`
+	// TotalWaves is the number of waves of the rollout.
+	// +optional
+	TotalWaves int32 `json:"totalWaves,omitempty"`
+	// UpdatedNodeGroups are the target node groups that the revision has been applied to.
+	// +optional
+	UpdatedNodeGroups []string `json:"updatedNodeGroups,omitempty"`
+	// WaveStartTime is the time when the current wave started.
+	// +optional
+	WaveStartTime *metav1.Time `json:"waveStartTime,omitempty"`
+	// Message is a human readable message indicating details about the rollout.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
+// RolloutPhase is the phase of the rollout.
+type RolloutPhase string
+
+const (
+	// RolloutProgressing means the current wave is being rolled out.
+	RolloutProgressing RolloutPhase = "Progressing"
+	// RolloutPaused means the rollout is paused because of failures or waiting
+	// for manual promotion.
+	RolloutPaused RolloutPhase = "Paused"
+	// RolloutCompleted means the revision has been rolled out to all the target node groups.
+	RolloutCompleted RolloutPhase = "Completed"
+	// RolloutAborted means the rollout is aborted manually, the node groups that have not
+	// been rolled out will keep their current workloads until the next revision.
+	RolloutAborted RolloutPhase = "Aborted"
+)
+
+const (
+	// RolloutActionAnnotation is the annotation to promote or abort the rollout of an EdgeApplication
+	// manually, it will be removed once the action is taken.
+	RolloutActionAnnotation = "apps.kubeedge.io/rollout-action"
+	// RolloutActionPromote promotes the rollout to the next wave, no matter whether the
+	// current wave is available.
+	RolloutActionPromote = "promote"
+	// RolloutActionAbort aborts the rollout.
+	RolloutActionAbort = "abort"
+)
+
 // ManifestStatus contains running status of a specific manifest in spec.
 type ManifestStatus struct {
 	// Identifier represents the identity of a resource linking to manifests in spec.
@@ -163,9 +370,14 @@ type ManifestStatus struct {
 	// Valid condition types are:
 	// 1. Processing: this workload is under processing and the current state of manifest does not match the desired.
 	// 2. Available: the current status of this workload matches the desired.
-	// +kubebuilder:validation:Enum=Processing;Available
+	// 3. OverrideFailed: the overriders of the target node group cannot be applied to this workload.
+	// +kubebuilder:validation:Enum=Processing;Available;OverrideFailed
 	// +optional
 	Condition ManifestCondition `json:"conditions,omitempty"`
+
+	// Message is a human readable message indicating details about the condition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ResourceIdentifier provides the identifiers needed to interact with any arbitrary object.
@@ -210,6 +422,9 @@ const (
 	// EdgeAppAvailable represents that the manifest has been applied successfully and the current
 	// status matches the desired.
 	EdgeAppAvailable ManifestCondition = "Available"
+	// EdgeAppOverrideFailed represents that the overriders of the target node group cannot be applied
+	// to the manifest, and the workload of the node group is not created or updated.
+	EdgeAppOverrideFailed ManifestCondition = "OverrideFailed"
 )
 
 // +genclient
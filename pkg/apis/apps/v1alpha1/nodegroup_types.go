@@ -20,7 +20,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// NodeGroupSpec defines the desired state of NodeGroup
+// NodeGroupSpec defines the desired state of NodeGroup.
+// A node matches the selector of the NodeGroup if it satisfies all the specified
+// MatchLabels, LabelSelector and NodeFieldSelector. If none of them is specified,
+// no node matches the selector. Nodes specified in Nodes are selected whether they
+// match the selector or not.
 type NodeGroupSpec struct {
 	// Nodes contains names of all the nodes in the nodegroup.
 	// +optional
@@ -29,8 +33,98 @@ type NodeGroupSpec struct {
 	// MatchLabels are used to select nodes that have these labels.
 	// +optional
 	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+
+	// LabelSelector is used to select nodes by labels, with the full semantics of
+	// label selector, including matchExpressions.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// NodeFieldSelector is used to select nodes by the fields of node, such as
+	// architecture and kubeedge version.
+	// The requirements are ANDed.
+	// +optional
+	NodeFieldSelector []NodeFieldSelectorRequirement `json:"nodeFieldSelector,omitempty"`
+
+	// ExcludeNodes contains names of nodes that should never be members of this NodeGroup,
+	// even if they are specified in Nodes or match the selector.
+	// +optional
+	ExcludeNodes []string `json:"excludeNodes,omitempty"`
+
+	// Exclusive indicates that the nodes of this NodeGroup must not be selected by any
+	// other NodeGroup. A node that is selected by an exclusive NodeGroup and any other
+	// NodeGroup will not become a member of either of them, and the conflict will be
+	// reported in the status of these NodeGroups.
+	// +optional
+	Exclusive bool `json:"exclusive,omitempty"`
+
+	// MaintenanceWindows specifies the periods of time when the nodes of this NodeGroup
+	// can be disrupted, such as being upgraded by NodeUpgradeJob.
+	// The nodes can be disrupted at any time if no window is specified.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time when the nodes can be disrupted.
+type MaintenanceWindow struct {
+	// Schedule is the start time of the window in cron format, which consists of five fields:
+	// minute, hour, day of month, month and day of week, such as "0 2 * * *".
+	// Each field supports "*", lists, ranges and steps, such as "1,15", "1-5" and "*/2".
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Schedule string `json:"schedule"`
+
+	// Duration is the length of the window, such as "3h". It should not be longer than 24h.
+	// +required
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone name that Schedule is based on, such as "Asia/Shanghai".
+	// Default to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
+// NodeFieldSelectorRequirement is a selector that contains a node field, values and
+// an operator that relates the field and values.
+type NodeFieldSelectorRequirement struct {
+	// Key is the node field that the selector applies to.
+	// +kubebuilder:validation:Enum=architecture;operatingSystem;osImage;kernelVersion;containerRuntimeVersion;kubeletVersion;kubeedgeVersion
+	// +required
+	Key NodeField `json:"key"`
+	// Operator represents the relationship of the field to the values.
+	// Valid operators are In, NotIn, Exists and DoesNotExist.
+	// +kubebuilder:validation:Enum=In;NotIn;Exists;DoesNotExist
+	// +required
+	Operator metav1.LabelSelectorOperator `json:"operator"`
+	// Values is an array of string values. If the operator is In or NotIn,
+	// the values array must be non-empty. If the operator is Exists or DoesNotExist,
+	// the values array must be empty.
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// NodeField is the field of node that can be used in NodeFieldSelectorRequirement.
+type NodeField string
+
+const (
+	// NodeFieldArchitecture is the architecture reported by the node, e.g. amd64, arm64.
+	NodeFieldArchitecture NodeField = "architecture"
+	// NodeFieldOperatingSystem is the operating system reported by the node, e.g. linux.
+	NodeFieldOperatingSystem NodeField = "operatingSystem"
+	// NodeFieldOSImage is the os image reported by the node.
+	NodeFieldOSImage NodeField = "osImage"
+	// NodeFieldKernelVersion is the kernel version reported by the node.
+	NodeFieldKernelVersion NodeField = "kernelVersion"
+	// NodeFieldContainerRuntimeVersion is the container runtime version reported by the node,
+	// e.g. containerd://1.6.9.
+	NodeFieldContainerRuntimeVersion NodeField = "containerRuntimeVersion"
+	// NodeFieldKubeletVersion is the kubelet version reported by the node,
+	// e.g. v1.22.6-kubeedge-v1.13.0.
+	NodeFieldKubeletVersion NodeField = "kubeletVersion"
+	// NodeFieldKubeEdgeVersion is the kubeedge version of edge node, parsed from the
+	// kubelet version, e.g. v1.13.0. It does not exist on non-edge nodes.
+	NodeFieldKubeEdgeVersion NodeField = "kubeedgeVersion"
+)
+
 // NodeGroupStatus contains the observed status of all selected nodes in
 // this NodeGroup, including nodes that have been one of the members of this NodeGroup
 // and those have not.
@@ -51,7 +145,8 @@ type NodeStatus struct {
 	// SelectionStatus contains status of the selection result for this node.
 	// +required
 	SelectionStatus SelectionStatus `json:"selectionStatus"`
-	// SelectionStatusReason contains human-readable reason for this SelectionStatus.
+	// SelectionStatusReason contains human-readable reason for this SelectionStatus,
+	// e.g. how the node is selected or why it fails to be selected.
 	// +optional
 	SelectionStatusReason string `json:"selectionStatusReason,omitempty"`
 }
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations used to choose the offline migration policy of pods. They can be set on
+// the pod (usually through the pod template of the workload) or on the namespace,
+// and the pod annotations take precedence over the namespace annotations.
+const (
+	// OfflineMigrationPolicyAnnotation specifies the type of the offline migration policy,
+	// the value is one of Forever, TolerationSeconds and None.
+	OfflineMigrationPolicyAnnotation = "apps.kubeedge.io/offline-migration-policy"
+	// OfflineMigrationTolerationSecondsAnnotation specifies the tolerationSeconds used by
+	// the TolerationSeconds policy.
+	OfflineMigrationTolerationSecondsAnnotation = "apps.kubeedge.io/offline-migration-toleration-seconds"
+	// OfflineMigrationAppliedAnnotation is set by the admission webhook to report the policy
+	// applied to the pod and where the policy comes from.
+	OfflineMigrationAppliedAnnotation = "apps.kubeedge.io/offline-migration-applied"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=omp
+
+// OfflineMigrationPolicy decides how long the pods tolerate their edge nodes being unreachable
+// before being evicted and migrated to other nodes.
+// It applies to the pods with label app-offline.kubeedge.io=autonomy that are placed on the
+// NodeGroups it selects, unless the pods or their namespaces choose a policy by annotations.
+type OfflineMigrationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of the desired behavior of OfflineMigrationPolicy.
+	// +required
+	Spec OfflineMigrationPolicySpec `json:"spec"`
+}
+
+// OfflineMigrationPolicySpec defines the desired behavior of OfflineMigrationPolicy.
+type OfflineMigrationPolicySpec struct {
+	// NodeGroups are the names of NodeGroups whose pods this policy applies to.
+	// If it is empty, the policy is the cluster default policy and applies to pods
+	// that no other policy applies to.
+	// +optional
+	NodeGroups []string `json:"nodeGroups,omitempty"`
+
+	// Type is the type of the policy.
+	// +kubebuilder:validation:Enum=Forever;TolerationSeconds;None
+	// +required
+	Type OfflineMigrationPolicyType `json:"type"`
+
+	// TolerationSeconds is how long the pods tolerate their nodes being unreachable,
+	// it is required when Type is TolerationSeconds.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TolerationSeconds *int64 `json:"tolerationSeconds,omitempty"`
+}
+
+// OfflineMigrationPolicyType is the type of the offline migration policy.
+type OfflineMigrationPolicyType string
+
+const (
+	// OfflineMigrationForever makes the pods tolerate unreachable nodes forever,
+	// so the pods are never migrated.
+	OfflineMigrationForever OfflineMigrationPolicyType = "Forever"
+	// OfflineMigrationTolerationSeconds makes the pods tolerate unreachable nodes for
+	// TolerationSeconds, then the pods are evicted and migrated to other nodes.
+	OfflineMigrationTolerationSeconds OfflineMigrationPolicyType = "TolerationSeconds"
+	// OfflineMigrationNone keeps the tolerations of the pods unchanged.
+	OfflineMigrationNone OfflineMigrationPolicyType = "None"
+)
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OfflineMigrationPolicyList contains a list of OfflineMigrationPolicy.
+type OfflineMigrationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OfflineMigrationPolicy `json:"items"`
+}
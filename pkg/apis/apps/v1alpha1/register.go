@@ -62,6 +62,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&EdgeApplicationList{},
 		&NodeGroup{},
 		&NodeGroupList{},
+		&OfflineMigrationPolicy{},
+		&OfflineMigrationPolicyList{},
 	)
 	// AddToGroupVersion allows the serialization of client types like ListOptions.
 	v1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -22,9 +22,54 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AffinityOverrider) DeepCopyInto(out *AffinityOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AffinityOverrider.
+func (in *AffinityOverrider) DeepCopy() *AffinityOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(AffinityOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandArgsOverrider) DeepCopyInto(out *CommandArgsOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandArgsOverrider.
+func (in *CommandArgsOverrider) DeepCopy() *CommandArgsOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandArgsOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EdgeApplication) DeepCopyInto(out *EdgeApplication) {
 	*out = *in
@@ -91,6 +136,11 @@ func (in *EdgeApplicationSpec) DeepCopyInto(out *EdgeApplicationSpec) {
 	*out = *in
 	in.WorkloadTemplate.DeepCopyInto(&out.WorkloadTemplate)
 	in.WorkloadScope.DeepCopyInto(&out.WorkloadScope)
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -112,6 +162,11 @@ func (in *EdgeApplicationStatus) DeepCopyInto(out *EdgeApplicationStatus) {
 		*out = make([]ManifestStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.RolloutStatus != nil {
+		in, out := &in.RolloutStatus, &out.RolloutStatus
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -125,6 +180,29 @@ func (in *EdgeApplicationStatus) DeepCopy() *EdgeApplicationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvOverrider) DeepCopyInto(out *EnvOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvOverrider.
+func (in *EnvOverrider) DeepCopy() *EnvOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageOverrider) DeepCopyInto(out *ImageOverrider) {
 	*out = *in
@@ -162,6 +240,44 @@ func (in *ImagePredicate) DeepCopy() *ImagePredicate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPatchOverrider) DeepCopyInto(out *JSONPatchOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPatchOverrider.
+func (in *JSONPatchOverrider) DeepCopy() *JSONPatchOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPatchOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Manifest) DeepCopyInto(out *Manifest) {
 	*out = *in
@@ -196,6 +312,27 @@ func (in *ManifestStatus) DeepCopy() *ManifestStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeFieldSelectorRequirement) DeepCopyInto(out *NodeFieldSelectorRequirement) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFieldSelectorRequirement.
+func (in *NodeFieldSelectorRequirement) DeepCopy() *NodeFieldSelectorRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeFieldSelectorRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 	*out = *in
@@ -272,6 +409,28 @@ func (in *NodeGroupSpec) DeepCopyInto(out *NodeGroupSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeFieldSelector != nil {
+		in, out := &in.NodeFieldSelector, &out.NodeFieldSelector
+		*out = make([]NodeFieldSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludeNodes != nil {
+		in, out := &in.ExcludeNodes, &out.ExcludeNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -322,6 +481,92 @@ func (in *NodeStatus) DeepCopy() *NodeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflineMigrationPolicy) DeepCopyInto(out *OfflineMigrationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflineMigrationPolicy.
+func (in *OfflineMigrationPolicy) DeepCopy() *OfflineMigrationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflineMigrationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OfflineMigrationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflineMigrationPolicyList) DeepCopyInto(out *OfflineMigrationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OfflineMigrationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflineMigrationPolicyList.
+func (in *OfflineMigrationPolicyList) DeepCopy() *OfflineMigrationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflineMigrationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OfflineMigrationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflineMigrationPolicySpec) DeepCopyInto(out *OfflineMigrationPolicySpec) {
+	*out = *in
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TolerationSeconds != nil {
+		in, out := &in.TolerationSeconds, &out.TolerationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflineMigrationPolicySpec.
+func (in *OfflineMigrationPolicySpec) DeepCopy() *OfflineMigrationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflineMigrationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Overriders) DeepCopyInto(out *Overriders) {
 	*out = *in
@@ -337,6 +582,56 @@ func (in *Overriders) DeepCopyInto(out *Overriders) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvOverriders != nil {
+		in, out := &in.EnvOverriders, &out.EnvOverriders
+		*out = make([]EnvOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourcesOverriders != nil {
+		in, out := &in.ResourcesOverriders, &out.ResourcesOverriders
+		*out = make([]ResourcesOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CommandOverriders != nil {
+		in, out := &in.CommandOverriders, &out.CommandOverriders
+		*out = make([]CommandArgsOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ArgsOverriders != nil {
+		in, out := &in.ArgsOverriders, &out.ArgsOverriders
+		*out = make([]CommandArgsOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TolerationsOverrider != nil {
+		in, out := &in.TolerationsOverrider, &out.TolerationsOverrider
+		*out = new(TolerationsOverrider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AffinityOverrider != nil {
+		in, out := &in.AffinityOverrider, &out.AffinityOverrider
+		*out = new(AffinityOverrider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JSONPatchOverriders != nil {
+		in, out := &in.JSONPatchOverriders, &out.JSONPatchOverriders
+		*out = make([]JSONPatchOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StrategicMergePatch != nil {
+		in, out := &in.StrategicMergePatch, &out.StrategicMergePatch
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -389,6 +684,74 @@ func (in *ResourceTemplate) DeepCopy() *ResourceTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcesOverrider) DeepCopyInto(out *ResourcesOverrider) {
+	*out = *in
+	in.Value.DeepCopyInto(&out.Value)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcesOverrider.
+func (in *ResourcesOverrider) DeepCopy() *ResourcesOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcesOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.UpdatedNodeGroups != nil {
+		in, out := &in.UpdatedNodeGroups, &out.UpdatedNodeGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WaveStartTime != nil {
+		in, out := &in.WaveStartTime, &out.WaveStartTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetNodeGroup) DeepCopyInto(out *TargetNodeGroup) {
 	*out = *in
@@ -406,6 +769,29 @@ func (in *TargetNodeGroup) DeepCopy() *TargetNodeGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TolerationsOverrider) DeepCopyInto(out *TolerationsOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TolerationsOverrider.
+func (in *TolerationsOverrider) DeepCopy() *TolerationsOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(TolerationsOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadScope) DeepCopyInto(out *WorkloadScope) {
 	*out = *in
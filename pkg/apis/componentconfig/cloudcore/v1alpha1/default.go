@@ -40,6 +40,18 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 				BindAddress:     "127.0.0.1:9091",
 				EnableProfiling: false,
 			},
+			Audit: &Audit{
+				Enable:        false,
+				LogPath:       constants.DefaultCloudAuditLogPath,
+				LogMaxAge:     constants.DefaultAuditLogMaxAge,
+				LogMaxBackups: constants.DefaultAuditLogMaxBackups,
+				LogMaxSize:    constants.DefaultAuditLogMaxSize,
+			},
+			Tracing: &Tracing{
+				Enable:                 false,
+				Endpoint:               constants.DefaultTracingEndpoint,
+				SamplingRatePerMillion: constants.DefaultTracingSamplingRatePerMillion,
+			},
 		},
 		KubeAPIConfig: &KubeAPIConfig{
 			ContentType: constants.DefaultKubeContentType,
@@ -113,6 +125,12 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 			},
 			DynamicController: &DynamicController{
 				Enable: false,
+				Subscription: &DynamicControllerSubscription{
+					Enable: false,
+					DefaultResources: []string{"nodes", "services", "endpoints", "endpointslices.discovery.k8s.io",
+						"pods", "configmaps", "secrets", "namespaces"},
+					DefaultMaxWatchesPerNode: constants.DefaultMaxWatchesPerNode,
+				},
 			},
 			CloudStream: &CloudStream{
 				Enable:                  false,
@@ -130,6 +148,15 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 				Address:     "0.0.0.0",
 				Port:        9443,
 				RestTimeout: 60,
+				ServiceInvocation: &ServiceInvocation{
+					Enable:         false,
+					Address:        "0.0.0.0",
+					Port:           constants.DefaultServiceInvocationPort,
+					Timeout:        60,
+					MaxTimeout:     300,
+					MaxBodySize:    constants.DefaultServiceInvocationMaxBodySize,
+					AsyncResultTTL: 300,
+				},
 			},
 			IptablesManager: &IptablesManager{
 				Enable: true,
@@ -45,6 +45,50 @@ type CommonConfig struct {
 
 	// MonitorServer holds config that exposes prometheus metrics and pprof
 	MonitorServer MonitorServer `json:"monitorServer,omitempty"`
+
+	// Audit indicates the config of audit logging of the Applications from edge nodes,
+	// and the audit events forwarded by edge nodes
+	Audit *Audit `json:"audit,omitempty"`
+
+	// Tracing indicates the config of OpenTelemetry tracing of the messages between cloud and edge
+	Tracing *Tracing `json:"tracing,omitempty"`
+}
+
+// Tracing indicates the config of OpenTelemetry tracing.
+// The spans are exported over OTLP gRPC to the collector.
+type Tracing struct {
+	// Enable indicates whether the tracing is enabled
+	// default false
+	Enable bool `json:"enable"`
+	// Endpoint is the address of the OTLP gRPC collector
+	// default "localhost:4317"
+	Endpoint string `json:"endpoint,omitempty"`
+	// SamplingRatePerMillion is the number of the traces sampled per million messages
+	// default 10000
+	SamplingRatePerMillion int32 `json:"samplingRatePerMillion,omitempty"`
+}
+
+// Audit indicates the config of audit logging of CloudCore.
+// The audit events are in the format of Kubernetes audit events.
+type Audit struct {
+	// Enable indicates whether the audit logging is enabled
+	// default false
+	Enable bool `json:"enable"`
+	// PolicyFile is the path to the Kubernetes audit policy file, which decides the audit level of requests.
+	// The metadata of all requests is audited if it is empty.
+	PolicyFile string `json:"policyFile,omitempty"`
+	// LogPath is the path of the audit log file, "-" means standard out
+	// default "/var/log/kubeedge/cloudcore-audit.log"
+	LogPath string `json:"logPath,omitempty"`
+	// LogMaxAge is the max days to retain the rotated audit log files
+	// default 7
+	LogMaxAge int32 `json:"logMaxAge,omitempty"`
+	// LogMaxBackups is the max number of the rotated audit log files to retain
+	// default 10
+	LogMaxBackups int32 `json:"logMaxBackups,omitempty"`
+	// LogMaxSize is the max size in megabytes of the audit log file before it gets rotated
+	// default 100
+	LogMaxSize int32 `json:"logMaxSize,omitempty"`
 }
 
 // MonitorServer indicates MonitorServer config
@@ -56,6 +100,12 @@ type MonitorServer struct {
 	// EnableProfiling enables profiling via web interface on /debug/pprof handler.
 	// Profiling handlers will be handled by monitor server.
 	EnableProfiling bool `json:"enableProfiling,omitempty"`
+
+	// EnableNodeLabel adds the node label to the metrics of each edge node, e.g. the message
+	// backlog and the ack latency of cloudhub, otherwise the metrics of all nodes are aggregated.
+	// It produces series per node, enable it only with a moderate number of edge nodes.
+	// default false
+	EnableNodeLabel bool `json:"enableNodeLabel,omitempty"`
 }
 
 // KubeAPIConfig indicates the configuration for interacting with k8s server
@@ -422,6 +472,35 @@ type DynamicController struct {
 	// if set to false (for debugging etc.), skip checking other dynamicController configs.
 	// default true
 	Enable bool `json:"enable"`
+	// PassThroughPaths indicates the requests from edge metaserver that are allowed to be passed
+	// through to the apiserver, every entry has the form "<path>::<verb>", the path may end with "*"
+	// to match all sub paths.
+	// default the discovery, OpenAPI, version and health check paths
+	PassThroughPaths []string `json:"passThroughPaths,omitempty"`
+	// Subscription indicates the limits of the resources that the applications on edge nodes
+	// may watch through MetaServer
+	Subscription *DynamicControllerSubscription `json:"subscription,omitempty"`
+}
+
+// DynamicControllerSubscription indicates the limits of the watches from edge nodes.
+// Besides the DefaultResources, an edge node may only watch the resources it declares with
+// the node labels "subscription.kubeedge.io/<resource>.<group>", whose values are the max
+// numbers of watches it requests, e.g. "subscription.kubeedge.io/deployments.apps: 5".
+// The watches of a node on a resource are capped by the smaller of its declaration and the limit here.
+type DynamicControllerSubscription struct {
+	// Enable indicates whether the watches from edge nodes are limited
+	// default false
+	Enable bool `json:"enable"`
+	// DefaultResources indicates the resources that all edge nodes may watch without declaring them,
+	// every entry has the form "<resource>.<group>", or "<resource>" for the core group
+	// default nodes, services, endpoints, endpointslices.discovery.k8s.io, pods, configmaps, secrets and namespaces
+	DefaultResources []string `json:"defaultResources,omitempty"`
+	// Limits maps the resources of the form "<resource>.<group>" to the max number of watches of each edge node on them
+	Limits map[string]int32 `json:"limits,omitempty"`
+	// DefaultMaxWatchesPerNode indicates the max number of watches of each edge node on the resources not in Limits,
+	// 0 means no limit
+	// default 100
+	DefaultMaxWatchesPerNode int32 `json:"defaultMaxWatchesPerNode,omitempty"`
 }
 
 // CloudStream indicates the stream controller
@@ -463,6 +542,35 @@ type Router struct {
 	Address     string `json:"address,omitempty"`
 	Port        uint32 `json:"port,omitempty"`
 	RestTimeout uint32 `json:"restTimeout,omitempty"`
+	// ServiceInvocation indicates the config of the API invoking the http services on the edge nodes
+	ServiceInvocation *ServiceInvocation `json:"serviceInvocation,omitempty"`
+}
+
+// ServiceInvocation indicates the config of the API invoking the http services on the edge nodes,
+// served over https at /nodes/{node}/services/{target}/{path}
+type ServiceInvocation struct {
+	// Enable indicates whether the API is served
+	// default false
+	Enable bool `json:"enable"`
+	// Address indicates the address the API listens on
+	// default "0.0.0.0"
+	Address string `json:"address,omitempty"`
+	// Port indicates the port the API listens on
+	// default 10005
+	Port uint32 `json:"port,omitempty"`
+	// Timeout indicates the default timeout in seconds to wait for the response from the edge node,
+	// a request may set a shorter or a longer timeout up to MaxTimeout
+	// default 60
+	Timeout uint32 `json:"timeout,omitempty"`
+	// MaxTimeout indicates the max timeout in seconds that a request may set
+	// default 300
+	MaxTimeout uint32 `json:"maxTimeout,omitempty"`
+	// MaxBodySize indicates the max size in bytes of the request and response bodies
+	// default 12582912
+	MaxBodySize int64 `json:"maxBodySize,omitempty"`
+	// AsyncResultTTL indicates how long in seconds the result of an async invocation is kept
+	// default 300
+	AsyncResultTTL uint32 `json:"asyncResultTTL,omitempty"`
 }
 
 // IptablesManager indicates the config of Iptables
@@ -44,11 +44,34 @@ func ValidateCloudCoreConfiguration(c *v1alpha1.CloudCoreConfig) field.ErrorList
 	allErrs = append(allErrs, ValidateModuleSyncController(*c.Modules.SyncController)...)
 	allErrs = append(allErrs, ValidateModuleDynamicController(*c.Modules.DynamicController)...)
 	allErrs = append(allErrs, ValidateModuleCloudStream(*c.Modules.CloudStream)...)
+	if c.Modules.Router != nil {
+		allErrs = append(allErrs, ValidateModuleRouter(*c.Modules.Router)...)
+	}
 	return allErrs
 }
 
 func ValidateCommonConfig(c v1alpha1.CommonConfig) field.ErrorList {
-	return validateHostPort(c.MonitorServer.BindAddress, field.NewPath("monitorServer.bindAddress"))
+	allErrs := validateHostPort(c.MonitorServer.BindAddress, field.NewPath("monitorServer.bindAddress"))
+	if c.Tracing != nil {
+		allErrs = append(allErrs, ValidateTracing(*c.Tracing)...)
+	}
+	return allErrs
+}
+
+// ValidateTracing validates `t` and returns an errorList if it is invalid
+func ValidateTracing(t v1alpha1.Tracing) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !t.Enable {
+		return allErrs
+	}
+	if t.Endpoint == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("tracing.endpoint"), "endpoint must not be empty"))
+	}
+	if t.SamplingRatePerMillion < 0 || t.SamplingRatePerMillion > 1000000 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("tracing.samplingRatePerMillion"), t.SamplingRatePerMillion,
+			"samplingRatePerMillion must be in [0, 1000000]"))
+	}
+	return allErrs
 }
 
 func validateHostPort(input string, fldPath *field.Path) field.ErrorList {
@@ -169,6 +192,35 @@ func ValidateModuleDynamicController(d v1alpha1.DynamicController) field.ErrorLi
 	}
 
 	allErrs := field.ErrorList{}
+	if d.Subscription != nil && d.Subscription.Enable {
+		allErrs = append(allErrs, ValidateDynamicControllerSubscription(*d.Subscription, field.NewPath("Subscription"))...)
+	}
+	return allErrs
+}
+
+// ValidateDynamicControllerSubscription validates `s` and returns an errorList if it is invalid
+func ValidateDynamicControllerSubscription(s v1alpha1.DynamicControllerSubscription, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, resource := range s.DefaultResources {
+		if resource == "" || strings.Contains(resource, "/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("DefaultResources").Index(i), resource,
+				"must have the form <resource>.<group>"))
+		}
+	}
+	for resource, limit := range s.Limits {
+		if resource == "" || strings.Contains(resource, "/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Limits"), resource,
+				"must have the form <resource>.<group>"))
+		}
+		if limit < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Limits").Key(resource), limit,
+				"must not be negative"))
+		}
+	}
+	if s.DefaultMaxWatchesPerNode < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("DefaultMaxWatchesPerNode"), s.DefaultMaxWatchesPerNode,
+			"must not be negative"))
+	}
 	return allErrs
 }
 
@@ -203,6 +255,29 @@ func ValidateModuleCloudStream(d v1alpha1.CloudStream) field.ErrorList {
 	return allErrs
 }
 
+// ValidateModuleRouter validates `r` and returns an errorList if it is invalid
+func ValidateModuleRouter(r v1alpha1.Router) field.ErrorList {
+	if !r.Enable || r.ServiceInvocation == nil || !r.ServiceInvocation.Enable {
+		return field.ErrorList{}
+	}
+
+	allErrs := field.ErrorList{}
+	s := r.ServiceInvocation
+	if s.Port == 0 || s.Port > 65535 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("ServiceInvocation").Child("Port"), s.Port,
+			"must be between 1 and 65535"))
+	}
+	if s.MaxTimeout < s.Timeout {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("ServiceInvocation").Child("MaxTimeout"), s.MaxTimeout,
+			"must not be less than Timeout"))
+	}
+	if s.MaxBodySize <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("ServiceInvocation").Child("MaxBodySize"), s.MaxBodySize,
+			"must be positive"))
+	}
+	return allErrs
+}
+
 // ValidateKubeAPIConfig validates `k` and returns an errorList if it is invalid
 func ValidateKubeAPIConfig(k v1alpha1.KubeAPIConfig) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -352,6 +352,24 @@ func TestValidateModuleDynamicController(t *testing.T) {
 			},
 			expected: field.ErrorList{},
 		},
+		{
+			name: "case3 invalid subscription",
+			input: v1alpha1.DynamicController{
+				Enable: true,
+				Subscription: &v1alpha1.DynamicControllerSubscription{
+					Enable:                   true,
+					DefaultResources:         []string{"pods", "apps/v1/deployments"},
+					Limits:                   map[string]int32{"deployments.apps": -1},
+					DefaultMaxWatchesPerNode: 10,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("Subscription").Child("DefaultResources").Index(1), "apps/v1/deployments",
+					"must have the form <resource>.<group>"),
+				field.Invalid(field.NewPath("Subscription").Child("Limits").Key("deployments.apps"), int32(-1),
+					"must not be negative"),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -361,6 +379,60 @@ func TestValidateModuleDynamicController(t *testing.T) {
 	}
 }
 
+func TestValidateModuleRouter(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    v1alpha1.Router
+		expected field.ErrorList
+	}{
+		{
+			name: "case1 service invocation not enabled",
+			input: v1alpha1.Router{
+				Enable:            true,
+				ServiceInvocation: &v1alpha1.ServiceInvocation{Enable: false},
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case2 all ok",
+			input: v1alpha1.Router{
+				Enable: true,
+				ServiceInvocation: &v1alpha1.ServiceInvocation{
+					Enable:      true,
+					Port:        10005,
+					Timeout:     60,
+					MaxTimeout:  300,
+					MaxBodySize: 1 << 20,
+				},
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case3 invalid service invocation",
+			input: v1alpha1.Router{
+				Enable: true,
+				ServiceInvocation: &v1alpha1.ServiceInvocation{
+					Enable:     true,
+					Port:       0,
+					Timeout:    60,
+					MaxTimeout: 30,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("ServiceInvocation").Child("Port"), uint32(0), "must be between 1 and 65535"),
+				field.Invalid(field.NewPath("ServiceInvocation").Child("MaxTimeout"), uint32(30), "must not be less than Timeout"),
+				field.Invalid(field.NewPath("ServiceInvocation").Child("MaxBodySize"), int64(0), "must be positive"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateModuleRouter(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
 func TestValidateModuleCloudStream(t *testing.T) {
 	dir := t.TempDir()
 
@@ -519,6 +591,46 @@ func TestValidateCommonConfig(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+		{
+			name: "invalid tracing sampling rate",
+			commonConfig: v1alpha1.CommonConfig{
+				MonitorServer: v1alpha1.MonitorServer{
+					BindAddress: "127.0.0.1:9091",
+				},
+				Tracing: &v1alpha1.Tracing{
+					Enable:                 true,
+					Endpoint:               "localhost:4317",
+					SamplingRatePerMillion: 2000000,
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "empty tracing endpoint",
+			commonConfig: v1alpha1.CommonConfig{
+				MonitorServer: v1alpha1.MonitorServer{
+					BindAddress: "127.0.0.1:9091",
+				},
+				Tracing: &v1alpha1.Tracing{
+					Enable: true,
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "valid tracing config",
+			commonConfig: v1alpha1.CommonConfig{
+				MonitorServer: v1alpha1.MonitorServer{
+					BindAddress: "127.0.0.1:9091",
+				},
+				Tracing: &v1alpha1.Tracing{
+					Enable:                 true,
+					Endpoint:               "localhost:4317",
+					SamplingRatePerMillion: 10000,
+				},
+			},
+			expectedErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
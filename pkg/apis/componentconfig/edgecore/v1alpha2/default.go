@@ -120,6 +120,13 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 					TLSMqttCertFile:       constants.DefaultMqttCertFile,
 					TLSMqttPrivateKeyFile: constants.DefaultMqttKeyFile,
 				},
+				MqttServerAuth: &MqttServerAuth{
+					Enable:            false,
+					TLSCAFile:         constants.DefaultMqttCAFile,
+					TLSCertFile:       constants.DefaultMqttCertFile,
+					TLSPrivateKeyFile: constants.DefaultMqttKeyFile,
+					EnableDeviceACLs:  true,
+				},
 			},
 			MetaManager: &MetaManager{
 				Enable:             true,
@@ -133,13 +140,32 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 					TLSCertFile:           constants.DefaultCertFile,
 					TLSPrivateKeyFile:     constants.DefaultKeyFile,
 					ServiceAccountIssuers: []string{constants.DefaultServiceAccountIssuer},
+					OfflineTokenIssuer: &OfflineTokenIssuer{
+						Enable:               false,
+						KeyFile:              constants.DefaultOfflineTokenIssuerKeyFile,
+						CertFile:             constants.DefaultOfflineTokenIssuerCertFile,
+						MaxExpirationSeconds: constants.DefaultOfflineTokenMaxExpirationSeconds,
+					},
+					Audit: &MetaServerAudit{
+						Enable:        false,
+						LogPath:       constants.DefaultMetaServerAuditLogPath,
+						LogMaxAge:     constants.DefaultAuditLogMaxAge,
+						LogMaxBackups: constants.DefaultAuditLogMaxBackups,
+						LogMaxSize:    constants.DefaultAuditLogMaxSize,
+					},
 				},
 			},
 			ServiceBus: &ServiceBus{
-				Enable:  false,
-				Server:  "127.0.0.1",
-				Port:    9060,
-				Timeout: 60,
+				Enable:      false,
+				Server:      "127.0.0.1",
+				Port:        9060,
+				Timeout:     60,
+				MaxBodySize: constants.DefaultServiceBusMaxBodySize,
+				TLS: &ServiceBusTLS{
+					Enable:            false,
+					TLSCertFile:       constants.DefaultCertFile,
+					TLSPrivateKeyFile: constants.DefaultKeyFile,
+				},
 			},
 			DeviceTwin: &DeviceTwin{
 				Enable: true,
@@ -158,6 +184,16 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 				WriteDeadline:           15,
 			},
 		},
+		MonitorServer: &MonitorServer{
+			Enable:      false,
+			BindAddress: constants.DefaultEdgeMonitorServerAddr,
+		},
+		Tracing: &Tracing{
+			Enable:                 false,
+			Endpoint:               constants.DefaultTracingEndpoint,
+			SamplingRatePerMillion: constants.DefaultTracingSamplingRatePerMillion,
+			MaxBufferedSpans:       constants.DefaultTracingMaxBufferedSpans,
+		},
 	}
 	return
 }
@@ -32,6 +32,15 @@ const (
 	MqttModeExternal MqttMode = 2
 )
 
+const (
+	// MqttBridgeOut mirrors the topics from the internal mqtt broker to the remote broker
+	MqttBridgeOut MqttBridgeDirection = "out"
+	// MqttBridgeIn mirrors the topics from the remote broker to the internal mqtt broker
+	MqttBridgeIn MqttBridgeDirection = "in"
+	// MqttBridgeBoth mirrors the topics in both directions
+	MqttBridgeBoth MqttBridgeDirection = "both"
+)
+
 const (
 	// DataBaseDriverName is sqlite3
 	DataBaseDriverName = "sqlite3"
@@ -41,6 +50,7 @@ const (
 
 type ProtocolName string
 type MqttMode int
+type MqttBridgeDirection string
 
 // EdgeCoreConfig indicates the EdgeCore config which read from EdgeCore config file
 type EdgeCoreConfig struct {
@@ -53,6 +63,43 @@ type EdgeCoreConfig struct {
 	Modules *Modules `json:"modules,omitempty"`
 	// FeatureGates is a map of feature names to bools that enable or disable alpha/experimental features.
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// MonitorServer indicates the monitor server config of edgecore
+	MonitorServer *MonitorServer `json:"monitorServer,omitempty"`
+	// Tracing indicates the config of OpenTelemetry tracing of the messages between cloud and edge
+	Tracing *Tracing `json:"tracing,omitempty"`
+}
+
+// Tracing indicates the config of OpenTelemetry tracing.
+// The spans are exported over OTLP gRPC to the collector.
+type Tracing struct {
+	// Enable indicates whether the tracing is enabled
+	// default false
+	Enable bool `json:"enable"`
+	// Endpoint is the address of the OTLP gRPC collector
+	// default "localhost:4317"
+	Endpoint string `json:"endpoint,omitempty"`
+	// SamplingRatePerMillion is the number of the traces sampled per million messages
+	// started on edge, the messages from cloud follow the sampling decision of cloudcore
+	// default 10000
+	SamplingRatePerMillion int32 `json:"samplingRatePerMillion,omitempty"`
+	// MaxBufferedSpans is the max number of the spans buffered while the collector is unreachable,
+	// e.g. when the edge node is offline, the oldest spans are dropped when it is exceeded
+	// default 2048
+	MaxBufferedSpans int32 `json:"maxBufferedSpans,omitempty"`
+}
+
+// MonitorServer indicates the config of the server serving the metrics and health of edgecore
+type MonitorServer struct {
+	// Enable indicates whether the monitor server is enabled
+	// default false
+	Enable bool `json:"enable"`
+	// BindAddress is the IP address and port for the monitor server to serve on,
+	// the metrics can also be scraped from cloudstream with the path /metrics/edgecore
+	// default "127.0.0.1:10352"
+	BindAddress string `json:"bindAddress,omitempty"`
+	// EnableProfiling enables profiling via web interface on /debug/pprof handler.
+	// default false
+	EnableProfiling bool `json:"enableProfiling,omitempty"`
 }
 
 // DataBase indicates the database info
@@ -916,6 +963,52 @@ type EventBus struct {
 	MqttMode MqttMode `json:"mqttMode"`
 	// Tls indicates tls config for EventBus module
 	TLS *EventBusTLS `json:"eventBusTLS,omitempty"`
+	// MqttServerAuth indicates the client authentication and topic ACLs of the internal mqtt broker
+	MqttServerAuth *MqttServerAuth `json:"mqttServerAuth,omitempty"`
+	// MqttBridges indicates the bridges mirroring topics between the internal mqtt broker and
+	// the remote brokers, which only work when the internal mqtt broker is enabled.
+	// The bridges managed by the eventbus messages are stored in the database, and replace
+	// the ones with the same name here
+	MqttBridges []MqttBridge `json:"mqttBridges,omitempty"`
+}
+
+// MqttBridge indicates a bridge between the internal mqtt broker and a remote mqtt broker
+type MqttBridge struct {
+	// Name indicates the unique name of the bridge
+	Name string `json:"name"`
+	// Server indicates the url of the remote mqtt broker, e.g. "tcp://192.168.1.10:1883"
+	Server string `json:"server"`
+	// ClientID indicates the client id connecting to the remote broker
+	// default "kubeedge-bridge-<nodeName>-<name>"
+	ClientID string `json:"clientID,omitempty"`
+	// Username indicates the username connecting to the remote broker
+	Username string `json:"username,omitempty"`
+	// Password indicates the password connecting to the remote broker
+	Password string `json:"password,omitempty"`
+	// TLS indicates the tls config connecting to the remote broker
+	TLS *EventBusTLS `json:"tls,omitempty"`
+	// Topics indicates the topics mirrored by the bridge
+	Topics []MqttBridgeTopic `json:"topics"`
+	// MaxBufferedMessages indicates the max number of the messages to the remote broker buffered
+	// while it is unreachable, the oldest messages are dropped when it is exceeded
+	// default 1000
+	MaxBufferedMessages int32 `json:"maxBufferedMessages,omitempty"`
+}
+
+// MqttBridgeTopic indicates the topics mirrored by a bridge. A topic matching the pattern is
+// localPrefix + topic on the internal mqtt broker, and remotePrefix + topic on the remote broker.
+type MqttBridgeTopic struct {
+	// Pattern indicates the mqtt topic filter of the mirrored topics
+	Pattern string `json:"pattern"`
+	// Direction indicates the direction of mirroring, "out", "in" or "both"
+	Direction MqttBridgeDirection `json:"direction"`
+	// QOS indicates the qos of mirroring the messages
+	// default 0
+	QOS uint8 `json:"qos,omitempty"`
+	// LocalPrefix indicates the prefix of the topics on the internal mqtt broker
+	LocalPrefix string `json:"localPrefix,omitempty"`
+	// RemotePrefix indicates the prefix of the topics on the remote broker
+	RemotePrefix string `json:"remotePrefix,omitempty"`
 }
 
 // EventBusTLS indicates the EventBus tls config with MQTT broker
@@ -934,6 +1027,55 @@ type EventBusTLS struct {
 	TLSMqttPrivateKeyFile string `json:"tlsMqttPrivateKeyFile,omitempty"`
 }
 
+// MqttServerAuth indicates the client authentication and topic ACLs of the internal mqtt broker
+type MqttServerAuth struct {
+	// Enable indicates whether the clients of the internal mqtt broker must authenticate,
+	// with username and password or with client certificate, and may only publish and
+	// subscribe the topics granted by the ACLs
+	// default false
+	Enable bool `json:"enable"`
+	// Users indicates the usernames and passwords of the clients
+	Users []MqttUser `json:"users,omitempty"`
+	// TLSCAFile indicates the CA file verifying the client certificates, the common name of the
+	// client certificate is taken as the username. The TLS files are only used when
+	// mqttServerInternal is a "tls://", "mqtts://" or "wss://" url
+	// default "/etc/kubeedge/ca/rootCA.crt"
+	TLSCAFile string `json:"tlsCAFile,omitempty"`
+	// TLSCertFile indicates the file containing x509 Certificate served by the internal mqtt broker
+	// default "/etc/kubeedge/certs/server.crt"
+	TLSCertFile string `json:"tlsCertFile,omitempty"`
+	// TLSPrivateKeyFile indicates the file containing x509 private key matching tlsCertFile
+	// default "/etc/kubeedge/certs/server.key"
+	TLSPrivateKeyFile string `json:"tlsPrivateKeyFile,omitempty"`
+	// ACLs indicates the topics that the clients may publish and subscribe
+	ACLs []MqttACL `json:"acls,omitempty"`
+	// EnableDeviceACLs indicates whether a mapper, authenticated with the mapper name as username,
+	// may publish and subscribe the topics of the devices of its protocol, derived from the
+	// device and mapper registrations
+	// default true
+	EnableDeviceACLs bool `json:"enableDeviceACLs"`
+}
+
+// MqttUser indicates the credentials of a client of the internal mqtt broker
+type MqttUser struct {
+	// Username indicates the username of the client
+	Username string `json:"username"`
+	// Password indicates the password of the client
+	Password string `json:"password"`
+}
+
+// MqttACL indicates the topics granted to a client of the internal mqtt broker.
+// The topics are mqtt topic filters, in which "+" matches one level and "#" matches
+// all the remaining levels.
+type MqttACL struct {
+	// Username indicates the username of the client, "*" grants the topics to all the clients
+	Username string `json:"username"`
+	// Publish indicates the topics that the client may publish to
+	Publish []string `json:"publish,omitempty"`
+	// Subscribe indicates the topics that the client may subscribe
+	Subscribe []string `json:"subscribe,omitempty"`
+}
+
 // MetaManager indicates the MetaManager module config
 type MetaManager struct {
 	// Enable indicates whether MetaManager is enabled,
@@ -960,6 +1102,64 @@ type MetaServer struct {
 	ServiceAccountIssuers  []string `json:"serviceAccountIssuers"`
 	APIAudiences           []string `json:"apiAudiences"`
 	ServiceAccountKeyFiles []string `json:"serviceAccountKeyFiles"`
+	// PassThroughPaths indicates the non-resource requests which are passed through to the cloud apiserver,
+	// every entry has the form "<path>::<verb>", the path may end with "*" to match all sub paths.
+	// The responses of get requests are cached in the edge db so that they can be served offline.
+	// default the discovery, OpenAPI, version and health check paths
+	PassThroughPaths []string `json:"passThroughPaths,omitempty"`
+	// OfflineTokenIssuer indicates the config of minting ServiceAccount tokens on edge
+	// when they cannot be got from cloud
+	OfflineTokenIssuer *OfflineTokenIssuer `json:"offlineTokenIssuer,omitempty"`
+	// Audit indicates the config of audit logging of the requests to MetaServer
+	Audit *MetaServerAudit `json:"audit,omitempty"`
+}
+
+// MetaServerAudit indicates the config of audit logging of the requests to MetaServer.
+// The audit events are in the format of Kubernetes audit events, recording the user or
+// ServiceAccount of the request, the verb, resource, namespace, name, authorization decision
+// and latency.
+type MetaServerAudit struct {
+	// Enable indicates whether the requests to MetaServer are audited
+	// default false
+	Enable bool `json:"enable"`
+	// PolicyFile is the path to the Kubernetes audit policy file, which decides the audit level of requests.
+	// The metadata of all requests is audited if it is empty.
+	PolicyFile string `json:"policyFile,omitempty"`
+	// LogPath is the path of the audit log file, "-" means standard out
+	// default "/var/log/kubeedge/metaserver-audit.log"
+	LogPath string `json:"logPath,omitempty"`
+	// LogMaxAge is the max days to retain the rotated audit log files
+	// default 7
+	LogMaxAge int32 `json:"logMaxAge,omitempty"`
+	// LogMaxBackups is the max number of the rotated audit log files to retain
+	// default 10
+	LogMaxBackups int32 `json:"logMaxBackups,omitempty"`
+	// LogMaxSize is the max size in megabytes of the audit log file before it gets rotated
+	// default 100
+	LogMaxSize int32 `json:"logMaxSize,omitempty"`
+	// ForwardToCloud indicates whether the audit events are also forwarded to cloudcore in batches,
+	// the events are only forwarded when the node is connected to the cloud
+	// default false
+	ForwardToCloud bool `json:"forwardToCloud,omitempty"`
+}
+
+// OfflineTokenIssuer indicates the config of the offline ServiceAccount token issuer.
+// The tokens are signed by a per-node key, which is delegated by cloudcore with a
+// certificate signed by the cloudcore CA. Only the ServiceAccounts allowed by the
+// ServiceAccountAccess synced to the node get tokens, and the tokens are only accepted by MetaServer.
+type OfflineTokenIssuer struct {
+	// Enable indicates whether tokens are minted on edge when the cloud is unreachable
+	// default false
+	Enable bool `json:"enable"`
+	// KeyFile is the per-node signing key, it is generated by EdgeCore if not exist
+	// default "/etc/kubeedge/certs/sa-issuer.key"
+	KeyFile string `json:"keyFile,omitempty"`
+	// CertFile is the delegation certificate of KeyFile signed by cloudcore
+	// default "/etc/kubeedge/certs/sa-issuer.crt"
+	CertFile string `json:"certFile,omitempty"`
+	// MaxExpirationSeconds is the max lifetime of the minted tokens, longer expiration requested is capped to it
+	// default 600
+	MaxExpirationSeconds int64 `json:"maxExpirationSeconds,omitempty"`
 }
 
 // ServiceBus indicates the ServiceBus module config
@@ -974,6 +1174,38 @@ type ServiceBus struct {
 	Port int `json:"port"`
 	// Timeout indicates timeout for servicebus receive mseeage
 	Timeout int `json:"timeout"`
+	// TLS indicates the TLS config of the http server
+	TLS *ServiceBusTLS `json:"tls,omitempty"`
+	// TokenFile indicates the file of the bearer tokens accepted by the http server, one token per line.
+	// If TokenFile or TLS.TLSCAFile is set, a request must carry a valid token or a verified client certificate
+	TokenFile string `json:"tokenFile,omitempty"`
+	// AllowedTargets indicates the non-loopback hosts, as "host" or "host:port", that the requests from
+	// the cloud may be forwarded to. The Kubernetes Services in the form of "<service>.<namespace>.svc"
+	// are resolved from the edge metaserver cache and always allowed
+	AllowedTargets []string `json:"allowedTargets,omitempty"`
+	// UpstreamCAFile indicates the CA file verifying the HTTPS targets, the system CAs are used if it is empty
+	UpstreamCAFile string `json:"upstreamCAFile,omitempty"`
+	// MaxBodySize indicates the max size in bytes of the request body of the http server, a larger body is
+	// rejected with 413 instead of being truncated. The response bodies of the targets are not limited, they
+	// are streamed to the cloud in chunks of MaxBodySize
+	// default 5000000
+	MaxBodySize int64 `json:"maxBodySize,omitempty"`
+}
+
+// ServiceBusTLS indicates the TLS config of the servicebus http server
+type ServiceBusTLS struct {
+	// Enable indicates whether the http server serves over TLS
+	// default false
+	Enable bool `json:"enable"`
+	// TLSCAFile indicates the CA file verifying the client certificates, the client certificates
+	// are not verified if it is empty
+	TLSCAFile string `json:"tlsCAFile,omitempty"`
+	// TLSCertFile indicates the file containing x509 Certificate for HTTPS
+	// default "/etc/kubeedge/certs/server.crt"
+	TLSCertFile string `json:"tlsCertFile,omitempty"`
+	// TLSPrivateKeyFile indicates the file containing x509 private key matching tlsCertFile
+	// default "/etc/kubeedge/certs/server.key"
+	TLSPrivateKeyFile string `json:"tlsPrivateKeyFile,omitempty"`
 }
 
 // DeviceTwin indicates the DeviceTwin module config
@@ -18,8 +18,12 @@ package validation
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path"
+	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -39,6 +43,12 @@ func ValidateEdgeCoreConfiguration(c *v1alpha2.EdgeCoreConfig) field.ErrorList {
 	allErrs = append(allErrs, ValidateModuleDeviceTwin(*c.Modules.DeviceTwin)...)
 	allErrs = append(allErrs, ValidateModuleDBTest(*c.Modules.DBTest)...)
 	allErrs = append(allErrs, ValidateModuleEdgeStream(*c.Modules.EdgeStream)...)
+	if c.MonitorServer != nil {
+		allErrs = append(allErrs, ValidateMonitorServer(*c.MonitorServer)...)
+	}
+	if c.Tracing != nil {
+		allErrs = append(allErrs, ValidateTracing(*c.Tracing)...)
+	}
 	return allErrs
 }
 
@@ -91,9 +101,109 @@ func ValidateModuleEventBus(m v1alpha2.EventBus) field.ErrorList {
 			fmt.Sprintf("Mode need in [%v,%v] range", v1alpha2.MqttModeInternal,
 				v1alpha2.MqttModeExternal)))
 	}
+	if m.MqttServerAuth != nil && m.MqttServerAuth.Enable {
+		allErrs = append(allErrs, ValidateMqttServerAuth(*m.MqttServerAuth)...)
+	}
+	if len(m.MqttBridges) > 0 && m.MqttMode > v1alpha2.MqttModeBoth {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("mqttBridges"), len(m.MqttBridges),
+			"mqtt bridges require the internal mqtt broker"))
+	}
+	names := make(map[string]bool)
+	for i, bridge := range m.MqttBridges {
+		path := field.NewPath("mqttBridges").Index(i)
+		if names[bridge.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), bridge.Name))
+		}
+		names[bridge.Name] = true
+		allErrs = append(allErrs, ValidateMqttBridge(bridge, path)...)
+	}
 	return allErrs
 }
 
+// ValidateMqttBridge validates `b` and returns an errorList if it is invalid
+func ValidateMqttBridge(b v1alpha2.MqttBridge, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if b.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("name"), "name must not be empty"))
+	}
+	if _, err := url.ParseRequestURI(b.Server); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("server"), b.Server, err.Error()))
+	}
+	if b.MaxBufferedMessages < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxBufferedMessages"), b.MaxBufferedMessages,
+			"maxBufferedMessages must not be a negative number"))
+	}
+	if len(b.Topics) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("topics"), "at least one topic is required"))
+	}
+	for i, topic := range b.Topics {
+		topicPath := path.Child("topics").Index(i)
+		if !isValidTopicFilter(topic.Pattern) {
+			allErrs = append(allErrs, field.Invalid(topicPath.Child("pattern"), topic.Pattern, "invalid mqtt topic filter"))
+		}
+		switch topic.Direction {
+		case v1alpha2.MqttBridgeOut, v1alpha2.MqttBridgeIn, v1alpha2.MqttBridgeBoth:
+		default:
+			allErrs = append(allErrs, field.NotSupported(topicPath.Child("direction"), topic.Direction,
+				[]string{string(v1alpha2.MqttBridgeOut), string(v1alpha2.MqttBridgeIn), string(v1alpha2.MqttBridgeBoth)}))
+		}
+		if topic.QOS > 2 {
+			allErrs = append(allErrs, field.Invalid(topicPath.Child("qos"), topic.QOS, "qos must be 0, 1 or 2"))
+		}
+		if strings.ContainsAny(topic.LocalPrefix+topic.RemotePrefix, "+#") {
+			allErrs = append(allErrs, field.Invalid(topicPath, topic.LocalPrefix+topic.RemotePrefix,
+				"topic prefixes must not contain wildcards"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateMqttServerAuth validates `a` and returns an errorList if it is invalid
+func ValidateMqttServerAuth(a v1alpha2.MqttServerAuth) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, user := range a.Users {
+		if user.Username == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("mqttServerAuth", "users").Index(i).Child("username"),
+				"username must not be empty"))
+		}
+	}
+	for i, acl := range a.ACLs {
+		path := field.NewPath("mqttServerAuth", "acls").Index(i)
+		if acl.Username == "" {
+			allErrs = append(allErrs, field.Required(path.Child("username"), "username must not be empty"))
+		}
+		for j, topic := range acl.Publish {
+			if !isValidTopicFilter(topic) {
+				allErrs = append(allErrs, field.Invalid(path.Child("publish").Index(j), topic, "invalid mqtt topic filter"))
+			}
+		}
+		for j, topic := range acl.Subscribe {
+			if !isValidTopicFilter(topic) {
+				allErrs = append(allErrs, field.Invalid(path.Child("subscribe").Index(j), topic, "invalid mqtt topic filter"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// isValidTopicFilter checks whether the wildcards "+" and "#" occupy whole levels of the topic
+// filter, and "#" is the last level
+func isValidTopicFilter(topic string) bool {
+	if topic == "" {
+		return false
+	}
+	levels := strings.Split(topic, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return false
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return false
+		}
+	}
+	return true
+}
+
 // ValidateModuleMetaManager validates `m` and returns an errorList if it is invalid
 func ValidateModuleMetaManager(m v1alpha2.MetaManager) field.ErrorList {
 	if !m.Enable {
@@ -109,6 +219,40 @@ func ValidateModuleServiceBus(s v1alpha2.ServiceBus) field.ErrorList {
 		return field.ErrorList{}
 	}
 	allErrs := field.ErrorList{}
+	if s.TLS != nil && s.TLS.Enable {
+		if !utilvalidation.FileIsExist(s.TLS.TLSCertFile) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("tls", "tlsCertFile"), s.TLS.TLSCertFile, "TLSCertFile not exist"))
+		}
+		if !utilvalidation.FileIsExist(s.TLS.TLSPrivateKeyFile) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("tls", "tlsPrivateKeyFile"), s.TLS.TLSPrivateKeyFile, "TLSPrivateKeyFile not exist"))
+		}
+		if s.TLS.TLSCAFile != "" && !utilvalidation.FileIsExist(s.TLS.TLSCAFile) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("tls", "tlsCAFile"), s.TLS.TLSCAFile, "TLSCAFile not exist"))
+		}
+	}
+	if s.TokenFile != "" && !utilvalidation.FileIsExist(s.TokenFile) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("tokenFile"), s.TokenFile, "TokenFile not exist"))
+	}
+	if s.UpstreamCAFile != "" && !utilvalidation.FileIsExist(s.UpstreamCAFile) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("upstreamCAFile"), s.UpstreamCAFile, "UpstreamCAFile not exist"))
+	}
+	for i, target := range s.AllowedTargets {
+		host := target
+		if h, port, err := net.SplitHostPort(target); err == nil {
+			if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("allowedTargets").Index(i), target, "invalid port"))
+				continue
+			}
+			host = h
+		}
+		if host == "" || strings.ContainsAny(host, "/:") {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("allowedTargets").Index(i), target,
+				"must be \"host\" or \"host:port\""))
+		}
+	}
+	if s.MaxBodySize <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("maxBodySize"), s.MaxBodySize, "must be positive"))
+	}
 	return allErrs
 }
 
@@ -138,3 +282,36 @@ func ValidateModuleEdgeStream(m v1alpha2.EdgeStream) field.ErrorList {
 	}
 	return allErrs
 }
+
+// ValidateMonitorServer validates `m` and returns an errorList if it is invalid
+func ValidateMonitorServer(m v1alpha2.MonitorServer) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !m.Enable {
+		return allErrs
+	}
+	if _, _, err := net.SplitHostPort(m.BindAddress); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("bindAddress"), m.BindAddress,
+			fmt.Sprintf("bindAddress must be in the form of host:port, %v", err)))
+	}
+	return allErrs
+}
+
+// ValidateTracing validates `t` and returns an errorList if it is invalid
+func ValidateTracing(t v1alpha2.Tracing) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !t.Enable {
+		return allErrs
+	}
+	if t.Endpoint == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("endpoint"), "endpoint must not be empty"))
+	}
+	if t.SamplingRatePerMillion < 0 || t.SamplingRatePerMillion > 1000000 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("samplingRatePerMillion"), t.SamplingRatePerMillion,
+			"samplingRatePerMillion must be in [0, 1000000]"))
+	}
+	if t.MaxBufferedSpans < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("maxBufferedSpans"), t.MaxBufferedSpans,
+			"maxBufferedSpans must not be a negative number"))
+	}
+	return allErrs
+}
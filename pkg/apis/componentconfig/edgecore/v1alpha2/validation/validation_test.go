@@ -243,6 +243,101 @@ func TestValidateModuleEventBus(t *testing.T) {
 			},
 			expected: field.ErrorList{},
 		},
+		{
+			name: "case3 mqtt server auth not right",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 0,
+				MqttServerAuth: &v1alpha2.MqttServerAuth{
+					Enable: true,
+					Users:  []v1alpha2.MqttUser{{Password: "secret"}},
+					ACLs: []v1alpha2.MqttACL{
+						{
+							Username:  "mapper",
+							Publish:   []string{"$hw/events/device/+/twin/update", "$hw/#/twin"},
+							Subscribe: []string{"$hw/events/device/dev+/twin/#"},
+						},
+					},
+				},
+			},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("mqttServerAuth", "users").Index(0).Child("username"),
+					"username must not be empty"),
+				field.Invalid(field.NewPath("mqttServerAuth", "acls").Index(0).Child("publish").Index(1),
+					"$hw/#/twin", "invalid mqtt topic filter"),
+				field.Invalid(field.NewPath("mqttServerAuth", "acls").Index(0).Child("subscribe").Index(0),
+					"$hw/events/device/dev+/twin/#", "invalid mqtt topic filter"),
+			},
+		},
+		{
+			name: "case5 mqtt bridges not right",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 0,
+				MqttBridges: []v1alpha2.MqttBridge{
+					{
+						Name:   "site",
+						Server: "tcp://192.168.1.10:1883",
+						Topics: []v1alpha2.MqttBridgeTopic{
+							{Pattern: "sensors/#", Direction: v1alpha2.MqttBridgeOut, QOS: 1},
+						},
+					},
+					{
+						Name:   "site",
+						Server: "192.168.1.11",
+						Topics: []v1alpha2.MqttBridgeTopic{
+							{Pattern: "commands/#", Direction: "up", QOS: 3},
+						},
+					},
+				},
+			},
+			expected: field.ErrorList{
+				field.Duplicate(field.NewPath("mqttBridges").Index(1).Child("name"), "site"),
+				field.Invalid(field.NewPath("mqttBridges").Index(1).Child("server"), "192.168.1.11",
+					"parse \"192.168.1.11\": invalid URI for request"),
+				field.NotSupported(field.NewPath("mqttBridges").Index(1).Child("topics").Index(0).Child("direction"),
+					v1alpha2.MqttBridgeDirection("up"), []string{"out", "in", "both"}),
+				field.Invalid(field.NewPath("mqttBridges").Index(1).Child("topics").Index(0).Child("qos"),
+					uint8(3), "qos must be 0, 1 or 2"),
+			},
+		},
+		{
+			name: "case6 mqtt bridges require internal mqtt broker",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 2,
+				MqttBridges: []v1alpha2.MqttBridge{
+					{
+						Name:   "site",
+						Server: "tcp://192.168.1.10:1883",
+						Topics: []v1alpha2.MqttBridgeTopic{
+							{Pattern: "sensors/#", Direction: v1alpha2.MqttBridgeBoth, RemotePrefix: "plant1/"},
+						},
+					},
+				},
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("mqttBridges"), 1,
+				"mqtt bridges require the internal mqtt broker")},
+		},
+		{
+			name: "case4 mqtt server auth ok",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 0,
+				MqttServerAuth: &v1alpha2.MqttServerAuth{
+					Enable: true,
+					Users:  []v1alpha2.MqttUser{{Username: "mapper", Password: "secret"}},
+					ACLs: []v1alpha2.MqttACL{
+						{
+							Username:  "*",
+							Publish:   []string{"$hw/events/device/+/twin/update"},
+							Subscribe: []string{"$hw/events/device/#"},
+						},
+					},
+				},
+			},
+			expected: field.ErrorList{},
+		},
 	}
 
 	for _, c := range cases {
@@ -297,10 +392,33 @@ func TestValidateModuleServiceBus(t *testing.T) {
 		{
 			name: "case2 enabled",
 			input: v1alpha2.ServiceBus{
-				Enable: true,
+				Enable:      true,
+				MaxBodySize: 5 * 1e6,
 			},
 			expected: field.ErrorList{},
 		},
+		{
+			name: "case3 tls and targets not right",
+			input: v1alpha2.ServiceBus{
+				Enable: true,
+				TLS: &v1alpha2.ServiceBusTLS{
+					Enable:            true,
+					TLSCertFile:       "/not/exist/server.crt",
+					TLSPrivateKeyFile: "/not/exist/server.key",
+				},
+				TokenFile:      "/not/exist/tokens",
+				AllowedTargets: []string{"192.168.1.10", "camera.local:8443", "camera.local:0", "camera.local/api"},
+				MaxBodySize:    -1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("tls", "tlsCertFile"), "/not/exist/server.crt", "TLSCertFile not exist"),
+				field.Invalid(field.NewPath("tls", "tlsPrivateKeyFile"), "/not/exist/server.key", "TLSPrivateKeyFile not exist"),
+				field.Invalid(field.NewPath("tokenFile"), "/not/exist/tokens", "TokenFile not exist"),
+				field.Invalid(field.NewPath("allowedTargets").Index(2), "camera.local:0", "invalid port"),
+				field.Invalid(field.NewPath("allowedTargets").Index(3), "camera.local/api", "must be \"host\" or \"host:port\""),
+				field.Invalid(field.NewPath("maxBodySize"), int64(-1), "must be positive"),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -396,3 +514,87 @@ func TestValidateModuleEdgeStream(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateMonitorServer(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    v1alpha2.MonitorServer
+		expected field.ErrorList
+	}{
+		{
+			name: "case1 not enabled",
+			input: v1alpha2.MonitorServer{
+				Enable: false,
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case2 enabled",
+			input: v1alpha2.MonitorServer{
+				Enable:      true,
+				BindAddress: "127.0.0.1:10352",
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case3 invalid bindAddress",
+			input: v1alpha2.MonitorServer{
+				Enable:      true,
+				BindAddress: "127.0.0.1",
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("bindAddress"), "127.0.0.1",
+				"bindAddress must be in the form of host:port, address 127.0.0.1: missing port in address")},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateMonitorServer(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
+func TestValidateTracing(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    v1alpha2.Tracing
+		expected field.ErrorList
+	}{
+		{
+			name: "case1 not enabled",
+			input: v1alpha2.Tracing{
+				Enable: false,
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case2 enabled",
+			input: v1alpha2.Tracing{
+				Enable:                 true,
+				Endpoint:               "localhost:4317",
+				SamplingRatePerMillion: 10000,
+				MaxBufferedSpans:       2048,
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case3 invalid config",
+			input: v1alpha2.Tracing{
+				Enable:                 true,
+				SamplingRatePerMillion: -1,
+				MaxBufferedSpans:       -1,
+			},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("endpoint"), "endpoint must not be empty"),
+				field.Invalid(field.NewPath("samplingRatePerMillion"), int32(-1), "samplingRatePerMillion must be in [0, 1000000]"),
+				field.Invalid(field.NewPath("maxBufferedSpans"), int32(-1), "maxBufferedSpans must not be a negative number"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateTracing(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeJob is used to run an action, such as updating the config of EdgeCore, restarting EdgeCore,
+// rebooting the host or collecting logs, on edge nodes from cloud side.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+type NodeJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of NodeJob.
+	// +optional
+	Spec NodeJobSpec `json:"spec,omitempty"`
+	// Most recently observed status of the NodeJob.
+	// +optional
+	Status NodeJobStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeJobList is a list of NodeJob.
+type NodeJobList struct {
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of NodeJobs.
+	Items []NodeJob `json:"items"`
+}
+
+// NodeJobSpec is the specification of the desired behavior of the NodeJob.
+type NodeJobSpec struct {
+	// Action is the name of the action to run on edge nodes.
+	// The built-in actions are updateConfig, restartEdgeCore, reboot and collectLogs,
+	// and more actions can be registered on edge side.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Action string `json:"action"`
+	// Parameters are passed to the action on edge nodes, the valid keys depend on the action:
+	// updateConfig requires "patch", a JSON merge patch applied to the EdgeCore config;
+	// collectLogs accepts "detail" to collect more information;
+	// restartEdgeCore and reboot accept no parameter.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// TimeoutSeconds limits the duration of running the action on one edge node.
+	// Default to 300.
+	// If set to 0, we'll use the default value 300.
+	// +optional
+	TimeoutSeconds *uint32 `json:"timeoutSeconds,omitempty"`
+	// NodeNames is a request to select some specific nodes. If it is non-empty,
+	// the job simply select these edge nodes to run the action.
+	// Please note that sets of NodeNames and LabelSelector are ORed.
+	// Users must set one and can only set one.
+	// +optional
+	NodeNames []string `json:"nodeNames,omitempty"`
+	// LabelSelector is a filter to select member clusters by labels.
+	// It must match a node's labels for the NodeJob to be operated on that node.
+	// Please note that sets of NodeNames and LabelSelector are ORed.
+	// Users must set one and can only set one.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// Concurrency specifies the max number of edge nodes that run the action at the same time.
+	// The default Concurrency value is 1.
+	// +optional
+	Concurrency int32 `json:"concurrency,omitempty"`
+}
+
+// Built-in actions of NodeJob and their parameters.
+const (
+	// NodeJobActionUpdateConfig applies the JSON merge patch in parameter "patch" to the EdgeCore config,
+	// and restarts EdgeCore to make it effective.
+	NodeJobActionUpdateConfig = "updateConfig"
+	// NodeJobActionRestartEdgeCore restarts EdgeCore.
+	NodeJobActionRestartEdgeCore = "restartEdgeCore"
+	// NodeJobActionReboot reboots the host of the edge node.
+	NodeJobActionReboot = "reboot"
+	// NodeJobActionCollectLogs collects the log bundle like `keadm debug collect`,
+	// and uploads it to cloudcore.
+	NodeJobActionCollectLogs = "collectLogs"
+
+	// NodeJobParamPatch is the JSON merge patch used by NodeJobActionUpdateConfig.
+	NodeJobParamPatch = "patch"
+	// NodeJobParamDetail indicates whether NodeJobActionCollectLogs collects more information, "true" or "false".
+	NodeJobParamDetail = "detail"
+)
+
+// NodeJobState describe the state of NodeJob and of running the action on each edge node.
+// +kubebuilder:validation:Enum=running;completed
+type NodeJobState string
+
+// Valid values of NodeJobState
+const (
+	NodeJobInitialValue NodeJobState = ""
+	NodeJobRunning      NodeJobState = "running"
+	NodeJobCompleted    NodeJobState = "completed"
+)
+
+// NodeJobResult describe the result of running the action on edge nodes.
+// +kubebuilder:validation:Enum=success;failed
+type NodeJobResult string
+
+// Valid values of NodeJobResult
+const (
+	NodeJobSuccess NodeJobResult = "success"
+	NodeJobFailed  NodeJobResult = "failed"
+)
+
+// NodeJobStatus stores the status of NodeJob.
+// contains the status of running the action on each edge node.
+// +kubebuilder:validation:Type=object
+type NodeJobStatus struct {
+	// State represents for the state of the NodeJob.
+	// There are three possible state values: "", running and completed.
+	State NodeJobState `json:"state,omitempty"`
+	// Status contains the status of running the action on each edge node.
+	Status []NodeJobNodeStatus `json:"status,omitempty"`
+}
+
+// NodeJobNodeStatus stores the status of running the action on an edge node.
+// +kubebuilder:validation:Type=object
+type NodeJobNodeStatus struct {
+	// NodeName is the name of edge node.
+	NodeName string `json:"nodeName,omitempty"`
+	// State represents for the state of running the action on the edge node.
+	// There are three possible state values: "", running and completed.
+	State NodeJobState `json:"state,omitempty"`
+	// Result represents the result of the action, it is set when the state is completed.
+	// +optional
+	Result NodeJobResult `json:"result,omitempty"`
+	// Message is the output of the action if it succeeds, or the error reason if it fails.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Artifact is where the artifact collected from the edge node is stored,
+	// in the format of <cloudcore host>:<file path>, such as the log bundle of collectLogs.
+	// +optional
+	Artifact string `json:"artifact,omitempty"`
+	// Time is the time when the action starts running on the edge node, or when the result is reported.
+	// +optional
+	Time string `json:"time,omitempty"`
+}
@@ -25,6 +25,7 @@ import (
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // NodeUpgradeJob is used to upgrade edge node from cloud side.
+// Edge nodes are only upgraded in the maintenance windows of the NodeGroups they belong to.
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
@@ -91,6 +92,50 @@ type NodeUpgradeJobSpec struct {
 	// The default Concurrency value is 1.
 	// +optional
 	Concurrency int32 `json:"concurrency,omitempty"`
+	// PreflightChecks specifies the checks that run on every selected edge node before any node is upgraded.
+	// Nodes that fail the checks or do not report the results in TimeoutSeconds are skipped,
+	// and the results are reported in the status of each node.
+	// +optional
+	PreflightChecks *PreflightChecks `json:"preflightChecks,omitempty"`
+	// PrePull indicates whether to pull the Image on every selected edge node before any node is upgraded,
+	// so that the upgrade does not spend time pulling the image on slow links.
+	// Nodes that fail to pull the image in TimeoutSeconds are skipped.
+	// +optional
+	PrePull bool `json:"prePull,omitempty"`
+	// Canary specifies a canary phase that upgrades a few nodes before the others.
+	// The NodeUpgradeJob is halted if too many canary nodes fail to upgrade.
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+}
+
+// PreflightChecks specifies the checks that run on edge nodes before upgrading.
+type PreflightChecks struct {
+	// MinAvailableDiskMB is the minimum available disk space in MB required on the edge node.
+	// If set to 0, the disk space will not be checked.
+	// +optional
+	MinAvailableDiskMB int64 `json:"minAvailableDiskMB,omitempty"`
+	// MinAvailableMemoryMB is the minimum available memory in MB required on the edge node.
+	// If set to 0, the memory will not be checked.
+	// +optional
+	MinAvailableMemoryMB int64 `json:"minAvailableMemoryMB,omitempty"`
+	// MaxMinorVersionSkew is the maximum difference of minor version allowed between
+	// the current EdgeCore version and the Version to upgrade.
+	// If not set, the version skew will not be checked.
+	// +optional
+	MaxMinorVersionSkew *int32 `json:"maxMinorVersionSkew,omitempty"`
+}
+
+// CanaryStrategy specifies the canary phase of the NodeUpgradeJob.
+type CanaryStrategy struct {
+	// Nodes is the number of edge nodes to upgrade in the canary phase.
+	// Default to 1.
+	// +optional
+	Nodes int32 `json:"nodes,omitempty"`
+	// FailureThreshold is the maximum number of canary nodes that are allowed to fail to upgrade.
+	// The NodeUpgradeJob is halted and the other nodes are not upgraded once the failures exceed it.
+	// Default to 0.
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
 }
 
 // UpgradeResult describe the result status of upgrade operation on edge nodes.
@@ -105,7 +150,7 @@ const (
 )
 
 // UpgradeState describe the UpgradeState of upgrade operation on edge nodes.
-// +kubebuilder:validation:Enum=upgrading;completed
+// +kubebuilder:validation:Enum=upgrading;completed;skipped;waiting;halted;paused
 type UpgradeState string
 
 // Valid values of UpgradeState
@@ -113,6 +158,27 @@ const (
 	InitialValue UpgradeState = ""
 	Upgrading    UpgradeState = "upgrading"
 	Completed    UpgradeState = "completed"
+	// Skipped is only used by edge nodes, it means the node fails the pre-flight checks or the image pre-pull.
+	Skipped UpgradeState = "skipped"
+	// Waiting is only used by edge nodes, it means the node is waiting for its maintenance window to upgrade.
+	Waiting UpgradeState = "waiting"
+	// Halted is only used by NodeUpgradeJob, it means too many canary nodes fail to upgrade.
+	Halted UpgradeState = "halted"
+	// Paused is only used by NodeUpgradeJob, it means no node is upgrading because the nodes
+	// to upgrade are out of their maintenance windows. It will be resumed when a window begins.
+	Paused UpgradeState = "paused"
+)
+
+// UpgradePhase describe the phase of the NodeUpgradeJob.
+// +kubebuilder:validation:Enum=preflight;prepull;canary;upgrade
+type UpgradePhase string
+
+// Valid values of UpgradePhase
+const (
+	PreflightPhase UpgradePhase = "preflight"
+	PrePullPhase   UpgradePhase = "prepull"
+	CanaryPhase    UpgradePhase = "canary"
+	UpgradingPhase UpgradePhase = "upgrade"
 )
 
 // NodeUpgradeJobStatus stores the status of NodeUpgradeJob.
@@ -120,8 +186,15 @@ const (
 // +kubebuilder:validation:Type=object
 type NodeUpgradeJobStatus struct {
 	// State represents for the state phase of the NodeUpgradeJob.
-	// There are three possible state values: "", upgrading and completed.
+	// There are five possible state values: "", upgrading, paused, completed and halted.
 	State UpgradeState `json:"state,omitempty"`
+	// Phase represents for the current phase of the NodeUpgradeJob.
+	// There are four possible phase values: preflight, prepull, canary and upgrade.
+	// +optional
+	Phase UpgradePhase `json:"phase,omitempty"`
+	// Reason is the reason why the NodeUpgradeJob is halted.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 	// Status contains upgrade Status for each edge node.
 	Status []UpgradeStatus `json:"status,omitempty"`
 }
@@ -132,12 +205,45 @@ type UpgradeStatus struct {
 	// NodeName is the name of edge node.
 	NodeName string `json:"nodeName,omitempty"`
 	// State represents for the upgrade state phase of the edge node.
-	// There are three possible state values: "", upgrading and completed.
+	// There are five possible state values: "", waiting, upgrading, completed and skipped.
 	State UpgradeState `json:"state,omitempty"`
+	// Preflight is the result of the pre-flight checks on the edge node.
+	// +optional
+	Preflight *PreUpgradeResult `json:"preflight,omitempty"`
+	// PrePull is the result of pulling the image on the edge node before upgrading.
+	// +optional
+	PrePull *PreUpgradeResult `json:"prePull,omitempty"`
 	// History is the last upgrade result of the edge node.
 	History History `json:"history,omitempty"`
 }
 
+// PreUpgradeResult stores the result of an operation done on the edge node before upgrading.
+// +kubebuilder:validation:Type=object
+type PreUpgradeResult struct {
+	// Succeeded indicates whether the operation is successful.
+	Succeeded bool `json:"succeeded"`
+	// Reason is the error reason of the operation failure.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Checks contains the result of each pre-flight check.
+	// +optional
+	Checks []PreflightCheckResult `json:"checks,omitempty"`
+	// Time is the time when the result is reported.
+	// +optional
+	Time string `json:"time,omitempty"`
+}
+
+// PreflightCheckResult stores the result of one pre-flight check.
+type PreflightCheckResult struct {
+	// Name is the name of the check, such as disk, memory and versionSkew.
+	Name string `json:"name"`
+	// Passed indicates whether the edge node passes the check.
+	Passed bool `json:"passed"`
+	// Message is the detail of the check.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // History stores the information about upgrade history record.
 // +kubebuilder:validation:Type=object
 type History struct {
@@ -59,6 +59,8 @@ type RuleEndpointSpec struct {
 	// Properties: properties of endpoint. for example:
 	// servicebus:
 	// {"service_port":"8080"}
+	// or, for a target that is not at 127.0.0.1 of the edge node:
+	// {"service_port":"8443","service_host":"camera.default.svc","service_scheme":"https"}
 	Properties map[string]string `json:"properties,omitempty"`
 }
 
@@ -30,6 +30,7 @@ type AppsV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	EdgeApplicationsGetter
 	NodeGroupsGetter
+	OfflineMigrationPoliciesGetter
 }
 
 // AppsV1alpha1Client is used to interact with features provided by the apps.kubeedge.io group.
@@ -45,6 +46,10 @@ func (c *AppsV1alpha1Client) NodeGroups() NodeGroupInterface {
 	return newNodeGroups(c)
 }
 
+func (c *AppsV1alpha1Client) OfflineMigrationPolicies() OfflineMigrationPolicyInterface {
+	return newOfflineMigrationPolicies(c)
+}
+
 // NewForConfig creates a new AppsV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -36,6 +36,10 @@ func (c *FakeAppsV1alpha1) NodeGroups() v1alpha1.NodeGroupInterface {
 	return &FakeNodeGroups{c}
 }
 
+func (c *FakeAppsV1alpha1) OfflineMigrationPolicies() v1alpha1.OfflineMigrationPolicyInterface {
+	return &FakeOfflineMigrationPolicies{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeAppsV1alpha1) RESTClient() rest.Interface {
@@ -0,0 +1,122 @@
+/*
+Copyright The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeOfflineMigrationPolicies implements OfflineMigrationPolicyInterface
+type FakeOfflineMigrationPolicies struct {
+	Fake *FakeAppsV1alpha1
+}
+
+var offlinemigrationpoliciesResource = schema.GroupVersionResource{Group: "apps.kubeedge.io", Version: "v1alpha1", Resource: "offlinemigrationpolicies"}
+
+var offlinemigrationpoliciesKind = schema.GroupVersionKind{Group: "apps.kubeedge.io", Version: "v1alpha1", Kind: "OfflineMigrationPolicy"}
+
+// Get takes name of the offlineMigrationPolicy, and returns the corresponding offlineMigrationPolicy object, and an error if there is any.
+func (c *FakeOfflineMigrationPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.OfflineMigrationPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(offlinemigrationpoliciesResource, name), &v1alpha1.OfflineMigrationPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.OfflineMigrationPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of OfflineMigrationPolicies that match those selectors.
+func (c *FakeOfflineMigrationPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.OfflineMigrationPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(offlinemigrationpoliciesResource, offlinemigrationpoliciesKind, opts), &v1alpha1.OfflineMigrationPolicyList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.OfflineMigrationPolicyList{ListMeta: obj.(*v1alpha1.OfflineMigrationPolicyList).ListMeta}
+	for _, item := range obj.(*v1alpha1.OfflineMigrationPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested offlineMigrationPolicies.
+func (c *FakeOfflineMigrationPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(offlinemigrationpoliciesResource, opts))
+}
+
+// Create takes the representation of a offlineMigrationPolicy and creates it.  Returns the server's representation of the offlineMigrationPolicy, and an error, if there is any.
+func (c *FakeOfflineMigrationPolicies) Create(ctx context.Context, offlineMigrationPolicy *v1alpha1.OfflineMigrationPolicy, opts v1.CreateOptions) (result *v1alpha1.OfflineMigrationPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(offlinemigrationpoliciesResource, offlineMigrationPolicy), &v1alpha1.OfflineMigrationPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.OfflineMigrationPolicy), err
+}
+
+// Update takes the representation of a offlineMigrationPolicy and updates it. Returns the server's representation of the offlineMigrationPolicy, and an error, if there is any.
+func (c *FakeOfflineMigrationPolicies) Update(ctx context.Context, offlineMigrationPolicy *v1alpha1.OfflineMigrationPolicy, opts v1.UpdateOptions) (result *v1alpha1.OfflineMigrationPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(offlinemigrationpoliciesResource, offlineMigrationPolicy), &v1alpha1.OfflineMigrationPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.OfflineMigrationPolicy), err
+}
+
+// Delete takes name of the offlineMigrationPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeOfflineMigrationPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(offlinemigrationpoliciesResource, name, opts), &v1alpha1.OfflineMigrationPolicy{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeOfflineMigrationPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(offlinemigrationpoliciesResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.OfflineMigrationPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched offlineMigrationPolicy.
+func (c *FakeOfflineMigrationPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.OfflineMigrationPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(offlinemigrationpoliciesResource, name, pt, data, subresources...), &v1alpha1.OfflineMigrationPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.OfflineMigrationPolicy), err
+}
@@ -21,3 +21,5 @@ package v1alpha1
 type EdgeApplicationExpansion interface{}
 
 type NodeGroupExpansion interface{}
+
+type OfflineMigrationPolicyExpansion interface{}
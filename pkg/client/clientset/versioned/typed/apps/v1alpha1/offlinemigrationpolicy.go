@@ -0,0 +1,168 @@
+/*
+Copyright The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+	scheme "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// OfflineMigrationPoliciesGetter has a method to return a OfflineMigrationPolicyInterface.
+// A group's client should implement this interface.
+type OfflineMigrationPoliciesGetter interface {
+	OfflineMigrationPolicies() OfflineMigrationPolicyInterface
+}
+
+// OfflineMigrationPolicyInterface has methods to work with OfflineMigrationPolicy resources.
+type OfflineMigrationPolicyInterface interface {
+	Create(ctx context.Context, offlineMigrationPolicy *v1alpha1.OfflineMigrationPolicy, opts v1.CreateOptions) (*v1alpha1.OfflineMigrationPolicy, error)
+	Update(ctx context.Context, offlineMigrationPolicy *v1alpha1.OfflineMigrationPolicy, opts v1.UpdateOptions) (*v1alpha1.OfflineMigrationPolicy, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.OfflineMigrationPolicy, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.OfflineMigrationPolicyList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.OfflineMigrationPolicy, err error)
+	OfflineMigrationPolicyExpansion
+}
+
+// offlineMigrationPolicies implements OfflineMigrationPolicyInterface
+type offlineMigrationPolicies struct {
+	client rest.Interface
+}
+
+// newOfflineMigrationPolicies returns a OfflineMigrationPolicies
+func newOfflineMigrationPolicies(c *AppsV1alpha1Client) *offlineMigrationPolicies {
+	return &offlineMigrationPolicies{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the offlineMigrationPolicy, and returns the corresponding offlineMigrationPolicy object, and an error if there is any.
+func (c *offlineMigrationPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.OfflineMigrationPolicy, err error) {
+	result = &v1alpha1.OfflineMigrationPolicy{}
+	err = c.client.Get().
+		Resource("offlinemigrationpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of OfflineMigrationPolicies that match those selectors.
+func (c *offlineMigrationPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.OfflineMigrationPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.OfflineMigrationPolicyList{}
+	err = c.client.Get().
+		Resource("offlinemigrationpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested offlineMigrationPolicies.
+func (c *offlineMigrationPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("offlinemigrationpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a offlineMigrationPolicy and creates it.  Returns the server's representation of the offlineMigrationPolicy, and an error, if there is any.
+func (c *offlineMigrationPolicies) Create(ctx context.Context, offlineMigrationPolicy *v1alpha1.OfflineMigrationPolicy, opts v1.CreateOptions) (result *v1alpha1.OfflineMigrationPolicy, err error) {
+	result = &v1alpha1.OfflineMigrationPolicy{}
+	err = c.client.Post().
+		Resource("offlinemigrationpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(offlineMigrationPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a offlineMigrationPolicy and updates it. Returns the server's representation of the offlineMigrationPolicy, and an error, if there is any.
+func (c *offlineMigrationPolicies) Update(ctx context.Context, offlineMigrationPolicy *v1alpha1.OfflineMigrationPolicy, opts v1.UpdateOptions) (result *v1alpha1.OfflineMigrationPolicy, err error) {
+	result = &v1alpha1.OfflineMigrationPolicy{}
+	err = c.client.Put().
+		Resource("offlinemigrationpolicies").
+		Name(offlineMigrationPolicy.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(offlineMigrationPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the offlineMigrationPolicy and deletes it. Returns an error if one occurs.
+func (c *offlineMigrationPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("offlinemigrationpolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *offlineMigrationPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("offlinemigrationpolicies").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched offlineMigrationPolicy.
+func (c *offlineMigrationPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.OfflineMigrationPolicy, err error) {
+	result = &v1alpha1.OfflineMigrationPolicy{}
+	err = c.client.Patch(pt).
+		Resource("offlinemigrationpolicies").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
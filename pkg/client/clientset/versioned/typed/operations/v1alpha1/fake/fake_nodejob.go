@@ -0,0 +1,133 @@
+/*
+Copyright The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNodeJobs implements NodeJobInterface
+type FakeNodeJobs struct {
+	Fake *FakeOperationsV1alpha1
+}
+
+var nodejobsResource = schema.GroupVersionResource{Group: "operations", Version: "v1alpha1", Resource: "nodejobs"}
+
+var nodejobsKind = schema.GroupVersionKind{Group: "operations", Version: "v1alpha1", Kind: "NodeJob"}
+
+// Get takes name of the nodeJob, and returns the corresponding nodeJob object, and an error if there is any.
+func (c *FakeNodeJobs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.NodeJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(nodejobsResource, name), &v1alpha1.NodeJob{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NodeJob), err
+}
+
+// List takes label and field selectors, and returns the list of NodeJobs that match those selectors.
+func (c *FakeNodeJobs) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.NodeJobList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(nodejobsResource, nodejobsKind, opts), &v1alpha1.NodeJobList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.NodeJobList{ListMeta: obj.(*v1alpha1.NodeJobList).ListMeta}
+	for _, item := range obj.(*v1alpha1.NodeJobList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested nodeJobs.
+func (c *FakeNodeJobs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(nodejobsResource, opts))
+}
+
+// Create takes the representation of a nodeJob and creates it.  Returns the server's representation of the nodeJob, and an error, if there is any.
+func (c *FakeNodeJobs) Create(ctx context.Context, nodeJob *v1alpha1.NodeJob, opts v1.CreateOptions) (result *v1alpha1.NodeJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(nodejobsResource, nodeJob), &v1alpha1.NodeJob{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NodeJob), err
+}
+
+// Update takes the representation of a nodeJob and updates it. Returns the server's representation of the nodeJob, and an error, if there is any.
+func (c *FakeNodeJobs) Update(ctx context.Context, nodeJob *v1alpha1.NodeJob, opts v1.UpdateOptions) (result *v1alpha1.NodeJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(nodejobsResource, nodeJob), &v1alpha1.NodeJob{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NodeJob), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNodeJobs) UpdateStatus(ctx context.Context, nodeJob *v1alpha1.NodeJob, opts v1.UpdateOptions) (*v1alpha1.NodeJob, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(nodejobsResource, "status", nodeJob), &v1alpha1.NodeJob{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NodeJob), err
+}
+
+// Delete takes name of the nodeJob and deletes it. Returns an error if one occurs.
+func (c *FakeNodeJobs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(nodejobsResource, name, opts), &v1alpha1.NodeJob{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNodeJobs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(nodejobsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.NodeJobList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched nodeJob.
+func (c *FakeNodeJobs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NodeJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(nodejobsResource, name, pt, data, subresources...), &v1alpha1.NodeJob{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NodeJob), err
+}
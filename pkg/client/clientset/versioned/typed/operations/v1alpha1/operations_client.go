@@ -28,6 +28,7 @@ import (
 
 type OperationsV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	NodeJobsGetter
 	NodeUpgradeJobsGetter
 }
 
@@ -36,6 +37,10 @@ type OperationsV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *OperationsV1alpha1Client) NodeJobs() NodeJobInterface {
+	return newNodeJobs(c)
+}
+
 func (c *OperationsV1alpha1Client) NodeUpgradeJobs() NodeUpgradeJobInterface {
 	return newNodeUpgradeJobs(c)
 }
@@ -28,6 +28,8 @@ type Interface interface {
 	EdgeApplications() EdgeApplicationInformer
 	// NodeGroups returns a NodeGroupInformer.
 	NodeGroups() NodeGroupInformer
+	// OfflineMigrationPolicies returns a OfflineMigrationPolicyInformer.
+	OfflineMigrationPolicies() OfflineMigrationPolicyInformer
 }
 
 type version struct {
@@ -50,3 +52,8 @@ func (v *version) EdgeApplications() EdgeApplicationInformer {
 func (v *version) NodeGroups() NodeGroupInformer {
 	return &nodeGroupInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// OfflineMigrationPolicies returns a OfflineMigrationPolicyInformer.
+func (v *version) OfflineMigrationPolicies() OfflineMigrationPolicyInformer {
+	return &offlineMigrationPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -62,6 +62,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().EdgeApplications().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("nodegroups"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().NodeGroups().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("offlinemigrationpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().OfflineMigrationPolicies().Informer()}, nil
 
 		// Group=devices, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithResource("devices"):
@@ -70,6 +72,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Devices().V1beta1().DeviceModels().Informer()}, nil
 
 		// Group=operations, Version=v1alpha1
+	case operationsv1alpha1.SchemeGroupVersion.WithResource("nodejobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().NodeJobs().Informer()}, nil
 	case operationsv1alpha1.SchemeGroupVersion.WithResource("nodeupgradejobs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().NodeUpgradeJobs().Informer()}, nil
 
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// NodeJobs returns a NodeJobInformer.
+	NodeJobs() NodeJobInformer
 	// NodeUpgradeJobs returns a NodeUpgradeJobInformer.
 	NodeUpgradeJobs() NodeUpgradeJobInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// NodeJobs returns a NodeJobInformer.
+func (v *version) NodeJobs() NodeJobInformer {
+	return &nodeJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // NodeUpgradeJobs returns a NodeUpgradeJobInformer.
 func (v *version) NodeUpgradeJobs() NodeUpgradeJobInformer {
 	return &nodeUpgradeJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
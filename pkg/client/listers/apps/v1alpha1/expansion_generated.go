@@ -29,3 +29,7 @@ type EdgeApplicationNamespaceListerExpansion interface{}
 // NodeGroupListerExpansion allows custom methods to be added to
 // NodeGroupLister.
 type NodeGroupListerExpansion interface{}
+
+// OfflineMigrationPolicyListerExpansion allows custom methods to be added to
+// OfflineMigrationPolicyLister.
+type OfflineMigrationPolicyListerExpansion interface{}
@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1alpha1
 
+// NodeJobListerExpansion allows custom methods to be added to
+// NodeJobLister.
+type NodeJobListerExpansion interface{}
+
 // NodeUpgradeJobListerExpansion allows custom methods to be added to
 // NodeUpgradeJobLister.
 type NodeUpgradeJobListerExpansion interface{}
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"k8s.io/klog/v2"
+)
+
+// bufferedExporter buffers the spans failed to export, e.g. while the edge node is offline,
+// and exports them with the following spans once the collector is reachable again.
+type bufferedExporter struct {
+	sdktrace.SpanExporter
+
+	lock   sync.Mutex
+	max    int
+	buffer []sdktrace.ReadOnlySpan
+}
+
+func newBufferedExporter(exporter sdktrace.SpanExporter, max int) *bufferedExporter {
+	return &bufferedExporter{
+		SpanExporter: exporter,
+		max:          max,
+	}
+}
+
+func (e *bufferedExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	all := make([]sdktrace.ReadOnlySpan, 0, len(e.buffer)+len(spans))
+	all = append(append(all, e.buffer...), spans...)
+	if err := e.SpanExporter.ExportSpans(ctx, all); err != nil {
+		if dropped := len(all) - e.max; dropped > 0 {
+			klog.Warningf("drop %d spans since the span buffer is full", dropped)
+			all = all[dropped:]
+		}
+		e.buffer = all
+		klog.V(4).Infof("failed to export spans, %d spans are buffered: %v", len(e.buffer), err)
+		return nil
+	}
+	e.buffer = nil
+	return nil
+}
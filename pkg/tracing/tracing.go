@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing traces the messages between the modules of cloudcore and edgecore
+// with OpenTelemetry. The trace context is carried in the header of the messages,
+// so that the spans of all the modules handling a message, on both cloud and edge,
+// belong to one trace.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/version"
+)
+
+const (
+	instrumentationName = "github.com/kubeedge/kubeedge"
+
+	// traceParentKey is the key of the W3C trace context
+	traceParentKey = "traceparent"
+
+	// NodeNameKey is the attribute of the name of the edge node
+	NodeNameKey = attribute.Key("kubeedge.node.name")
+
+	// shutdownTimeout is the timeout of exporting the remaining spans on shutdown
+	shutdownTimeout = 5 * time.Second
+)
+
+// Options are the options of the tracing
+type Options struct {
+	// Endpoint is the address of the OTLP gRPC collector
+	Endpoint string
+	// SamplingRatePerMillion is the number of the traces sampled per million
+	SamplingRatePerMillion int32
+	// MaxBufferedSpans is the max number of the spans buffered while the collector is
+	// unreachable, the spans failed to export are dropped if it is zero
+	MaxBufferedSpans int
+}
+
+var (
+	// enabled indicates whether the tracing is initialized,
+	// no span is started if it is false
+	enabled atomic.Bool
+
+	tracer     trace.Tracer = trace.NewNoopTracerProvider().Tracer(instrumentationName)
+	provider   *sdktrace.TracerProvider
+	propagator = propagation.TraceContext{}
+)
+
+// Init initializes the tracer provider exporting the spans of the service over OTLP.
+// Shutdown must be called on exit to export the remaining spans.
+func Init(serviceName string, opts Options, attrs ...attribute.KeyValue) error {
+	clientOpts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(opts.Endpoint),
+		otlptracegrpc.WithInsecure(),
+	}
+	if opts.MaxBufferedSpans > 0 {
+		// the spans are buffered instead of retried, so the exporter does not block on an unreachable collector
+		clientOpts = append(clientOpts, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{Enabled: false}))
+	}
+	var exporter sdktrace.SpanExporter
+	exporter, err := otlptracegrpc.New(context.Background(), clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create the OTLP exporter: %v", err)
+	}
+	if opts.MaxBufferedSpans > 0 {
+		exporter = newBufferedExporter(exporter, opts.MaxBufferedSpans)
+	}
+
+	attrs = append(attrs, semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceVersionKey.String(version.Get().String()))
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)),
+		// the messages from the other side follow the sampling decision of the side starting the trace
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(float64(opts.SamplingRatePerMillion)/1000000))),
+	)
+	otel.SetTracerProvider(tp)
+	provider = tp
+	setTracer(tp.Tracer(instrumentationName))
+
+	klog.Infof("tracing is enabled, the spans are exported to %s", opts.Endpoint)
+	return nil
+}
+
+// Shutdown exports the remaining spans and stops the tracer provider, it does nothing
+// if the tracing is not initialized
+func Shutdown() {
+	if provider == nil {
+		return
+	}
+	enabled.Store(false)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := provider.Shutdown(ctx); err != nil {
+		klog.Errorf("failed to shutdown the tracing: %v", err)
+	}
+}
+
+func setTracer(t trace.Tracer) {
+	tracer = t
+	enabled.Store(true)
+}
+
+// StartMessageSpan starts the span of the module handling the message, as the child of the
+// span carried by the message, and carries the new span in the message to the next module.
+// The caller must end the span after handling the message.
+func StartMessageSpan(module string, msg *model.Message) trace.Span {
+	if !enabled.Load() {
+		return trace.SpanFromContext(context.Background())
+	}
+
+	ctx := propagator.Extract(context.Background(), messageCarrier{msg: msg})
+	ctx, span := tracer.Start(ctx, module+" "+msg.GetOperation(),
+		trace.WithAttributes(
+			attribute.String("kubeedge.message.id", msg.GetID()),
+			attribute.String("kubeedge.message.source", msg.GetSource()),
+			attribute.String("kubeedge.message.group", msg.GetGroup()),
+			attribute.String("kubeedge.message.resource", msg.GetResource()),
+			attribute.String("kubeedge.message.operation", msg.GetOperation()),
+		))
+	propagator.Inject(ctx, messageCarrier{msg: msg})
+	return span
+}
+
+// EndSpan ends the span, and marks it as failed if err is not nil
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// messageCarrier carries the trace context in the header of the message
+type messageCarrier struct {
+	msg *model.Message
+}
+
+func (c messageCarrier) Get(key string) string {
+	if key != traceParentKey {
+		return ""
+	}
+	return c.msg.GetTraceParent()
+}
+
+func (c messageCarrier) Set(key, value string) {
+	if key == traceParentKey {
+		c.msg.SetTraceParent(value)
+	}
+}
+
+func (c messageCarrier) Keys() []string {
+	return []string{traceParentKey}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+// fakeExporter records the exported spans, or fails the export if err is set
+type fakeExporter struct {
+	lock  sync.Mutex
+	err   error
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *fakeExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.err != nil {
+		return e.err
+	}
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *fakeExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestStartMessageSpanDisabled(t *testing.T) {
+	msg := model.NewMessage("").BuildRouter("edgecontroller", "resource", "default/pod/test", model.UpdateOperation)
+	span := StartMessageSpan("cloudhub", msg)
+	defer span.End()
+
+	if span.SpanContext().IsValid() {
+		t.Errorf("expected no span when tracing is not enabled")
+	}
+	if msg.GetTraceParent() != "" {
+		t.Errorf("expected no trace context in the message, but got %s", msg.GetTraceParent())
+	}
+}
+
+func TestStartMessageSpan(t *testing.T) {
+	exporter := &fakeExporter{}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() {
+		tracer = trace.NewNoopTracerProvider().Tracer(instrumentationName)
+		enabled.Store(false)
+	}()
+	setTracer(tp.Tracer(instrumentationName))
+
+	msg := model.NewMessage("").BuildRouter("edgecontroller", "resource", "default/pod/test", model.UpdateOperation)
+	cloudSpan := StartMessageSpan("cloudhub", msg)
+	if msg.GetTraceParent() == "" {
+		t.Fatalf("expected the trace context in the message")
+	}
+	EndSpan(cloudSpan, nil)
+
+	// the message is sent to edge in JSON
+	edgeMsg := *msg
+	edgeSpan := StartMessageSpan("edgehub", &edgeMsg)
+	EndSpan(edgeSpan, errors.New("failed to dispatch"))
+
+	if len(exporter.spans) != 2 {
+		t.Fatalf("expected 2 spans, but got %d", len(exporter.spans))
+	}
+	cloud, edge := exporter.spans[0], exporter.spans[1]
+	if cloud.Name() != "cloudhub update" || edge.Name() != "edgehub update" {
+		t.Errorf("unexpected span names %s and %s", cloud.Name(), edge.Name())
+	}
+	if edge.Parent().SpanID() != cloud.SpanContext().SpanID() ||
+		edge.SpanContext().TraceID() != cloud.SpanContext().TraceID() {
+		t.Errorf("expected the edge span to be the child of the cloud span")
+	}
+	if edge.Status().Description != "failed to dispatch" {
+		t.Errorf("expected the edge span failed, but got status %v", edge.Status())
+	}
+	if edgeMsg.GetTraceParent() == msg.GetTraceParent() {
+		t.Errorf("expected the message to carry the edge span to the next module")
+	}
+}
+
+func TestBufferedExporter(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	newSpans := func(n int) []sdktrace.ReadOnlySpan {
+		var spans []sdktrace.ReadOnlySpan
+		for i := 0; i < n; i++ {
+			_, span := tp.Tracer("test").Start(context.Background(), "test")
+			span.End()
+			spans = append(spans, span.(sdktrace.ReadOnlySpan))
+		}
+		return spans
+	}
+
+	fake := &fakeExporter{err: errors.New("collector is unreachable")}
+	exporter := newBufferedExporter(fake, 3)
+
+	if err := exporter.ExportSpans(context.Background(), newSpans(2)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := exporter.ExportSpans(context.Background(), newSpans(2)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exporter.buffer) != 3 {
+		t.Fatalf("expected 3 buffered spans, but got %d", len(exporter.buffer))
+	}
+
+	fake.err = nil
+	if err := exporter.ExportSpans(context.Background(), newSpans(1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.spans) != 4 || len(exporter.buffer) != 0 {
+		t.Errorf("expected 4 exported spans and empty buffer, but got %d and %d", len(fake.spans), len(exporter.buffer))
+	}
+}
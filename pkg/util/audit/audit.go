@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit contains the helpers shared by the audit logging of CloudCore and EdgeCore,
+// the audit events and policies are the same as Kubernetes.
+package audit
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/audit/policy"
+	auditlog "k8s.io/apiserver/plugin/pkg/audit/log"
+)
+
+// DefaultPolicy audits the metadata of all requests, the RequestReceived stage is omitted
+// since the ResponseComplete stage contains the same information.
+var DefaultPolicy = &auditinternal.Policy{
+	OmitStages: []auditinternal.Stage{auditinternal.StageRequestReceived},
+	Rules: []auditinternal.PolicyRule{
+		{Level: auditinternal.LevelMetadata},
+	},
+}
+
+// LoadPolicy loads the audit policy from the file, DefaultPolicy is used if the file is empty
+func LoadPolicy(policyFile string) (audit.PolicyRuleEvaluator, error) {
+	if policyFile == "" {
+		return policy.NewPolicyRuleEvaluator(DefaultPolicy), nil
+	}
+	p, err := policy.LoadPolicyFromFile(policyFile)
+	if err != nil {
+		return nil, err
+	}
+	return policy.NewPolicyRuleEvaluator(p), nil
+}
+
+// NewLogBackend creates the backend writing audit events in JSON lines to the file at path,
+// which is rotated by maxSize in megabytes. "-" means standard out.
+func NewLogBackend(path string, maxAge, maxBackups, maxSize int) (audit.Backend, error) {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, err
+		}
+		w = &lumberjack.Logger{
+			Filename:   path,
+			MaxAge:     maxAge,
+			MaxBackups: maxBackups,
+			MaxSize:    maxSize,
+			Compress:   false,
+		}
+	}
+	return auditlog.NewBackend(w, auditlog.FormatJson, auditv1.SchemeGroupVersion), nil
+}
+
+// ToV1Events converts the internal audit events to the v1 events to be transferred
+func ToV1Events(events []*auditinternal.Event) ([]auditv1.Event, error) {
+	out := make([]auditv1.Event, len(events))
+	for i, ev := range events {
+		if err := audit.Scheme.Convert(ev, &out[i], nil); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// FromV1Events converts the v1 audit events to the internal events to be processed by backends
+func FromV1Events(events []auditv1.Event) ([]*auditinternal.Event, error) {
+	out := make([]*auditinternal.Event, len(events))
+	for i := range events {
+		out[i] = &auditinternal.Event{}
+		if err := audit.Scheme.Convert(&events[i], out[i], nil); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
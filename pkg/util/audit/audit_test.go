@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+const testPolicy = `apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+- level: RequestResponse
+  resources:
+  - group: ""
+    resources: ["secrets"]
+- level: None
+`
+
+func TestLoadPolicy(t *testing.T) {
+	secretAttrs := authorizer.AttributesRecord{
+		User: &user.DefaultInfo{Name: "system:serviceaccount:default:app"}, Verb: "get",
+		ResourceRequest: true, APIVersion: "v1", Resource: "secrets", Namespace: "default", Name: "token",
+	}
+	podAttrs := secretAttrs
+	podAttrs.Resource = "pods"
+
+	evaluator, err := LoadPolicy("")
+	if err != nil {
+		t.Fatalf("failed to load default policy: %v", err)
+	}
+	if level := evaluator.EvaluatePolicyRule(podAttrs).Level; level != auditinternal.LevelMetadata {
+		t.Errorf("expect default level Metadata, got %s", level)
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err = os.WriteFile(path, []byte(testPolicy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	evaluator, err = LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("failed to load policy: %v", err)
+	}
+	if level := evaluator.EvaluatePolicyRule(secretAttrs).Level; level != auditinternal.LevelRequestResponse {
+		t.Errorf("expect level RequestResponse for secrets, got %s", level)
+	}
+	if level := evaluator.EvaluatePolicyRule(podAttrs).Level; level != auditinternal.LevelNone {
+		t.Errorf("expect level None for pods, got %s", level)
+	}
+
+	if _, err = LoadPolicy(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Errorf("expect error for missing policy file")
+	}
+}
+
+func TestLogBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	backend, err := NewLogBackend(path, 1, 1, 1)
+	if err != nil {
+		t.Fatalf("failed to create log backend: %v", err)
+	}
+	ev := &auditinternal.Event{
+		Level:   auditinternal.LevelMetadata,
+		AuditID: "1",
+		Stage:   auditinternal.StageResponseComplete,
+		Verb:    "get",
+		User:    authnv1.UserInfo{Username: "system:serviceaccount:default:app"},
+	}
+	if !backend.ProcessEvents(ev) {
+		t.Fatalf("failed to process audit event")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	var logged map[string]interface{}
+	if err = json.Unmarshal(data, &logged); err != nil {
+		t.Fatalf("audit log is not a JSON line: %v", err)
+	}
+	if logged["kind"] != "Event" || logged["auditID"] != "1" {
+		t.Errorf("unexpected audit log %s", data)
+	}
+}
+
+func TestConvertEvents(t *testing.T) {
+	events := []*auditinternal.Event{{
+		Level:       auditinternal.LevelMetadata,
+		AuditID:     "1",
+		Verb:        "list",
+		Annotations: map[string]string{"authorization.k8s.io/decision": "allow"},
+	}}
+	v1Events, err := ToV1Events(events)
+	if err != nil {
+		t.Fatalf("failed to convert to v1 events: %v", err)
+	}
+	got, err := FromV1Events(v1Events)
+	if err != nil {
+		t.Fatalf("failed to convert from v1 events: %v", err)
+	}
+	if len(got) != 1 || got[0].AuditID != "1" || got[0].Annotations["authorization.k8s.io/decision"] != "allow" {
+		t.Errorf("unexpected events after conversion: %+v", got)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenancewindow
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a cron schedule with five fields: minute, hour, day of month, month and day of week.
+// Each field is a bit set of the values it matches.
+type Schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// dayOfMonthAny and dayOfWeekAny indicate the fields are "*". As standard cron does,
+	// a time matches if either day field matches when both of them are restricted.
+	dayOfMonthAny, dayOfWeekAny bool
+}
+
+type fieldBounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteBounds     = fieldBounds{name: "minute", min: 0, max: 59}
+	hourBounds       = fieldBounds{name: "hour", min: 0, max: 23}
+	dayOfMonthBounds = fieldBounds{name: "day of month", min: 1, max: 31}
+	monthBounds      = fieldBounds{name: "month", min: 1, max: 12}
+	// both 0 and 7 are Sunday
+	dayOfWeekBounds = fieldBounds{name: "day of week", min: 0, max: 7}
+)
+
+// ParseSchedule parses the cron schedule, such as "0 2 * * *" and "30 1 * * 1-5".
+func ParseSchedule(spec string) (*Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in schedule %q, found %d", spec, len(fields))
+	}
+
+	var err error
+	s := &Schedule{
+		dayOfMonthAny: fields[2] == "*",
+		dayOfWeekAny:  fields[4] == "*",
+	}
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dayOfMonth, err = parseField(fields[2], dayOfMonthBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dayOfWeek, err = parseField(fields[4], dayOfWeekBounds); err != nil {
+		return nil, err
+	}
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1
+	}
+	return s, nil
+}
+
+// parseField parses a comma separated list of "*", values, ranges and steps.
+func parseField(field string, bounds fieldBounds) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangeSpec, step := item, 1
+		if index := strings.Index(item, "/"); index != -1 {
+			rangeSpec = item[:index]
+			var err error
+			if step, err = strconv.Atoi(item[index+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", item[index+1:], bounds.name)
+			}
+		}
+
+		start, end := bounds.min, bounds.max
+		switch {
+		case rangeSpec == "*":
+		case strings.Contains(rangeSpec, "-"):
+			parts := strings.SplitN(rangeSpec, "-", 2)
+			var err error
+			if start, err = parseValue(parts[0], bounds); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(parts[1], bounds); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangeSpec, bounds.name)
+			}
+		default:
+			value, err := parseValue(rangeSpec, bounds)
+			if err != nil {
+				return 0, err
+			}
+			start = value
+			// "5/10" means from 5 to the max with step 10
+			if step == 1 {
+				end = value
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(value string, bounds fieldBounds) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", value, bounds.name)
+	}
+	if v < bounds.min || v > bounds.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d] in %s field", v, bounds.min, bounds.max, bounds.name)
+	}
+	return v, nil
+}
+
+// Matches returns whether the minute of t matches the schedule, in the location of t.
+func (s *Schedule) Matches(t time.Time) bool {
+	return s.minute&(1<<uint(t.Minute())) != 0 &&
+		s.hour&(1<<uint(t.Hour())) != 0 &&
+		s.matchesDay(t)
+}
+
+// matchesDay returns whether the day of t matches the schedule, in the location of t.
+func (s *Schedule) matchesDay(t time.Time) bool {
+	if s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// latestTimeOfDay returns the latest hour and minute matching the schedule at or before hour:minute.
+func (s *Schedule) latestTimeOfDay(hour, minute int) (int, int, bool) {
+	for h := hour; h >= 0; h-- {
+		if s.hour&(1<<uint(h)) == 0 {
+			continue
+		}
+		limit := 59
+		if h == hour {
+			limit = minute
+		}
+		// the highest bit of the minutes not after limit
+		if m := bits.Len64(s.minute&(1<<uint(limit+1)-1)) - 1; m >= 0 {
+			return h, m, true
+		}
+	}
+	return 0, 0, false
+}
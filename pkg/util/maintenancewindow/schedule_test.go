@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenancewindow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	cases := map[string]bool{
+		"0 2 * * *":        true,
+		"*/15 0-5 * * 1-5": true,
+		"0 2 1,15 * 7":     true,
+		"5/10 * * * *":     true,
+		"0 2 * *":          false,
+		"60 2 * * *":       false,
+		"0 2 * * 8":        false,
+		"0 5-2 * * *":      false,
+		"0 */0 * * *":      false,
+		"a 2 * * *":        false,
+	}
+	for spec, valid := range cases {
+		_, err := ParseSchedule(spec)
+		if (err == nil) != valid {
+			t.Errorf("unexpected result for schedule %q, want valid: %v, got error: %v", spec, valid, err)
+		}
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	// 2023-10-02 is Monday
+	monday := time.Date(2023, 10, 2, 2, 0, 0, 0, time.UTC)
+	cases := []struct {
+		spec string
+		time time.Time
+		want bool
+	}{
+		{spec: "0 2 * * *", time: monday, want: true},
+		{spec: "0 2 * * *", time: monday.Add(time.Minute), want: false},
+		{spec: "*/15 * * * *", time: monday.Add(45 * time.Minute), want: true},
+		{spec: "0 2 * * 1-5", time: monday, want: true},
+		{spec: "0 2 * * 0,6", time: monday, want: false},
+		{spec: "0 2 * * 7", time: monday.AddDate(0, 0, 6), want: true},
+		// either day field matches when both are restricted
+		{spec: "0 2 15 * 1", time: monday, want: true},
+		{spec: "0 2 2 * 5", time: monday, want: true},
+		{spec: "0 2 15 * 5", time: monday, want: false},
+		{spec: "0 2 * 11 *", time: monday, want: false},
+	}
+	for _, c := range cases {
+		s, err := ParseSchedule(c.spec)
+		if err != nil {
+			t.Fatalf("failed to parse schedule %q: %v", c.spec, err)
+		}
+		if got := s.Matches(c.time); got != c.want {
+			t.Errorf("schedule %q matches %s, want: %v, got: %v", c.spec, c.time, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenancewindow
+
+import (
+	"fmt"
+	"time"
+	// embed the time zone database, the images of cloudcore may not contain it
+	_ "time/tzdata"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+// MaxDuration is the max duration of a maintenance window
+const MaxDuration = 24 * time.Hour
+
+// window is a parsed MaintenanceWindow
+type window struct {
+	schedule *Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+func parse(w appsv1alpha1.MaintenanceWindow) (*window, error) {
+	schedule, err := ParseSchedule(w.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	if w.Duration.Duration <= 0 || w.Duration.Duration > MaxDuration {
+		return nil, fmt.Errorf("duration %s must be greater than 0 and not longer than %s", w.Duration.Duration, MaxDuration)
+	}
+	location := time.UTC
+	if w.TimeZone != "" {
+		if location, err = time.LoadLocation(w.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", w.TimeZone, err)
+		}
+	}
+	return &window{schedule: schedule, duration: w.Duration.Duration, location: location}, nil
+}
+
+// contains returns whether now is in a window started at or before now.
+func (w *window) contains(now time.Time) bool {
+	start, ok := w.latestStart(now)
+	return ok && !start.After(now) && now.Sub(start) < w.duration
+}
+
+// latestStart returns the latest start of the windows at or before now. Since windows are not
+// longer than MaxDuration, only the starts of today and yesterday are considered.
+func (w *window) latestStart(now time.Time) (time.Time, bool) {
+	now = now.In(w.location)
+	for days := 0; days <= 1; days++ {
+		day := now.AddDate(0, 0, -days)
+		if !w.schedule.matchesDay(day) {
+			continue
+		}
+		hour, minute := 23, 59
+		if days == 0 {
+			hour, minute = now.Hour(), now.Minute()
+		}
+		if h, m, ok := w.schedule.latestTimeOfDay(hour, minute); ok {
+			return time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, w.location), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Validate checks the schedule, duration and time zone of the MaintenanceWindow.
+func Validate(w appsv1alpha1.MaintenanceWindow) error {
+	_, err := parse(w)
+	return err
+}
+
+// InWindow returns whether now is in any of the windows.
+// It always returns true if there's no window, which means there's no restriction.
+func InWindow(windows []appsv1alpha1.MaintenanceWindow, now time.Time) (bool, error) {
+	if len(windows) == 0 {
+		return true, nil
+	}
+	for _, mw := range windows {
+		w, err := parse(mw)
+		if err != nil {
+			return false, err
+		}
+		if w.contains(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
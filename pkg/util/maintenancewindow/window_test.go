@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenancewindow
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+func TestInWindow(t *testing.T) {
+	// from 02:00 to 05:00 in Shanghai, which is from 18:00 to 21:00 in UTC
+	windows := []appsv1alpha1.MaintenanceWindow{
+		{
+			Schedule: "0 2 * * *",
+			Duration: metav1.Duration{Duration: 3 * time.Hour},
+			TimeZone: "Asia/Shanghai",
+		},
+	}
+	cases := []struct {
+		time time.Time
+		want bool
+	}{
+		{time: time.Date(2023, 10, 2, 17, 59, 0, 0, time.UTC), want: false},
+		{time: time.Date(2023, 10, 2, 18, 0, 0, 0, time.UTC), want: true},
+		{time: time.Date(2023, 10, 2, 20, 59, 59, 0, time.UTC), want: true},
+		{time: time.Date(2023, 10, 2, 21, 0, 0, 0, time.UTC), want: false},
+	}
+	for _, c := range cases {
+		got, err := InWindow(windows, c.time)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != c.want {
+			t.Errorf("in window at %s, want: %v, got: %v", c.time, c.want, got)
+		}
+	}
+
+	if got, _ := InWindow(nil, time.Now()); !got {
+		t.Errorf("expect always in window without windows")
+	}
+}
+
+func TestInWindowAcrossDays(t *testing.T) {
+	// from 22:30 to 02:30 of the next day, starting on Friday and Saturday
+	windows := []appsv1alpha1.MaintenanceWindow{
+		{Schedule: "30 22 * * 5,6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+	}
+	// 2023-10-06 is Friday
+	cases := []struct {
+		time time.Time
+		want bool
+	}{
+		{time: time.Date(2023, 10, 5, 23, 0, 0, 0, time.UTC), want: false},
+		{time: time.Date(2023, 10, 6, 22, 29, 59, 0, time.UTC), want: false},
+		{time: time.Date(2023, 10, 6, 22, 30, 0, 0, time.UTC), want: true},
+		{time: time.Date(2023, 10, 7, 2, 29, 0, 0, time.UTC), want: true},
+		{time: time.Date(2023, 10, 7, 2, 30, 0, 0, time.UTC), want: false},
+		{time: time.Date(2023, 10, 8, 1, 0, 0, 0, time.UTC), want: true},
+		{time: time.Date(2023, 10, 8, 23, 0, 0, 0, time.UTC), want: false},
+	}
+	for _, c := range cases {
+		got, err := InWindow(windows, c.time)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != c.want {
+			t.Errorf("in window at %s, want: %v, got: %v", c.time, c.want, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		window appsv1alpha1.MaintenanceWindow
+		valid  bool
+	}{
+		"valid": {
+			window: appsv1alpha1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "America/New_York"},
+			valid:  true,
+		},
+		"zero duration": {
+			window: appsv1alpha1.MaintenanceWindow{Schedule: "0 2 * * *"},
+			valid:  false,
+		},
+		"too long duration": {
+			window: appsv1alpha1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 25 * time.Hour}},
+			valid:  false,
+		},
+		"invalid time zone": {
+			window: appsv1alpha1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Base"},
+			valid:  false,
+		},
+	}
+	for name, c := range cases {
+		if err := Validate(c.window); (err == nil) != c.valid {
+			t.Errorf("case %s: want valid: %v, got error: %v", name, c.valid, err)
+		}
+	}
+}
@@ -1,16 +1,130 @@
 package passthrough
 
+import (
+	"path"
+	"strings"
+	"sync"
+)
+
 type passRequest string
 
 const (
 	versionRequest passRequest = "/version::get"
+
+	// wildcard marks a path pattern as a prefix match, e.g. "/apis/*::get"
+	wildcard  = "*"
+	separator = "::"
 )
 
-var passThroughMap = map[passRequest]bool{
-	versionRequest: true,
+// DefaultPassThroughPaths is the allow-list used when no pass-through paths are configured.
+// Discovery, OpenAPI and health check requests are non-resource requests, so they can be
+// answered by the cloud apiserver directly and cached at the edge for offline use.
+// Pass-through requests are sent with the credentials of cloudcore, so requests whose
+// response depends on the identity of the requester, e.g. selfsubjectaccessreviews, must not be added.
+// The edge metaserver answers selfsubjectaccessreviews by itself.
+var DefaultPassThroughPaths = []string{
+	string(versionRequest),
+	"/healthz::get",
+	"/livez::get",
+	"/readyz::get",
+	"/api::get",
+	"/api/*::get",
+	"/apis::get",
+	"/apis/*::get",
+	"/openapi/*::get",
+}
+
+var (
+	lock           sync.RWMutex
+	passThroughMap = map[passRequest]bool{}
+	// passThroughPrefixes holds the wildcard entries of the allow-list, they
+	// are only matched against non-resource requests
+	passThroughPrefixes []passRequest
+)
+
+func init() {
+	SetPassThroughPaths(nil)
+}
+
+// SetPassThroughPaths replaces the pass-through allow-list. Every entry has the
+// form "<path>::<verb>", and the path may end with "*" to match all sub paths.
+// If paths is empty, DefaultPassThroughPaths is used.
+func SetPassThroughPaths(paths []string) {
+	if len(paths) == 0 {
+		paths = DefaultPassThroughPaths
+	}
+
+	exact := make(map[passRequest]bool, len(paths))
+	var prefixes []passRequest
+	for _, p := range paths {
+		path, verb, ok := strings.Cut(strings.TrimSpace(p), separator)
+		if !ok || path == "" || verb == "" {
+			continue
+		}
+		verb = strings.ToLower(verb)
+		if strings.HasSuffix(path, wildcard) {
+			prefixes = append(prefixes, passRequest(strings.TrimSuffix(path, wildcard)+separator+verb))
+			continue
+		}
+		exact[passRequest(path+separator+verb)] = true
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	passThroughMap = exact
+	passThroughPrefixes = prefixes
+}
+
+// IsPassThroughPath determining whether the uri can be passed through.
+// The uri is cleaned before matching, and wildcard entries never match the paths
+// of resources, e.g. "/apis/*" matches "/apis/apps/v1" but not "/apis/apps/v1/deployments".
+func IsPassThroughPath(uri, verb string) bool {
+	uri = CleanPath(uri)
+	if IsPassThroughResourcePath(uri, verb) {
+		return true
+	}
+	if isResourcePath(uri) {
+		return false
+	}
+
+	lock.RLock()
+	defer lock.RUnlock()
+	for _, prefix := range passThroughPrefixes {
+		prefixPath, prefixVerb, _ := strings.Cut(string(prefix), separator)
+		if verb == prefixVerb && strings.HasPrefix(uri, prefixPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPassThroughResourcePath determining whether the resource request uri can be passed through,
+// only the exact entries of the allow-list are matched so that wildcard entries for
+// discovery requests never take over the normal resource requests
+func IsPassThroughResourcePath(uri, verb string) bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return passThroughMap[passRequest(CleanPath(uri)+separator+verb)]
+}
+
+// CleanPath returns the shortest path equivalent to uri, so that paths like
+// "/openapi/../api/v1/secrets" can not bypass the allow-list
+func CleanPath(uri string) string {
+	if uri == "" {
+		return uri
+	}
+	return path.Clean("/" + uri)
 }
 
-// IsPassThroughPath determining whether the uri can be passed through
-func IsPassThroughPath(path, verb string) bool {
-	return passThroughMap[passRequest(path+"::"+verb)]
+// isResourcePath returns whether the cleaned uri is the path of resources rather than
+// the discovery path of the legacy group "/api/<version>" or the groups "/apis/<group>/<version>"
+func isResourcePath(uri string) bool {
+	parts := strings.Split(strings.TrimPrefix(uri, "/"), "/")
+	switch parts[0] {
+	case "api":
+		return len(parts) > 2
+	case "apis":
+		return len(parts) > 3
+	}
+	return false
 }
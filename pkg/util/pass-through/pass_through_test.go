@@ -10,10 +10,10 @@ func TestIsPassThroughPath(t *testing.T) {
 		want bool
 	}{
 		{
-			name: "/healthz::get is not pass through path",
+			name: "/healthz::get is pass through path",
 			path: "/healthz",
 			verb: "get",
-			want: false,
+			want: true,
 		}, {
 			name: "/version::post is not pass through path",
 			path: "/version",
@@ -24,6 +24,41 @@ func TestIsPassThroughPath(t *testing.T) {
 			path: "/version",
 			verb: "get",
 			want: true,
+		}, {
+			name: "/apis/apps/v1::get is pass through path",
+			path: "/apis/apps/v1",
+			verb: "get",
+			want: true,
+		}, {
+			name: "/openapi/v2::get is pass through path",
+			path: "/openapi/v2",
+			verb: "get",
+			want: true,
+		}, {
+			name: "/api/v1/namespaces/default/secrets/token::get is not pass through path",
+			path: "/api/v1/namespaces/default/secrets/token",
+			verb: "get",
+			want: false,
+		}, {
+			name: "/apis/apps/v1/deployments::get is not pass through path",
+			path: "/apis/apps/v1/deployments",
+			verb: "get",
+			want: false,
+		}, {
+			name: "/openapi/../api/v1/secrets::get is not pass through path",
+			path: "/openapi/../api/v1/secrets",
+			verb: "get",
+			want: false,
+		}, {
+			name: "/openapi/v3/apis/apps/v1::get is pass through path",
+			path: "/openapi/v3/apis/apps/v1",
+			verb: "get",
+			want: true,
+		}, {
+			name: "/metrics::get is not pass through path",
+			path: "/metrics",
+			verb: "get",
+			want: false,
 		},
 	}
 	for _, tt := range tests {
@@ -34,3 +69,46 @@ func TestIsPassThroughPath(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPassThroughResourcePath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		verb string
+		want bool
+	}{
+		{
+			name: "selfsubjectaccessreviews create is not pass through path",
+			path: "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews",
+			verb: "create",
+			want: false,
+		}, {
+			name: "wildcard entries are not matched for resource requests",
+			path: "/apis/apps/v1/deployments",
+			verb: "get",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPassThroughResourcePath(tt.path, tt.verb); got != tt.want {
+				t.Errorf("IsPassThroughResourcePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetPassThroughPaths(t *testing.T) {
+	defer SetPassThroughPaths(nil)
+
+	SetPassThroughPaths([]string{"/version::get", "/custom/*::GET", "invalid"})
+	if IsPassThroughPath("/healthz", "get") {
+		t.Errorf("expected /healthz to be removed from the allow-list")
+	}
+	if !IsPassThroughPath("/custom/path", "get") {
+		t.Errorf("expected /custom/path to be allowed by the wildcard entry")
+	}
+	if !IsPassThroughPath("/version", "get") {
+		t.Errorf("expected /version to be allowed")
+	}
+}
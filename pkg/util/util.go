@@ -108,6 +108,21 @@ func Command(name string, arg []string) (string, error) {
 	return strings.Trim(string(ret), "\n"), nil
 }
 
+// HasSystemd checks if systemd exist.
+// if command run failed, then check it by sd_booted.
+func HasSystemd() bool {
+	if err := exec.Command("file", "/sbin/init").Run(); err == nil {
+		return true
+	}
+	// checks whether `SystemdBootPath` exists and is a directory
+	// reference http://www.freedesktop.org/software/systemd/man/sd_booted.html
+	fi, err := os.Lstat(constants.SystemdBootPath)
+	if err != nil {
+		return false
+	}
+	return fi.IsDir()
+}
+
 // GetCurPath returns filepath
 func GetCurPath() string {
 	file, _ := exec.LookPath(os.Args[0])
@@ -78,6 +78,9 @@ type MessageHeader struct {
 	// message type indicates the context type that delivers the message, such as channel, unixsocket, etc.
 	// if the value is empty, the channel context type will be used.
 	MessageType string `json:"type,omitempty"`
+	// the W3C trace context of the message, which links the spans of
+	// the modules handling the message across cloud and edge.
+	TraceParent string `json:"traceparent,omitempty"`
 }
 
 // BuildRouter sets route and resource operation in message
@@ -104,6 +107,17 @@ func (msg *Message) GetType() string {
 	return msg.Header.MessageType
 }
 
+// SetTraceParent sets the trace context of the message
+func (msg *Message) SetTraceParent(traceParent string) *Message {
+	msg.Header.TraceParent = traceParent
+	return msg
+}
+
+// GetTraceParent returns the trace context of the message
+func (msg *Message) GetTraceParent() string {
+	return msg.Header.TraceParent
+}
+
 // IsEmpty is empty
 func (msg *Message) IsEmpty() bool {
 	return reflect.DeepEqual(msg, &Message{})
@@ -246,6 +260,7 @@ func (msg *Message) NewRespByMessage(message *Message, content interface{}) *Mes
 	return NewMessage(message.GetID()).SetRoute(message.GetSource(), message.GetGroup()).
 		SetResourceOperation(message.GetResource(), ResponseOperation).
 		SetType(message.GetType()).
+		SetTraceParent(message.GetTraceParent()).
 		FillBody(content)
 }
 
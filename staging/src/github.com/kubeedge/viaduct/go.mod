@@ -8,6 +8,7 @@ require (
 	github.com/gorilla/websocket v1.4.2
 	github.com/kubeedge/beehive v0.0.0
 	github.com/lucas-clemente/quic-go v0.10.1
+	google.golang.org/protobuf v1.26.0
 	k8s.io/klog/v2 v2.9.0
 )
 
@@ -22,7 +23,6 @@ require (
 	github.com/onsi/ginkgo v1.11.0 // indirect
 	github.com/onsi/gomega v1.8.1 // indirect
 	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550 // indirect
-	google.golang.org/protobuf v1.26.0 // indirect
 )
 
 replace (
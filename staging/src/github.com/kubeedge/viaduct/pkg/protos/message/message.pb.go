@@ -99,7 +99,9 @@ type MessageHeader struct {
 	// the flag will be set in send sync
 	Sync bool `protobuf:"varint,4,opt,name=Sync,proto3" json:"Sync,omitempty"`
 	// message type
-	MessageType          string   `protobuf:"bytes,5,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
+	MessageType string `protobuf:"bytes,5,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
+	// the W3C trace context of the message
+	TraceParent          string   `protobuf:"bytes,6,opt,name=TraceParent,proto3" json:"TraceParent,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -165,6 +167,13 @@ func (m *MessageHeader) GetMessageType() string {
 	return ""
 }
 
+func (m *MessageHeader) GetTraceParent() string {
+	if m != nil {
+		return m.TraceParent
+	}
+	return ""
+}
+
 type Message struct {
 	Header               *MessageHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	Router               *MessageRouter `protobuf:"bytes,2,opt,name=router,proto3" json:"router,omitempty"`
@@ -229,22 +238,22 @@ func init() {
 func init() { proto.RegisterFile("message.proto", fileDescriptor_33c57e4bae7b9afd) }
 
 var fileDescriptor_33c57e4bae7b9afd = []byte{
-	// 261 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xb1, 0x4e, 0xc3, 0x30,
-	0x10, 0x86, 0x95, 0xd0, 0x26, 0xed, 0x95, 0x32, 0x9c, 0x50, 0x65, 0x21, 0x86, 0x2a, 0x13, 0x53,
-	0x06, 0x78, 0x04, 0x22, 0x41, 0x06, 0x04, 0x72, 0xfb, 0x02, 0x26, 0x9c, 0xa0, 0x43, 0x6c, 0xcb,
-	0x76, 0x86, 0xcc, 0x3c, 0x00, 0xaf, 0x8c, 0x72, 0x71, 0x02, 0x48, 0x6c, 0xfe, 0xee, 0x7e, 0xdd,
-	0xff, 0xeb, 0x37, 0x6c, 0x5b, 0xf2, 0x5e, 0xbd, 0x53, 0x69, 0x9d, 0x09, 0x06, 0xf3, 0x88, 0x85,
-	0x87, 0xed, 0xd3, 0xf8, 0x94, 0xa6, 0x0b, 0xe4, 0x70, 0x07, 0xd9, 0xc1, 0x74, 0xae, 0x21, 0x91,
-	0xec, 0x93, 0x9b, 0xb5, 0x8c, 0x84, 0x97, 0xb0, 0x7c, 0x70, 0xa6, 0xb3, 0x22, 0xe5, 0xf1, 0x08,
-	0x78, 0x05, 0xab, 0x67, 0x4b, 0x4e, 0x9d, 0x8c, 0x16, 0x67, 0xbc, 0x98, 0x19, 0x05, 0xe4, 0x92,
-	0xbc, 0xe9, 0x1a, 0x12, 0x0b, 0x5e, 0x4d, 0x58, 0x7c, 0x25, 0xb3, 0xeb, 0x23, 0xa9, 0x37, 0x72,
-	0x78, 0x01, 0x69, 0x5d, 0x45, 0xc7, 0xb4, 0xae, 0x86, 0xbb, 0x2f, 0xca, 0x91, 0x0e, 0x75, 0x15,
-	0x0d, 0x67, 0xc6, 0x6b, 0x58, 0x1f, 0x4f, 0x2d, 0xf9, 0xa0, 0x5a, 0xcb, 0xa6, 0x28, 0x7f, 0x06,
-	0x88, 0xb0, 0x38, 0xf4, 0xba, 0x61, 0xcb, 0x95, 0xe4, 0x37, 0xee, 0x61, 0x13, 0xed, 0x8e, 0xbd,
-	0x25, 0xb1, 0xe4, 0x83, 0xbf, 0x47, 0xc5, 0x67, 0x02, 0x79, 0x64, 0x2c, 0x21, 0xfb, 0xe0, 0x54,
-	0x9c, 0x67, 0x73, 0xbb, 0x2b, 0xa7, 0xee, 0xfe, 0x64, 0x96, 0x51, 0x35, 0xe8, 0x1d, 0x77, 0xc7,
-	0x49, 0xff, 0xd1, 0x8f, 0xcd, 0xca, 0xa8, 0x1a, 0x7a, 0xb9, 0x37, 0x3a, 0x90, 0x0e, 0x9c, 0xfe,
-	0x5c, 0x4e, 0xf8, 0x9a, 0xf1, 0xe7, 0xdc, 0x7d, 0x07, 0x00, 0x00, 0xff, 0xff, 0xd1, 0xe5, 0x28,
-	0xb7, 0xad, 0x01, 0x00, 0x00,
+	// 271 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x51, 0xc1, 0x4e, 0xeb, 0x30,
+	0x10, 0x54, 0xf2, 0xda, 0xa4, 0xdd, 0xbe, 0x72, 0x58, 0xa1, 0xca, 0x42, 0x1c, 0xaa, 0x9c, 0x38,
+	0xe5, 0x00, 0x9f, 0x40, 0x24, 0xc8, 0x01, 0x81, 0xdc, 0xfc, 0x80, 0x09, 0x2b, 0xe8, 0x21, 0x71,
+	0x64, 0x3b, 0x87, 0x9e, 0xf9, 0x22, 0xfe, 0x10, 0x79, 0xed, 0x84, 0x22, 0x71, 0xdb, 0x99, 0x1d,
+	0xef, 0xec, 0x8e, 0x61, 0xdb, 0x91, 0xb5, 0xea, 0x9d, 0xca, 0xc1, 0x68, 0xa7, 0x31, 0x8f, 0xb0,
+	0xb0, 0xb0, 0x7d, 0x0a, 0xa5, 0xd4, 0xa3, 0x23, 0x83, 0x3b, 0xc8, 0x0e, 0x7a, 0x34, 0x2d, 0x89,
+	0x64, 0x9f, 0xdc, 0xac, 0x65, 0x44, 0x78, 0x09, 0xcb, 0x07, 0xa3, 0xc7, 0x41, 0xa4, 0x4c, 0x07,
+	0x80, 0x57, 0xb0, 0x7a, 0x1e, 0xc8, 0xa8, 0xa3, 0xee, 0xc5, 0x3f, 0x6e, 0xcc, 0x18, 0x05, 0xe4,
+	0x92, 0xac, 0x1e, 0x5b, 0x12, 0x0b, 0x6e, 0x4d, 0xb0, 0xf8, 0x4a, 0x66, 0xd7, 0x47, 0x52, 0x6f,
+	0x64, 0xf0, 0x02, 0xd2, 0xba, 0x8a, 0x8e, 0x69, 0x5d, 0xf9, 0xb9, 0x2f, 0xca, 0x50, 0xef, 0xea,
+	0x2a, 0x1a, 0xce, 0x18, 0xaf, 0x61, 0xdd, 0x1c, 0x3b, 0xb2, 0x4e, 0x75, 0x03, 0x9b, 0xa2, 0xfc,
+	0x21, 0x10, 0x61, 0x71, 0x38, 0xf5, 0x2d, 0x5b, 0xae, 0x24, 0xd7, 0xb8, 0x87, 0x4d, 0xb4, 0x6b,
+	0x4e, 0x03, 0x89, 0x25, 0x0f, 0x3c, 0xa7, 0xbc, 0xa2, 0x31, 0xaa, 0xa5, 0x60, 0x22, 0xb2, 0xa0,
+	0x38, 0xa3, 0x8a, 0xcf, 0x04, 0xf2, 0xf8, 0x02, 0x4b, 0xc8, 0x3e, 0x78, 0x6f, 0xde, 0x78, 0x73,
+	0xbb, 0x2b, 0xa7, 0x74, 0x7f, 0x5d, 0x25, 0xa3, 0xca, 0xeb, 0x0d, 0xa7, 0x2b, 0xd2, 0xbf, 0xf5,
+	0x21, 0x7b, 0x19, 0x55, 0x3e, 0xb9, 0x7b, 0xdd, 0x3b, 0xbf, 0x89, 0xbf, 0xef, 0xbf, 0x9c, 0xe0,
+	0x6b, 0xc6, 0xdf, 0x77, 0xf7, 0x3d, 0x00, 0xbd, 0x9d, 0xd1, 0x95, 0xcf, 0x01, 0x00, 0x00,
 }
@@ -79,6 +79,14 @@ func (srv *WSServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	state := &conn.ConnectionState{
+		State:   api.StatConnected,
+		Headers: req.Header.Clone(),
+	}
+	if req.TLS != nil {
+		state.PeerCertificates = req.TLS.PeerCertificates
+	}
+
 	conn := conn.NewConnection(&conn.ConnectionOptions{
 		ConnType: api.ProtocolTypeWS,
 		Base:     wsConn,
@@ -86,10 +94,7 @@ func (srv *WSServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		Consumer: srv.options.Consumer,
 		Handler:  srv.options.Handler,
 		CtrlLane: lane.NewLane(api.ProtocolTypeWS, wsConn),
-		State: &conn.ConnectionState{
-			State:   api.StatConnected,
-			Headers: req.Header.Clone(),
-		},
+		State:    state,
 		AutoRoute:          srv.options.AutoRoute,
 		OnReadTransportErr: srv.options.OnReadTransportErr,
 	})
@@ -25,6 +25,7 @@ func (t *MessageTranslator) protoToModel(src *message.Message, dst *model.Messag
 
 	// TODO:
 	dst.Header.Sync = src.Header.Sync
+	dst.SetTraceParent(src.Header.TraceParent)
 
 	return nil
 }
@@ -34,6 +35,7 @@ func (t *MessageTranslator) modelToProto(src *model.Message, dst *message.Messag
 	dst.Header.ParentID = src.GetParentID()
 	dst.Header.Timestamp = int64(src.GetTimestamp())
 	dst.Header.Sync = src.IsSync()
+	dst.Header.TraceParent = src.GetTraceParent()
 	dst.Router.Source = src.GetSource()
 	dst.Router.Group = src.GetGroup()
 	dst.Router.Resouce = src.GetResource()
@@ -0,0 +1,35 @@
+package translator
+
+import (
+	"testing"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	msg := model.NewMessage("").
+		BuildRouter("edgecontroller", "resource", "default/pod/nginx", model.UpdateOperation).
+		SetTraceParent(traceParent).
+		FillBody("content")
+
+	tran := NewTran()
+	raw, err := tran.Encode(msg)
+	if err != nil {
+		t.Fatalf("failed to encode message: %v", err)
+	}
+	decoded := &model.Message{}
+	if err := tran.Decode(raw, decoded); err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+
+	if decoded.GetID() != msg.GetID() || decoded.GetResource() != msg.GetResource() || decoded.GetOperation() != msg.GetOperation() {
+		t.Errorf("expected message %v, but got %v", msg, decoded)
+	}
+	if decoded.GetTraceParent() != traceParent {
+		t.Errorf("expected trace parent %s, but got %s", traceParent, decoded.GetTraceParent())
+	}
+	if content, ok := decoded.GetContent().([]byte); !ok || string(content) != "content" {
+		t.Errorf("expected content %q, but got %v", "content", decoded.GetContent())
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/kubeedge/beehive/pkg/core"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+const (
+	// cloudHubStartTimeout is the timeout of cloudhub syncing its informers and preparing the certificates
+	cloudHubStartTimeout = 2 * time.Minute
+	// hollowNodeCertCommonName is the common name of the client certificate shared by all the hollow nodes,
+	// cloudhub identifies the nodes by the node_id header instead of the certificate
+	hollowNodeCertCommonName = "kubeedge-scale-hollow-node"
+)
+
+// apiServer is the apiserver that cloudcore and the workload talk to.
+type apiServer struct {
+	config         *rest.Config
+	kubeConfigPath string
+	stop           func()
+}
+
+// startAPIServer starts an envtest apiserver with the KubeEdge CRDs installed,
+// or uses the cluster of --kubeconfig if it is specified.
+func startAPIServer(o *scaleOptions) (*apiServer, error) {
+	if o.KubeConfig != "" {
+		config, err := clientcmd.BuildConfigFromFlags("", o.KubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig %s: %v", o.KubeConfig, err)
+		}
+		return &apiServer{config: config, kubeConfigPath: o.KubeConfig, stop: func() {}}, nil
+	}
+
+	crdDirs, err := crdDirectories(o.CRDDir)
+	if err != nil {
+		return nil, err
+	}
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     crdDirs,
+		ErrorIfCRDPathMissing: true,
+		BinaryAssetsDirectory: o.EnvtestBinDir,
+	}
+	config, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start envtest apiserver: %v", err)
+	}
+	stop := func() {
+		if err := env.Stop(); err != nil {
+			klog.Errorf("failed to stop envtest apiserver: %v", err)
+		}
+	}
+
+	// cloudcore loads the apiserver from a kubeconfig file
+	user, err := env.AddUser(envtest.User{Name: "kubeedge-scale", Groups: []string{"system:masters"}}, nil)
+	if err != nil {
+		stop()
+		return nil, fmt.Errorf("failed to add envtest user: %v", err)
+	}
+	kubeConfig, err := user.KubeConfig()
+	if err != nil {
+		stop()
+		return nil, fmt.Errorf("failed to get kubeconfig of envtest user: %v", err)
+	}
+	dir, err := os.MkdirTemp("", "kubeedge-scale-")
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	kubeConfigPath := filepath.Join(dir, "kubeconfig")
+	if err = os.WriteFile(kubeConfigPath, kubeConfig, 0600); err != nil {
+		stop()
+		return nil, err
+	}
+
+	return &apiServer{
+		config:         config,
+		kubeConfigPath: kubeConfigPath,
+		stop: func() {
+			stop()
+			os.RemoveAll(dir)
+		},
+	}, nil
+}
+
+// crdDirectories returns the sub directories of the CRD dir, since envtest does not
+// read CRDs recursively.
+func crdDirectories(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRD dir %s: %v", dir, err)
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return dirs, nil
+}
+
+// cloudHubEndpoint is where the hollow nodes connect to.
+type cloudHubEndpoint struct {
+	addr      string
+	tlsConfig *tls.Config
+}
+
+// startCloudCore starts the cloudcore modules that serve the edge nodes in this process,
+// and returns once cloudhub has prepared its certificates.
+func startCloudCore(kubeConfigPath string, o *scaleOptions) (*cloudHubEndpoint, error) {
+	config := v1alpha1.NewDefaultCloudCoreConfig()
+	config.KubeAPIConfig.KubeConfig = kubeConfigPath
+
+	hub := config.Modules.CloudHub
+	if hub.NodeLimit < int32(o.Nodes) {
+		hub.NodeLimit = int32(o.Nodes)
+	}
+	hub.AdvertiseAddress = []string{"127.0.0.1"}
+	hub.WebSocket.Address = "127.0.0.1"
+	hub.WebSocket.Port = o.WebSocketPort
+	hub.HTTPS.Address = "127.0.0.1"
+	hub.HTTPS.Port = o.HTTPSPort
+	hub.Quic.Enable = false
+	hub.UnixSocket.Enable = false
+	// the certificates are generated by cloudhub and saved to the secrets,
+	// never use the ones of a cloudcore installed on this host
+	certDir, err := os.MkdirTemp("", "kubeedge-scale-certs-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(certDir)
+	hub.TLSCAFile = filepath.Join(certDir, "rootCA.crt")
+	hub.TLSCAKeyFile = filepath.Join(certDir, "rootCA.key")
+	hub.TLSCertFile = filepath.Join(certDir, "server.crt")
+	hub.TLSPrivateKeyFile = filepath.Join(certDir, "server.key")
+	v1alpha1.AdjustCloudCoreConfig(config)
+
+	client.InitKubeEdgeClient(config.KubeAPIConfig)
+	gis := informers.GetInformersManager()
+
+	cloudhub.Register(hub)
+	edgecontroller.Register(config.Modules.EdgeController)
+	devicecontroller.Register(config.Modules.DeviceController)
+
+	ctx := beehiveContext.GetContext()
+	core.StartModules()
+	gis.Start(ctx.Done())
+
+	select {
+	case <-cloudhub.DoneTLSTunnelCerts:
+	case <-time.After(cloudHubStartTimeout):
+		return nil, fmt.Errorf("timeout waiting for cloudhub to start")
+	}
+
+	tlsConfig, err := hollowNodeTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &cloudHubEndpoint{
+		addr:      net.JoinHostPort(hub.WebSocket.Address, fmt.Sprint(hub.WebSocket.Port)),
+		tlsConfig: tlsConfig,
+	}, nil
+}
+
+// hollowNodeTLSConfig signs a client certificate with the CA of cloudhub,
+// the same way that cloudhub signs the certificates of edgecore.
+func hollowNodeTLSConfig() (*tls.Config, error) {
+	caCert, err := x509.ParseCertificate(hubconfig.Config.Ca)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cloudhub CA: %v", err)
+	}
+	caKey, err := x509.ParseECPrivateKey(hubconfig.Config.CaKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cloudhub CA key: %v", err)
+	}
+	key, err := httpserver.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	certDER, err := httpserver.NewCertFromCa(&certutil.Config{
+		CommonName: hollowNodeCertCommonName,
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caCert, key.Public(), caKey, 365)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign hollow node certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(
+		pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: certDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return &tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
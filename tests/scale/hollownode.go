@@ -0,0 +1,346 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	devicecontrollerconstants "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	edgeapi "github.com/kubeedge/kubeedge/common/types"
+	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/viaduct/pkg/api"
+	wsclient "github.com/kubeedge/viaduct/pkg/client"
+	"github.com/kubeedge/viaduct/pkg/conn"
+)
+
+const (
+	// projectID is the default project ID of edgehub
+	projectID = "e632aba927ea4ac2b575ec1603d56f10"
+	// keepaliveInterval must be shorter than the keepalive interval of cloudhub
+	keepaliveInterval = 10 * time.Second
+	// pendingTimeout is how long an upstream message waits for its response before it is counted as lost
+	pendingTimeout = time.Minute
+	connectRetries = 30
+	connectTimeout = 30 * time.Second
+)
+
+// pendingMessage is an upstream message waiting for the response of cloud.
+type pendingMessage struct {
+	kind   string
+	sentAt time.Time
+}
+
+// hollowNode emulates the cloudhub facing part of edgecore: it keeps the websocket
+// connection alive, acks the messages from cloud, reports the node status and
+// the status of the pods bound to it. A full hollow edgecore can not be used,
+// since the beehive modules of only one edgecore can run in a process.
+type hollowNode struct {
+	name           string
+	hub            *cloudHubEndpoint
+	stats          *latencyStats
+	statusInterval time.Duration
+
+	connection conn.Connection
+
+	lock sync.Mutex
+	// pods maps the namespace/name of the pods bound to this node to their UIDs
+	pods map[string]types.UID
+	// delivered records the latest sent-at annotation delivered for each object,
+	// so that a message resent by cloudhub is measured only once
+	delivered map[string]string
+	// pending maps the IDs of the upstream messages to when they are sent
+	pending map[string]pendingMessage
+}
+
+func newHollowNode(name string, hub *cloudHubEndpoint, stats *latencyStats, statusInterval time.Duration) *hollowNode {
+	return &hollowNode{
+		name:           name,
+		hub:            hub,
+		stats:          stats,
+		statusInterval: statusInterval,
+		pods:           make(map[string]types.UID),
+		delivered:      make(map[string]string),
+		pending:        make(map[string]pendingMessage),
+	}
+}
+
+// connect connects to cloudhub the same way as the websocket client of edgehub.
+func (n *hollowNode) connect() error {
+	header := make(http.Header)
+	header.Set("node_id", n.name)
+	header.Set("project_id", projectID)
+	client := &wsclient.Client{
+		Options: wsclient.Options{
+			HandshakeTimeout: connectTimeout,
+			TLSConfig:        n.hub.tlsConfig,
+			Type:             api.ProtocolTypeWS,
+			Addr:             strings.Join([]string{"wss:/", n.hub.addr, projectID, n.name, "events"}, "/"),
+			AutoRoute:        false,
+			ConnUse:          api.UseTypeMessage,
+		},
+		ExOpts: api.WSClientOption{Header: header},
+	}
+
+	var err error
+	// cloudhub starts the websocket server a while after its certificates are prepared
+	for i := 0; i < connectRetries; i++ {
+		n.connection, err = client.Connect()
+		if err == nil {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("failed to connect hollow node %s to cloudhub: %v", n.name, err)
+}
+
+func (n *hollowNode) close() {
+	if n.connection != nil {
+		n.connection.Close()
+	}
+}
+
+// run serves the connection until the context is done or the connection is broken.
+func (n *hollowNode) run(ctx context.Context) {
+	go n.keepalive(ctx)
+	go n.reportNodeStatus(ctx)
+
+	for {
+		msg := model.Message{}
+		if err := n.connection.ReadMessage(&msg); err != nil {
+			if ctx.Err() == nil {
+				klog.Errorf("hollow node %s failed to read message: %v", n.name, err)
+			}
+			return
+		}
+		n.handleMessage(&msg)
+	}
+}
+
+func (n *hollowNode) keepalive(ctx context.Context) {
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+	for {
+		msg := model.NewMessage("").
+			BuildRouter(modules.EdgeHubModuleName, messagepkg.ResourceGroupName, "node", messagepkg.OperationKeepalive).
+			FillBody("ping")
+		if err := n.connection.WriteMessageAsync(msg); err != nil {
+			klog.Errorf("hollow node %s failed to send keepalive: %v", n.name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (n *hollowNode) reportNodeStatus(ctx context.Context) {
+	ticker := time.NewTicker(n.statusInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n.expirePending()
+		resource := strings.Join([]string{v1.NamespaceDefault, model.ResourceTypeNodeStatus, n.name}, "/")
+		n.sendUpstream(kindNodeStatus, resource, &edgeapi.NodeStatusRequest{Status: hollowNodeStatus()})
+	}
+}
+
+// handleMessage acks the message, records the latency of the objects delivered,
+// and reports the status of the pods bound to this node.
+func (n *hollowNode) handleMessage(msg *model.Message) {
+	if msg.GetOperation() == model.ResponseOperation && n.receiveResponse(msg.GetParentID()) {
+		return
+	}
+	// acking a message that does not require ack is a no-op in cloudhub
+	ack := msg.NewRespByMessage(msg, "OK")
+	if err := n.connection.WriteMessageAsync(ack); err != nil {
+		klog.Errorf("hollow node %s failed to ack message %s: %v", n.name, msg.GetID(), err)
+	}
+
+	// the resource is trimmed by cloudhub to <namespace>/<type>/<name>
+	tokens := strings.Split(msg.GetResource(), "/")
+	if len(tokens) != 3 {
+		return
+	}
+	namespace, kind, name := tokens[0], tokens[1], tokens[2]
+	switch kind {
+	case model.ResourceTypePod, model.ResourceTypeConfigmap, devicecontrollerconstants.ResourceTypeDevice:
+	default:
+		return
+	}
+	data, err := msg.GetContentData()
+	if err != nil {
+		return
+	}
+	obj := &metav1.PartialObjectMetadata{}
+	if err = json.Unmarshal(data, obj); err != nil {
+		klog.Warningf("hollow node %s failed to unmarshal %s %s/%s: %v", n.name, kind, namespace, name, err)
+		return
+	}
+
+	switch msg.GetOperation() {
+	case model.InsertOperation, model.UpdateOperation:
+		n.observeDelivery(kind, namespace, name, obj.Annotations[sentAtAnnotation])
+		if kind == model.ResourceTypePod {
+			n.addPod(namespace, name, obj.UID)
+		}
+	case model.DeleteOperation:
+		if kind == model.ResourceTypePod {
+			n.lock.Lock()
+			delete(n.pods, namespace+"/"+name)
+			n.lock.Unlock()
+		}
+	}
+}
+
+func (n *hollowNode) observeDelivery(kind, namespace, name, sentAt string) {
+	if sentAt == "" {
+		return
+	}
+	nanos, err := strconv.ParseInt(sentAt, 10, 64)
+	if err != nil {
+		return
+	}
+	key := kind + "/" + namespace + "/" + name
+	n.lock.Lock()
+	if n.delivered[key] == sentAt {
+		n.lock.Unlock()
+		return
+	}
+	n.delivered[key] = sentAt
+	n.lock.Unlock()
+	n.stats.observe(kind, time.Since(time.Unix(0, nanos)))
+}
+
+// addPod reports the pod running the first time it is bound to this node.
+func (n *hollowNode) addPod(namespace, name string, uid types.UID) {
+	key := namespace + "/" + name
+	n.lock.Lock()
+	oldUID, exists := n.pods[key]
+	n.pods[key] = uid
+	n.lock.Unlock()
+	if exists && oldUID == uid {
+		return
+	}
+
+	now := metav1.Now()
+	status := edgeapi.PodStatusRequest{
+		UID:  uid,
+		Name: name,
+		Status: v1.PodStatus{
+			Phase:     v1.PodRunning,
+			StartTime: &now,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodInitialized, Status: v1.ConditionTrue},
+				{Type: v1.PodReady, Status: v1.ConditionTrue},
+				{Type: v1.ContainersReady, Status: v1.ConditionTrue},
+			},
+		},
+	}
+	resource := strings.Join([]string{namespace, model.ResourceTypePodStatus, name}, "/")
+	n.sendUpstream(kindPodStatus, resource, status)
+}
+
+func (n *hollowNode) podCount() int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return len(n.pods)
+}
+
+func (n *hollowNode) sendUpstream(kind, resource string, content interface{}) {
+	msg := model.NewMessage("").
+		BuildRouter(modules.EdgedModuleName, messagepkg.ResourceGroupName, resource, model.UpdateOperation).
+		FillBody(content)
+	n.lock.Lock()
+	n.pending[msg.GetID()] = pendingMessage{kind: kind, sentAt: time.Now()}
+	n.lock.Unlock()
+	if err := n.connection.WriteMessageAsync(msg); err != nil {
+		klog.Errorf("hollow node %s failed to send %s: %v", n.name, kind, err)
+	}
+}
+
+// receiveResponse records the latency of the upstream message that the response is for,
+// and returns false if the response is not for an upstream message of this node.
+func (n *hollowNode) receiveResponse(parentID string) bool {
+	n.lock.Lock()
+	pending, ok := n.pending[parentID]
+	delete(n.pending, parentID)
+	n.lock.Unlock()
+	if ok {
+		n.stats.observe(pending.kind, time.Since(pending.sentAt))
+	}
+	return ok
+}
+
+// expirePending counts the upstream messages that never get a response as lost.
+func (n *hollowNode) expirePending() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	for id, pending := range n.pending {
+		if time.Since(pending.sentAt) > pendingTimeout {
+			delete(n.pending, id)
+			n.stats.lose(pending.kind)
+		}
+	}
+}
+
+func hollowNodeStatus() v1.NodeStatus {
+	now := metav1.Now()
+	resources := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("8Gi"),
+		v1.ResourcePods:   resource.MustParse("110"),
+	}
+	return v1.NodeStatus{
+		Capacity:    resources,
+		Allocatable: resources,
+		Phase:       v1.NodeRunning,
+		Conditions: []v1.NodeCondition{{
+			Type:               v1.NodeReady,
+			Status:             v1.ConditionTrue,
+			Reason:             "EdgeReady",
+			Message:            "edge is posting ready status",
+			LastHeartbeatTime:  now,
+			LastTransitionTime: now,
+		}},
+		Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "127.0.0.1"}},
+		NodeInfo: v1.NodeSystemInfo{
+			OperatingSystem: "linux",
+			Architecture:    "amd64",
+			KubeletVersion:  "v1.26.7-kubeedge-scale",
+		},
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The scale command starts a cloudcore and N hollow edge nodes in one process,
+// drives a scripted workload through the apiserver and reports the message
+// latency and the cloudhub memory used per node session.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/component-base/cli/globalflag"
+	"k8s.io/klog/v2"
+)
+
+type scaleOptions struct {
+	Nodes              int
+	PodsPerNode        int
+	Duration           time.Duration
+	ConnectTimeout     time.Duration
+	PodChurnQPS        float64
+	ConfigMapChurnQPS  float64
+	DeviceChurnQPS     float64
+	NodeStatusInterval time.Duration
+
+	KubeConfig    string
+	EnvtestBinDir string
+	CRDDir        string
+
+	WebSocketPort uint32
+	HTTPSPort     uint32
+
+	MemProfileRate int
+	Output         string
+	Baseline       string
+	MaxRegression  float64
+}
+
+func newScaleOptions() *scaleOptions {
+	return &scaleOptions{
+		Nodes:              100,
+		PodsPerNode:        2,
+		Duration:           5 * time.Minute,
+		ConnectTimeout:     2 * time.Minute,
+		PodChurnQPS:        5,
+		ConfigMapChurnQPS:  5,
+		DeviceChurnQPS:     5,
+		NodeStatusInterval: 10 * time.Second,
+		EnvtestBinDir:      os.Getenv("KUBEBUILDER_ASSETS"),
+		CRDDir:             "build/crds",
+		WebSocketPort:      10000,
+		HTTPSPort:          10002,
+		MemProfileRate:     4096,
+		MaxRegression:      0.2,
+	}
+}
+
+func (o *scaleOptions) addFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.Nodes, "nodes", o.Nodes, "Number of hollow edge nodes connected to cloudhub.")
+	fs.IntVar(&o.PodsPerNode, "pods-per-node", o.PodsPerNode, "Number of pods bound to each hollow node.")
+	fs.DurationVar(&o.Duration, "duration", o.Duration, "How long the churn runs after all the nodes are connected.")
+	fs.DurationVar(&o.ConnectTimeout, "connect-timeout", o.ConnectTimeout, "Timeout for all the hollow nodes to connect and receive their initial pods.")
+	fs.Float64Var(&o.PodChurnQPS, "pod-churn-qps", o.PodChurnQPS, "Pods deleted and recreated per second, 0 disables the pod churn.")
+	fs.Float64Var(&o.ConfigMapChurnQPS, "configmap-churn-qps", o.ConfigMapChurnQPS, "ConfigMaps updated per second, 0 disables the configmap churn.")
+	fs.Float64Var(&o.DeviceChurnQPS, "device-churn-qps", o.DeviceChurnQPS, "Device twin desired values updated per second, 0 disables the device churn.")
+	fs.DurationVar(&o.NodeStatusInterval, "node-status-interval", o.NodeStatusInterval, "Interval of the node status reported by each hollow node.")
+	fs.StringVar(&o.KubeConfig, "kubeconfig", o.KubeConfig, "Use the cluster in this kubeconfig instead of starting an envtest apiserver.")
+	fs.StringVar(&o.EnvtestBinDir, "envtest-bin-dir", o.EnvtestBinDir, "Directory of the etcd and kube-apiserver binaries used by envtest, defaults to $KUBEBUILDER_ASSETS.")
+	fs.StringVar(&o.CRDDir, "crd-dir", o.CRDDir, "Directory of the KubeEdge CRDs installed to the envtest apiserver.")
+	fs.Uint32Var(&o.WebSocketPort, "websocket-port", o.WebSocketPort, "Port of the cloudhub websocket server.")
+	fs.Uint32Var(&o.HTTPSPort, "https-port", o.HTTPSPort, "Port of the cloudhub https server.")
+	fs.IntVar(&o.MemProfileRate, "mem-profile-rate", o.MemProfileRate, "runtime.MemProfileRate used to attribute the cloudhub memory, lower is more accurate and slower.")
+	fs.StringVar(&o.Output, "output", o.Output, "File the JSON report is written to, defaults to stdout.")
+	fs.StringVar(&o.Baseline, "baseline", o.Baseline, "JSON report of a previous run to compare with.")
+	fs.Float64Var(&o.MaxRegression, "max-regression", o.MaxRegression, "Relative increase over the baseline that fails the run, e.g. 0.2 for 20%.")
+}
+
+func (o *scaleOptions) validate() error {
+	if o.Nodes <= 0 {
+		return fmt.Errorf("--nodes must be positive, got %d", o.Nodes)
+	}
+	if o.PodsPerNode < 0 {
+		return fmt.Errorf("--pods-per-node must not be negative, got %d", o.PodsPerNode)
+	}
+	if o.NodeStatusInterval <= 0 {
+		return fmt.Errorf("--node-status-interval must be positive, got %v", o.NodeStatusInterval)
+	}
+	if o.PodChurnQPS < 0 || o.ConfigMapChurnQPS < 0 || o.DeviceChurnQPS < 0 {
+		return fmt.Errorf("churn qps must not be negative")
+	}
+	if o.KubeConfig == "" && o.EnvtestBinDir == "" {
+		return fmt.Errorf("either --kubeconfig or --envtest-bin-dir must be specified")
+	}
+	return nil
+}
+
+func main() {
+	command := newScaleCommand()
+	if err := command.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newScaleCommand() *cobra.Command {
+	o := newScaleOptions()
+	cmd := &cobra.Command{
+		Use: "scale",
+		Long: `scale starts cloudcore and N hollow edge nodes in one process and drives a scripted
+workload of pods, configmaps, device twins and node status through the apiserver.
+The hollow nodes speak the cloudhub protocol directly instead of running edgecore,
+so thousands of them fit in one process. It reports the latency percentiles of the
+downstream delivery and the upstream status, and the cloudhub memory per node session,
+and compares them with a baseline report if one is given.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.validate(); err != nil {
+				return err
+			}
+			// the profile rate must be set before the allocations to be attributed
+			runtime.MemProfileRate = o.MemProfileRate
+
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+			return run(ctx, o)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.AddGoFlagSet(flag.CommandLine)
+	globalflag.AddGlobalFlags(fs, cmd.Name())
+	o.addFlags(fs)
+	return cmd
+}
+
+func run(ctx context.Context, o *scaleOptions) error {
+	env, err := startAPIServer(o)
+	if err != nil {
+		return err
+	}
+	defer env.stop()
+
+	workload, err := newWorkload(env.config, o)
+	if err != nil {
+		return err
+	}
+	// the edge nodes must exist before cloudcore starts, so that the
+	// location cache of edgecontroller knows them
+	if err = workload.createNodes(ctx); err != nil {
+		return err
+	}
+
+	hub, err := startCloudCore(env.kubeConfigPath, o)
+	if err != nil {
+		return err
+	}
+	before := takeMemSnapshot()
+
+	stats := newLatencyStats()
+	nodes := make([]*hollowNode, 0, o.Nodes)
+	for i := 0; i < o.Nodes; i++ {
+		node := newHollowNode(workload.nodeName(i), hub, stats, o.NodeStatusInterval)
+		if err = node.connect(); err != nil {
+			return err
+		}
+		go node.run(ctx)
+		nodes = append(nodes, node)
+	}
+	defer func() {
+		for _, node := range nodes {
+			node.close()
+		}
+	}()
+	klog.Infof("%d hollow nodes connected", len(nodes))
+
+	if err = workload.createObjects(ctx); err != nil {
+		return err
+	}
+	if err = waitForPods(ctx, nodes, o.PodsPerNode, o.ConnectTimeout); err != nil {
+		return err
+	}
+	// the memory of the sessions is measured when the initial objects are delivered,
+	// the churn below only adds noise of the objects in flight
+	after := takeMemSnapshot()
+	stats.reset()
+
+	klog.Infof("running churn for %v", o.Duration)
+	churnCtx, cancel := context.WithTimeout(ctx, o.Duration)
+	defer cancel()
+	workload.churn(churnCtx)
+
+	report := newReport(o, stats, before, after)
+	if err = report.write(o.Output); err != nil {
+		return err
+	}
+	if o.Baseline == "" {
+		return nil
+	}
+	baseline, err := loadReport(o.Baseline)
+	if err != nil {
+		return err
+	}
+	if regressions := report.compare(baseline, o.MaxRegression); len(regressions) > 0 {
+		for _, r := range regressions {
+			klog.Errorf("regression: %s", r)
+		}
+		return fmt.Errorf("%d regressions over the baseline %s", len(regressions), o.Baseline)
+	}
+	return nil
+}
+
+// waitForPods waits until each hollow node has received its initial pods.
+func waitForPods(ctx context.Context, nodes []*hollowNode, podsPerNode int, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		ready := 0
+		for _, node := range nodes {
+			if node.podCount() >= podsPerNode {
+				ready++
+			}
+		}
+		if ready == len(nodes) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("only %d of %d hollow nodes received their pods: %v", ready, len(nodes), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// The kinds of the upstream messages, the downstream messages use their resource types as kinds.
+const (
+	kindNodeStatus = "nodestatus"
+	kindPodStatus  = "podstatus"
+)
+
+// The categories that the in-use memory is attributed to by the allocation stacks.
+const (
+	memNodeSession     = "NodeSession"
+	memNodeMessagePool = "NodeMessagePool"
+	memCloudHub        = "cloudhub"
+	// memViaduct includes the client side connections of the hollow nodes,
+	// since they share the code and the goroutines with the server side
+	memViaduct = "viaduct"
+)
+
+// memCategories maps the memory categories to the prefixes of the functions in the
+// allocation stacks. NodeSession and NodeMessagePool are also counted in cloudhub.
+var memCategories = map[string][]string{
+	memNodeSession: {
+		"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session.NewNodeSession",
+		"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session.(*NodeSession)",
+	},
+	memNodeMessagePool: {
+		"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common.InitNodeMessagePool",
+		"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common.(*NodeMessagePool)",
+		"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/dispatcher.(*messageDispatcher).enqueue",
+	},
+	memCloudHub: {
+		"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/",
+	},
+	memViaduct: {
+		"github.com/kubeedge/viaduct/pkg/",
+	},
+}
+
+// latencyStats collects the latency samples of each kind of message.
+type latencyStats struct {
+	lock    sync.Mutex
+	samples map[string][]time.Duration
+	lost    map[string]int
+}
+
+func newLatencyStats() *latencyStats {
+	return &latencyStats{
+		samples: make(map[string][]time.Duration),
+		lost:    make(map[string]int),
+	}
+}
+
+func (s *latencyStats) observe(kind string, latency time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.samples[kind] = append(s.samples[kind], latency)
+}
+
+func (s *latencyStats) lose(kind string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lost[kind]++
+}
+
+// reset drops the samples collected so far, e.g. the ones of the initial sync.
+func (s *latencyStats) reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.samples = make(map[string][]time.Duration)
+	s.lost = make(map[string]int)
+}
+
+func (s *latencyStats) summarize() map[string]latencySummary {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	summaries := make(map[string]latencySummary)
+	for kind, samples := range s.samples {
+		summaries[kind] = summarizeLatency(samples, s.lost[kind])
+	}
+	for kind, lost := range s.lost {
+		if _, ok := summaries[kind]; !ok {
+			summaries[kind] = latencySummary{Lost: lost}
+		}
+	}
+	return summaries
+}
+
+type latencySummary struct {
+	Count int     `json:"count"`
+	Lost  int     `json:"lost,omitempty"`
+	P50Ms float64 `json:"p50Ms"`
+	P90Ms float64 `json:"p90Ms"`
+	P99Ms float64 `json:"p99Ms"`
+	MaxMs float64 `json:"maxMs"`
+}
+
+func summarizeLatency(samples []time.Duration, lost int) latencySummary {
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return latencySummary{
+		Count: len(sorted),
+		Lost:  lost,
+		P50Ms: milliseconds(percentile(sorted, 50)),
+		P90Ms: milliseconds(percentile(sorted, 90)),
+		P99Ms: milliseconds(percentile(sorted, 99)),
+		MaxMs: milliseconds(percentile(sorted, 100)),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// memSnapshot is the memory of the process at some point.
+type memSnapshot struct {
+	heapInuse  uint64
+	goroutines int
+	// inuse maps the memory categories to their in-use bytes
+	inuse map[string]int64
+}
+
+func takeMemSnapshot() memSnapshot {
+	// the memory profile is as of the last completed GC cycle, and the frees
+	// of a cycle are only published after the next one
+	runtime.GC()
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return memSnapshot{
+		heapInuse:  stats.HeapInuse,
+		goroutines: runtime.NumGoroutine(),
+		inuse:      attributeMemory(memProfile(), int64(runtime.MemProfileRate)),
+	}
+}
+
+func memProfile() []runtime.MemProfileRecord {
+	n, _ := runtime.MemProfile(nil, false)
+	for {
+		// allocations between the two calls may add records
+		records := make([]runtime.MemProfileRecord, n+50)
+		var ok bool
+		if n, ok = runtime.MemProfile(records, false); ok {
+			return records[:n]
+		}
+	}
+}
+
+// attributeMemory sums the in-use bytes of the records to the categories
+// whose functions are in their allocation stacks.
+func attributeMemory(records []runtime.MemProfileRecord, rate int64) map[string]int64 {
+	inuse := make(map[string]int64, len(memCategories))
+	for category := range memCategories {
+		inuse[category] = 0
+	}
+	for i := range records {
+		bytes := scaleHeapSample(records[i].InUseObjects(), records[i].InUseBytes(), rate)
+		if bytes == 0 {
+			continue
+		}
+		for category := range matchCategories(records[i].Stack()) {
+			inuse[category] += bytes
+		}
+	}
+	return inuse
+}
+
+func matchCategories(stack []uintptr) map[string]struct{} {
+	matched := make(map[string]struct{})
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		for category, prefixes := range memCategories {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(frame.Function, prefix) {
+					matched[category] = struct{}{}
+				}
+			}
+		}
+		if !more {
+			return matched
+		}
+	}
+}
+
+// scaleHeapSample estimates the bytes of the sampled objects, the same way as pprof does.
+func scaleHeapSample(count, size, rate int64) int64 {
+	if count == 0 || size == 0 {
+		return 0
+	}
+	if rate <= 1 {
+		return size
+	}
+	avgSize := float64(size) / float64(count)
+	scale := 1 / (1 - math.Exp(-avgSize/float64(rate)))
+	return int64(float64(size) * scale)
+}
+
+type memoryReport struct {
+	// BytesPerSession maps the memory categories to the in-use bytes per node session
+	BytesPerSession map[string]int64 `json:"bytesPerSession"`
+	// HeapInuseDeltaPerSession includes the memory of the hollow nodes in the same process
+	HeapInuseDeltaPerSession int64   `json:"heapInuseDeltaPerSession"`
+	GoroutinesPerSession     float64 `json:"goroutinesPerSession"`
+}
+
+type report struct {
+	Nodes       int                       `json:"nodes"`
+	PodsPerNode int                       `json:"podsPerNode"`
+	Duration    string                    `json:"duration"`
+	Latency     map[string]latencySummary `json:"latency"`
+	Memory      memoryReport              `json:"memory"`
+}
+
+// newReport summarizes the latency, and the memory growth from before the nodes
+// connected to after their initial objects were delivered.
+func newReport(o *scaleOptions, stats *latencyStats, before, after memSnapshot) *report {
+	sessions := int64(o.Nodes)
+	memory := memoryReport{
+		BytesPerSession:          make(map[string]int64, len(after.inuse)),
+		HeapInuseDeltaPerSession: (int64(after.heapInuse) - int64(before.heapInuse)) / sessions,
+		GoroutinesPerSession:     float64(after.goroutines-before.goroutines) / float64(sessions),
+	}
+	for category, bytes := range after.inuse {
+		memory.BytesPerSession[category] = (bytes - before.inuse[category]) / sessions
+	}
+	return &report{
+		Nodes:       o.Nodes,
+		PodsPerNode: o.PodsPerNode,
+		Duration:    o.Duration.String(),
+		Latency:     stats.summarize(),
+		Memory:      memory,
+	}
+}
+
+func (r *report) write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func loadReport(path string) (*report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %v", path, err)
+	}
+	r := &report{}
+	if err = json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal report %s: %v", path, err)
+	}
+	return r, nil
+}
+
+// compare returns the memory per session and the p99 latencies that grow
+// more than maxRegression relative to the baseline.
+func (r *report) compare(baseline *report, maxRegression float64) []string {
+	if baseline.Nodes != r.Nodes || baseline.PodsPerNode != r.PodsPerNode {
+		klog.Warningf("baseline runs %d nodes with %d pods per node, the current run %d nodes with %d pods per node",
+			baseline.Nodes, baseline.PodsPerNode, r.Nodes, r.PodsPerNode)
+	}
+
+	var regressions []string
+	exceeds := func(name string, current, base float64) {
+		if base > 0 && current > base*(1+maxRegression) {
+			regressions = append(regressions, fmt.Sprintf("%s is %.2f, %.1f%% over the baseline %.2f",
+				name, current, (current/base-1)*100, base))
+		}
+	}
+
+	categories := make([]string, 0, len(baseline.Memory.BytesPerSession))
+	for category := range baseline.Memory.BytesPerSession {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		exceeds(fmt.Sprintf("%s bytes per session", category),
+			float64(r.Memory.BytesPerSession[category]), float64(baseline.Memory.BytesPerSession[category]))
+	}
+	exceeds("heap in use delta per session", float64(r.Memory.HeapInuseDeltaPerSession), float64(baseline.Memory.HeapInuseDeltaPerSession))
+	exceeds("goroutines per session", r.Memory.GoroutinesPerSession, baseline.Memory.GoroutinesPerSession)
+
+	kinds := make([]string, 0, len(baseline.Latency))
+	for kind := range baseline.Latency {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		current, ok := r.Latency[kind]
+		if !ok {
+			continue
+		}
+		exceeds(fmt.Sprintf("%s p99 latency in ms", kind), current.P99Ms, baseline.Latency[kind].P99Ms)
+	}
+	return regressions
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 200; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	cases := []struct {
+		p      float64
+		expect time.Duration
+	}{
+		{p: 0, expect: time.Millisecond},
+		{p: 50, expect: 100 * time.Millisecond},
+		{p: 99, expect: 198 * time.Millisecond},
+		{p: 100, expect: 200 * time.Millisecond},
+	}
+	for _, c := range cases {
+		if got := percentile(sorted, c.p); got != c.expect {
+			t.Errorf("p%v: expect %v, got %v", c.p, c.expect, got)
+		}
+	}
+	if got := percentile(nil, 99); got != 0 {
+		t.Errorf("expect 0 for no samples, got %v", got)
+	}
+}
+
+func TestLatencyStats(t *testing.T) {
+	stats := newLatencyStats()
+	for _, ms := range []int{30, 10, 20} {
+		stats.observe(kindNodeStatus, time.Duration(ms)*time.Millisecond)
+	}
+	stats.lose(kindNodeStatus)
+	stats.lose(kindPodStatus)
+
+	summaries := stats.summarize()
+	nodeStatus := summaries[kindNodeStatus]
+	if nodeStatus.Count != 3 || nodeStatus.Lost != 1 || nodeStatus.P50Ms != 20 || nodeStatus.MaxMs != 30 {
+		t.Errorf("unexpected node status summary %+v", nodeStatus)
+	}
+	if podStatus := summaries[kindPodStatus]; podStatus.Count != 0 || podStatus.Lost != 1 {
+		t.Errorf("unexpected pod status summary %+v", podStatus)
+	}
+
+	stats.reset()
+	if summaries = stats.summarize(); len(summaries) != 0 {
+		t.Errorf("expect no summary after reset, got %+v", summaries)
+	}
+}
+
+func TestScaleHeapSample(t *testing.T) {
+	if got := scaleHeapSample(0, 0, 512*1024); got != 0 {
+		t.Errorf("expect 0 for no objects, got %d", got)
+	}
+	if got := scaleHeapSample(10, 1000, 1); got != 1000 {
+		t.Errorf("expect every allocation sampled with rate 1, got %d", got)
+	}
+	// the objects much larger than the rate are always sampled
+	if got := scaleHeapSample(1, 1<<20, 4096); got != 1<<20 {
+		t.Errorf("expect the large object not scaled, got %d", got)
+	}
+	// the small objects are sampled with the probability of about size/rate
+	if got := scaleHeapSample(1, 64, 4096); got < 64*4096/64 || got > 64*4096/64+64 {
+		t.Errorf("expect the small object scaled to about the rate, got %d", got)
+	}
+}
+
+func TestReportCompare(t *testing.T) {
+	baseline := &report{
+		Nodes:       100,
+		PodsPerNode: 2,
+		Latency: map[string]latencySummary{
+			"pod":          {Count: 10, P99Ms: 100},
+			kindNodeStatus: {Count: 10, P99Ms: 50},
+		},
+		Memory: memoryReport{
+			BytesPerSession:          map[string]int64{memNodeSession: 1000, memNodeMessagePool: 2000},
+			HeapInuseDeltaPerSession: 10000,
+			GoroutinesPerSession:     6,
+		},
+	}
+	current := &report{
+		Nodes:       100,
+		PodsPerNode: 2,
+		Latency: map[string]latencySummary{
+			"pod":          {Count: 10, P99Ms: 110},
+			kindNodeStatus: {Count: 10, P99Ms: 80},
+		},
+		Memory: memoryReport{
+			BytesPerSession:          map[string]int64{memNodeSession: 1500, memNodeMessagePool: 2100},
+			HeapInuseDeltaPerSession: 11000,
+			GoroutinesPerSession:     6,
+		},
+	}
+
+	regressions := current.compare(baseline, 0.2)
+	if len(regressions) != 2 {
+		t.Fatalf("expect 2 regressions, got %v", regressions)
+	}
+	if !strings.HasPrefix(regressions[0], memNodeSession+" bytes per session is 1500.00, 50.0%") {
+		t.Errorf("unexpected regression %q", regressions[0])
+	}
+	if !strings.HasPrefix(regressions[1], kindNodeStatus+" p99 latency") {
+		t.Errorf("unexpected regression %q", regressions[1])
+	}
+
+	if regressions = current.compare(current, 0); len(regressions) != 0 {
+		t.Errorf("expect no regression over itself, got %v", regressions)
+	}
+}
+
+func TestReportWriteAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	r := &report{
+		Nodes:   1,
+		Latency: map[string]latencySummary{"configmap": {Count: 1, P99Ms: 1.5}},
+		Memory:  memoryReport{BytesPerSession: map[string]int64{memCloudHub: 42}},
+	}
+	if err := r.write(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Latency["configmap"].P99Ms != 1.5 || loaded.Memory.BytesPerSession[memCloudHub] != 42 {
+		t.Errorf("unexpected report loaded %+v", loaded)
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2023 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/apis/devices/v1beta1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+const (
+	// sentAtAnnotation records in unix nanoseconds when the workload wrote the object,
+	// the hollow nodes compute the delivery latency from it
+	sentAtAnnotation = "scale.kubeedge.io/sent-at"
+
+	workloadNamespace   = "kubeedge-scale"
+	hollowNodePrefix    = "hollow-edge-node-"
+	deviceModelName     = "scale-sensor"
+	devicePropertyName  = "temperature"
+	deviceProtocolName  = "modbus"
+	workloadPodImage    = "registry.k8s.io/pause:3.9"
+	workloadClientQPS   = 1000
+	workloadClientBurst = 2000
+)
+
+// workload creates the objects bound to the hollow nodes and churns them through the apiserver.
+type workload struct {
+	kubeClient kubernetes.Interface
+	crdClient  crdClientset.Interface
+	o          *scaleOptions
+}
+
+func newWorkload(config *rest.Config, o *scaleOptions) (*workload, error) {
+	config = rest.CopyConfig(config)
+	config.QPS = workloadClientQPS
+	config.Burst = workloadClientBurst
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	crdClient, err := crdClientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &workload{kubeClient: kubeClient, crdClient: crdClient, o: o}, nil
+}
+
+func (w *workload) nodeName(i int) string {
+	return hollowNodePrefix + strconv.Itoa(i)
+}
+
+func podName(node string, i int) string {
+	return fmt.Sprintf("%s-pod-%d", node, i)
+}
+
+func configMapName(node string) string {
+	return node + "-config"
+}
+
+func deviceName(node string) string {
+	return node + "-sensor"
+}
+
+func sentAt() map[string]string {
+	return map[string]string{sentAtAnnotation: strconv.FormatInt(time.Now().UnixNano(), 10)}
+}
+
+// createNodes creates the edge nodes of the hollow nodes.
+func (w *workload) createNodes(ctx context.Context) error {
+	for i := 0; i < w.o.Nodes; i++ {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   w.nodeName(i),
+				Labels: map[string]string{constants.EdgeNodeRoleKey: constants.EdgeNodeRoleValue},
+			},
+		}
+		if _, err := w.kubeClient.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create node %s: %v", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// createObjects creates a configmap, a device and the pods for each hollow node.
+func (w *workload) createObjects(ctx context.Context) error {
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: workloadNamespace}}
+	if _, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %v", workloadNamespace, err)
+	}
+	deviceModel := &v1beta1.DeviceModel{
+		ObjectMeta: metav1.ObjectMeta{Name: deviceModelName, Namespace: workloadNamespace},
+		Spec: v1beta1.DeviceModelSpec{
+			Protocol: deviceProtocolName,
+			Properties: []v1beta1.ModelProperty{{
+				Name:       devicePropertyName,
+				Type:       v1beta1.INT,
+				AccessMode: v1beta1.ReadWrite,
+			}},
+		},
+	}
+	if _, err := w.crdClient.DevicesV1beta1().DeviceModels(workloadNamespace).Create(ctx, deviceModel, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create device model %s: %v", deviceModelName, err)
+	}
+
+	for i := 0; i < w.o.Nodes; i++ {
+		node := w.nodeName(i)
+		// the configmap is sent to the node only when a pod on the node uses it
+		configMap := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: configMapName(node), Namespace: workloadNamespace, Annotations: sentAt()},
+			Data:       map[string]string{"generation": "0"},
+		}
+		if _, err := w.kubeClient.CoreV1().ConfigMaps(workloadNamespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create configmap %s: %v", configMap.Name, err)
+		}
+		device := &v1beta1.Device{
+			ObjectMeta: metav1.ObjectMeta{Name: deviceName(node), Namespace: workloadNamespace, Annotations: sentAt()},
+			Spec: v1beta1.DeviceSpec{
+				DeviceModelRef: &v1.LocalObjectReference{Name: deviceModelName},
+				NodeName:       node,
+				Protocol:       v1beta1.ProtocolConfig{ProtocolName: deviceProtocolName},
+				Properties: []v1beta1.DeviceProperty{{
+					Name:    devicePropertyName,
+					Desired: v1beta1.TwinProperty{Value: "0"},
+				}},
+			},
+		}
+		if _, err := w.crdClient.DevicesV1beta1().Devices(workloadNamespace).Create(ctx, device, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create device %s: %v", device.Name, err)
+		}
+		for j := 0; j < w.o.PodsPerNode; j++ {
+			if err := w.createPod(ctx, node, j); err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *workload) createPod(ctx context.Context, node string, i int) error {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: podName(node, i), Namespace: workloadNamespace, Annotations: sentAt()},
+		Spec: v1.PodSpec{
+			NodeName:                     node,
+			AutomountServiceAccountToken: pointer.Bool(false),
+			Containers: []v1.Container{{
+				Name:         "pause",
+				Image:        workloadPodImage,
+				VolumeMounts: []v1.VolumeMount{{Name: "config", MountPath: "/etc/config"}},
+			}},
+			Volumes: []v1.Volume{{
+				Name: "config",
+				VolumeSource: v1.VolumeSource{
+					ConfigMap: &v1.ConfigMapVolumeSource{
+						LocalObjectReference: v1.LocalObjectReference{Name: configMapName(node)},
+					},
+				},
+			}},
+		},
+	}
+	if _, err := w.kubeClient.CoreV1().Pods(workloadNamespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// churn runs the churn of each kind at its qps until the context is done.
+func (w *workload) churn(ctx context.Context) {
+	var group wait.Group
+	if w.o.PodsPerNode > 0 {
+		group.Start(func() { w.churnAt(ctx, w.o.PodChurnQPS, w.churnPod) })
+	}
+	group.Start(func() { w.churnAt(ctx, w.o.ConfigMapChurnQPS, w.churnConfigMap) })
+	group.Start(func() { w.churnAt(ctx, w.o.DeviceChurnQPS, w.churnDevice) })
+	group.Wait()
+}
+
+func (w *workload) churnAt(ctx context.Context, qps float64, churn func(ctx context.Context, node string) error) {
+	if qps <= 0 {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / qps))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		node := w.nodeName(rand.Intn(w.o.Nodes))
+		if err := churn(ctx, node); err != nil && ctx.Err() == nil {
+			klog.Warningf("churn failed: %v", err)
+		}
+	}
+}
+
+// churnPod deletes a pod of the node and creates it again.
+func (w *workload) churnPod(ctx context.Context, node string) error {
+	i := rand.Intn(w.o.PodsPerNode)
+	name := podName(node, i)
+	err := w.kubeClient.CoreV1().Pods(workloadNamespace).Delete(ctx, name, metav1.DeleteOptions{GracePeriodSeconds: pointer.Int64(0)})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete pod %s: %v", name, err)
+	}
+	return w.createPod(ctx, node, i)
+}
+
+// churnConfigMap updates the configmap used by the pods of the node.
+func (w *workload) churnConfigMap(ctx context.Context, node string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := w.kubeClient.CoreV1().ConfigMaps(workloadNamespace).Get(ctx, configMapName(node), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		generation, _ := strconv.Atoi(configMap.Data["generation"])
+		configMap.Data["generation"] = strconv.Itoa(generation + 1)
+		configMap.Annotations = sentAt()
+		_, err = w.kubeClient.CoreV1().ConfigMaps(workloadNamespace).Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// churnDevice updates the desired value of the device twin bound to the node.
+func (w *workload) churnDevice(ctx context.Context, node string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		device, err := w.crdClient.DevicesV1beta1().Devices(workloadNamespace).Get(ctx, deviceName(node), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if len(device.Spec.Properties) == 0 {
+			return fmt.Errorf("device %s has no property", device.Name)
+		}
+		value, _ := strconv.Atoi(device.Spec.Properties[0].Desired.Value)
+		device.Spec.Properties[0].Desired.Value = strconv.Itoa(value + 1)
+		device.Annotations = sentAt()
+		_, err = w.crdClient.DevicesV1beta1().Devices(workloadNamespace).Update(ctx, device, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,41 @@
+#!/bin/bash
+
+# Copyright 2023 The KubeEdge Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Runs the hollow-node scale test, all the arguments are passed to it, e.g.
+#   tests/scripts/scale_test.sh --nodes=1000 --duration=10m --output=report.json
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/../.." && pwd -P)"
+ENVTEST_K8S_VERSION=${ENVTEST_K8S_VERSION:-"1.26.1"}
+ENVTEST_DOWNLOAD_DIR=${ENVTEST_DOWNLOAD_DIR:-"/tmp/envtest/bin"}
+# setup-envtest is pinned to a version that builds with the go version of go.mod
+SETUP_ENVTEST_VERSION=${SETUP_ENVTEST_VERSION:-"v0.0.0-20230216140739-c98506dc3b8e"}
+
+SCALE_BIN_DIR="${ROOT_DIR}/_output/local/bin"
+
+cd "${ROOT_DIR}"
+mkdir -p "${SCALE_BIN_DIR}"
+GOBIN="${SCALE_BIN_DIR}" go install sigs.k8s.io/controller-runtime/tools/setup-envtest@${SETUP_ENVTEST_VERSION}
+ENVTEST_BIN_DIR=$("${SCALE_BIN_DIR}/setup-envtest" use ${ENVTEST_K8S_VERSION} --bin-dir=${ENVTEST_DOWNLOAD_DIR} -p path)
+
+go build -o "${SCALE_BIN_DIR}/scale" ./tests/scale
+"${SCALE_BIN_DIR}/scale" \
+    --envtest-bin-dir="${ENVTEST_BIN_DIR}" \
+    --crd-dir="${ROOT_DIR}/build/crds" \
+    "$@"